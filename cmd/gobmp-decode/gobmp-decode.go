@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/message"
+	"github.com/sbezverk/gobmp/pkg/parser"
+	"github.com/sbezverk/gobmp/pkg/recorder"
+)
+
+var (
+	file    string
+	pcap    bool
+	port    int
+	splitAF bool
+	splitLU bool
+	// maxMsgLen is the maximum length of BMP message in the file
+	maxMsgLen int
+)
+
+func init() {
+	flag.StringVar(&file, "file", "", "Capture file produced by gobmp --capture-dir, pcap file or a file with raw BMP stream to decode")
+	flag.BoolVar(&pcap, "pcap", false, "Force processing of the file as pcap, otherwise the format is detected automatically")
+	flag.IntVar(&port, "port", 0, "When processing pcap file, decode only TCP streams using this port, 0 decodes all streams carrying BMP")
+	flag.BoolVar(&splitAF, "split-af", true, "When set true ipv4 and ipv6 messages get ipv4 and ipv6 specific types")
+	flag.BoolVar(&splitLU, "split-lu", false, "When set true labeled unicast messages get transport type along with classful transport messages")
+	flag.IntVar(&maxMsgLen, "max-message-length", bmp.DefaultMaxMessageLength, "Maximum length of BMP message in bytes, decoding stops at a longer message")
+}
+
+// decoded defines the structure of a line printed to the standard output
+type decoded struct {
+	Received string          `json:"received,omitempty"`
+	Router   string          `json:"router,omitempty"`
+	Type     int             `json:"type"`
+	Key      string          `json:"key,omitempty"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// stdout implements pub.Publisher interface and prints messages as JSON lines
+type stdout struct {
+	w        *bufio.Writer
+	router   string
+	received time.Time
+}
+
+func (s *stdout) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	d := decoded{
+		Router: s.router,
+		Type:   msgType,
+		Key:    string(msgHash),
+		Value:  msg,
+	}
+	if !s.received.IsZero() {
+		d.Received = s.received.Format(time.RFC3339Nano)
+	}
+	b, err := json.Marshal(&d)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	_, err = s.w.Write(b)
+
+	return err
+}
+
+func (s *stdout) Stop() {
+	s.w.Flush()
+}
+
+func main() {
+	flag.Parse()
+	_ = flag.Set("logtostderr", "true")
+	if file == "" {
+		glog.Errorf("file to decode must be specified with --file")
+		os.Exit(1)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		glog.Errorf("fail to open file %s with error: %+v", file, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	var r recorder.Reader
+	if pcap || port != 0 {
+		r, err = recorder.NewPcapReader(f, uint16(port), recorder.WithMaxMessageLength(maxMsgLen))
+	} else {
+		r, err = recorder.NewReader(f, recorder.WithMaxMessageLength(maxMsgLen))
+	}
+	if err != nil {
+		glog.Errorf("fail to read file %s with error: %+v", file, err)
+		os.Exit(1)
+	}
+	out := &stdout{
+		w: bufio.NewWriter(os.Stdout),
+	}
+	if err := decode(r, out); err != nil {
+		out.Stop()
+		glog.Errorf("fail to decode file %s with error: %+v", file, err)
+		os.Exit(1)
+	}
+	out.Stop()
+}
+
+// decode runs all records through the parser and the producer, each router gets its own parser
+// session and producer as both keep the state of the BMP session.
+func decode(r recorder.Reader, out *stdout) error {
+	sessions := make(map[string]parser.Session)
+	producers := make(map[string]message.Producer)
+	records := 0
+	for {
+		rec, err := r.Next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fmt.Errorf("record %d: %w", records+1, err)
+		}
+		records++
+		prod, ok := producers[rec.Router]
+		if !ok {
+			prod = message.NewProducer(out, splitAF, splitLU)
+			producers[rec.Router] = prod
+			sessions[rec.Router] = parser.NewSession()
+		}
+		s := sessions[rec.Router]
+		out.router = rec.Router
+		out.received = rec.Timestamp
+		queue := make(chan bmp.Message)
+		go func(b []byte) {
+			s.Parse(b, queue)
+			close(queue)
+		}(rec.Message)
+		for msg := range queue {
+			prod.ProduceMessage(msg)
+		}
+	}
+	glog.V(5).Infof("decoded %d records", records)
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/mrt"
+	"github.com/sbezverk/gobmp/pkg/recorder"
+)
+
+var (
+	file       string
+	pcap       bool
+	port       int
+	dir        string
+	interval   time.Duration
+	postPolicy bool
+	router     string
+	// maxMsgLen is the maximum length of BMP message in the file
+	maxMsgLen int
+)
+
+func init() {
+	flag.StringVar(&file, "file", "", "Capture file produced by gobmp --capture-dir, pcap file or a file with raw BMP stream to export")
+	flag.BoolVar(&pcap, "pcap", false, "Force processing of the file as pcap, otherwise the format is detected automatically")
+	flag.IntVar(&port, "port", 0, "When processing pcap file, export only TCP streams using this port, 0 exports all streams carrying BMP")
+	flag.StringVar(&dir, "mrt-dir", "./mrt", "Directory to store MRT files in, a subdirectory is created for every router")
+	flag.DurationVar(&interval, "mrt-interval", 15*time.Minute, "Interval of RIB snapshots and rotation of updates files, 0 writes a single snapshot at the end")
+	flag.BoolVar(&postPolicy, "mrt-post-policy", false, "Export Adj-RIB-In post-policy routes instead of pre-policy routes")
+	flag.StringVar(&router, "router", "", "Router address used for raw BMP streams which do not carry the router address")
+	flag.IntVar(&maxMsgLen, "max-message-length", bmp.DefaultMaxMessageLength, "Maximum length of BMP message in bytes, export stops at a longer message")
+}
+
+// routerAddr implements net.Addr for router addresses stored in records
+type routerAddr string
+
+func (a routerAddr) Network() string { return "tcp" }
+func (a routerAddr) String() string  { return string(a) }
+
+func main() {
+	flag.Parse()
+	_ = flag.Set("logtostderr", "true")
+	if file == "" {
+		glog.Errorf("file to export must be specified with --file")
+		os.Exit(1)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		glog.Errorf("fail to open file %s with error: %+v", file, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	var r recorder.Reader
+	if pcap || port != 0 {
+		r, err = recorder.NewPcapReader(f, uint16(port), recorder.WithMaxMessageLength(maxMsgLen))
+	} else {
+		r, err = recorder.NewReader(f, recorder.WithMaxMessageLength(maxMsgLen))
+	}
+	if err != nil {
+		glog.Errorf("fail to read file %s with error: %+v", file, err)
+		os.Exit(1)
+	}
+	e, err := mrt.NewExporter(&mrt.Config{
+		Dir:        dir,
+		Interval:   interval,
+		PostPolicy: postPolicy,
+	})
+	if err != nil {
+		glog.Errorf("fail to initialize MRT exporter with error: %+v", err)
+		os.Exit(1)
+	}
+	if err := export(r, e); err != nil {
+		glog.Errorf("fail to export file %s with error: %+v", file, err)
+		os.Exit(1)
+	}
+}
+
+// export passes all records to the exporter, each router gets its own session. Records without
+// the receive timestamp, found in raw BMP streams, are stamped with the current time.
+func export(r recorder.Reader, e recorder.Recorder) error {
+	sessions := make(map[string]recorder.Session)
+	defer func() {
+		for name, s := range sessions {
+			if err := s.Close(); err != nil {
+				glog.Errorf("fail to close MRT export of router %s with error: %+v", name, err)
+			}
+		}
+	}()
+	records := 0
+	for {
+		rec, err := r.Next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fmt.Errorf("record %d: %w", records+1, err)
+		}
+		records++
+		name := rec.Router
+		if name == "" {
+			name = router
+		}
+		s, ok := sessions[name]
+		if !ok {
+			if s, err = e.NewSession(routerAddr(name)); err != nil {
+				return err
+			}
+			sessions[name] = s
+		}
+		ts := rec.Timestamp
+		if ts.IsZero() {
+			ts = time.Now()
+		}
+		if err := s.Record(ts, rec.Message); err != nil {
+			return err
+		}
+	}
+	glog.V(5).Infof("exported %d records", records)
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"net"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/recorder"
+	"github.com/sbezverk/gobmp/pkg/speaker"
+)
+
+var (
+	collector         string
+	capture           string
+	pcapPort          int
+	speed             float64
+	iterations        int
+	routerIP          string
+	routerAS          uint
+	peers             int
+	peerIP            string
+	peerAS            uint
+	prefixes          int
+	prefixesPerUpdate int
+	churnRate         float64
+	duration          time.Duration
+	reportInterval    time.Duration
+	// maxMsgLen is the maximum length of BMP message in the capture
+	maxMsgLen int
+)
+
+func init() {
+	flag.StringVar(&collector, "collector", "localhost:5000", "Address and port of BMP collector")
+	flag.StringVar(&capture, "capture", "", "Capture file produced by gobmp --capture-dir, pcap file or raw BMP stream to replay, when not set a synthetic session is generated")
+	flag.IntVar(&pcapPort, "pcap-port", 0, "When replaying pcap file, replay only TCP streams using this port")
+	flag.IntVar(&maxMsgLen, "max-message-length", bmp.DefaultMaxMessageLength, "Maximum length of BMP message in bytes, replay stops at a longer message")
+	flag.Float64Var(&speed, "speed", 1, "Replay speed multiplier applied to recorded gaps between messages, 0 replays as fast as possible")
+	flag.IntVar(&iterations, "iterations", 1, "Number of times to replay the capture or to run the synthetic session")
+	flag.StringVar(&routerIP, "router-ip", "192.168.0.1", "Address of the synthetic router")
+	flag.UintVar(&routerAS, "router-as", 65000, "AS of the synthetic router")
+	flag.IntVar(&peers, "peers", 1, "Number of synthetic peers")
+	flag.StringVar(&peerIP, "peer-ip", "192.168.1.1", "Address of the first synthetic peer, following peers get consecutive addresses")
+	flag.UintVar(&peerAS, "peer-as", 65001, "AS of the first synthetic peer, following peers get consecutive ASes")
+	flag.IntVar(&prefixes, "prefixes", 1000, "Number of prefixes advertised by each synthetic peer")
+	flag.IntVar(&prefixesPerUpdate, "prefixes-per-update", 100, "Number of prefixes packed in a single update of the initial table")
+	flag.Float64Var(&churnRate, "churn-rate", 0, "Number of prefix flaps per second after the initial table, scaled by speed")
+	flag.DurationVar(&duration, "duration", time.Minute, "Duration of churn after the initial table, 0 churns until interrupted")
+	flag.DurationVar(&reportInterval, "report-interval", 5*time.Second, "Interval of throughput reports")
+}
+
+func main() {
+	flag.Parse()
+	_ = flag.Set("logtostderr", "true")
+	stop := make(chan struct{})
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	go func() {
+		<-c
+		close(stop)
+	}()
+	stats := speaker.NewStats()
+	reportStop := make(chan struct{})
+	go stats.Report(reportInterval, reportStop)
+	for i := 0; i < iterations; i++ {
+		if err := run(stats, stop); err != nil {
+			glog.Errorf("iteration %d failed with error: %+v", i+1, err)
+			close(reportStop)
+			os.Exit(1)
+		}
+		select {
+		case <-stop:
+			i = iterations
+		default:
+		}
+	}
+	close(reportStop)
+	glog.Infof("sent %s", stats)
+}
+
+func run(stats *speaker.Stats, stop <-chan struct{}) error {
+	if capture != "" {
+		f, err := os.Open(capture)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		var r recorder.Reader
+		if pcapPort != 0 {
+			r, err = recorder.NewPcapReader(f, uint16(pcapPort), recorder.WithMaxMessageLength(maxMsgLen))
+		} else {
+			r, err = recorder.NewReader(f, recorder.WithMaxMessageLength(maxMsgLen))
+		}
+		if err != nil {
+			return err
+		}
+		return speaker.Replay(r, collector, speed, stats, stop)
+	}
+	s, err := speaker.Dial(collector, stats)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	rate := churnRate
+	if speed > 0 {
+		rate *= speed
+	}
+
+	return speaker.Synthetic(s, &speaker.SyntheticConfig{
+		RouterIP:          net.ParseIP(routerIP),
+		RouterAS:          uint32(routerAS),
+		Peers:             peers,
+		PeerIP:            net.ParseIP(peerIP),
+		PeerAS:            uint32(peerAS),
+		Prefixes:          prefixes,
+		PrefixesPerUpdate: prefixesPerUpdate,
+		ChurnRate:         rate,
+		Duration:          duration,
+	}, stop)
+}
@@ -8,16 +8,20 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"net/http"
 	_ "net/http/pprof"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
 	"github.com/sbezverk/gobmp/pkg/dumper"
 	"github.com/sbezverk/gobmp/pkg/filer"
 	"github.com/sbezverk/gobmp/pkg/gobmpsrv"
 	"github.com/sbezverk/gobmp/pkg/kafka"
+	"github.com/sbezverk/gobmp/pkg/mrt"
 	"github.com/sbezverk/gobmp/pkg/pub"
+	"github.com/sbezverk/gobmp/pkg/recorder"
 )
 
 var (
@@ -27,8 +31,22 @@ var (
 	kafkaSrv  string
 	intercept string
 	splitAF   string
+	splitLU   string
 	dump      string
 	file      string
+	// Messages file rotation, compression and retention parameters
+	fileMaxSize        int64
+	fileRotateInterval time.Duration
+	fileCompress       string
+	fileMaxSegments    int
+	fileMaxAge         time.Duration
+	captureDir         string
+	// MRT export parameters
+	mrtDir        string
+	mrtInterval   time.Duration
+	mrtPostPolicy bool
+	// Maximum length of BMP message accepted from the router
+	maxMsgLen int
 )
 
 func init() {
@@ -38,9 +56,20 @@ func init() {
 	flag.StringVar(&kafkaSrv, "kafka-server", "", "URL to access Kafka server")
 	flag.StringVar(&intercept, "intercept", "false", "When intercept set \"true\", all incomming BMP messges will be copied to TCP port specified by destination-port, otherwise received BMP messages will be published to Kafka.")
 	flag.StringVar(&splitAF, "split-af", "true", "When set \"true\" (default) ipv4 and ipv6 will be published in separate topics. if set \"false\" the same topic will be used for both address families.")
+	flag.StringVar(&splitLU, "split-lu", "false", "When set \"true\" labeled unicast will be published in transport topic along with classful transport, otherwise it is published in unicast topics.")
 	flag.IntVar(&perfPort, "performance-port", 56767, "port used for performance debugging")
 	flag.StringVar(&dump, "dump", "", "Dump resulting messages to file when \"dump=file\" or to the standard output when \"dump=console\"")
 	flag.StringVar(&file, "msg-file", "/tmp/messages.json", "Full path anf file name to store messages when \"dump=file\"")
+	flag.Int64Var(&fileMaxSize, "msg-file-max-size", 0, "Size in bytes after which messages file is rotated, 0 disables size based rotation")
+	flag.DurationVar(&fileRotateInterval, "msg-file-rotate-interval", 0, "Interval after which messages file is rotated, 0 disables time based rotation")
+	flag.StringVar(&fileCompress, "msg-file-compress", "none", "Compression of rotated messages file segments, \"none\", \"gzip\" or \"zstd\"")
+	flag.IntVar(&fileMaxSegments, "msg-file-max-segments", 0, "Maximum number of rotated messages file segments to keep, 0 keeps all segments")
+	flag.DurationVar(&fileMaxAge, "msg-file-max-age", 0, "Maximum age of rotated messages file segments to keep, 0 keeps all segments")
+	flag.StringVar(&captureDir, "capture-dir", "", "When set, raw BMP messages of each session are stored in a capture file in this directory")
+	flag.StringVar(&mrtDir, "mrt-dir", "", "When set, updates and RIB snapshots of each router are exported in MRT format into a subdirectory of this directory")
+	flag.DurationVar(&mrtInterval, "mrt-interval", 15*time.Minute, "Interval of MRT RIB snapshots and rotation of MRT updates files")
+	flag.BoolVar(&mrtPostPolicy, "mrt-post-policy", false, "Export Adj-RIB-In post-policy routes to MRT instead of pre-policy routes")
+	flag.IntVar(&maxMsgLen, "max-message-length", bmp.DefaultMaxMessageLength, "Maximum length of BMP message in bytes, the session with the router sending a longer message is reset")
 }
 
 var (
@@ -76,7 +105,17 @@ func main() {
 	var err error
 	switch strings.ToLower(dump) {
 	case "file":
-		publisher = filer.NewFiler(file)
+		publisher, err = filer.NewFiler(file, &filer.Config{
+			MaxSize:        fileMaxSize,
+			RotateInterval: fileRotateInterval,
+			Compress:       fileCompress,
+			MaxSegments:    fileMaxSegments,
+			MaxAge:         fileMaxAge,
+		})
+		if err != nil {
+			glog.Errorf("fail to initialize file publisher with error: %+v", err)
+			os.Exit(1)
+		}
 	case "console":
 		publisher = dumper.NewDumper()
 	default:
@@ -100,7 +139,30 @@ func main() {
 		glog.Errorf("fail to parse to bool the value of the intercept flag with error: %+v", err)
 		os.Exit(1)
 	}
-	bmpSrv, err := gobmpsrv.NewBMPServer(srcPort, dstPort, interceptFlag, publisher, splitAFFlag)
+	splitLUFlag, err := strconv.ParseBool(splitLU)
+	if err != nil {
+		glog.Errorf("fail to parse to bool the value of the split-lu flag with error: %+v", err)
+		os.Exit(1)
+	}
+	var capture, export recorder.Recorder
+	if captureDir != "" {
+		if capture, err = recorder.NewRecorder(captureDir); err != nil {
+			glog.Errorf("fail to initialize raw BMP recorder with error: %+v", err)
+			os.Exit(1)
+		}
+	}
+	if mrtDir != "" {
+		if export, err = mrt.NewExporter(&mrt.Config{
+			Dir:        mrtDir,
+			Interval:   mrtInterval,
+			PostPolicy: mrtPostPolicy,
+		}); err != nil {
+			glog.Errorf("fail to initialize MRT exporter with error: %+v", err)
+			os.Exit(1)
+		}
+	}
+	rec := recorder.NewTee(capture, export)
+	bmpSrv, err := gobmpsrv.NewBMPServer(srcPort, dstPort, interceptFlag, publisher, splitAFFlag, splitLUFlag, rec, maxMsgLen)
 	if err != nil {
 		glog.Errorf("fail to setup new gobmp server with error: %+v", err)
 		os.Exit(1)
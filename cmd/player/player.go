@@ -6,15 +6,17 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"sync"
+	"os/signal"
+	"strings"
 	"time"
 
 	"encoding/json"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/dumper"
 	"github.com/sbezverk/gobmp/pkg/filer"
 	"github.com/sbezverk/gobmp/pkg/kafka"
-	"github.com/sbezverk/gobmp/pkg/tools"
+	"github.com/sbezverk/gobmp/pkg/pub"
 )
 
 var (
@@ -22,74 +24,127 @@ var (
 	file       string
 	delay      int
 	iterations int
+	speed      float64
+	types      string
+	routers    string
+	peers      string
+	dump       string
+	dumpFile   string
 )
 
 func init() {
 	flag.StringVar(&msgSrvAddr, "message-server", "", "URL to the messages supplying server")
-	flag.StringVar(&file, "msg-file", "/tmp/messages.json", "File with the bmp messages to replay")
+	flag.StringVar(&file, "msg-file", "/tmp/messages.json", "File with the bmp messages to replay, rotated and compressed segments of the file are replayed first")
 	flag.IntVar(&delay, "delay", 0, "Delay in seconds to add between sending messages")
 	flag.IntVar(&iterations, "iterations", 1, "Number of iterations to replay messages")
+	flag.Float64Var(&speed, "speed", 0, "Replay speed multiplier preserving gaps between stored messages, 0 replays messages as fast as possible")
+	flag.StringVar(&types, "types", "", "Comma separated list of message types to replay, all types are replayed when empty")
+	flag.StringVar(&routers, "routers", "", "Comma separated list of router addresses to replay messages of, all routers when empty")
+	flag.StringVar(&peers, "peers", "", "Comma separated list of peer addresses to replay messages of, all peers when empty")
+	flag.StringVar(&dump, "dump", "", "Publish messages to file when \"dump=file\" or to the standard output when \"dump=console\", to Kafka otherwise")
+	flag.StringVar(&dumpFile, "dump-file", "/tmp/replay.json", "Full path and file name to store messages when \"dump=file\"")
+}
+
+func setupSignalHandler() <-chan struct{} {
+	stop := make(chan struct{})
+	c := make(chan os.Signal, 2)
+	signal.Notify(c, os.Interrupt)
+	go func() {
+		<-c
+		close(stop)
+		<-c
+		os.Exit(1) // second signal. Exit directly.
+	}()
+
+	return stop
 }
 
 func main() {
 	flag.Parse()
 	_ = flag.Set("logtostderr", "true")
-	glog.Infof("kafka server url: %s", msgSrvAddr)
-	// Open messages file
-	f, err := os.Open(file)
-	if err != nil {
-		glog.Errorf("fail to open messages file %s with error: %+v", file, err)
+	if speed < 0 {
+		glog.Errorf("speed must not be negative")
 		os.Exit(1)
 	}
-	defer f.Close()
-
-	// Initializing publisher process
-	publisher, err := kafka.NewKafkaPublisher(msgSrvAddr)
+	f, err := newFilter(types, routers, peers)
 	if err != nil {
-		glog.Errorf("fail to initialize Kafka publisher with error: %+v", err)
+		glog.Errorf("fail to parse messages filter with error: %+v", err)
 		os.Exit(1)
 	}
-	glog.V(5).Infof("Kafka publisher has been successfully initialized.")
-	defer publisher.Stop()
-
-	msgs, err := loadMessages(f)
+	// Discover messages file and its rotated segments
+	segments, err := filer.Segments(file)
 	if err != nil {
-		glog.Errorf("Failed to load messages with error: %+v", err)
+		glog.Errorf("fail to find messages file %s with error: %+v", file, err)
 		os.Exit(1)
 	}
-	records := 0
-	var wg sync.WaitGroup
+
+	// Initializing publisher process
+	var publisher pub.Publisher
+	switch strings.ToLower(dump) {
+	case "file":
+		publisher, err = filer.NewFiler(dumpFile, nil)
+		if err != nil {
+			glog.Errorf("fail to initialize file publisher with error: %+v", err)
+			os.Exit(1)
+		}
+	case "console":
+		publisher = dumper.NewDumper()
+	default:
+		glog.Infof("kafka server url: %s", msgSrvAddr)
+		publisher, err = kafka.NewKafkaPublisher(msgSrvAddr)
+		if err != nil {
+			glog.Errorf("fail to initialize Kafka publisher with error: %+v", err)
+			os.Exit(1)
+		}
+		glog.V(5).Infof("Kafka publisher has been successfully initialized.")
+	}
+
+	msgs := make([]*filer.MsgOut, 0)
+	for _, s := range segments {
+		m, err := loadSegment(s)
+		if err != nil {
+			glog.Errorf("Failed to load messages with error: %+v", err)
+			publisher.Stop()
+			os.Exit(1)
+		}
+		msgs = append(msgs, m...)
+	}
+	stop := setupSignalHandler()
+loop:
 	for i := 0; i < iterations; i++ {
 		start := time.Now()
-		for e := 0; e < len(msgs); e++ {
-			wg.Add(1)
-			go func(msg *filer.MsgOut) {
-				defer wg.Done()
-				if err := publisher.PublishMessage(msg.Type, msg.Key, msg.Value); err != nil {
-					glog.Errorf("fail to publish message type: %d message key: %s with error: %+v", msg.Type, tools.MessageHex(msg.Key), err)
-				}
-			}(msgs[e])
-			records++
-			// If delay was specified in the input parameters, wait for n-seconds before sending next message.
-			time.Sleep(time.Second * time.Duration(delay))
+		records := replay(msgs, publisher, f, speed, time.Second*time.Duration(delay), stop)
+		glog.Infof("%3f seconds took to process %d records", time.Since(start).Seconds(), records)
+		select {
+		case <-stop:
+			break loop
+		default:
 		}
-		wg.Wait()
-		glog.Infof("%3f seconds took to process %d records", time.Now().Sub(start).Seconds(), records)
-		records = 0
 	}
+	publisher.Stop()
 
 	os.Exit(0)
 }
 
-func loadMessages(f *os.File) ([]*filer.MsgOut, error) {
+func loadSegment(name string) ([]*filer.MsgOut, error) {
+	r, err := filer.OpenSegment(name)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open messages file %s with error: %+v", name, err)
+	}
+	defer r.Close()
+
+	return loadMessages(r, name)
+}
+
+func loadMessages(r io.Reader, name string) ([]*filer.MsgOut, error) {
 	msgs := make([]*filer.MsgOut, 0)
-	m := bufio.NewReader(f)
+	m := bufio.NewReader(r)
 	done := false
 	for !done {
 		b, err := m.ReadBytes('\n')
 		if err != nil {
 			if err != io.EOF {
-				return nil, fmt.Errorf("fail to read messages file %s with error: %+v", file, err)
+				return nil, fmt.Errorf("fail to read messages file %s with error: %+v", name, err)
 			}
 			done = true
 			continue
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/filer"
+	"github.com/sbezverk/gobmp/pkg/pub"
+	"github.com/sbezverk/gobmp/pkg/tools"
+)
+
+// msgInfo defines fields of a stored message used to filter and to schedule the message
+type msgInfo struct {
+	RouterIP string `json:"router_ip,omitempty"`
+	PeerIP   string `json:"peer_ip,omitempty"`
+	// RemoteIP carries the peer address in Peer State Change messages
+	RemoteIP  string `json:"remote_ip,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// filter defines sets of message types, routers and peers to replay, an empty set matches everything
+type filter struct {
+	types   map[int]bool
+	routers map[string]bool
+	peers   map[string]bool
+}
+
+func newFilter(types, routers, peers string) (*filter, error) {
+	f := &filter{
+		types:   make(map[int]bool),
+		routers: make(map[string]bool),
+		peers:   make(map[string]bool),
+	}
+	for _, t := range splitList(types) {
+		n, err := strconv.Atoi(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message type %s", t)
+		}
+		f.types[n] = true
+	}
+	for _, r := range splitList(routers) {
+		ip := net.ParseIP(r)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid router address %s", r)
+		}
+		f.routers[ip.String()] = true
+	}
+	for _, p := range splitList(peers) {
+		ip := net.ParseIP(p)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid peer address %s", p)
+		}
+		f.peers[ip.String()] = true
+	}
+
+	return f, nil
+}
+
+func splitList(s string) []string {
+	l := make([]string, 0)
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			l = append(l, e)
+		}
+	}
+
+	return l
+}
+
+func matchIP(set map[string]bool, addr string) bool {
+	if len(set) == 0 {
+		return true
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	return set[ip.String()]
+}
+
+func (f *filter) match(msg *filer.MsgOut, info *msgInfo) bool {
+	if len(f.types) != 0 && !f.types[msg.Type] {
+		return false
+	}
+	if !matchIP(f.routers, info.RouterIP) {
+		return false
+	}
+	peer := info.PeerIP
+	if peer == "" {
+		peer = info.RemoteIP
+	}
+
+	return matchIP(f.peers, peer)
+}
+
+// msgTime returns the time the message was stored, messages stored without the time fall back
+// to the peer timestamp carried in the message, stored is false for the peer timestamp.
+func msgTime(msg *filer.MsgOut, info *msgInfo) (t time.Time, stored bool, ok bool) {
+	if msg.Timestamp != 0 {
+		return time.Unix(0, msg.Timestamp), true, true
+	}
+	if info.Timestamp == "" {
+		return time.Time{}, false, false
+	}
+	t, err := time.Parse(time.StampMicro, info.Timestamp)
+	if err != nil {
+		return time.Time{}, false, false
+	}
+
+	return t, false, true
+}
+
+// replay publishes messages matching the filter in the original order, gaps between messages
+// are preserved and divided by speed, when speed is 0, messages are published as fast as possible.
+// delay is added between consecutive messages. replay returns the number of published messages.
+func replay(msgs []*filer.MsgOut, publisher pub.Publisher, f *filter, speed float64, delay time.Duration, stop <-chan struct{}) int {
+	start := time.Now()
+	// The time base is set by the first message with the time, messages with the time of the other
+	// base are not paced and get published right after the previous message.
+	var first time.Time
+	var firstStored bool
+	var offset, added time.Duration
+	records := 0
+	for _, msg := range msgs {
+		info := &msgInfo{}
+		if err := json.Unmarshal(msg.Value, info); err != nil {
+			glog.V(5).Infof("fail to unmarshal message type: %d key: %s with error: %+v", msg.Type, tools.MessageHex(msg.Key), err)
+		}
+		if !f.match(msg, info) {
+			continue
+		}
+		if t, stored, ok := msgTime(msg, info); ok && speed > 0 {
+			if first.IsZero() {
+				first, firstStored = t, stored
+			}
+			if stored == firstStored {
+				offset = time.Duration(float64(t.Sub(first)) / speed)
+			}
+		}
+		if records != 0 {
+			added += delay
+		}
+		if !waitUntil(start.Add(offset+added), stop) {
+			break
+		}
+		if err := publisher.PublishMessage(msg.Type, msg.Key, msg.Value); err != nil {
+			glog.Errorf("fail to publish message type: %d message key: %s with error: %+v", msg.Type, tools.MessageHex(msg.Key), err)
+		}
+		records++
+	}
+
+	return records
+}
+
+// waitUntil waits until the deadline computed from the replay start, to avoid accumulation of timer errors,
+// it returns false when stop gets closed.
+func waitUntil(deadline time.Time, stop <-chan struct{}) bool {
+	d := time.Until(deadline)
+	if d <= 0 {
+		select {
+		case <-stop:
+			return false
+		default:
+			return true
+		}
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/filer"
+)
+
+type published struct {
+	msgType int
+	at      time.Time
+}
+
+type testPublisher struct {
+	msgs []published
+}
+
+func (p *testPublisher) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	p.msgs = append(p.msgs, published{msgType: msgType, at: time.Now()})
+	return nil
+}
+
+func (p *testPublisher) Stop() {}
+
+func testMessages() []*filer.MsgOut {
+	base := time.Now().UnixNano()
+	return []*filer.MsgOut{
+		{Type: 10, Value: []byte(`{"action":"up","router_ip":"10.0.0.1","remote_ip":"192.168.0.1"}`), Timestamp: base},
+		{Type: 7, Value: []byte(`{"action":"add","router_ip":"10.0.0.1","peer_ip":"192.168.0.1"}`), Timestamp: base + int64(100*time.Millisecond)},
+		{Type: 7, Value: []byte(`{"action":"add","router_ip":"10.0.0.2","peer_ip":"192.168.0.2"}`), Timestamp: base + int64(200*time.Millisecond)},
+		{Type: 11, Value: []byte(`{"action":"add","router_ip":"10.0.0.2","peer_ip":"192.168.0.1"}`), Timestamp: base + int64(400*time.Millisecond)},
+	}
+}
+
+func TestReplayFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		types   string
+		routers string
+		peers   string
+		expect  []int
+		fail    bool
+	}{
+		{
+			name:   "all messages",
+			expect: []int{10, 7, 7, 11},
+		},
+		{
+			name:   "by type",
+			types:  "7, 11",
+			expect: []int{7, 7, 11},
+		},
+		{
+			name:    "by router",
+			routers: "10.0.0.2",
+			expect:  []int{7, 11},
+		},
+		{
+			name:   "by peer including peer state change",
+			peers:  "192.168.0.1",
+			expect: []int{10, 7, 11},
+		},
+		{
+			name:    "by type router and peer",
+			types:   "7",
+			routers: "10.0.0.1",
+			peers:   "192.168.0.1",
+			expect:  []int{7},
+		},
+		{
+			name:  "invalid type",
+			types: "up",
+			fail:  true,
+		},
+		{
+			name:    "invalid router",
+			routers: "router",
+			fail:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newFilter(tt.types, tt.routers, tt.peers)
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("supposed to succeed but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			p := &testPublisher{}
+			if n := replay(testMessages(), p, f, 0, 0, make(chan struct{})); n != len(tt.expect) {
+				t.Fatalf("expected %d published messages but got %d", len(tt.expect), n)
+			}
+			got := make([]int, 0)
+			for _, m := range p.msgs {
+				got = append(got, m.msgType)
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("expected message types %v but got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestReplaySpeed(t *testing.T) {
+	f, _ := newFilter("", "", "")
+	p := &testPublisher{}
+	start := time.Now()
+	// Messages span 400ms, at the speed 2 the replay takes 200ms
+	replay(testMessages(), p, f, 2, 0, make(chan struct{}))
+	if len(p.msgs) != 4 {
+		t.Fatalf("expected 4 published messages but got %d", len(p.msgs))
+	}
+	expect := []time.Duration{0, 50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond}
+	for i, m := range p.msgs {
+		if d := m.at.Sub(start); d < expect[i] || d > expect[i]+100*time.Millisecond {
+			t.Errorf("message %d expected to be published after %s but was published after %s", i, expect[i], d)
+		}
+	}
+}
+
+func TestReplayPeerTimestamp(t *testing.T) {
+	f, _ := newFilter("", "", "")
+	p := &testPublisher{}
+	msgs := []*filer.MsgOut{
+		{Type: 7, Value: []byte(`{"timestamp":"Sep  9 06:34:58.000000"}`)},
+		{Type: 7, Value: []byte(`{"timestamp":"Sep  9 06:34:58.200000"}`)},
+	}
+	start := time.Now()
+	replay(msgs, p, f, 1, 0, make(chan struct{}))
+	if d := p.msgs[1].at.Sub(start); d < 200*time.Millisecond {
+		t.Fatalf("second message expected to be published after 200ms but was published after %s", d)
+	}
+}
+
+func TestReplayMixedTimestamps(t *testing.T) {
+	f, _ := newFilter("", "", "")
+	p := &testPublisher{}
+	base := time.Now().UnixNano()
+	// The stored time of the second message is not comparable with peer timestamps
+	msgs := []*filer.MsgOut{
+		{Type: 7, Value: []byte(`{"timestamp":"Sep  9 06:34:58.000000"}`)},
+		{Type: 7, Value: []byte(`{"timestamp":"Sep  9 06:34:58.100000"}`), Timestamp: base},
+		{Type: 7, Value: []byte(`{"timestamp":"Sep  9 06:34:58.200000"}`)},
+	}
+	start := time.Now()
+	replay(msgs, p, f, 2, 0, make(chan struct{}))
+	if len(p.msgs) != 3 {
+		t.Fatalf("expected 3 published messages but got %d", len(p.msgs))
+	}
+	expect := []time.Duration{0, 0, 100 * time.Millisecond}
+	for i, m := range p.msgs {
+		if d := m.at.Sub(start); d < expect[i] || d > expect[i]+100*time.Millisecond {
+			t.Errorf("message %d expected to be published after %s but was published after %s", i, expect[i], d)
+		}
+	}
+}
+
+func TestReplayStop(t *testing.T) {
+	f, _ := newFilter("", "", "")
+	p := &testPublisher{}
+	stop := make(chan struct{})
+	close(stop)
+	if n := replay(testMessages(), p, f, 1, 0, stop); n != 0 {
+		t.Fatalf("expected no published messages after stop but got %d", n)
+	}
+}
@@ -7,6 +7,7 @@ require (
 	github.com/arangodb/go-driver v0.0.0-20200403100147-ca5dd87ffe93
 	github.com/go-test/deep v1.0.6
 	github.com/golang/glog v0.0.0-20160126235308-23def4e6c14b
+	github.com/klauspost/compress v1.10.10
 	github.com/segmentio/kafka-go v0.4.2
 	golang.org/x/sync v0.0.0-20200625203802-6e8e738ad208
 	gopkg.in/yaml.v2 v2.2.8 // indirect
@@ -0,0 +1,58 @@
+package base
+
+import (
+	"fmt"
+)
+
+// ErrorAction defines the approach to handle a malformed BGP Update per rfc7606 section 2,
+// actions are ordered by severity.
+type ErrorAction int
+
+const (
+	// AttributeDiscard defines the approach when the malformed attribute is discarded
+	// and the rest of the update is processed
+	AttributeDiscard ErrorAction = iota + 1
+	// TreatAsWithdraw defines the approach when all routes of the update are handled as withdrawn
+	TreatAsWithdraw
+	// AFISAFIDisable defines the approach when routes of the malformed MP_REACH_NLRI or MP_UNREACH_NLRI
+	// attribute cannot be processed
+	AFISAFIDisable
+	// SessionReset defines the approach when the update cannot be processed at all
+	SessionReset
+)
+
+var errorActionNames = map[ErrorAction]string{
+	AttributeDiscard: "attribute-discard",
+	TreatAsWithdraw:  "treat-as-withdraw",
+	AFISAFIDisable:   "afi-safi-disable",
+	SessionReset:     "session-reset",
+}
+
+func (a ErrorAction) String() string {
+	if n, ok := errorActionNames[a]; ok {
+		return n
+	}
+
+	return fmt.Sprintf("unknown action %d", int(a))
+}
+
+// DecodeError defines an error found while decoding BGP Update
+type DecodeError struct {
+	// AttributeType is the type code of the malformed path attribute, 0 when the error is not
+	// specific to a path attribute.
+	AttributeType uint8
+	// Offset is the offset of the malformed element from the beginning of BGP Update message body,
+	// for a malformed route of MP_REACH_NLRI or MP_UNREACH_NLRI it is the offset from the beginning
+	// of the attribute's NLRI field.
+	Offset int
+	Reason string
+	Action ErrorAction
+}
+
+func (e *DecodeError) Error() string {
+	if e.AttributeType != 0 {
+		return fmt.Sprintf("malformed attribute %d at offset %d: %s, %s", e.AttributeType, e.Offset, e.Reason, e.Action)
+	}
+
+	return fmt.Sprintf("malformed update at offset %d: %s, %s", e.Offset, e.Reason, e.Action)
+}
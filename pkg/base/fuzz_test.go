@@ -0,0 +1,78 @@
+//go:build go1.18
+// +build go1.18
+
+package base
+
+import "testing"
+
+func FuzzUnmarshalPrefixNLRI(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte, ipv4 bool) {
+		UnmarshalPrefixNLRI(b, ipv4)
+	})
+}
+
+func FuzzUnmarshalLinkNLRI(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalLinkNLRI(b)
+	})
+}
+
+func FuzzUnmarshalMSDTV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalMSDTV(b)
+	})
+}
+
+func FuzzUnmarshalMultiTopologyIdentifierTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalMultiTopologyIdentifierTLV(b)
+	})
+}
+
+func FuzzUnmarshalNodeNLRI(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalNodeNLRI(b)
+	})
+}
+
+func FuzzUnmarshalTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalTLV(b)
+	})
+}
+
+func FuzzUnmarshalSubTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSubTLV(b)
+	})
+}
+
+func FuzzUnmarshalRoutes(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalRoutes(b)
+	})
+}
+
+func FuzzUnmarshalIPReachabilityInformation(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalIPReachabilityInformation(b)
+	})
+}
+
+func FuzzUnmarshalLinkDescriptor(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalLinkDescriptor(b)
+	})
+}
+
+func FuzzUnmarshalNodeDescriptor(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalNodeDescriptor(b)
+	})
+}
+
+func FuzzUnmarshalPrefixDescriptor(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalPrefixDescriptor(b)
+	})
+}
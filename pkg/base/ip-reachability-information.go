@@ -1,6 +1,8 @@
 package base
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
@@ -17,6 +19,9 @@ func UnmarshalIPReachabilityInformation(b []byte) (*IPReachabilityInformation, e
 	if glog.V(6) {
 		glog.Infof("IPReachabilityInformationTLV Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("not enough bytes to unmarshal IP Reachability Information TLV")
+	}
 	ipr := IPReachabilityInformation{
 		LengthInBits: b[0],
 	}
@@ -128,12 +128,21 @@ func UnmarshalLinkNLRI(b []byte) (*LinkNLRI, error) {
 	p++
 	// Skip 3 reserved bytes
 	//	p += 3
+	if p+8 > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal Link NLRI")
+	}
 	l.Identifier = make([]byte, 8)
 	copy(l.Identifier, b[p:p+8])
 	p += 8
 	// Local Node Descriptor
 	// Get Node Descriptor's length, skip Node Descriptor Type
+	if p+4 > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal Node Descriptor")
+	}
 	ndl := binary.BigEndian.Uint16(b[p+2 : p+4])
+	if p+int(ndl)+4 > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal Node Descriptor of length %d", ndl)
+	}
 	ln, err := UnmarshalNodeDescriptor(b[p : p+int(ndl)+4])
 	if err != nil {
 		return nil, err
@@ -145,7 +154,13 @@ func UnmarshalLinkNLRI(b []byte) (*LinkNLRI, error) {
 	p += int(ndl)
 	// Remote Node Descriptor
 	// Get Node Descriptor's length, skip Node Descriptor Type
+	if p+4 > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal Remote Node Descriptor")
+	}
 	ndl = binary.BigEndian.Uint16(b[p+2 : p+4])
+	if p+int(ndl)+4 > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal Remote Node Descriptor of length %d", ndl)
+	}
 	rn, err := UnmarshalNodeDescriptor(b[p : p+int(ndl)+4])
 	if err != nil {
 		return nil, err
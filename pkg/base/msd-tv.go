@@ -1,6 +1,8 @@
 package base
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
@@ -18,6 +20,9 @@ func UnmarshalMSDTV(b []byte) ([]*MSDTV, error) {
 	}
 	tvs := make([]*MSDTV, 0)
 	for p := 0; p < len(b); {
+		if p+2 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal MSD Type Value tuple")
+		}
 		tv := &MSDTV{}
 		tv.Type = b[p]
 		p++
@@ -0,0 +1,28 @@
+package base
+
+import "fmt"
+
+// UnmarshalMulticastAddress unmarshals multicast source, group or originating router's address prefixed by
+// its length in bits, zero length denotes the wildcard per rfc6625. It returns the address and the number
+// of consumed bytes.
+func UnmarshalMulticastAddress(b []byte) ([]byte, int, error) {
+	if len(b) == 0 {
+		return nil, 0, fmt.Errorf("not enough bytes to unmarshal address length")
+	}
+	l := int(b[0])
+	switch l {
+	case 0:
+	case 32:
+	case 128:
+	default:
+		return nil, 0, fmt.Errorf("invalid address length %d", l)
+	}
+	l /= 8
+	if 1+l > len(b) {
+		return nil, 0, fmt.Errorf("not enough bytes to unmarshal address of length %d", l)
+	}
+	addr := make([]byte, l)
+	copy(addr, b[1:1+l])
+
+	return addr, 1 + l, nil
+}
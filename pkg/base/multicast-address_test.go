@@ -0,0 +1,67 @@
+package base
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalMulticastAddress(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		fail   bool
+		expect []byte
+		n      int
+	}{
+		{
+			name:   "wildcard",
+			input:  []byte{0x00, 0x20, 0xe8, 0x01, 0x01, 0x01},
+			expect: []byte{},
+			n:      1,
+		},
+		{
+			name:   "ipv4",
+			input:  []byte{0x20, 0xe8, 0x01, 0x01, 0x01, 0x00},
+			expect: []byte{0xe8, 0x01, 0x01, 0x01},
+			n:      5,
+		},
+		{
+			name:   "ipv6",
+			input:  []byte{0x80, 0xff, 0x3e, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+			expect: []byte{0xff, 0x3e, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+			n:      17,
+		},
+		{
+			name:  "invalid length",
+			input: []byte{0x18, 0xe8, 0x01, 0x01},
+			fail:  true,
+		},
+		{
+			name:  "truncated address",
+			input: []byte{0x20, 0xe8, 0x01},
+			fail:  true,
+		},
+		{
+			name:  "empty",
+			input: []byte{},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, n, err := UnmarshalMulticastAddress(tt.input)
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("supposed to succeed but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if !reflect.DeepEqual(addr, tt.expect) || n != tt.n {
+				t.Errorf("expected address %v of %d bytes but got %v of %d bytes", tt.expect, tt.n, addr, n)
+			}
+		})
+	}
+}
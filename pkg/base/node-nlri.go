@@ -69,6 +69,9 @@ func UnmarshalNodeNLRI(b []byte) (*NodeNLRI, error) {
 	n.ProtocolID = ProtoID(b[p])
 	p++
 
+	if p+8 > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal Node NLRI")
+	}
 	n.Identifier = make([]byte, 8)
 	copy(n.Identifier, b[p:p+8])
 	p += 8
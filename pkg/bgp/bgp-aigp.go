@@ -0,0 +1,31 @@
+package bgp
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// unmarshalAttrAIGP returns the accumulated IGP metric carried in AIGP TLV of AIGP attribute per rfc7311,
+// TLVs of other types are skipped.
+func unmarshalAttrAIGP(b []byte) (uint64, error) {
+	for p := 0; p < len(b); {
+		if p+3 > len(b) {
+			return 0, fmt.Errorf("not enough bytes to unmarshal AIGP TLV")
+		}
+		t := b[p]
+		// TLV length includes type and length fields
+		l := int(binary.BigEndian.Uint16(b[p+1 : p+3]))
+		if l < 3 || p+l > len(b) {
+			return 0, fmt.Errorf("invalid AIGP TLV length %d", l)
+		}
+		if t == 1 {
+			if l != 11 {
+				return 0, fmt.Errorf("invalid AIGP TLV length %d", l)
+			}
+			return binary.BigEndian.Uint64(b[p+3 : p+11]), nil
+		}
+		p += l
+	}
+
+	return 0, fmt.Errorf("AIGP TLV is not found")
+}
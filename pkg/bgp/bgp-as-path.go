@@ -0,0 +1,230 @@
+package bgp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ASSet defines AS_SET path segment type
+	ASSet = 1
+	// ASSequence defines AS_SEQUENCE path segment type
+	ASSequence = 2
+	// ASConfedSequence defines AS_CONFED_SEQUENCE path segment type per rfc5065
+	ASConfedSequence = 3
+	// ASConfedSet defines AS_CONFED_SET path segment type per rfc5065
+	ASConfedSet = 4
+	// ASTrans defines the reserved 2 bytes AS used in place of 4 bytes ASes per rfc6793
+	ASTrans = 23456
+)
+
+// ASPathSegmentTypes defines names of AS_PATH segment types
+var ASPathSegmentTypes = map[uint8]string{
+	ASSet:            "set",
+	ASSequence:       "sequence",
+	ASConfedSequence: "confed_sequence",
+	ASConfedSet:      "confed_set",
+}
+
+// ASPathSegment defines a single segment of AS_PATH or AS4_PATH attribute
+type ASPathSegment struct {
+	Type string   `json:"type"`
+	ASes []uint32 `json:"ases"`
+}
+
+func (s *ASPathSegment) isConfed() bool {
+	return s.Type == ASPathSegmentTypes[ASConfedSequence] || s.Type == ASPathSegmentTypes[ASConfedSet]
+}
+
+func (s *ASPathSegment) isSet() bool {
+	return s.Type == ASPathSegmentTypes[ASSet] || s.Type == ASPathSegmentTypes[ASConfedSet]
+}
+
+// len returns the number of ASes the segment contributes to the AS path length
+// used by the decision process per rfc4271 section 9.1.2.2 and rfc5065 section 5.3
+func (s *ASPathSegment) len() int {
+	switch {
+	case s.isConfed():
+		return 0
+	case s.isSet():
+		return 1
+	}
+
+	return len(s.ASes)
+}
+
+// ASPath defines AS_PATH attribute as a list of typed path segments
+type ASPath []*ASPathSegment
+
+// String returns the text representation of AS path, ASes of AS_SET are enclosed in {},
+// of AS_CONFED_SEQUENCE in () and of AS_CONFED_SET in [], for example "65001 {65002,65003}".
+func (a ASPath) String() string {
+	s := make([]string, 0, len(a))
+	for _, seg := range a {
+		ases := make([]string, len(seg.ASes))
+		for i, as := range seg.ASes {
+			ases[i] = strconv.FormatUint(uint64(as), 10)
+		}
+		switch seg.Type {
+		case ASPathSegmentTypes[ASSet]:
+			s = append(s, "{"+strings.Join(ases, ",")+"}")
+		case ASPathSegmentTypes[ASConfedSequence]:
+			s = append(s, "("+strings.Join(ases, " ")+")")
+		case ASPathSegmentTypes[ASConfedSet]:
+			s = append(s, "["+strings.Join(ases, ",")+"]")
+		default:
+			s = append(s, strings.Join(ases, " "))
+		}
+	}
+
+	return strings.Join(s, " ")
+}
+
+// Len returns AS path length as used by the decision process, AS_SET counts as 1
+// and confederation segments are not counted.
+func (a ASPath) Len() int {
+	l := 0
+	for _, seg := range a {
+		l += seg.len()
+	}
+
+	return l
+}
+
+// OriginAS returns the rightmost AS of AS path when the last segment is AS_SEQUENCE,
+// otherwise the origin cannot be determined and 0 is returned, see rfc6811 section 2.
+func (a ASPath) OriginAS() uint32 {
+	if len(a) == 0 {
+		return 0
+	}
+	last := a[len(a)-1]
+	if last.Type != ASPathSegmentTypes[ASSequence] || len(last.ASes) == 0 {
+		return 0
+	}
+
+	return last.ASes[len(last.ASes)-1]
+}
+
+// ASes returns ASes of all segments as a flat list
+func (a ASPath) ASes() []uint32 {
+	if len(a) == 0 {
+		return nil
+	}
+	ases := make([]uint32, 0)
+	for _, seg := range a {
+		ases = append(ases, seg.ASes...)
+	}
+
+	return ases
+}
+
+// unmarshalAttrASPath returns segments of AS_PATH attribute, as4 defines if ASes are encoded
+// in 4 or 2 bytes which depends on 4 bytes AS capability negotiated by the peers.
+func unmarshalAttrASPath(b []byte, as4 bool) (ASPath, error) {
+	asl := 2
+	if as4 {
+		asl = 4
+	}
+	path := make(ASPath, 0)
+	for p := 0; p < len(b); {
+		if p+2 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal AS path segment header")
+		}
+		t, ok := ASPathSegmentTypes[b[p]]
+		if !ok {
+			return nil, fmt.Errorf("invalid AS path segment type %d", b[p])
+		}
+		l := int(b[p+1])
+		p += 2
+		if l == 0 {
+			return nil, fmt.Errorf("invalid AS path segment of zero length")
+		}
+		if p+l*asl > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal AS path segment of %d ASes of %d bytes", l, asl)
+		}
+		seg := &ASPathSegment{
+			Type: t,
+			ASes: make([]uint32, l),
+		}
+		for n := 0; n < l; n++ {
+			if as4 {
+				seg.ASes[n] = binary.BigEndian.Uint32(b[p : p+4])
+			} else {
+				seg.ASes[n] = uint32(binary.BigEndian.Uint16(b[p : p+2]))
+			}
+			p += asl
+		}
+		path = append(path, seg)
+	}
+
+	return path, nil
+}
+
+// unmarshalAggregator returns AS and address of AGGREGATOR or AS4_AGGREGATOR attribute
+func unmarshalAggregator(b []byte, as4 bool) (uint32, string, error) {
+	asl := 2
+	if as4 {
+		asl = 4
+	}
+	if len(b) != asl+4 {
+		return 0, "", fmt.Errorf("invalid aggregator length %d", len(b))
+	}
+	var as uint32
+	if as4 {
+		as = binary.BigEndian.Uint32(b[:4])
+	} else {
+		as = uint32(binary.BigEndian.Uint16(b[:2]))
+	}
+
+	return as, net.IP(b[asl:]).To4().String(), nil
+}
+
+// mergeAS4Path reconstructs AS path of a session without 4 bytes AS capability from AS_PATH
+// and AS4_PATH per rfc6793 section 4.2.3. Leading ASes of AS_PATH not covered by AS4_PATH
+// are prepended to AS4_PATH, confederation segments of AS4_PATH are discarded.
+func mergeAS4Path(path, as4path ASPath) ASPath {
+	tail := make(ASPath, 0, len(as4path))
+	for _, seg := range as4path {
+		if !seg.isConfed() {
+			tail = append(tail, seg)
+		}
+	}
+	need := path.Len() - tail.Len()
+	if len(tail) == 0 || need < 0 {
+		return path
+	}
+	merged := make(ASPath, 0, len(path)+len(tail))
+	for _, seg := range path {
+		if need == 0 && !seg.isConfed() {
+			break
+		}
+		switch {
+		case seg.isConfed():
+			merged = append(merged, seg)
+		case seg.isSet():
+			merged = append(merged, seg)
+			need--
+		case len(seg.ASes) <= need:
+			merged = append(merged, seg)
+			need -= len(seg.ASes)
+		default:
+			merged = append(merged, &ASPathSegment{Type: seg.Type, ASes: seg.ASes[:need]})
+			need = 0
+		}
+	}
+	for _, seg := range tail {
+		// Joining adjacent AS_SEQUENCE segments split by the merge
+		if l := len(merged); l != 0 && merged[l-1].Type == ASPathSegmentTypes[ASSequence] && seg.Type == ASPathSegmentTypes[ASSequence] {
+			ases := make([]uint32, 0, len(merged[l-1].ASes)+len(seg.ASes))
+			ases = append(ases, merged[l-1].ASes...)
+			merged[l-1] = &ASPathSegment{Type: seg.Type, ASes: append(ases, seg.ASes...)}
+			continue
+		}
+		merged = append(merged, seg)
+	}
+
+	return merged
+}
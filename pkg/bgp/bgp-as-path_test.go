@@ -0,0 +1,175 @@
+package bgp
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestASPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		as4    bool
+		expect ASPath
+		str    string
+		len    int
+		origin uint32
+		fail   bool
+	}{
+		{
+			name:  "sequence and set",
+			input: []byte{2, 1, 0, 0, 0xfd, 0xe9, 1, 2, 0, 0, 0xfd, 0xea, 0, 0, 0xfd, 0xeb},
+			as4:   true,
+			expect: ASPath{
+				{Type: "sequence", ASes: []uint32{65001}},
+				{Type: "set", ASes: []uint32{65002, 65003}},
+			},
+			str:    "65001 {65002,65003}",
+			len:    2,
+			origin: 0,
+		},
+		{
+			name:  "confederation",
+			input: []byte{3, 2, 0xfc, 0x00, 0xfc, 0x01, 4, 1, 0xfc, 0x02, 2, 2, 0xfd, 0xe9, 0xfd, 0xea},
+			expect: ASPath{
+				{Type: "confed_sequence", ASes: []uint32{64512, 64513}},
+				{Type: "confed_set", ASes: []uint32{64514}},
+				{Type: "sequence", ASes: []uint32{65001, 65002}},
+			},
+			str:    "(64512 64513) [64514] 65001 65002",
+			len:    2,
+			origin: 65002,
+		},
+		{
+			name:   "empty",
+			input:  []byte{},
+			expect: ASPath{},
+		},
+		{
+			name:  "4 bytes path decoded as 2 bytes",
+			input: []byte{2, 1, 0, 0, 0xfd, 0xe9},
+			fail:  true,
+		},
+		{
+			name:  "invalid segment type",
+			input: []byte{5, 1, 0xfd, 0xe9},
+			fail:  true,
+		},
+		{
+			name:  "zero length segment",
+			input: []byte{2, 0},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := unmarshalAttrASPath(tt.input, tt.as4)
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("supposed to succeed but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if diff := deep.Equal(tt.expect, path); diff != nil {
+				t.Errorf("Diffs: %+v", diff)
+			}
+			if s := path.String(); s != tt.str {
+				t.Errorf("expected string %q but got %q", tt.str, s)
+			}
+			if l := path.Len(); l != tt.len {
+				t.Errorf("expected length %d but got %d", tt.len, l)
+			}
+			if o := path.OriginAS(); o != tt.origin {
+				t.Errorf("expected origin AS %d but got %d", tt.origin, o)
+			}
+		})
+	}
+}
+
+func TestReconcileAS4(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []byte
+		as4        bool
+		path       string
+		count      int32
+		origin     uint32
+		aggregator uint32
+	}{
+		{
+			name: "as4 path and as4 aggregator merged",
+			// AS_PATH 65001 23456 23456, AGGREGATOR 23456 10.0.0.1, AS4_PATH 100000 200000, AS4_AGGREGATOR 200000 10.0.0.1
+			input: []byte{0x40, 2, 8, 2, 3, 0xfd, 0xe9, 0x5b, 0xa0, 0x5b, 0xa0,
+				0xc0, 7, 6, 0x5b, 0xa0, 10, 0, 0, 1,
+				0xc0, 17, 10, 2, 2, 0, 0x01, 0x86, 0xa0, 0, 0x03, 0x0d, 0x40,
+				0xc0, 18, 8, 0, 0x03, 0x0d, 0x40, 10, 0, 0, 1},
+			path:       "65001 100000 200000",
+			count:      3,
+			origin:     200000,
+			aggregator: 200000,
+		},
+		{
+			name: "aggregator with 2 bytes as ignores as4 path",
+			// AS_PATH 65001 23456, AGGREGATOR 65002 10.0.0.1, AS4_PATH 100000
+			input: []byte{0x40, 2, 6, 2, 2, 0xfd, 0xe9, 0x5b, 0xa0,
+				0xc0, 7, 6, 0xfd, 0xea, 10, 0, 0, 1,
+				0xc0, 17, 6, 2, 1, 0, 0x01, 0x86, 0xa0},
+			path:       "65001 23456",
+			count:      2,
+			origin:     23456,
+			aggregator: 65002,
+		},
+		{
+			name: "as4 path longer than as path is ignored",
+			// AS_PATH 23456, AS4_PATH 100000 200000
+			input: []byte{0x40, 2, 4, 2, 1, 0x5b, 0xa0,
+				0xc0, 17, 10, 2, 2, 0, 0x01, 0x86, 0xa0, 0, 0x03, 0x0d, 0x40},
+			path:   "23456",
+			count:  1,
+			origin: 23456,
+		},
+		{
+			name: "as set and confederation segments of as4 path",
+			// AS_PATH 65001 23456 {23456,65003}, AS4_PATH (64512) 100000 {100001,65003}
+			input: []byte{0x40, 2, 12, 2, 2, 0xfd, 0xe9, 0x5b, 0xa0, 1, 2, 0x5b, 0xa0, 0xfd, 0xeb,
+				0xc0, 17, 22, 3, 1, 0, 0, 0xfc, 0, 2, 1, 0, 0x01, 0x86, 0xa0, 1, 2, 0, 0x01, 0x86, 0xa1, 0, 0, 0xfd, 0xeb},
+			path:   "65001 100000 {100001,65003}",
+			count:  3,
+			origin: 0,
+		},
+		{
+			name: "4 bytes session ignores as4 path",
+			// AS_PATH 100000, AS4_PATH 200000
+			input: []byte{0x40, 2, 6, 2, 1, 0, 0x01, 0x86, 0xa0,
+				0xc0, 17, 6, 2, 1, 0, 0x03, 0x0d, 0x40},
+			as4:    true,
+			path:   "100000",
+			count:  1,
+			origin: 100000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs, err := UnmarshalBGPBaseAttributes(tt.input, tt.as4)
+			if err != nil {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if attrs.ASPathString != tt.path {
+				t.Errorf("expected AS path %q but got %q", tt.path, attrs.ASPathString)
+			}
+			if attrs.ASPathCount != tt.count {
+				t.Errorf("expected AS path count %d but got %d", tt.count, attrs.ASPathCount)
+			}
+			if attrs.OriginAS != tt.origin {
+				t.Errorf("expected origin AS %d but got %d", tt.origin, attrs.OriginAS)
+			}
+			if attrs.AggregatorAS != tt.aggregator {
+				t.Errorf("expected aggregator AS %d but got %d", tt.aggregator, attrs.AggregatorAS)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net"
 	"strconv"
 
@@ -16,100 +17,151 @@ import (
 // codes for each can be found:
 // https://www.iana.org/assignments/bgp-parameters/bgp-parameters.xhtml#bgp-parameters-2
 type BaseAttributes struct {
-	BaseAttrHash     string   `json:"base_attr_hash,omitempty"`
-	Origin           string   `json:"origin,omitempty"`
-	ASPath           []uint32 `json:"as_path,omitempty"`
-	ASPathCount      int32    `json:"as_path_count,omitempty"`
-	Nexthop          string   `json:"nexthop,omitempty"`
-	MED              uint32   `json:"med,omitempty"`
-	LocalPref        uint32   `json:"local_pref,omitempty"`
-	IsAtomicAgg      bool     `json:"is_atomic_agg"`
-	Aggregator       []byte   `json:"aggregator,omitempty"`
-	CommunityList    []string `json:"community_list,omitempty"`
-	OriginatorID     string   `json:"originator_id,omitempty"`
-	ClusterList      string   `json:"cluster_list,omitempty"`
-	ExtCommunityList []string `json:"ext_community_list,omitempty"`
-	AS4Path          []uint32 `json:"as4_path,omitempty"`
-	AS4PathCount     int32    `json:"as4_path_count,omitempty"`
-	AS4Aggregator    []byte   `json:"as4_aggregator,omitempty"`
-	// PMSITunnel
-	TunnelEncapAttr []byte `json:"-"`
+	BaseAttrHash     string      `json:"base_attr_hash,omitempty"`
+	Origin           string      `json:"origin,omitempty"`
+	ASPath           []uint32    `json:"as_path,omitempty"`
+	ASPathCount      int32       `json:"as_path_count,omitempty"`
+	ASPathSegments   ASPath      `json:"as_path_segments,omitempty"`
+	ASPathString     string      `json:"as_path_string,omitempty"`
+	OriginAS         uint32      `json:"origin_as,omitempty"`
+	Nexthop          string      `json:"nexthop,omitempty"`
+	MED              uint32      `json:"med,omitempty"`
+	LocalPref        uint32      `json:"local_pref,omitempty"`
+	IsAtomicAgg      bool        `json:"is_atomic_agg"`
+	Aggregator       []byte      `json:"aggregator,omitempty"`
+	AggregatorAS     uint32      `json:"aggregator_as,omitempty"`
+	AggregatorAddr   string      `json:"aggregator_addr,omitempty"`
+	CommunityList    []string    `json:"community_list,omitempty"`
+	OriginatorID     string      `json:"originator_id,omitempty"`
+	ClusterList      string      `json:"cluster_list,omitempty"`
+	ExtCommunityList []string    `json:"ext_community_list,omitempty"`
+	AS4Path          []uint32    `json:"as4_path,omitempty"`
+	AS4PathCount     int32       `json:"as4_path_count,omitempty"`
+	AS4Aggregator    []byte      `json:"as4_aggregator,omitempty"`
+	PMSITunnel       *PMSITunnel `json:"pmsi_tunnel,omitempty"`
+	TunnelEncapAttr  []byte      `json:"-"`
 	// TraficEng
 	// IPv6SpecExtCommunity
-	// AIGP
+	AIGP *uint64 `json:"aigp,omitempty"`
 	// PEDistinguisherLable
-	LgCommunityList []string `json:"large_community_list,omitempty"`
-	// SecPath
-	// AttrSet
+	LgCommunityList   []string            `json:"large_community_list,omitempty"`
+	BGPsecPath        *BGPsecPath         `json:"bgpsec_path,omitempty"`
+	OTC               uint32              `json:"otc,omitempty"`
+	AttrSet           *AttrSet            `json:"attr_set,omitempty"`
+	UnknownAttributes []*UnknownAttribute `json:"unknown_attributes,omitempty"`
+}
+
+// AttrSet defines ATTR_SET attribute per rfc6368, the attributes of the customer network
+// carried across the provider's network.
+type AttrSet struct {
+	OriginAS   uint32          `json:"origin_as"`
+	Attributes *BaseAttributes `json:"attributes,omitempty"`
+}
+
+// UnknownAttribute defines a path attribute which is not decoded, the value is hex encoded.
+type UnknownAttribute struct {
+	Type  uint8  `json:"type"`
+	Flags uint8  `json:"flags"`
+	Value string `json:"value"`
 }
 
 // UnmarshalBGPBaseAttributes discovers all present Base Attributes in BGP Update
-// and instantiates BaseAttributes object
-func UnmarshalBGPBaseAttributes(b []byte) (*BaseAttributes, error) {
+// and instantiates BaseAttributes object, as4 defines if the peers negotiated 4 bytes AS capability.
+// For 2 bytes AS sessions, AS4_PATH and AS4_AGGREGATOR are merged into AS path and aggregator per rfc6793.
+func UnmarshalBGPBaseAttributes(b []byte, as4 bool) (*BaseAttributes, error) {
 	if glog.V(6) {
 		glog.Infof("UnmarshalBGPBaseAttributes RAW: %+v", tools.MessageHex(b))
 	}
+	attrs, err := UnmarshalBGPPathAttributes(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBaseAttributes(attrs, as4)
+}
+
+// newBaseAttributes instantiates BaseAttributes object from path attributes of BGP Update
+func newBaseAttributes(attrs []PathAttribute, as4 bool) (*BaseAttributes, error) {
 	baseAttr := BaseAttributes{}
-	for p := 0; p < len(b); {
-		flag := b[p]
-		p++
-		t := b[p]
-		p++
-		var l uint16
-		// Checking for Extened
-		if flag&0x10 == 0x10 {
-			l = binary.BigEndian.Uint16(b[p : p+2])
-			p += 2
-		} else {
-			l = uint16(b[p])
-			p++
-		}
-		switch t {
+	var asPath, as4Path ASPath
+	var err error
+	for _, attr := range attrs {
+		b := attr.Attribute
+		switch attr.AttributeType {
 		case 1:
-			baseAttr.Origin = unmarshalAttrOrigin(b[p : p+int(l)])
+			baseAttr.Origin = unmarshalAttrOrigin(b)
 		case 2:
-			baseAttr.ASPath = unmarshalAttrASPath(b[p : p+int(l)])
-			baseAttr.ASPathCount = int32(len(baseAttr.ASPath))
+			if asPath, err = unmarshalAttrASPath(b, as4); err != nil {
+				glog.Warningf("fail to unmarshal AS_PATH with error: %+v", err)
+			}
 		case 3:
-			baseAttr.Nexthop = unmarshalAttrNextHop(b[p : p+int(l)])
+			baseAttr.Nexthop = unmarshalAttrNextHop(b)
 		case 4:
-			baseAttr.MED = unmarshalAttrMED(b[p : p+int(l)])
+			baseAttr.MED = unmarshalAttrMED(b)
 		case 5:
-			baseAttr.LocalPref = unmarshalAttrLocalPref(b[p : p+int(l)])
+			baseAttr.LocalPref = unmarshalAttrLocalPref(b)
 		case 6:
 			baseAttr.IsAtomicAgg = true
 		case 7:
-			baseAttr.Aggregator = unmarshalAttrAggregator(b[p : p+int(l)])
+			baseAttr.Aggregator = unmarshalAttrAggregator(b)
 		case 8:
-			baseAttr.CommunityList = unmarshalAttrCommunity(b[p : p+int(l)])
+			baseAttr.CommunityList = unmarshalAttrCommunity(b)
 		case 9:
-			baseAttr.OriginatorID = unmarshalAttrOriginatorID(b[p : p+int(l)])
+			baseAttr.OriginatorID = unmarshalAttrOriginatorID(b)
 		case 10:
-			baseAttr.ClusterList = unmarshalAttrClusterList(b[p : p+int(l)])
+			baseAttr.ClusterList = unmarshalAttrClusterList(b)
 		case 16:
-			baseAttr.ExtCommunityList = unmarshalAttrExtCommunity(b[p : p+int(l)])
+			baseAttr.ExtCommunityList = unmarshalAttrExtCommunity(b)
 		case 17:
-			baseAttr.AS4Path = unmarshalAttrAS4Path(b[p : p+int(l)])
-			baseAttr.AS4PathCount = int32(len(baseAttr.AS4Path))
+			if as4Path, err = unmarshalAttrASPath(b, true); err != nil {
+				glog.Warningf("fail to unmarshal AS4_PATH with error: %+v", err)
+			}
+			baseAttr.AS4Path = as4Path.ASes()
+			baseAttr.AS4PathCount = int32(as4Path.Len())
 		case 18:
-			baseAttr.AS4Aggregator = unmarshalAttrAS4Aggregator(b[p : p+int(l)])
+			baseAttr.AS4Aggregator = unmarshalAttrAS4Aggregator(b)
+		case 14, 15, 29, 40:
+			// MP_REACH_NLRI, MP_UNREACH_NLRI, BGP-LS and Prefix SID attributes are processed with NLRI
 		case 22:
+			if baseAttr.PMSITunnel, err = unmarshalAttrPMSITunnel(b); err != nil {
+				glog.Warningf("fail to unmarshal PMSI Tunnel with error: %+v", err)
+				baseAttr.addUnknownAttribute(attr)
+			}
 		case 23:
-			baseAttr.TunnelEncapAttr = make([]byte, l)
-			copy(baseAttr.TunnelEncapAttr, b[p:p+int(l)])
-		case 24:
-		case 25:
+			baseAttr.TunnelEncapAttr = make([]byte, len(b))
+			copy(baseAttr.TunnelEncapAttr, b)
 		case 26:
-		case 27:
-		case 28:
-		case 29:
+			aigp, err := unmarshalAttrAIGP(b)
+			if err != nil {
+				glog.Warningf("fail to unmarshal AIGP with error: %+v", err)
+				baseAttr.addUnknownAttribute(attr)
+				break
+			}
+			baseAttr.AIGP = &aigp
 		case 32:
-			baseAttr.LgCommunityList = unmarshalAttrLgCommunity(b[p : p+int(l)])
+			baseAttr.LgCommunityList = unmarshalAttrLgCommunity(b)
 		case 33:
+			if baseAttr.BGPsecPath, err = unmarshalAttrBGPsecPath(b); err != nil {
+				glog.Warningf("fail to unmarshal BGPsec_PATH with error: %+v", err)
+				baseAttr.addUnknownAttribute(attr)
+			}
+		case 35:
+			if len(b) != 4 {
+				glog.Warningf("invalid length of OTC %d", len(b))
+				baseAttr.addUnknownAttribute(attr)
+				break
+			}
+			baseAttr.OTC = binary.BigEndian.Uint32(b)
 		case 128:
+			if baseAttr.AttrSet, err = unmarshalAttrSet(b); err != nil {
+				glog.Warningf("fail to unmarshal ATTR_SET with error: %+v", err)
+				baseAttr.addUnknownAttribute(attr)
+			}
+		default:
+			baseAttr.addUnknownAttribute(attr)
 		}
-		p += int(l)
 	}
+	baseAttr.reconcileAS4(asPath, as4Path, as4)
 	// Calculating hash of all recovered base attributes
 	ba, err := json.Marshal(baseAttr)
 	if err != nil {
@@ -121,91 +173,96 @@ func UnmarshalBGPBaseAttributes(b []byte) (*BaseAttributes, error) {
 	return &baseAttr, nil
 }
 
-// unmarshalAttrOrigin returns the value of Origin attribute
-func unmarshalAttrOrigin(b []byte) string {
-	switch b[0] {
-	case 0:
-		return "igp"
-	case 1:
-		return "egp"
-	case 2:
-		return "incomplete"
-	default:
-		return ""
+// addUnknownAttribute preserves the attribute which is not decoded, so it is not lost downstream
+func (ba *BaseAttributes) addUnknownAttribute(attr PathAttribute) {
+	ba.UnknownAttributes = append(ba.UnknownAttributes, &UnknownAttribute{
+		Type:  attr.AttributeType,
+		Flags: attr.AttributeTypeFlags,
+		Value: hex.EncodeToString(attr.Attribute),
+	})
+}
+
+// unmarshalAttrSet returns the value of ATTR_SET attribute, AS_PATH of the set always carries
+// 4 bytes AS numbers.
+func unmarshalAttrSet(b []byte) (*AttrSet, error) {
+	if len(b) < 4 {
+		return nil, fmt.Errorf("invalid length of ATTR_SET %d", len(b))
+	}
+	set := &AttrSet{
+		OriginAS: binary.BigEndian.Uint32(b[0:4]),
+	}
+	if len(b) == 4 {
+		return set, nil
 	}
+	attrs, err := UnmarshalBGPPathAttributes(b[4:])
+	if err != nil {
+		return nil, err
+	}
+	if set.Attributes, err = newBaseAttributes(attrs, true); err != nil {
+		return nil, err
+	}
+
+	return set, nil
 }
 
-// unmarshalAttrASPath returns a slice with a list of ASes
-func unmarshalAttrASPath(b []byte) []uint32 {
-	if len(b) == 0 {
-		return nil
+// reconcileAS4 populates AS path and aggregator, for sessions without 4 bytes AS capability
+// AS4_PATH and AS4_AGGREGATOR are taken into account per rfc6793 section 4.2.3.
+func (ba *BaseAttributes) reconcileAS4(path, as4path ASPath, as4 bool) {
+	if len(ba.Aggregator) != 0 {
+		as, addr, err := unmarshalAggregator(ba.Aggregator, as4)
+		if err != nil {
+			glog.Warningf("fail to unmarshal AGGREGATOR with error: %+v", err)
+		} else {
+			ba.AggregatorAS, ba.AggregatorAddr = as, addr
+		}
 	}
-	path := make([]uint32, 0)
-	as4 := isASPath4(b)
-	for p := 0; p < len(b); {
-		// Skipping type
-		p++
-		// Length of path segment of type
-		l := b[p]
-		p++
-		// Attempting to detect if 2 or 4 bytes AS is used
-		for n := 0; n < int(l); n++ {
-			if as4 {
-				as := binary.BigEndian.Uint32(b[p : p+4])
-				p += 4
-				path = append(path, as)
+	// AGGREGATOR with 2 bytes AS other than AS_TRANS means AS4_AGGREGATOR and AS4_PATH must be ignored
+	if !as4 && (len(ba.Aggregator) == 0 || ba.AggregatorAS == ASTrans) {
+		if ba.AggregatorAS == ASTrans && len(ba.AS4Aggregator) != 0 {
+			if as, addr, err := unmarshalAggregator(ba.AS4Aggregator, true); err != nil {
+				glog.Warningf("fail to unmarshal AS4_AGGREGATOR with error: %+v", err)
 			} else {
-				as := binary.BigEndian.Uint16(b[p : p+2])
-				p += 2
-				path = append(path, uint32(as))
+				ba.AggregatorAS, ba.AggregatorAddr = as, addr
 			}
 		}
+		path = mergeAS4Path(path, as4path)
 	}
-
-	return path
+	if len(path) == 0 {
+		return
+	}
+	ba.ASPathSegments = path
+	ba.ASPath = path.ASes()
+	ba.ASPathCount = int32(path.Len())
+	ba.ASPathString = path.String()
+	ba.OriginAS = path.OriginAS()
 }
 
-func isASPath4(b []byte) bool {
-	p := 0
-	// Skipping type
-	p++
-	// Length of path segment in 2 or 4 bytes depending if AS2 or AS4 is used.
-	l := int(b[p])
-	p++
-	// Check if next segment can be found with AS4
-	if l*4 == len(b[p:]) {
-		// Found last AS4 segment, confirmed AS4
-		return true
-	}
-	// Check if next segment can be found with AS4
-	if l*2 == len(b[p:]) {
-		// Found last AS2 segment, confirmed AS2
-		return false
-	}
-	// Check if next segment can be found with AS4
-	if p+l*4 < len(b) {
-		if b[p+l*4] == 0x1 || b[p+l*4] == 0x2 {
-			// Found next AS4 segment, confirmed AS4
-			return true
-		}
+// unmarshalAttrOrigin returns the value of Origin attribute
+func unmarshalAttrOrigin(b []byte) string {
+	if len(b) != 1 {
+		return ""
 	}
-	// Check if next segment can be found with AS2
-	if p+l*2 < len(b) {
-		if b[p+l*2] == 0x1 || b[p+l*2] == 0x2 {
-			// Found next AS2 segment, confirmed AS2
-			return false
-		}
+	switch b[0] {
+	case 0:
+		return "igp"
+	case 1:
+		return "egp"
+	case 2:
+		return "incomplete"
+	default:
+		return ""
 	}
-	// Should never reach here
-	return false
 }
 
 // unmarshalAttrNextHop returns the value of Next Hop attribute
 func unmarshalAttrNextHop(b []byte) string {
-	if len(b) == 4 {
+	switch len(b) {
+	case 4:
 		return net.IP(b).To4().String()
+	case 16:
+		return net.IP(b).To16().String()
 	}
-	return net.IP(b).To16().String()
+	return ""
 }
 
 // unmarshalAttrMED returns the value of MED attribute
@@ -235,7 +292,7 @@ func unmarshalAttrAggregator(b []byte) []byte {
 // getCommunity returns a slice of communities
 func getCommunity(b []byte) []uint32 {
 	comm := make([]uint32, 0)
-	for p := 0; p < len(b); {
+	for p := 0; p+4 <= len(b); {
 		c := binary.BigEndian.Uint32(b[p : p+4])
 		p += 4
 		comm = append(comm, c)
@@ -268,7 +325,7 @@ func unmarshalAttrOriginatorID(b []byte) string {
 func getClusterID(b []byte) [][]byte {
 	cl := make([][]byte, 0)
 	i := 0
-	for p := 0; p < len(b); {
+	for p := 0; p+4 <= len(b); {
 		c := make([]byte, 4)
 		copy(c, b[p:p+4])
 		p += 4
@@ -321,25 +378,6 @@ func unmarshalAttrLgCommunity(b []byte) []string {
 	return s
 }
 
-// unmarshalAttrAS4Path returns a sequence of AS4 path segments
-func unmarshalAttrAS4Path(b []byte) []uint32 {
-	path := make([]uint32, 0)
-	for p := 0; p < len(b); {
-		// Skipping type
-		p++
-		// Length of path segment in 4 bytes
-		l := b[p]
-		p++
-		for n := 0; n < int(l); n++ {
-			as := binary.BigEndian.Uint32(b[p : p+4])
-			p += 4
-			path = append(path, as)
-		}
-	}
-
-	return path
-}
-
 // getAttrAS4Aggregator returns the value of AS4 AGGREGATOR attribute
 func unmarshalAttrAS4Aggregator(b []byte) []byte {
 	agg := make([]byte, len(b))
@@ -17,12 +17,19 @@ func TestUnmarshaBaseAttributes(t *testing.T) {
 			name:  "panic 1",
 			input: []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x20, 0x02, 0x06, 0x00, 0x00, 0x88, 0x38, 0x00, 0x00, 0x9a, 0x6d, 0x00, 0x00, 0x19, 0x35, 0x00, 0x00, 0x0a, 0x7f, 0x00, 0x00, 0x65, 0x20, 0x00, 0x00, 0x53, 0x4e, 0x01, 0x01, 0x00, 0x00, 0x12, 0xc9, 0x40, 0x03, 0x04, 0xc2, 0x1c, 0x62, 0x25, 0x80, 0x04, 0x04, 0x00, 0x00, 0x00, 0x00, 0xc0, 0x07, 0x08, 0x00, 0x00, 0x65, 0x20, 0xc0, 0x78, 0x51, 0x88, 0xc0, 0x08, 0x18, 0x00, 0x00, 0x9a, 0x6d, 0x19, 0x35, 0x00, 0x56, 0x19, 0x35, 0x0b, 0xb8, 0x19, 0x35, 0x0c, 0x1c, 0x19, 0x35, 0x0c, 0x1e, 0x9a, 0x6d, 0xc2, 0x02, 0xc0, 0x20, 0x30, 0x00, 0x00, 0x88, 0x38, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x00, 0xd3, 0x00, 0x00, 0x88, 0x38, 0x00, 0x00, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x88, 0x38, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0x31, 0x00, 0x00, 0x88, 0x38, 0x00, 0x00, 0x00, 0x7a, 0x00, 0x00, 0x00, 0x01},
 			expect: &BaseAttributes{
-				BaseAttrHash:    "adfbe0fd919abd7a940eb75ed99af1e5",
-				Origin:          "igp",
-				ASPath:          []uint32{34872, 39533, 6453, 2687, 25888, 21326, 4809},
-				ASPathCount:     7,
+				BaseAttrHash: "a324c5e995a4539bbc1d60ae90e74a08",
+				Origin:       "igp",
+				ASPath:       []uint32{34872, 39533, 6453, 2687, 25888, 21326, 4809},
+				ASPathCount:  7,
+				ASPathSegments: ASPath{
+					{Type: "sequence", ASes: []uint32{34872, 39533, 6453, 2687, 25888, 21326}},
+					{Type: "set", ASes: []uint32{4809}},
+				},
+				ASPathString:    "34872 39533 6453 2687 25888 21326 {4809}",
 				Nexthop:         "194.28.98.37",
 				Aggregator:      []byte{0, 0, 101, 32, 192, 120, 81, 136},
+				AggregatorAS:    25888,
+				AggregatorAddr:  "192.120.81.136",
 				CommunityList:   []string{"0:39533", "6453:86", "6453:3000", "6453:3100", "6453:3102", "39533:49666"},
 				LgCommunityList: []string{"34872:10:211", "34872:11:1", "34872:100:49", "34872:122:1"},
 			},
@@ -30,7 +37,7 @@ func TestUnmarshaBaseAttributes(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := UnmarshalBGPBaseAttributes(tt.input)
+			got, err := UnmarshalBGPBaseAttributes(tt.input, true)
 			if err != nil {
 				t.Fatalf("expected to succeed but failed with error: %+v", err)
 			}
@@ -44,34 +51,182 @@ func TestUnmarshaBaseAttributes(t *testing.T) {
 	}
 }
 
+func TestUnmarshalOptionalAttributes(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		expect *BaseAttributes
+	}{
+		{
+			name: "pmsi tunnel ingress replication",
+			// EVPN IMET route's PMSI Tunnel with VNI 10100 and tunnel endpoint 10.0.0.1
+			input: []byte{0xc0, 0x16, 0x09, 0x00, 0x06, 0x00, 0x27, 0x74, 0x0a, 0x00, 0x00, 0x01},
+			expect: &BaseAttributes{
+				PMSITunnel: &PMSITunnel{TunnelType: "Ingress Replication", Label: 631, VNI: 10100, TunnelID: "10.0.0.1"},
+			},
+		},
+		{
+			name:  "pmsi tunnel mldp with leaf info",
+			input: []byte{0xc0, 0x16, 0x08, 0x01, 0x02, 0x00, 0x01, 0x01, 0x01, 0x02, 0x03},
+			expect: &BaseAttributes{
+				PMSITunnel: &PMSITunnel{LeafInfoRequired: true, TunnelType: "mLDP P2MP LSP", Label: 16, VNI: 257, TunnelID: "010203"},
+			},
+		},
+		{
+			name:  "pmsi tunnel mldp p2mp fec",
+			input: []byte{0xc0, 0x16, 0x13, 0x00, 0x02, 0x00, 0x00, 0x00, 0x06, 0x00, 0x01, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x04, 0x01, 0x00, 0x00, 0x01},
+			expect: &BaseAttributes{
+				PMSITunnel: &PMSITunnel{TunnelType: "mLDP P2MP LSP", TunnelID: "060001040a000001000401000001", RootNodeAddr: "10.0.0.1", OpaqueValue: "01000001"},
+			},
+		},
+		{
+			name:  "pmsi tunnel pim-ssm",
+			input: []byte{0xc0, 0x16, 0x0d, 0x00, 0x03, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x01, 0xe8, 0x01, 0x01, 0x01},
+			expect: &BaseAttributes{
+				PMSITunnel: &PMSITunnel{TunnelType: "PIM-SSM Tree", TunnelID: "0a000001e8010101", SenderAddr: "10.0.0.1", PGroup: "232.1.1.1"},
+			},
+		},
+		{
+			name:  "pmsi tunnel rsvp-te p2mp",
+			input: []byte{0xc0, 0x16, 0x11, 0x00, 0x01, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x64},
+			expect: &BaseAttributes{
+				PMSITunnel: &PMSITunnel{TunnelType: "RSVP-TE P2MP LSP", TunnelID: "0a0000010000000a00000064", ExtendedTunnelID: "10.0.0.1", RSVPTunnelID: 10, P2MPID: 100},
+			},
+		},
+		{
+			name:  "aigp",
+			input: []byte{0x80, 0x1a, 0x0b, 0x01, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0xe8},
+			expect: &BaseAttributes{
+				AIGP: func() *uint64 { v := uint64(1000); return &v }(),
+			},
+		},
+		{
+			name:  "aigp zero metric",
+			input: []byte{0x80, 0x1a, 0x0b, 0x01, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			expect: &BaseAttributes{
+				AIGP: func() *uint64 { v := uint64(0); return &v }(),
+			},
+		},
+		{
+			name:  "otc",
+			input: []byte{0xc0, 0x23, 0x04, 0x00, 0x00, 0xfd, 0xe8},
+			expect: &BaseAttributes{
+				OTC: 65000,
+			},
+		},
+		{
+			name:  "invalid otc",
+			input: []byte{0xc0, 0x23, 0x02, 0xfd, 0xe8},
+			expect: &BaseAttributes{
+				UnknownAttributes: []*UnknownAttribute{{Type: 35, Flags: 0xc0, Value: "fde8"}},
+			},
+		},
+		{
+			name: "attr set",
+			// Origin AS 65001 with ORIGIN igp and LOCAL_PREF 200 of the customer network
+			input: []byte{0xc0, 0x80, 0x0f, 0x00, 0x00, 0xfd, 0xe9, 0x40, 0x01, 0x01, 0x00, 0x40, 0x05, 0x04, 0x00, 0x00, 0x00, 0xc8},
+			expect: &BaseAttributes{
+				AttrSet: &AttrSet{
+					OriginAS: 65001,
+					Attributes: &BaseAttributes{
+						Origin:    "igp",
+						LocalPref: 200,
+					},
+				},
+			},
+		},
+		{
+			name: "bgpsec path",
+			input: []byte{0x90, 0x21, 0x00, 0x2b,
+				// Secure_Path with 2 segments
+				0x00, 0x0e, 0x01, 0x00, 0x00, 0x00, 0xfd, 0xe9, 0x02, 0x80, 0x00, 0x00, 0xfd, 0xea,
+				// Signature_Block with 1 signature
+				0x00, 0x1d, 0x01,
+				0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14,
+				0x00, 0x04, 0xde, 0xad, 0xbe, 0xef,
+			},
+			expect: &BaseAttributes{
+				BGPsecPath: &BGPsecPath{
+					SecurePath: []*SecurePathSegment{
+						{PCount: 1, AS: 65001},
+						{PCount: 2, ConfedSegment: true, AS: 65002},
+					},
+					SignatureBlocks: []*SignatureBlock{
+						{
+							AlgorithmSuiteID: 1,
+							Signatures: []*SignatureSegment{
+								{SKI: "0102030405060708090a0b0c0d0e0f1011121314", Signature: "deadbeef"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unknown attributes",
+			// PE Distinguisher Labels and unassigned attribute 250 with extended length
+			input: []byte{0xc0, 0x1b, 0x07, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x06, 0x41, 0xd0, 0xfa, 0x00, 0x02, 0xca, 0xfe},
+			expect: &BaseAttributes{
+				UnknownAttributes: []*UnknownAttribute{
+					{Type: 27, Flags: 0xc0, Value: "0a000001000641"},
+					{Type: 250, Flags: 0xd0, Value: "cafe"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalBGPBaseAttributes(tt.input, true)
+			if err != nil {
+				t.Fatalf("test failed with error: %+v", err)
+			}
+			// Hash is validated by TestUnmarshaBaseAttributes
+			got.BaseAttrHash = ""
+			if got.AttrSet != nil && got.AttrSet.Attributes != nil {
+				got.AttrSet.Attributes.BaseAttrHash = ""
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Logf("Differences: %+v", deep.Equal(tt.expect, got))
+				t.Fatalf("test failed as expected attributes %+v do not match actual attributes %+v", tt.expect, got)
+			}
+		})
+	}
+}
+
 func TestUnmarshalASPath(t *testing.T) {
 	tests := []struct {
 		name   string
 		input  []byte
+		as4    bool
 		asPath []uint32
 	}{
 		{
 			name:   "panic #1",
+			as4:    true,
 			input:  []byte{0x02, 0x08, 0x00, 0x00, 0x24, 0x58, 0x00, 0x00, 0x92, 0x5c, 0x00, 0x00, 0xf1, 0x88, 0x00, 0x04, 0x03, 0xb8, 0x00, 0x00, 0x6e, 0xd0, 0x00, 0x04, 0x03, 0xb8, 0x00, 0x00, 0x6e, 0xd0, 0x00, 0x04, 0x03, 0xb8},
 			asPath: []uint32{9304, 37468, 61832, 263096, 28368, 263096, 28368, 263096},
 		},
 		{
 			name:   "panic #2",
+			as4:    true,
 			input:  []byte{0x02, 0x48, 0x00, 0x00, 0xce, 0x89, 0x00, 0x00, 0x32, 0x9c, 0x00, 0x00, 0xf0, 0x1c, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0x14},
 			asPath: []uint32{52873, 12956, 61468, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 269844},
 		},
 		{
 			name:   "panic #3",
+			as4:    true,
 			input:  []byte{0x02, 0xa2, 0x00, 0x00, 0xbe, 0xb5, 0x00, 0x03, 0x21, 0x38, 0x00, 0x00, 0xc5, 0xc5, 0x00, 0x00, 0x00, 0xae, 0x00, 0x00, 0x6c, 0x66, 0x00, 0x00, 0xf0, 0x1c, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0xfa, 0x00, 0x04, 0x1e, 0x14},
 			asPath: []uint32{48821, 205112, 50629, 174, 27750, 61468, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 270074, 269844},
 		},
 		{
 			name:   "panic #4",
+			as4:    true,
 			input:  []byte{0x02, 0x06, 0x00, 0x00, 0x88, 0x38, 0x00, 0x00, 0x9a, 0x6d, 0x00, 0x00, 0x19, 0x35, 0x00, 0x00, 0x0a, 0x7f, 0x00, 0x00, 0x65, 0x20, 0x00, 0x00, 0x53, 0x4e, 0x01, 0x01, 0x00, 0x00, 0x12, 0xc9},
 			asPath: []uint32{34872, 39533, 6453, 2687, 25888, 21326, 4809},
 		},
 		{
 			name:   "1 AS4 segment",
+			as4:    true,
 			input:  []byte{0x02, 0x01, 0x00, 0x00, 0x88, 0x38},
 			asPath: []uint32{34872},
 		},
@@ -82,6 +237,7 @@ func TestUnmarshalASPath(t *testing.T) {
 		},
 		{
 			name:   "2 AS4 segments",
+			as4:    true,
 			input:  []byte{0x02, 0x01, 0x00, 0x00, 0x88, 0x38, 0x01, 0x01, 0x00, 0x00, 0x88, 0x38},
 			asPath: []uint32{34872, 34872},
 		},
@@ -97,9 +253,12 @@ func TestUnmarshalASPath(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		r := unmarshalAttrASPath(tt.input)
-		if !reflect.DeepEqual(tt.asPath, r) {
-			t.Fatalf("expected %+v and result %+v as path do not match", tt.asPath, r)
+		r, err := unmarshalAttrASPath(tt.input, tt.as4)
+		if err != nil {
+			t.Fatalf("%s: fail to unmarshal as path with error: %+v", tt.name, err)
+		}
+		if !reflect.DeepEqual(tt.asPath, r.ASes()) {
+			t.Fatalf("expected %+v and result %+v as path do not match", tt.asPath, r.ASes())
 		}
 	}
 }
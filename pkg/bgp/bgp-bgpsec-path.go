@@ -0,0 +1,94 @@
+package bgp
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// BGPsecPath defines BGPsec_PATH attribute per rfc8205 section 3
+type BGPsecPath struct {
+	SecurePath      []*SecurePathSegment `json:"secure_path"`
+	SignatureBlocks []*SignatureBlock    `json:"signature_blocks,omitempty"`
+}
+
+// SecurePathSegment defines a segment of Secure_Path
+type SecurePathSegment struct {
+	PCount        uint8  `json:"pcount"`
+	ConfedSegment bool   `json:"confed_segment"`
+	AS            uint32 `json:"as"`
+}
+
+// SignatureBlock defines Signature_Block of BGPsec_PATH, a block per algorithm suite
+type SignatureBlock struct {
+	AlgorithmSuiteID uint8               `json:"algorithm_suite_id"`
+	Signatures       []*SignatureSegment `json:"signatures"`
+}
+
+// SignatureSegment defines Subject Key Identifier and the signature of a single AS, both hex encoded
+type SignatureSegment struct {
+	SKI       string `json:"ski"`
+	Signature string `json:"signature"`
+}
+
+func unmarshalAttrBGPsecPath(b []byte) (*BGPsecPath, error) {
+	if len(b) < 2 {
+		return nil, fmt.Errorf("invalid BGPsec_PATH length %d", len(b))
+	}
+	// Secure_Path length includes the length field and each segment is 6 bytes
+	l := int(binary.BigEndian.Uint16(b[0:2]))
+	if l < 2 || (l-2)%6 != 0 || l > len(b) {
+		return nil, fmt.Errorf("invalid Secure_Path length %d", l)
+	}
+	path := &BGPsecPath{
+		SecurePath: make([]*SecurePathSegment, 0, (l-2)/6),
+	}
+	for p := 2; p < l; p += 6 {
+		path.SecurePath = append(path.SecurePath, &SecurePathSegment{
+			PCount:        b[p],
+			ConfedSegment: b[p+1]&0x80 == 0x80,
+			AS:            binary.BigEndian.Uint32(b[p+2 : p+6]),
+		})
+	}
+	for p := l; p < len(b); {
+		if p+3 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal Signature_Block")
+		}
+		bl := int(binary.BigEndian.Uint16(b[p : p+2]))
+		if bl < 3 || p+bl > len(b) {
+			return nil, fmt.Errorf("invalid Signature_Block length %d", bl)
+		}
+		sb, err := unmarshalSignatureBlock(b[p+2 : p+bl])
+		if err != nil {
+			return nil, err
+		}
+		path.SignatureBlocks = append(path.SignatureBlocks, sb)
+		p += bl
+	}
+
+	return path, nil
+}
+
+func unmarshalSignatureBlock(b []byte) (*SignatureBlock, error) {
+	sb := &SignatureBlock{
+		AlgorithmSuiteID: b[0],
+		Signatures:       make([]*SignatureSegment, 0),
+	}
+	for p := 1; p < len(b); {
+		// Subject Key Identifier is 20 bytes followed by 2 bytes of Signature length
+		if p+22 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal Signature Segment")
+		}
+		sl := int(binary.BigEndian.Uint16(b[p+20 : p+22]))
+		if p+22+sl > len(b) {
+			return nil, fmt.Errorf("invalid Signature length %d", sl)
+		}
+		sb.Signatures = append(sb.Signatures, &SignatureSegment{
+			SKI:       hex.EncodeToString(b[p : p+20]),
+			Signature: hex.EncodeToString(b[p+22 : p+22+sl]),
+		})
+		p += 22 + sl
+	}
+
+	return sb, nil
+}
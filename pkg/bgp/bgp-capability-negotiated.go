@@ -0,0 +1,204 @@
+package bgp
+
+// NegotiatedRole defines BGP Roles of both sides of the session per rfc9234
+type NegotiatedRole struct {
+	Local  string `json:"local"`
+	Remote string `json:"remote"`
+	// Match is true when the pair of roles is valid
+	Match bool `json:"match"`
+}
+
+// NegotiatedCapabilities defines capabilities in effect for a session, computed from capabilities
+// sent by the local speaker and capabilities received from the peer. Directional capabilities,
+// such as ADD-PATH, are expressed from the local speaker's point of view.
+type NegotiatedCapabilities struct {
+	Multiprotocol        []*AFISAFI              `json:"multiprotocol,omitempty"`
+	RouteRefresh         bool                    `json:"route_refresh"`
+	EnhancedRouteRefresh bool                    `json:"enhanced_route_refresh"`
+	ExtendedMessage      bool                    `json:"extended_message"`
+	AS4                  bool                    `json:"as4"`
+	GracefulRestart      bool                    `json:"graceful_restart"`
+	LLGR                 bool                    `json:"llgr"`
+	AddPath              []*AddPathTuple         `json:"add_path,omitempty"`
+	ExtendedNextHop      []*ExtendedNextHopTuple `json:"extended_nexthop,omitempty"`
+	MultipleLabels       []*MultipleLabelsTuple  `json:"multiple_labels,omitempty"`
+	Role                 *NegotiatedRole         `json:"role,omitempty"`
+}
+
+// validRoles lists valid pairs of local and remote BGP Roles per rfc9234
+var validRoles = map[[2]uint8]bool{
+	{0, 3}: true,
+	{3, 0}: true,
+	{1, 2}: true,
+	{2, 1}: true,
+	{4, 4}: true,
+}
+
+func (c Capability) has(code uint8) bool {
+	_, ok := c[code]
+	return ok
+}
+
+// multiprotocol returns AFI/SAFIs of Multiprotocol capabilities, when none is advertised,
+// IPv4 Unicast is implied.
+func (c Capability) multiprotocol() []*AFISAFI {
+	mp := make([]*AFISAFI, 0)
+	for _, d := range c[1] {
+		if d.Multiprotocol != nil {
+			mp = append(mp, d.Multiprotocol)
+		}
+	}
+	if len(mp) == 0 {
+		mp = append(mp, &AFISAFI{AFI: 1, SAFI: 1})
+	}
+
+	return mp
+}
+
+func (c Capability) addPath() map[AFISAFI]*AddPathTuple {
+	m := make(map[AFISAFI]*AddPathTuple)
+	for _, d := range c[69] {
+		for _, t := range d.AddPath {
+			m[t.AFISAFI] = t
+		}
+	}
+
+	return m
+}
+
+func (c Capability) extendedNextHop() map[ExtendedNextHopTuple]bool {
+	m := make(map[ExtendedNextHopTuple]bool)
+	for _, d := range c[5] {
+		for _, t := range d.ExtendedNextHop {
+			m[*t] = true
+		}
+	}
+
+	return m
+}
+
+func (c Capability) multipleLabels() map[AFISAFI]uint8 {
+	m := make(map[AFISAFI]uint8)
+	for _, d := range c[8] {
+		for _, t := range d.MultipleLabels {
+			m[t.AFISAFI] = t.Count
+		}
+	}
+
+	return m
+}
+
+func (c Capability) role() (uint8, bool) {
+	for _, d := range c[9] {
+		if len(d.Value) == 1 {
+			return d.Value[0], true
+		}
+	}
+
+	return 0, false
+}
+
+func roleName(r uint8) string {
+	if n, ok := BGPRoles[r]; ok {
+		return n
+	}
+
+	return "Unknown"
+}
+
+// NegotiateCapabilities computes capabilities in effect for a session from capabilities of sent and received Open messages
+func NegotiateCapabilities(sent, received Capability) *NegotiatedCapabilities {
+	n := &NegotiatedCapabilities{
+		Multiprotocol:        make([]*AFISAFI, 0),
+		RouteRefresh:         (sent.has(2) || sent.has(128)) && (received.has(2) || received.has(128)),
+		EnhancedRouteRefresh: sent.has(70) && received.has(70),
+		ExtendedMessage:      sent.has(6) && received.has(6),
+		AS4:                  sent.has(65) && received.has(65),
+		GracefulRestart:      sent.has(64) && received.has(64),
+		LLGR:                 sent.has(71) && received.has(71),
+		AddPath:              make([]*AddPathTuple, 0),
+		ExtendedNextHop:      make([]*ExtendedNextHopTuple, 0),
+		MultipleLabels:       make([]*MultipleLabelsTuple, 0),
+	}
+	rmp := make(map[AFISAFI]bool)
+	for _, mp := range received.multiprotocol() {
+		rmp[*mp] = true
+	}
+	for _, mp := range sent.multiprotocol() {
+		if rmp[*mp] {
+			n.Multiprotocol = append(n.Multiprotocol, mp)
+		}
+	}
+	rap := received.addPath()
+	for _, d := range sent[69] {
+		for _, l := range d.AddPath {
+			r, ok := rap[l.AFISAFI]
+			if !ok {
+				continue
+			}
+			t := &AddPathTuple{
+				AFISAFI: l.AFISAFI,
+				Send:    l.Send && r.Receive,
+				Receive: l.Receive && r.Send,
+			}
+			if t.Send || t.Receive {
+				n.AddPath = append(n.AddPath, t)
+			}
+		}
+	}
+	renh := received.extendedNextHop()
+	for _, d := range sent[5] {
+		for _, t := range d.ExtendedNextHop {
+			if renh[*t] {
+				n.ExtendedNextHop = append(n.ExtendedNextHop, t)
+			}
+		}
+	}
+	rml := received.multipleLabels()
+	for _, d := range sent[8] {
+		for _, t := range d.MultipleLabels {
+			rc, ok := rml[t.AFISAFI]
+			if !ok {
+				continue
+			}
+			c := t.Count
+			if rc < c {
+				c = rc
+			}
+			n.MultipleLabels = append(n.MultipleLabels, &MultipleLabelsTuple{AFISAFI: t.AFISAFI, Count: c})
+		}
+	}
+	lr, lok := sent.role()
+	rr, rok := received.role()
+	if lok && rok {
+		n.Role = &NegotiatedRole{
+			Local:  roleName(lr),
+			Remote: roleName(rr),
+			Match:  validRoles[[2]uint8{lr, rr}],
+		}
+	}
+
+	return n
+}
+
+// HasExtendedNextHop returns true when Extended Next Hop capability is negotiated for NLRI of afi/safi
+// with next hop of nhAFI per rfc8950
+func (n *NegotiatedCapabilities) HasExtendedNextHop(afi uint16, safi uint8, nhAFI uint16) bool {
+	for _, t := range n.ExtendedNextHop {
+		if t.NLRIAFI == afi && t.NLRISAFI == uint16(safi) && t.NextHopAFI == nhAFI {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MaxMessageLength returns the maximum length of BGP message of the session, messages up to 65535 bytes
+// are allowed when Extended Message capability is negotiated per rfc8654.
+func (n *NegotiatedCapabilities) MaxMessageLength() int {
+	if n.ExtendedMessage {
+		return BGPMaxExtendedMessageLength
+	}
+
+	return BGPMaxMessageLength
+}
@@ -0,0 +1,174 @@
+package bgp
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// AFISAFI defines a pair of Address Family Identifier and Subsequent Address Family Identifier
+type AFISAFI struct {
+	AFI  uint16 `json:"afi"`
+	SAFI uint8  `json:"safi"`
+}
+
+// GracefulRestartCapability defines Graceful Restart Capability per rfc4724 and rfc8538
+type GracefulRestartCapability struct {
+	RestartState bool                    `json:"restart_state"`
+	Notification bool                    `json:"notification"`
+	RestartTime  uint16                  `json:"restart_time"`
+	AFISAFI      []*GracefulRestartTuple `json:"afi_safi,omitempty"`
+}
+
+// GracefulRestartTuple defines per AFI/SAFI flags of Graceful Restart Capability
+type GracefulRestartTuple struct {
+	AFISAFI
+	ForwardingState bool `json:"forwarding_state"`
+}
+
+// LLGRTuple defines per AFI/SAFI parameters of Long-Lived Graceful Restart Capability
+type LLGRTuple struct {
+	AFISAFI
+	ForwardingState bool   `json:"forwarding_state"`
+	StaleTime       uint32 `json:"stale_time"`
+}
+
+// AddPathTuple defines per AFI/SAFI Send/Receive mode of ADD-PATH Capability per rfc7911
+type AddPathTuple struct {
+	AFISAFI
+	Send    bool `json:"send"`
+	Receive bool `json:"receive"`
+}
+
+// ExtendedNextHopTuple defines NLRI AFI/SAFI and Next Hop AFI of Extended Next Hop Encoding Capability per rfc8950
+type ExtendedNextHopTuple struct {
+	NLRIAFI    uint16 `json:"nlri_afi"`
+	NLRISAFI   uint16 `json:"nlri_safi"`
+	NextHopAFI uint16 `json:"nexthop_afi"`
+}
+
+// FQDNCapability defines FQDN Capability per draft-walton-bgp-hostname-capability
+type FQDNCapability struct {
+	Hostname   string `json:"hostname,omitempty"`
+	DomainName string `json:"domain_name,omitempty"`
+}
+
+// MultipleLabelsTuple defines per AFI/SAFI count of labels of Multiple Labels Capability per rfc8277
+type MultipleLabelsTuple struct {
+	AFISAFI
+	Count uint8 `json:"count"`
+}
+
+// BGPRoles defines names of BGP Roles per rfc9234
+var BGPRoles = map[uint8]string{
+	0: "Provider",
+	1: "RS",
+	2: "RS-Client",
+	3: "Customer",
+	4: "Peer",
+}
+
+// decode populates the decoded value of known capabilities, raw value is always preserved
+func (c *capabilityData) decode(code uint8) error {
+	b := c.Value
+	switch code {
+	case 1:
+		// According RFC https://tools.ietf.org/html/rfc2858#section-7 Length will always be 4 bytes.
+		if len(b) != 4 {
+			return fmt.Errorf("invalid length %d", len(b))
+		}
+		c.Multiprotocol = &AFISAFI{
+			AFI:  binary.BigEndian.Uint16(b[:2]),
+			SAFI: b[3],
+		}
+		c.Description += getAFISAFIString(c.Multiprotocol.AFI, c.Multiprotocol.SAFI)
+	case 5:
+		if len(b)%6 != 0 {
+			return fmt.Errorf("invalid length %d", len(b))
+		}
+		c.ExtendedNextHop = make([]*ExtendedNextHopTuple, 0)
+		for p := 0; p < len(b); p += 6 {
+			c.ExtendedNextHop = append(c.ExtendedNextHop, &ExtendedNextHopTuple{
+				NLRIAFI:    binary.BigEndian.Uint16(b[p : p+2]),
+				NLRISAFI:   binary.BigEndian.Uint16(b[p+2 : p+4]),
+				NextHopAFI: binary.BigEndian.Uint16(b[p+4 : p+6]),
+			})
+		}
+	case 8:
+		if len(b)%4 != 0 {
+			return fmt.Errorf("invalid length %d", len(b))
+		}
+		c.MultipleLabels = make([]*MultipleLabelsTuple, 0)
+		for p := 0; p < len(b); p += 4 {
+			c.MultipleLabels = append(c.MultipleLabels, &MultipleLabelsTuple{
+				AFISAFI: AFISAFI{AFI: binary.BigEndian.Uint16(b[p : p+2]), SAFI: b[p+2]},
+				Count:   b[p+3],
+			})
+		}
+	case 9:
+		if len(b) != 1 {
+			return fmt.Errorf("invalid length %d", len(b))
+		}
+		r, ok := BGPRoles[b[0]]
+		if !ok {
+			r = fmt.Sprintf("Unknown role %d", b[0])
+		}
+		c.Role = r
+	case 64:
+		if len(b) < 2 || (len(b)-2)%4 != 0 {
+			return fmt.Errorf("invalid length %d", len(b))
+		}
+		gr := &GracefulRestartCapability{
+			RestartState: b[0]&0x80 == 0x80,
+			Notification: b[0]&0x40 == 0x40,
+			RestartTime:  binary.BigEndian.Uint16(b[0:2]) & 0x0fff,
+			AFISAFI:      make([]*GracefulRestartTuple, 0),
+		}
+		for p := 2; p < len(b); p += 4 {
+			gr.AFISAFI = append(gr.AFISAFI, &GracefulRestartTuple{
+				AFISAFI:         AFISAFI{AFI: binary.BigEndian.Uint16(b[p : p+2]), SAFI: b[p+2]},
+				ForwardingState: b[p+3]&0x80 == 0x80,
+			})
+		}
+		c.GracefulRestart = gr
+	case 65:
+		if len(b) != 4 {
+			return fmt.Errorf("invalid length %d", len(b))
+		}
+		c.AS4 = binary.BigEndian.Uint32(b)
+	case 69:
+		if len(b)%4 != 0 {
+			return fmt.Errorf("invalid length %d", len(b))
+		}
+		c.AddPath = make([]*AddPathTuple, 0)
+		for p := 0; p < len(b); p += 4 {
+			c.AddPath = append(c.AddPath, &AddPathTuple{
+				AFISAFI: AFISAFI{AFI: binary.BigEndian.Uint16(b[p : p+2]), SAFI: b[p+2]},
+				Receive: b[p+3]&0x1 == 0x1,
+				Send:    b[p+3]&0x2 == 0x2,
+			})
+		}
+	case 71:
+		if len(b)%7 != 0 {
+			return fmt.Errorf("invalid length %d", len(b))
+		}
+		c.LLGR = make([]*LLGRTuple, 0)
+		for p := 0; p < len(b); p += 7 {
+			c.LLGR = append(c.LLGR, &LLGRTuple{
+				AFISAFI:         AFISAFI{AFI: binary.BigEndian.Uint16(b[p : p+2]), SAFI: b[p+2]},
+				ForwardingState: b[p+3]&0x80 == 0x80,
+				StaleTime:       uint32(b[p+4])<<16 | uint32(b[p+5])<<8 | uint32(b[p+6]),
+			})
+		}
+	case 73:
+		if len(b) < 1 || len(b) < 1+int(b[0])+1 || len(b) != 2+int(b[0])+int(b[1+int(b[0])]) {
+			return fmt.Errorf("invalid length %d", len(b))
+		}
+		hl := int(b[0])
+		c.FQDN = &FQDNCapability{
+			Hostname:   string(b[1 : 1+hl]),
+			DomainName: string(b[2+hl:]),
+		}
+	}
+
+	return nil
+}
@@ -1,7 +1,7 @@
 package bgp
 
 import (
-	"encoding/binary"
+	"fmt"
 	"strconv"
 
 	"github.com/golang/glog"
@@ -40,6 +40,16 @@ var BGPCapabilities = map[uint8]string{
 type capabilityData struct {
 	Value       []byte `json:"capability_value,omitempty"`
 	Description string `json:"capability_descr,omitempty"`
+	// Decoded value of known capabilities, only the field matching the capability code is set
+	Multiprotocol   *AFISAFI                   `json:"multiprotocol,omitempty"`
+	GracefulRestart *GracefulRestartCapability `json:"graceful_restart,omitempty"`
+	LLGR            []*LLGRTuple               `json:"llgr,omitempty"`
+	AddPath         []*AddPathTuple            `json:"add_path,omitempty"`
+	ExtendedNextHop []*ExtendedNextHopTuple    `json:"extended_nexthop,omitempty"`
+	AS4             uint32                     `json:"as4,omitempty"`
+	FQDN            *FQDNCapability            `json:"fqdn,omitempty"`
+	Role            string                     `json:"role,omitempty"`
+	MultipleLabels  []*MultipleLabelsTuple     `json:"multiple_labels,omitempty"`
 }
 
 // Capability Defines a structure for BGP Capability TLV which is sent as a part
@@ -85,10 +95,16 @@ func UnmarshalBGPCapability(b []byte) (Capability, error) {
 	}
 	caps := make(Capability, 0)
 	for p := 0; p < len(b); {
+		if p+2 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal BGP Capability")
+		}
 		code := b[p]
 		p++
 		length := b[p]
 		p++
+		if p+int(length) > len(b) {
+			return nil, fmt.Errorf("invalid length %d of BGP Capability %d", length, code)
+		}
 		capData := &capabilityData{}
 		capData.Value = make([]byte, length)
 		copy(capData.Value, b[p:p+int(length)])
@@ -97,12 +113,8 @@ func UnmarshalBGPCapability(b []byte) (Capability, error) {
 		if !ok {
 			capData.Description = "Unknown capability " + strconv.Itoa(int(code))
 		}
-		switch code {
-		case 1:
-			// According RFC https://tools.ietf.org/html/rfc2858#section-7 Length will always be 4 bytes.
-			afi := binary.BigEndian.Uint16(capData.Value[:2])
-			safi := capData.Value[3]
-			capData.Description += getAFISAFIString(afi, safi)
+		if err := capData.decode(code); err != nil {
+			glog.Warningf("fail to decode BGP Capability %d with error: %+v", code, err)
 		}
 		c, ok := caps[code]
 		if !ok {
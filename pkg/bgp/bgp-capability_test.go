@@ -0,0 +1,195 @@
+package bgp
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestUnmarshalBGPCapability(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		expect Capability
+		fail   bool
+	}{
+		{
+			name:  "graceful restart",
+			input: []byte{64, 10, 0xc0, 0x78, 0, 1, 1, 0x80, 0, 2, 1, 0},
+			expect: Capability{
+				64: []*capabilityData{
+					{
+						Value:       []byte{0xc0, 0x78, 0, 1, 1, 0x80, 0, 2, 1, 0},
+						Description: "Graceful Restart Capability",
+						GracefulRestart: &GracefulRestartCapability{
+							RestartState: true,
+							Notification: true,
+							RestartTime:  120,
+							AFISAFI: []*GracefulRestartTuple{
+								{AFISAFI: AFISAFI{AFI: 1, SAFI: 1}, ForwardingState: true},
+								{AFISAFI: AFISAFI{AFI: 2, SAFI: 1}, ForwardingState: false},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "long-lived graceful restart",
+			input: []byte{71, 7, 0, 1, 1, 0x80, 0, 0x0e, 0x10},
+			expect: Capability{
+				71: []*capabilityData{
+					{
+						Value:       []byte{0, 1, 1, 0x80, 0, 0x0e, 0x10},
+						Description: "Long-Lived Graceful Restart (LLGR) Capability",
+						LLGR: []*LLGRTuple{
+							{AFISAFI: AFISAFI{AFI: 1, SAFI: 1}, ForwardingState: true, StaleTime: 3600},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "add-path",
+			input: []byte{69, 8, 0, 1, 1, 1, 0, 2, 1, 3},
+			expect: Capability{
+				69: []*capabilityData{
+					{
+						Value:       []byte{0, 1, 1, 1, 0, 2, 1, 3},
+						Description: "ADD-PATH Capability",
+						AddPath: []*AddPathTuple{
+							{AFISAFI: AFISAFI{AFI: 1, SAFI: 1}, Receive: true},
+							{AFISAFI: AFISAFI{AFI: 2, SAFI: 1}, Send: true, Receive: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "route refresh, enhanced route refresh and extended message",
+			input: []byte{2, 0, 70, 0, 6, 0},
+			expect: Capability{
+				2:  []*capabilityData{{Value: []byte{}, Description: "Route Refresh Capability for BGP-4"}},
+				70: []*capabilityData{{Value: []byte{}, Description: "Enhanced Route Refresh Capability"}},
+				6:  []*capabilityData{{Value: []byte{}, Description: "BGP Extended Message"}},
+			},
+		},
+		{
+			name:  "fqdn",
+			input: []byte{73, 13, 3, 'r', 't', 'r', 8, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 's'},
+			expect: Capability{
+				73: []*capabilityData{
+					{
+						Value:       []byte{3, 'r', 't', 'r', 8, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 's'},
+						Description: "FQDN Capability",
+						FQDN:        &FQDNCapability{Hostname: "rtr", DomainName: "examples"},
+					},
+				},
+			},
+		},
+		{
+			name:  "bgp role and multiple labels",
+			input: []byte{9, 1, 3, 8, 4, 0, 1, 4, 2},
+			expect: Capability{
+				9: []*capabilityData{
+					{Value: []byte{3}, Description: "BGP Role (TEMPORARY)", Role: "Customer"},
+				},
+				8: []*capabilityData{
+					{
+						Value:          []byte{0, 1, 4, 2},
+						Description:    "Multiple Labels Capability",
+						MultipleLabels: []*MultipleLabelsTuple{{AFISAFI: AFISAFI{AFI: 1, SAFI: 4}, Count: 2}},
+					},
+				},
+			},
+		},
+		{
+			name:  "malformed value keeps raw bytes",
+			input: []byte{65, 2, 0, 1},
+			expect: Capability{
+				65: []*capabilityData{
+					{Value: []byte{0, 1}, Description: "Support for 4-octet AS number capability"},
+				},
+			},
+		},
+		{
+			name:  "length exceeds the buffer",
+			input: []byte{65, 4, 0, 1},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caps, err := UnmarshalBGPCapability(tt.input)
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("supposed to succeed but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if diff := deep.Equal(tt.expect, caps); diff != nil {
+				t.Errorf("Diffs: %+v", diff)
+			}
+		})
+	}
+}
+
+func TestNegotiateCapabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		sent     []byte
+		received []byte
+		expect   *NegotiatedCapabilities
+	}{
+		{
+			name: "intersection",
+			// MP IPv4 and IPv6 unicast, route refresh, 4-byte AS, ADD-PATH IPv4 receive and IPv6 both,
+			// extended next hop IPv4 over IPv6, multiple labels IPv4 LU 3, role Provider
+			sent: []byte{1, 4, 0, 1, 0, 1, 1, 4, 0, 2, 0, 1, 2, 0, 65, 4, 0, 0, 0xfd, 0xe8,
+				69, 8, 0, 1, 1, 1, 0, 2, 1, 3, 5, 6, 0, 1, 0, 1, 0, 2, 8, 4, 0, 1, 4, 3, 9, 1, 0},
+			// MP IPv4 unicast, prestandard route refresh, 4-byte AS, ADD-PATH IPv4 send, extended message,
+			// extended next hop IPv4 over IPv6, multiple labels IPv4 LU 2, role Customer
+			received: []byte{1, 4, 0, 1, 0, 1, 128, 0, 65, 4, 0, 0, 0xfd, 0xe9,
+				69, 4, 0, 1, 1, 2, 6, 0, 5, 6, 0, 1, 0, 1, 0, 2, 8, 4, 0, 1, 4, 2, 9, 1, 3},
+			expect: &NegotiatedCapabilities{
+				Multiprotocol:   []*AFISAFI{{AFI: 1, SAFI: 1}},
+				RouteRefresh:    true,
+				AS4:             true,
+				AddPath:         []*AddPathTuple{{AFISAFI: AFISAFI{AFI: 1, SAFI: 1}, Receive: true}},
+				ExtendedNextHop: []*ExtendedNextHopTuple{{NLRIAFI: 1, NLRISAFI: 1, NextHopAFI: 2}},
+				MultipleLabels:  []*MultipleLabelsTuple{{AFISAFI: AFISAFI{AFI: 1, SAFI: 4}, Count: 2}},
+				Role:            &NegotiatedRole{Local: "Provider", Remote: "Customer", Match: true},
+			},
+		},
+		{
+			name:     "implied ipv4 unicast and role mismatch",
+			sent:     []byte{9, 1, 4},
+			received: []byte{9, 1, 0},
+			expect: &NegotiatedCapabilities{
+				Multiprotocol:   []*AFISAFI{{AFI: 1, SAFI: 1}},
+				AddPath:         []*AddPathTuple{},
+				ExtendedNextHop: []*ExtendedNextHopTuple{},
+				MultipleLabels:  []*MultipleLabelsTuple{},
+				Role:            &NegotiatedRole{Local: "Peer", Remote: "Provider", Match: false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent, err := UnmarshalBGPCapability(tt.sent)
+			if err != nil {
+				t.Fatalf("fail to unmarshal sent capabilities with error: %+v", err)
+			}
+			received, err := UnmarshalBGPCapability(tt.received)
+			if err != nil {
+				t.Fatalf("fail to unmarshal received capabilities with error: %+v", err)
+			}
+			if diff := deep.Equal(tt.expect, NegotiateCapabilities(sent, received)); diff != nil {
+				t.Errorf("Diffs: %+v", diff)
+			}
+		})
+	}
+}
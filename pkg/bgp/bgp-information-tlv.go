@@ -1,6 +1,8 @@
 package bgp
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
@@ -20,10 +22,16 @@ func UnmarshalBGPTLV(b []byte) ([]InformationalTLV, Capability, error) {
 	tlvs := make([]InformationalTLV, 0)
 	caps := make(Capability, 0)
 	for p := 0; p < len(b); {
+		if p+2 > len(b) {
+			return nil, nil, fmt.Errorf("not enough bytes to unmarshal BGP informational tlv")
+		}
 		t := b[p]
 		p++
 		l := b[p]
 		p++
+		if p+int(l) > len(b) {
+			return nil, nil, fmt.Errorf("not enough bytes to unmarshal BGP informational tlv type %d of length %d", t, l)
+		}
 		// Check if informational TLV carries Capabilities
 		if t == 2 {
 			c, err := UnmarshalBGPCapability(b[p : p+int(l)])
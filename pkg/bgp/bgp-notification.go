@@ -0,0 +1,162 @@
+package bgp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/tools"
+)
+
+const (
+	// BGPMinNotificationMessageLength defines a minimum length of BGP Notification Message
+	// without the marker, 2 bytes of length, 1 byte of type, error code and error subcode.
+	BGPMinNotificationMessageLength = 5
+)
+
+// NotificationMessage defines BGP Notification Message structure
+type NotificationMessage struct {
+	Length       uint16
+	Type         byte
+	ErrorCode    uint8
+	ErrorSubcode uint8
+	Data         []byte
+}
+
+// notificationErrors defines names of NOTIFICATION error codes and subcodes, rfc4271, rfc6608, rfc7313,
+// rfc8538, rfc9234, rfc9384 and rfc9687.
+var notificationErrors = map[uint8]struct {
+	name     string
+	subcodes map[uint8]string
+}{
+	1: {"Message Header Error", map[uint8]string{
+		1: "Connection Not Synchronized",
+		2: "Bad Message Length",
+		3: "Bad Message Type",
+	}},
+	2: {"OPEN Message Error", map[uint8]string{
+		1:  "Unsupported Version Number",
+		2:  "Bad Peer AS",
+		3:  "Bad BGP Identifier",
+		4:  "Unsupported Optional Parameter",
+		5:  "Authentication Failure",
+		6:  "Unacceptable Hold Time",
+		7:  "Unsupported Capability",
+		11: "Role Mismatch",
+	}},
+	3: {"UPDATE Message Error", map[uint8]string{
+		1:  "Malformed Attribute List",
+		2:  "Unrecognized Well-known Attribute",
+		3:  "Missing Well-known Attribute",
+		4:  "Attribute Flags Error",
+		5:  "Attribute Length Error",
+		6:  "Invalid ORIGIN Attribute",
+		7:  "AS Routing Loop",
+		8:  "Invalid NEXT_HOP Attribute",
+		9:  "Optional Attribute Error",
+		10: "Invalid Network Field",
+		11: "Malformed AS_PATH",
+	}},
+	4: {"Hold Timer Expired", map[uint8]string{}},
+	5: {"Finite State Machine Error", map[uint8]string{
+		1: "Receive Unexpected Message in OpenSent State",
+		2: "Receive Unexpected Message in OpenConfirm State",
+		3: "Receive Unexpected Message in Established State",
+	}},
+	6: {"Cease", map[uint8]string{
+		1:  "Maximum Number of Prefixes Reached",
+		2:  "Administrative Shutdown",
+		3:  "Peer De-configured",
+		4:  "Administrative Reset",
+		5:  "Connection Rejected",
+		6:  "Other Configuration Change",
+		7:  "Connection Collision Resolution",
+		8:  "Out of Resources",
+		9:  "Hard Reset",
+		10: "BFD Down",
+	}},
+	7: {"ROUTE-REFRESH Message Error", map[uint8]string{
+		1: "Invalid Message Length",
+	}},
+	8: {"Send Hold Timer Expired", map[uint8]string{}},
+}
+
+// ErrorCodeString returns the name of the error code
+func (n *NotificationMessage) ErrorCodeString() string {
+	if e, ok := notificationErrors[n.ErrorCode]; ok {
+		return e.name
+	}
+
+	return fmt.Sprintf("Unknown error code %d", n.ErrorCode)
+}
+
+// ErrorSubcodeString returns the name of the error subcode, for subcode 0 an empty string is returned
+func (n *NotificationMessage) ErrorSubcodeString() string {
+	if n.ErrorSubcode == 0 {
+		return ""
+	}
+	if e, ok := notificationErrors[n.ErrorCode]; ok {
+		if s, ok := e.subcodes[n.ErrorSubcode]; ok {
+			return s
+		}
+	}
+
+	return fmt.Sprintf("Unknown error subcode %d", n.ErrorSubcode)
+}
+
+// String returns the error code and subcode names
+func (n *NotificationMessage) String() string {
+	if s := n.ErrorSubcodeString(); s != "" {
+		return n.ErrorCodeString() + ": " + s
+	}
+
+	return n.ErrorCodeString()
+}
+
+// ShutdownCommunication returns the text of Shutdown Communication carried by Cease Notification
+// with Administrative Shutdown or Administrative Reset subcode per rfc8203 and rfc9003.
+func (n *NotificationMessage) ShutdownCommunication() (string, bool) {
+	if n.ErrorCode != 6 || (n.ErrorSubcode != 2 && n.ErrorSubcode != 4) || len(n.Data) == 0 {
+		return "", false
+	}
+	l := int(n.Data[0])
+	if l == 0 || 1+l > len(n.Data) || !utf8.Valid(n.Data[1:1+l]) {
+		return "", false
+	}
+
+	return string(n.Data[1 : 1+l]), true
+}
+
+// UnmarshalBGPNotificationMessage validate information passed in byte slice and returns NotificationMessage object,
+// the slice starts with the length field of BGP message header.
+func UnmarshalBGPNotificationMessage(b []byte) (*NotificationMessage, error) {
+	if glog.V(6) {
+		glog.Infof("BGPNotificationMessage Raw: %s", tools.MessageHex(b))
+	}
+	if len(b) < BGPMinNotificationMessageLength {
+		return nil, fmt.Errorf("BGP Notification Message length %d is invalid", len(b))
+	}
+	p := 0
+	m := NotificationMessage{}
+	m.Length = binary.BigEndian.Uint16(b[p : p+2])
+	p += 2
+	if b[p] != 3 {
+		return nil, fmt.Errorf("invalid message type %d for BGP Notification Message", b[p])
+	}
+	m.Type = b[p]
+	p++
+	m.ErrorCode = b[p]
+	p++
+	m.ErrorSubcode = b[p]
+	p++
+	// Data ends with the message, the length includes 16 bytes of the marker
+	end := int(m.Length) - 16
+	if end < p || end > len(b) {
+		end = len(b)
+	}
+	m.Data = make([]byte, end-p)
+	copy(m.Data, b[p:end])
+
+	return &m, nil
+}
@@ -85,6 +85,9 @@ func UnmarshalBGPOpenMessage(b []byte) (*OpenMessage, error) {
 	p += 4
 	m.OptParamLen = b[p]
 	p++
+	if p+int(m.OptParamLen) > len(b) {
+		return nil, fmt.Errorf("BGP Open Message optional parameters length %d is invalid", m.OptParamLen)
+	}
 	if m.OptParamLen != 0 {
 		if m.OptionalParameters, m.Capabilities, err = UnmarshalBGPTLV(b[p : p+int(m.OptParamLen)]); err != nil {
 			return nil, err
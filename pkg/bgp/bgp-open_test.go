@@ -28,16 +28,19 @@ func TestUnmarshalBGPOpenMessage(t *testing.T) {
 				Capabilities: Capability{
 					1: []*capabilityData{
 						{
-							Description: "Multiprotocol Extensions for BGP-4 : afi=1 safi=1 Unicast IPv4",
-							Value:       []byte{0, 1, 0, 1},
+							Description:   "Multiprotocol Extensions for BGP-4 : afi=1 safi=1 Unicast IPv4",
+							Value:         []byte{0, 1, 0, 1},
+							Multiprotocol: &AFISAFI{AFI: 1, SAFI: 1},
 						},
 						{
-							Description: "Multiprotocol Extensions for BGP-4 : afi=1 safi=4 MPLS Labels IPv4",
-							Value:       []byte{0, 1, 0, 4},
+							Description:   "Multiprotocol Extensions for BGP-4 : afi=1 safi=4 MPLS Labels IPv4",
+							Value:         []byte{0, 1, 0, 4},
+							Multiprotocol: &AFISAFI{AFI: 1, SAFI: 4},
 						},
 						{
-							Description: "Multiprotocol Extensions for BGP-4 : afi=1 safi=128 MPLS-labeled VPN IPv4",
-							Value:       []byte{0, 1, 0, 128},
+							Description:   "Multiprotocol Extensions for BGP-4 : afi=1 safi=128 MPLS-labeled VPN IPv4",
+							Value:         []byte{0, 1, 0, 128},
+							Multiprotocol: &AFISAFI{AFI: 1, SAFI: 128},
 						},
 					},
 					2: []*capabilityData{
@@ -50,12 +53,18 @@ func TestUnmarshalBGPOpenMessage(t *testing.T) {
 						{
 							Description: "Extended Next Hop Encoding",
 							Value:       []byte{0, 1, 0, 1, 0, 2, 0, 1, 0, 2, 0, 2, 0, 1, 0, 128, 0, 2},
+							ExtendedNextHop: []*ExtendedNextHopTuple{
+								{NLRIAFI: 1, NLRISAFI: 1, NextHopAFI: 2},
+								{NLRIAFI: 1, NLRISAFI: 2, NextHopAFI: 2},
+								{NLRIAFI: 1, NLRISAFI: 128, NextHopAFI: 2},
+							},
 						},
 					},
 					65: []*capabilityData{
 						{
 							Description: "Support for 4-octet AS number capability",
 							Value:       []byte{0, 0, 19, 206},
+							AS4:         5070,
 						},
 					},
 					128: []*capabilityData{
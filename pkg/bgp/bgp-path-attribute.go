@@ -2,6 +2,7 @@ package bgp
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
@@ -13,9 +14,12 @@ type PathAttribute struct {
 	AttributeType      uint8
 	AttributeLength    uint16
 	Attribute          []byte
+	// Offset of the attribute from the beginning of BGP Update message body
+	Offset int `json:"-"`
 }
 
-// UnmarshalBGPPathAttributes builds BGP Path attributes slice
+// UnmarshalBGPPathAttributes builds BGP Path attributes slice, when attributes cannot be delimited,
+// *DecodeError is returned.
 func UnmarshalBGPPathAttributes(b []byte) ([]PathAttribute, error) {
 	if glog.V(6) {
 		glog.Infof("BGPPathAttributes Raw: %s", tools.MessageHex(b))
@@ -23,22 +27,33 @@ func UnmarshalBGPPathAttributes(b []byte) ([]PathAttribute, error) {
 	attrs := make([]PathAttribute, 0)
 
 	for p := 0; p < len(b); {
+		if p+3 > len(b) {
+			return nil, &DecodeError{Offset: p, Reason: "not enough bytes for attribute header", Action: TreatAsWithdraw}
+		}
 		f := b[p]
 		t := b[p+1]
+		ap := p
 		p += 2
 		var l uint16
 		// Checking for Extened
 		if f&0x10 == 0x10 {
+			if p+2 > len(b) {
+				return nil, &DecodeError{AttributeType: t, Offset: ap, Reason: "not enough bytes for extended length", Action: TreatAsWithdraw}
+			}
 			l = binary.BigEndian.Uint16(b[p : p+2])
 			p += 2
 		} else {
 			l = uint16(b[p])
 			p++
 		}
+		if p+int(l) > len(b) {
+			return nil, &DecodeError{AttributeType: t, Offset: ap, Reason: fmt.Sprintf("attribute length %d exceeds path attributes", l), Action: TreatAsWithdraw}
+		}
 		pa := PathAttribute{
 			AttributeTypeFlags: f,
 			AttributeType:      t,
 			AttributeLength:    l,
+			Offset:             ap,
 		}
 		pa.Attribute = make([]byte, int(l))
 		copy(pa.Attribute, b[p:p+int(l)])
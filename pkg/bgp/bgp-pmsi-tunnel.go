@@ -0,0 +1,115 @@
+package bgp
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+)
+
+// PMSITunnelTypes defines names of P-Multicast Service Interface Tunnel types
+var PMSITunnelTypes = map[uint8]string{
+	0:  "No tunnel information present",
+	1:  "RSVP-TE P2MP LSP",
+	2:  "mLDP P2MP LSP",
+	3:  "PIM-SSM Tree",
+	4:  "PIM-SM Tree",
+	5:  "BIDIR-PIM Tree",
+	6:  "Ingress Replication",
+	7:  "mLDP MP2MP LSP",
+	8:  "Transport Tunnel",
+	9:  "Assisted Replication Tunnel",
+	11: "BIER",
+}
+
+// PMSITunnel defines PMSI Tunnel attribute per rfc6514 section 5
+type PMSITunnel struct {
+	LeafInfoRequired bool   `json:"leaf_info_required"`
+	TunnelType       string `json:"tunnel_type"`
+	// Label is the MPLS label carried in high-order 20 bits of MPLS Label field
+	Label uint32 `json:"label"`
+	// VNI is the whole 24 bits of MPLS Label field, it carries VXLAN VNI for EVPN
+	// Ingress Replication tunnels per rfc8365 section 5.1.3
+	VNI uint32 `json:"vni,omitempty"`
+	// TunnelID is the IP address of the tunnel endpoint for Ingress Replication,
+	// for other tunnel types it is hex encoded Tunnel Identifier.
+	TunnelID string `json:"tunnel_id,omitempty"`
+	// Sender Address and P-Multicast Group of PIM trees
+	SenderAddr string `json:"sender_address,omitempty"`
+	PGroup     string `json:"p_group,omitempty"`
+	// Extended Tunnel ID, Tunnel ID and P2MP ID of RSVP-TE P2MP LSP SESSION object
+	ExtendedTunnelID string `json:"extended_tunnel_id,omitempty"`
+	RSVPTunnelID     uint16 `json:"rsvp_tunnel_id,omitempty"`
+	P2MPID           uint32 `json:"p2mp_id,omitempty"`
+	// Root Node Address and Opaque Value of mLDP FEC element
+	RootNodeAddr string `json:"root_node_address,omitempty"`
+	OpaqueValue  string `json:"opaque_value,omitempty"`
+}
+
+func unmarshalAttrPMSITunnel(b []byte) (*PMSITunnel, error) {
+	if len(b) < 5 {
+		return nil, fmt.Errorf("invalid PMSI Tunnel length %d", len(b))
+	}
+	t, ok := PMSITunnelTypes[b[1]]
+	if !ok {
+		t = fmt.Sprintf("Unknown tunnel type %d", b[1])
+	}
+	v := uint32(b[2])<<16 | uint32(b[3])<<8 | uint32(b[4])
+	pmsi := &PMSITunnel{
+		LeafInfoRequired: b[0]&0x01 == 0x01,
+		TunnelType:       t,
+		// Label occupies high-order 20 bits of 3 bytes field
+		Label: v >> 4,
+		VNI:   v,
+	}
+	id := b[5:]
+	switch {
+	case len(id) == 0:
+	case b[1] == 6 && (len(id) == 4 || len(id) == 16):
+		pmsi.TunnelID = net.IP(id).String()
+	default:
+		pmsi.TunnelID = hex.EncodeToString(id)
+		pmsi.unmarshalTunnelID(b[1], id)
+	}
+
+	return pmsi, nil
+}
+
+// unmarshalTunnelID decodes Tunnel Identifier of PIM, RSVP-TE P2MP and mLDP tunnels per rfc6514 section 5,
+// Tunnel Identifier which does not match the tunnel type's format is left only in hex form.
+func (pmsi *PMSITunnel) unmarshalTunnelID(t uint8, id []byte) {
+	switch t {
+	case 1:
+		// Extended Tunnel ID, Reserved 2 bytes, Tunnel ID and P2MP ID
+		if len(id) != 12 && len(id) != 24 {
+			return
+		}
+		l := len(id) - 8
+		pmsi.ExtendedTunnelID = net.IP(id[:l]).String()
+		pmsi.RSVPTunnelID = binary.BigEndian.Uint16(id[l+2 : l+4])
+		pmsi.P2MPID = binary.BigEndian.Uint32(id[l+4:])
+	case 2, 7:
+		// mLDP FEC element: Type, Address Family, Address Length, Root Node Address, Opaque Length and Opaque Value
+		if len(id) < 4 {
+			return
+		}
+		l := int(id[3])
+		if (l != 4 && l != 16) || 4+l+2 > len(id) {
+			return
+		}
+		ol := int(binary.BigEndian.Uint16(id[4+l : 4+l+2]))
+		if 4+l+2+ol != len(id) {
+			return
+		}
+		pmsi.RootNodeAddr = net.IP(id[4 : 4+l]).String()
+		pmsi.OpaqueValue = hex.EncodeToString(id[4+l+2:])
+	case 3, 4, 5:
+		// Sender Address and P-Multicast Group
+		if len(id) != 8 && len(id) != 32 {
+			return
+		}
+		l := len(id) / 2
+		pmsi.SenderAddr = net.IP(id[:l]).String()
+		pmsi.PGroup = net.IP(id[l:]).String()
+	}
+}
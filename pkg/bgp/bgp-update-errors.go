@@ -0,0 +1,234 @@
+package bgp
+
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/bgpls"
+)
+
+// ErrorAction defines the approach to handle a malformed BGP Update per rfc7606 section 2,
+// it is shared with decoders of NLRI and attributes which do not depend on bgp package.
+type ErrorAction = base.ErrorAction
+
+const (
+	// AttributeDiscard defines the approach when the malformed attribute is discarded
+	// and the rest of the update is processed
+	AttributeDiscard = base.AttributeDiscard
+	// TreatAsWithdraw defines the approach when all routes of the update are handled as withdrawn
+	TreatAsWithdraw = base.TreatAsWithdraw
+	// AFISAFIDisable defines the approach when routes of the malformed MP_REACH_NLRI or MP_UNREACH_NLRI
+	// attribute cannot be processed
+	AFISAFIDisable = base.AFISAFIDisable
+	// SessionReset defines the approach when the update cannot be processed at all
+	SessionReset = base.SessionReset
+)
+
+// DecodeError defines an error found while decoding BGP Update
+type DecodeError = base.DecodeError
+
+// attributeFlags defines Optional and Transitive flags of known path attributes
+var attributeFlags = map[uint8]uint8{
+	1:   0x40,
+	2:   0x40,
+	3:   0x40,
+	4:   0x80,
+	5:   0x40,
+	6:   0x40,
+	7:   0xc0,
+	8:   0xc0,
+	9:   0x80,
+	10:  0x80,
+	14:  0x80,
+	15:  0x80,
+	16:  0xc0,
+	17:  0xc0,
+	18:  0xc0,
+	22:  0xc0,
+	26:  0x80,
+	32:  0xc0,
+	33:  0x80,
+	35:  0xc0,
+	128: 0xc0,
+}
+
+// validateAttribute checks the path attribute of a session with as4 capability and returns the error
+// with the action required per rfc7606 section 7, nil is returned for valid and unknown attributes.
+func validateAttribute(attr *PathAttribute, as4 bool) *DecodeError {
+	b := attr.Attribute
+	l := len(b)
+	reason := ""
+	action := TreatAsWithdraw
+	if flags, ok := attributeFlags[attr.AttributeType]; ok && attr.AttributeTypeFlags&0xc0 != flags {
+		reason = fmt.Sprintf("invalid flags 0x%02x", attr.AttributeTypeFlags)
+		switch attr.AttributeType {
+		case 14, 15:
+			action = SessionReset
+		case 6, 7, 17, 18, 26:
+			action = AttributeDiscard
+		}
+		return &DecodeError{AttributeType: attr.AttributeType, Reason: reason, Action: action}
+	}
+	switch attr.AttributeType {
+	case 1:
+		if l != 1 || b[0] > 2 {
+			reason = "invalid origin"
+		}
+	case 2:
+		if _, err := unmarshalAttrASPath(b, as4); err != nil {
+			reason = err.Error()
+		}
+	case 3, 4, 5, 9:
+		if l != 4 {
+			reason = fmt.Sprintf("invalid length %d", l)
+		}
+	case 6:
+		if l != 0 {
+			reason = fmt.Sprintf("invalid length %d", l)
+			action = AttributeDiscard
+		}
+	case 7:
+		if (as4 && l != 8) || (!as4 && l != 6) {
+			reason = fmt.Sprintf("invalid length %d", l)
+			action = AttributeDiscard
+		}
+	case 8, 10:
+		if l == 0 || l%4 != 0 {
+			reason = fmt.Sprintf("invalid length %d", l)
+		}
+	case 14:
+		// AFI, SAFI, Next Hop length and the reserved byte must fit
+		if l < 5 || l < 5+int(b[3]) {
+			reason = fmt.Sprintf("invalid length %d", l)
+			action = AFISAFIDisable
+		}
+	case 15:
+		if l < 3 {
+			reason = fmt.Sprintf("invalid length %d", l)
+			action = AFISAFIDisable
+		}
+	case 16:
+		if l == 0 || l%8 != 0 {
+			reason = fmt.Sprintf("invalid length %d", l)
+		}
+	case 17:
+		if _, err := unmarshalAttrASPath(b, true); err != nil {
+			reason = err.Error()
+			action = AttributeDiscard
+		}
+	case 18:
+		if l != 8 {
+			reason = fmt.Sprintf("invalid length %d", l)
+			action = AttributeDiscard
+		}
+	case 22:
+		if l < 5 {
+			reason = fmt.Sprintf("invalid length %d", l)
+		}
+	case 26:
+		// Malformed AIGP attribute is discarded per rfc7311 section 3.1
+		if _, err := unmarshalAttrAIGP(b); err != nil {
+			reason = err.Error()
+			action = AttributeDiscard
+		}
+	case 29:
+		// Malformed BGP-LS attribute is discarded per rfc9552 section 8.2.2
+		if _, err := bgpls.UnmarshalBGPLSNLRI(b); err != nil {
+			reason = err.Error()
+			if de, ok := err.(*DecodeError); ok {
+				reason = de.Reason
+			}
+			action = AttributeDiscard
+		}
+	case 32:
+		if l == 0 || l%12 != 0 {
+			reason = fmt.Sprintf("invalid length %d", l)
+		}
+	case 35:
+		if l != 4 {
+			reason = fmt.Sprintf("invalid length %d", l)
+		}
+	case 128:
+		if l < 4 {
+			reason = fmt.Sprintf("invalid length %d", l)
+		}
+	}
+	if reason == "" {
+		return nil
+	}
+
+	return &DecodeError{AttributeType: attr.AttributeType, Reason: reason, Action: action}
+}
+
+// validateAttributes checks path attributes of the update and returns attributes which should be
+// processed, discarded attributes and duplicates are removed, errors are recorded in the update.
+// offset is the offset of path attributes from the beginning of the update.
+func (up *Update) validateAttributes(attrs []PathAttribute, offset int, as4 bool) []PathAttribute {
+	valid := make([]PathAttribute, 0, len(attrs))
+	seen := make(map[uint8]bool)
+	for i := range attrs {
+		attr := &attrs[i]
+		if seen[attr.AttributeType] {
+			// Multiple MP_REACH_NLRI or MP_UNREACH_NLRI cannot be processed, other duplicates
+			// are discarded per rfc7606 section 3.g
+			action := AttributeDiscard
+			if attr.AttributeType == 14 || attr.AttributeType == 15 {
+				action = SessionReset
+			}
+			up.addError(&DecodeError{AttributeType: attr.AttributeType, Offset: attr.Offset, Reason: "duplicate attribute", Action: action})
+			continue
+		}
+		seen[attr.AttributeType] = true
+		if err := validateAttribute(attr, as4); err != nil {
+			err.Offset = attr.Offset
+			up.addError(err)
+			if err.Action == AttributeDiscard || err.Action == AFISAFIDisable {
+				continue
+			}
+		}
+		valid = append(valid, *attr)
+	}
+	// Well-known mandatory attributes must be present when the update carries reachability
+	// information, rfc7606 section 3.d
+	if len(up.NLRI) != 0 || seen[14] {
+		missing := []uint8{1, 2}
+		if len(up.NLRI) != 0 {
+			missing = append(missing, 3)
+		}
+		for _, t := range missing {
+			if !seen[t] {
+				up.addError(&DecodeError{AttributeType: t, Offset: offset, Reason: "missing well-known mandatory attribute", Action: TreatAsWithdraw})
+			}
+		}
+	}
+
+	return valid
+}
+
+func (up *Update) addError(err *DecodeError) {
+	up.Errors = append(up.Errors, err)
+}
+
+// ErrorAction returns the most severe action required by errors found in the update,
+// 0 is returned when the update is well formed.
+func (up *Update) ErrorAction() ErrorAction {
+	var a ErrorAction
+	for _, err := range up.Errors {
+		if err.Action > a {
+			a = err.Action
+		}
+	}
+
+	return a
+}
+
+// IsTreatAsWithdraw returns true when routes advertised by the update must be handled as withdrawn
+func (up *Update) IsTreatAsWithdraw() bool {
+	for _, err := range up.Errors {
+		if err.Action == TreatAsWithdraw {
+			return true
+		}
+	}
+
+	return false
+}
@@ -13,6 +13,16 @@ import (
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
 
+const (
+	// BGPHeaderLength defines the length of BGP message header, 16 bytes of marker, 2 bytes of length and 1 byte of type
+	BGPHeaderLength = 19
+	// BGPMaxMessageLength defines the maximum length of BGP message
+	BGPMaxMessageLength = 4096
+	// BGPMaxExtendedMessageLength defines the maximum length of BGP message when Extended Message capability
+	// is negotiated per rfc8654
+	BGPMaxExtendedMessageLength = 65535
+)
+
 // Update defines a structure of BGP Update message
 type Update struct {
 	WithdrawnRoutesLength    uint16
@@ -21,6 +31,10 @@ type Update struct {
 	PathAttributes           []PathAttribute
 	NLRI                     []base.Route
 	BaseAttributes           *BaseAttributes
+	// Errors lists errors found in the update which did not prevent its processing, rfc7606
+	Errors []*DecodeError
+	// caps are capabilities negotiated by the peers, nil when not known
+	caps *NegotiatedCapabilities
 }
 
 // GetAllAttributeID return a slixe of int with all attributes found in BGP Update
@@ -48,13 +62,18 @@ func (up *Update) GetBaseAttrHash() string {
 func (up *Update) GetMPReachNLRI() (MPNLRI, error) {
 	for _, attr := range up.PathAttributes {
 		if attr.AttributeType == 14 {
-			return UnmarshalMPReachNLRI(attr.Attribute, up.HasPrefixSID())
+			return up.UnmarshalMPReachNLRI(attr.Attribute)
 		}
 	}
 
 	return nil, fmt.Errorf("not found")
 }
 
+// UnmarshalMPReachNLRI builds MP Reach NLRI attribute of the update with capabilities negotiated by the peers
+func (up *Update) UnmarshalMPReachNLRI(b []byte) (MPNLRI, error) {
+	return unmarshalMPReachNLRI(b, up.HasPrefixSID(), up.caps)
+}
+
 // GetMPUnReachNLRI Instantiates MP_UNReach NLRI if it exists in the update
 func (up *Update) GetMPUnReachNLRI() (MPNLRI, error) {
 	for _, attr := range up.PathAttributes {
@@ -96,6 +115,28 @@ func (up *Update) GetAttrPrefixSID() (*prefixsid.PSid, error) {
 	return nil, fmt.Errorf("not found")
 }
 
+// GetExtCommunity check for presense of BGP Attribute Extended Community (16) and instantiates it
+func (up *Update) GetExtCommunity() ([]ExtCommunity, error) {
+	for _, attr := range up.PathAttributes {
+		if attr.AttributeType == 16 {
+			return UnmarshalBGPExtCommunity(attr.Attribute)
+		}
+	}
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
+
+// HasEntropyLabelCapability check for presense of BGP Attribute Entropy Label Capability (28) and returns true is found
+func (up *Update) HasEntropyLabelCapability() bool {
+	for _, attr := range up.PathAttributes {
+		if attr.AttributeType == 28 {
+			return true
+		}
+	}
+
+	return false
+}
+
 // HasPrefixSID check for presense of BGP Attribute Prefix SID (40) and returns true is found
 func (up *Update) HasPrefixSID() bool {
 	for _, attr := range up.PathAttributes {
@@ -107,40 +148,67 @@ func (up *Update) HasPrefixSID() bool {
 	return false
 }
 
-// UnmarshalBGPUpdate build BGP Update object from the byte slice provided
-func UnmarshalBGPUpdate(b []byte) (*Update, error) {
+// UnmarshalBGPUpdate build BGP Update object from the byte slice provided, caps are capabilities
+// negotiated by the peers, when nil, 4 bytes AS capability is assumed. Malformed attributes are handled
+// per rfc7606 and recorded in Errors of the update, *DecodeError is returned only when the update
+// cannot be processed at all.
+func UnmarshalBGPUpdate(b []byte, caps *NegotiatedCapabilities) (*Update, error) {
 	if glog.V(6) {
 		glog.Infof("BGPUpdate Raw: %s", tools.MessageHex(b))
 	}
+	as4 := caps == nil || caps.AS4
 	p := 0
-	u := Update{}
+	u := Update{
+		caps: caps,
+	}
+	if len(b) < 4 {
+		return nil, &DecodeError{Offset: p, Reason: fmt.Sprintf("invalid update length %d", len(b)), Action: SessionReset}
+	}
 	u.WithdrawnRoutesLength = binary.BigEndian.Uint16(b[p : p+2])
 	p += 2
+	if p+int(u.WithdrawnRoutesLength)+2 > len(b) {
+		return nil, &DecodeError{Offset: p - 2, Reason: fmt.Sprintf("withdrawn routes length %d exceeds the update", u.WithdrawnRoutesLength), Action: SessionReset}
+	}
 	wdr, err := base.UnmarshalRoutes(b[p : p+int(u.WithdrawnRoutesLength)])
 	if err != nil {
-		return nil, err
+		return nil, &DecodeError{Offset: p, Reason: err.Error(), Action: SessionReset}
 	}
 	u.WithdrawnRoutes = wdr
 	p += int(u.WithdrawnRoutesLength)
 	u.TotalPathAttributeLength = binary.BigEndian.Uint16(b[p : p+2])
 	p += 2
+	if p+int(u.TotalPathAttributeLength) > len(b) {
+		return nil, &DecodeError{Offset: p - 2, Reason: fmt.Sprintf("total path attribute length %d exceeds the update", u.TotalPathAttributeLength), Action: SessionReset}
+	}
+	// Routes are decoded first as presence of NLRI defines which attributes are mandatory
+	routes, err := base.UnmarshalRoutes(b[p+int(u.TotalPathAttributeLength):])
+	if err != nil {
+		return nil, &DecodeError{Offset: p + int(u.TotalPathAttributeLength), Reason: err.Error(), Action: SessionReset}
+	}
+	u.NLRI = routes
 	attrs, err := UnmarshalBGPPathAttributes(b[p : p+int(u.TotalPathAttributeLength)])
 	if err != nil {
-		return nil, err
+		de, ok := err.(*DecodeError)
+		if !ok {
+			return nil, err
+		}
+		// Attributes cannot be delimited, the routes of the update are treated as withdrawn
+		de.Offset += p
+		u.addError(de)
+		attrs = make([]PathAttribute, 0)
+	} else {
+		for i := range attrs {
+			attrs[i].Offset += p
+		}
+		attrs = u.validateAttributes(attrs, p, as4)
 	}
 	// Building BGP's update Base attributes struct which is common to all messages
-	baseAttrs, err := UnmarshalBGPBaseAttributes(b[p : p+int(u.TotalPathAttributeLength)])
+	baseAttrs, err := newBaseAttributes(attrs, as4)
 	if err != nil {
 		return nil, err
 	}
 	u.PathAttributes = attrs
 	u.BaseAttributes = baseAttrs
-	p += int(u.TotalPathAttributeLength)
-	routes, err := base.UnmarshalRoutes(b[p:])
-	if err != nil {
-		return nil, err
-	}
-	u.NLRI = routes
 
 	return &u, nil
 }
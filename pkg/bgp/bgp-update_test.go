@@ -0,0 +1,135 @@
+package bgp
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+// update builds BGP Update message body from withdrawn routes, path attributes and nlri
+func update(withdrawn, attrs, nlri []byte) []byte {
+	b := make([]byte, 2, 4+len(withdrawn)+len(attrs)+len(nlri))
+	binary.BigEndian.PutUint16(b, uint16(len(withdrawn)))
+	b = append(b, withdrawn...)
+	b = append(b, byte(len(attrs)>>8), byte(len(attrs)))
+	b = append(b, attrs...)
+
+	return append(b, nlri...)
+}
+
+func TestUnmarshalBGPUpdateErrors(t *testing.T) {
+	origin := []byte{0x40, 1, 1, 0}
+	asPath := []byte{0x40, 2, 6, 2, 1, 0, 0, 0xfd, 0xe9}
+	nextHop := []byte{0x40, 3, 4, 192, 0, 2, 1}
+	nlri := []byte{24, 10, 0, 0}
+	join := func(s ...[]byte) []byte {
+		b := make([]byte, 0)
+		for _, e := range s {
+			b = append(b, e...)
+		}
+		return b
+	}
+	tests := []struct {
+		name     string
+		input    []byte
+		errors   []*DecodeError
+		action   ErrorAction
+		attrs    []uint8
+		nlri     int
+		withdraw bool
+		fail     bool
+	}{
+		{
+			name:  "well formed",
+			input: update(nil, join(origin, asPath, nextHop), nlri),
+			attrs: []uint8{1, 2, 3},
+			nlri:  1,
+		},
+		{
+			name:     "invalid origin is treat-as-withdraw",
+			input:    update(nil, join([]byte{0x40, 1, 1, 5}, asPath, nextHop), nlri),
+			errors:   []*DecodeError{{AttributeType: 1, Offset: 4, Reason: "invalid origin", Action: TreatAsWithdraw}},
+			action:   TreatAsWithdraw,
+			attrs:    []uint8{1, 2, 3},
+			nlri:     1,
+			withdraw: true,
+		},
+		{
+			name:   "malformed atomic aggregate is discarded",
+			input:  update(nil, join(origin, asPath, nextHop, []byte{0x40, 6, 1, 0}), nlri),
+			errors: []*DecodeError{{AttributeType: 6, Offset: 24, Reason: "invalid length 1", Action: AttributeDiscard}},
+			action: AttributeDiscard,
+			attrs:  []uint8{1, 2, 3},
+			nlri:   1,
+		},
+		{
+			name:   "duplicate med is discarded",
+			input:  update(nil, join(origin, asPath, nextHop, []byte{0x80, 4, 4, 0, 0, 0, 1, 0x80, 4, 4, 0, 0, 0, 2}), nlri),
+			errors: []*DecodeError{{AttributeType: 4, Offset: 31, Reason: "duplicate attribute", Action: AttributeDiscard}},
+			action: AttributeDiscard,
+			attrs:  []uint8{1, 2, 3, 4},
+			nlri:   1,
+		},
+		{
+			name:     "missing next hop is treat-as-withdraw",
+			input:    update(nil, join(origin, asPath), nlri),
+			errors:   []*DecodeError{{AttributeType: 3, Offset: 4, Reason: "missing well-known mandatory attribute", Action: TreatAsWithdraw}},
+			action:   TreatAsWithdraw,
+			attrs:    []uint8{1, 2},
+			nlri:     1,
+			withdraw: true,
+		},
+		{
+			name:     "attribute length overrun keeps nlri",
+			input:    update(nil, join(origin, asPath, []byte{0x40, 3, 8, 192, 0, 2, 1}), nlri),
+			errors:   []*DecodeError{{AttributeType: 3, Offset: 17, Reason: "attribute length 8 exceeds path attributes", Action: TreatAsWithdraw}},
+			action:   TreatAsWithdraw,
+			attrs:    []uint8{},
+			nlri:     1,
+			withdraw: true,
+		},
+		{
+			name:  "withdrawn routes length exceeds the update",
+			input: []byte{0, 10, 24, 10, 0, 0, 0, 0},
+			fail:  true,
+		},
+		{
+			name:  "total path attribute length exceeds the update",
+			input: []byte{0, 0, 0, 10, 0x40, 1, 1, 0},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := UnmarshalBGPUpdate(tt.input, nil)
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("supposed to succeed but failed with error: %+v", err)
+				}
+				if de, ok := err.(*DecodeError); !ok || de.Action != SessionReset {
+					t.Fatalf("expected session reset decode error but got: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if diff := deep.Equal(tt.errors, u.Errors); diff != nil {
+				t.Errorf("Diffs: %+v", diff)
+			}
+			if a := u.ErrorAction(); a != tt.action {
+				t.Errorf("expected action %s but got %s", tt.action, a)
+			}
+			if diff := deep.Equal(tt.attrs, u.GetAllAttributeID()); diff != nil {
+				t.Errorf("attributes diffs: %+v", diff)
+			}
+			if len(u.NLRI) != tt.nlri {
+				t.Errorf("expected %d nlri but got %d", tt.nlri, len(u.NLRI))
+			}
+			if w := u.IsTreatAsWithdraw(); w != tt.withdraw {
+				t.Errorf("expected treat-as-withdraw %t but got %t", tt.withdraw, w)
+			}
+		})
+	}
+}
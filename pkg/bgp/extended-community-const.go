@@ -120,4 +120,10 @@ const (
 	CPFlowspecRedirect = "flowspec-redirect="
 	// CPFlowspecTrafficRemarking defines Flowspec Traffic Remarking Sub type
 	CPFlowspecTrafficRemarking = "flowspec-traffic-remarking="
+
+	// ECPLayer2Info extended community prefix for Layer2 Info Extended Community	[RFC4761]
+	ECPLayer2Info = "l2info="
+
+	// ECPTransportClass extended community prefix for Transport Class ID Extended Community	[RFC9832]
+	ECPTransportClass = "tc="
 )
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"strings"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/flowspec"
 	"github.com/sbezverk/gobmp/pkg/tools"
+	"github.com/sbezverk/gobmp/pkg/vpls"
 )
 
 // ExtCommunity defines BGP Extended Commuity
@@ -32,6 +35,68 @@ func (ext *ExtCommunity) IsRouteTarget() bool {
 	return false
 }
 
+// GetTransportClassID returns Transport Class ID and true if the extended community is of Transport Class type
+func (ext *ExtCommunity) GetTransportClassID() (uint32, bool) {
+	if ext.Type&0xbf != 0x0a || ext.SubType == nil || *ext.SubType != 0x02 || len(ext.Value) != 6 {
+		return 0, false
+	}
+
+	return binary.BigEndian.Uint32(ext.Value[2:]), true
+}
+
+// GetEncapsulationType returns Tunnel Type and true if the extended community is of Encapsulation type
+func (ext *ExtCommunity) GetEncapsulationType() (uint16, bool) {
+	if ext.Type != 0x03 || ext.SubType == nil || *ext.SubType != 0x0c || len(ext.Value) < 6 {
+		return 0, false
+	}
+	// Tunnel Type occupies the last 2 bytes of the value, rfc9012 section 4.1
+	return binary.BigEndian.Uint16(ext.Value[4:6]), true
+}
+
+// IsDefaultGateway returns true if the extended community is of Default Gateway type
+func (ext *ExtCommunity) IsDefaultGateway() bool {
+	return ext.Type == 0x03 && ext.SubType != nil && *ext.SubType == 0x0d
+}
+
+// GetFlowspecAction returns Flowspec action and true if the extended community is of one of Flowspec action types
+// https://tools.ietf.org/html/rfc8955#section-7
+func (ext *ExtCommunity) GetFlowspecAction() (*flowspec.Action, bool) {
+	var a *flowspec.Action
+	var err error
+	switch {
+	case ext.Type == 0x08:
+		// Redirect to IP next hop [draft-simpson-idr-flowspec-redirect], the next hop of the route is used
+		a, err = flowspec.UnmarshalRedirectIPNextHop(ext.Value)
+	case ext.SubType == nil:
+		return nil, false
+	case ext.Type == 0x01 && *ext.SubType == 0x0c:
+		a, err = flowspec.UnmarshalRedirectIPNextHop(ext.Value)
+	case ext.Type == 0x80 && *ext.SubType == 0x06:
+		a, err = flowspec.UnmarshalTrafficRate(ext.Value, false)
+	case ext.Type == 0x80 && *ext.SubType == 0x0c:
+		a, err = flowspec.UnmarshalTrafficRate(ext.Value, true)
+	case ext.Type == 0x80 && *ext.SubType == 0x07:
+		a, err = flowspec.UnmarshalTrafficAction(ext.Value)
+	case ext.Type == 0x80 && *ext.SubType == 0x09:
+		a, err = flowspec.UnmarshalTrafficMarking(ext.Value)
+	case (ext.Type == 0x80 || ext.Type == 0x81 || ext.Type == 0x82) && *ext.SubType == 0x08:
+		if len(ext.Value) != 6 {
+			return nil, false
+		}
+		a = &flowspec.Action{
+			Type:        flowspec.ActionRedirect,
+			RouteTarget: strings.TrimPrefix(ext.String(), CPFlowspecRedirect),
+		}
+	default:
+		return nil, false
+	}
+	if err != nil {
+		return nil, false
+	}
+
+	return a, true
+}
+
 func makeExtCommunity(b []byte) (*ExtCommunity, error) {
 	ext := ExtCommunity{}
 	if len(b) != 8 {
@@ -48,16 +113,15 @@ func makeExtCommunity(b []byte) (*ExtCommunity, error) {
 		fallthrough
 	case 2:
 		fallthrough
+	case 0x0a:
+		fallthrough
+	case 3:
+		fallthrough
 	case 6:
 		st := uint8(b[p])
 		ext.SubType = &st
 		l = 6
 		p++
-	case 3:
-		st := uint8(b[p])
-		ext.SubType = &st
-		l = 6
-		p += 3
 	}
 	ext.Value = make([]byte, l)
 	copy(ext.Value, b[p:])
@@ -67,6 +131,9 @@ func makeExtCommunity(b []byte) (*ExtCommunity, error) {
 
 // UnmarshalBGPExtCommunity builds a slice of Extended Communities
 func UnmarshalBGPExtCommunity(b []byte) ([]ExtCommunity, error) {
+	if len(b)%8 != 0 {
+		return nil, fmt.Errorf("invalid length of extended community attribute %d", len(b))
+	}
 	exts := make([]ExtCommunity, 0)
 	for p := 0; p < len(b); {
 		if glog.V(6) {
@@ -227,6 +294,12 @@ var nonTransAS2SubTypes = map[uint8]string{
 	0x80: ECPVNIID,
 }
 
+// Transport Class Extended Community Sub-Types
+// 0x02	Transport Class ID	[RFC9832]
+var transportClassSubTypes = map[uint8]string{
+	0x2: ECPTransportClass,
+}
+
 // Generic Transitive Experimental Use Extended Community Sub-Types
 // 0x06               Flow spec traffic-rate
 // 0x07               Flow spec traffic-action (Use of the "Value" field is defined in the "Traffic Action Fields" registry)
@@ -268,11 +341,11 @@ func type3(subType uint8, value []byte) string {
 	var s string
 	switch subType {
 	case 0xb:
-		s = fmt.Sprintf("%d", binary.BigEndian.Uint32(value[0:4]))
+		s = fmt.Sprintf("%d", binary.BigEndian.Uint32(value[2:6]))
 	case 0xc:
-		s = fmt.Sprintf("%d", binary.BigEndian.Uint16(value[2:4]))
+		s = fmt.Sprintf("%d", binary.BigEndian.Uint16(value[4:6]))
 	default:
-		s = fmt.Sprintf("%d", binary.BigEndian.Uint32(value[0:4]))
+		s = fmt.Sprintf("%d", binary.BigEndian.Uint32(value[2:6]))
 	}
 	return getSubType(transOpaqueSubTypes, subType) + s
 }
@@ -308,6 +381,11 @@ func type8(subType uint8, value []byte) string {
 	return ECPFlowspec + "redirect_to_ip_next_hop"
 }
 
+// Transitive and Non-Transitive Transport Class Extended Community
+func type0a(subType uint8, value []byte) string {
+	return getSubType(transportClassSubTypes, subType) + fmt.Sprintf("%d", binary.BigEndian.Uint32(value[2:]))
+}
+
 // Non-Transitive Two-Octet AS-Specific Extended Community
 func type40(subType uint8, value []byte) string {
 	var s string
@@ -333,6 +411,10 @@ func type80(subType uint8, value []byte) string {
 			s = fmt.Sprintf("AS: %d Rate: %d bps", binary.BigEndian.Uint16(value[:2]), uint32(math.Float32frombits(binary.BigEndian.Uint32(value[2:])))*8)
 		case 0x08:
 			s = fmt.Sprintf("%d:%d", binary.BigEndian.Uint16(value[0:2]), binary.BigEndian.Uint32(value[2:]))
+		case 0x0a:
+			// Layer2 Info shares Type 0x80 with Flowspec communities
+			l2, _ := vpls.UnmarshalLayer2Info(value)
+			return ECPLayer2Info + l2.String()
 		case 0x09:
 			fallthrough
 		case 0x07:
@@ -387,7 +469,9 @@ var extComm = map[uint8]func(uint8, []byte) string{
 	0x3:  type3,
 	0x6:  type6,
 	0x8:  type8,
+	0x0a: type0a,
 	0x40: type40,
+	0x4a: type0a,
 	0x80: type80,
 	0x81: type81,
 	0x82: type82,
@@ -1,8 +1,11 @@
 package bgp
 
 import (
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/flowspec"
 )
 
 func TestExtendedCommunity(t *testing.T) {
@@ -26,6 +29,31 @@ func TestExtendedCommunity(t *testing.T) {
 			input:  []byte{0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
 			expect: "flowspec=redirect_to_ip_next_hop",
 		},
+		{
+			name:   "layer2 info community",
+			input:  []byte{0x80, 0x0a, 0x13, 0x03, 0x05, 0xdc, 0x00, 0x00},
+			expect: "l2info=encap:VPLS flags:C,S mtu:1500",
+		},
+		{
+			name:   "transport class community",
+			input:  []byte{0x0a, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x64},
+			expect: "tc=100",
+		},
+		{
+			name:   "non-transitive transport class community",
+			input:  []byte{0x4a, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0xc8},
+			expect: "tc=200",
+		},
+		{
+			name:   "color community",
+			input:  []byte{0x03, 0x0b, 0x00, 0x00, 0x00, 0x00, 0x00, 0x64},
+			expect: "color=100",
+		},
+		{
+			name:   "encapsulation community vxlan",
+			input:  []byte{0x03, 0x0c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+			expect: "encap=8",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -40,3 +68,104 @@ func TestExtendedCommunity(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFlowspecAction(t *testing.T) {
+	rate := float32(1000)
+	dscp := uint8(46)
+	tests := []struct {
+		name   string
+		input  []byte
+		expect *flowspec.Action
+	}{
+		{
+			name:   "traffic-rate-bytes",
+			input:  []byte{0x80, 0x06, 0x00, 0x00, 0x44, 0x7a, 0x00, 0x00},
+			expect: &flowspec.Action{Type: flowspec.ActionTrafficRateBytes, Rate: &rate},
+		},
+		{
+			name:   "traffic-action terminal",
+			input:  []byte{0x80, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+			expect: &flowspec.Action{Type: flowspec.ActionTrafficAction, Terminal: true},
+		},
+		{
+			name:   "redirect four-octet as",
+			input:  []byte{0x82, 0x08, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x64},
+			expect: &flowspec.Action{Type: flowspec.ActionRedirect, RouteTarget: "65000:100"},
+		},
+		{
+			name:   "traffic-marking",
+			input:  []byte{0x80, 0x09, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2e},
+			expect: &flowspec.Action{Type: flowspec.ActionTrafficMarking, DSCP: &dscp},
+		},
+		{
+			name:   "redirect to ip next hop",
+			input:  []byte{0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+			expect: &flowspec.Action{Type: flowspec.ActionRedirectIPNextHop, Copy: true},
+		},
+		{
+			name:   "redirect to ipv4 next hop",
+			input:  []byte{0x01, 0x0c, 0xc0, 0xa8, 0x01, 0x01, 0x00, 0x00},
+			expect: &flowspec.Action{Type: flowspec.ActionRedirectIPNextHop, NextHop: "192.168.1.1"},
+		},
+		{
+			name:  "route target",
+			input: []byte{0x00, 0x02, 0x00, 0x64, 0x00, 0x00, 0x00, 0x64},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext, err := makeExtCommunity(tt.input)
+			if err != nil {
+				t.Fatalf("failed with error: %+v", err)
+			}
+			got, ok := ext.GetFlowspecAction()
+			if ok != (tt.expect != nil) {
+				t.Fatalf("expected action %+v but got %t", tt.expect, ok)
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("expected action %+v does not match computed %+v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestGetEncapsulationType(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		expect uint16
+		ok     bool
+	}{
+		{
+			name:   "vxlan",
+			input:  []byte{0x03, 0x0c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+			expect: 8,
+			ok:     true,
+		},
+		{
+			name:   "mpls",
+			input:  []byte{0x03, 0x0c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a},
+			expect: 10,
+			ok:     true,
+		},
+		{
+			name:  "color",
+			input: []byte{0x03, 0x0b, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext, err := makeExtCommunity(tt.input)
+			if err != nil {
+				t.Fatalf("failed with error: %+v", err)
+			}
+			got, ok := ext.GetEncapsulationType()
+			if ok != tt.ok {
+				t.Fatalf("expected %t but got %t", tt.ok, ok)
+			}
+			if got != tt.expect {
+				t.Errorf("expected tunnel type %d but got %d", tt.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+//go:build go1.18
+// +build go1.18
+
+package bgp
+
+import "testing"
+
+func FuzzUnmarshalBGPUpdate(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte, as4 bool) {
+		UnmarshalBGPUpdate(b, &NegotiatedCapabilities{AS4: as4})
+	})
+}
+
+func FuzzUnmarshalBGPBaseAttributes(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte, as4 bool) {
+		UnmarshalBGPBaseAttributes(b, as4)
+	})
+}
+
+func FuzzUnmarshalBGPTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalBGPTLV(b)
+	})
+}
+
+func FuzzUnmarshalBGPLgCommunity(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalBGPLgCommunity(b)
+	})
+}
+
+func FuzzUnmarshalBGPPathAttributes(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalBGPPathAttributes(b)
+	})
+}
+
+func FuzzUnmarshalBGPCapability(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalBGPCapability(b)
+	})
+}
+
+func FuzzUnmarshalBGPOpenMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalBGPOpenMessage(b)
+	})
+}
+
+func FuzzUnmarshalBGPExtCommunity(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalBGPExtCommunity(b)
+	})
+}
+
+// nlri calls all NLRI getters as the producer does for MP_REACH_NLRI and MP_UNREACH_NLRI
+func nlri(mp MPNLRI) {
+	mp.GetAFISAFIType()
+	mp.GetNextHop()
+	mp.GetNextHopLinkLocal()
+	mp.GetNLRILU()
+	mp.GetNLRIUnicast()
+	mp.GetNLRIEVPN()
+	mp.GetNLRIVPLS()
+	mp.GetNLRIMVPN()
+	mp.GetNLRIRTC()
+	mp.GetNLRIL3VPN()
+	mp.GetNLRIClassfulTransport()
+	mp.GetNLRI71()
+	mp.GetNLRI73()
+	mp.GetFlowspecNLRI()
+}
+
+func FuzzUnmarshalMPReachNLRI(f *testing.F) {
+	f.Add([]byte{0, 1, 1, 4, 10, 0, 0, 1, 0, 24, 10, 1, 1}, false)
+	f.Add([]byte{0, 2, 1, 16, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 64, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 1}, false)
+	f.Add([]byte{0, 1, 128, 12, 0, 0, 0, 0, 0, 0, 0, 0, 10, 0, 0, 1, 0, 112, 0, 0x3e, 0x81, 0, 0, 0xfd, 0xe8, 0, 0, 0, 1, 10, 1, 1}, true)
+	f.Add([]byte{0x40, 0x04, 71, 4, 10, 0, 0, 1, 0, 0, 1, 0, 4, 0, 0, 0, 0}, false)
+	f.Add([]byte{0, 25, 70, 4, 10, 0, 0, 1, 0, 3, 17, 0, 1, 10, 0, 0, 1, 0, 1, 0, 0, 0, 0, 0, 0, 32, 10, 0, 0, 1}, false)
+	f.Fuzz(func(t *testing.T, b []byte, srv6 bool) {
+		mp, err := UnmarshalMPReachNLRI(b, srv6)
+		if err != nil {
+			return
+		}
+		nlri(mp)
+	})
+}
+
+func FuzzUnmarshalMPUnReachNLRI(f *testing.F) {
+	f.Add([]byte{0, 1, 1, 24, 10, 1, 1})
+	f.Add([]byte{0, 1, 133, 5, 1, 24, 10, 1, 1})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		mp, err := UnmarshalMPUnReachNLRI(b)
+		if err != nil {
+			return
+		}
+		nlri(mp)
+	})
+}
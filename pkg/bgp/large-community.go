@@ -32,6 +32,9 @@ func (lg *LgCommunity) String() string {
 
 // UnmarshalBGPLgCommunity builds a slice of Large Communities
 func UnmarshalBGPLgCommunity(b []byte) ([]LgCommunity, error) {
+	if len(b)%12 != 0 {
+		return nil, fmt.Errorf("invalid length of large community attribute %d", len(b))
+	}
 	lgs := make([]LgCommunity, 0)
 	for p := 0; p < len(b); {
 		lg, err := makeLgCommunity(b[p : p+12])
@@ -5,7 +5,10 @@ import (
 	"github.com/sbezverk/gobmp/pkg/evpn"
 	"github.com/sbezverk/gobmp/pkg/flowspec"
 	"github.com/sbezverk/gobmp/pkg/ls"
+	"github.com/sbezverk/gobmp/pkg/mvpn"
+	"github.com/sbezverk/gobmp/pkg/rtc"
 	"github.com/sbezverk/gobmp/pkg/srpolicy"
+	"github.com/sbezverk/gobmp/pkg/vpls"
 )
 
 // MPNLRI defines a common interface methind for MP Reach and MP Unreach NLRIs
@@ -14,11 +17,16 @@ type MPNLRI interface {
 	GetNLRILU() (*base.MPNLRI, error)
 	GetNLRIUnicast() (*base.MPNLRI, error)
 	GetNLRIEVPN() (*evpn.Route, error)
+	GetNLRIVPLS() (*vpls.Route, error)
+	GetNLRIMVPN() (*mvpn.Route, error)
+	GetNLRIRTC() (*rtc.Route, error)
 	GetNLRIL3VPN() (*base.MPNLRI, error)
+	GetNLRIClassfulTransport() (*base.MPNLRI, error)
 	GetNLRI71() (*ls.NLRI71, error)
 	GetNLRI73() (*srpolicy.NLRI73, error)
 	GetFlowspecNLRI() (*flowspec.NLRI, error)
 	GetNextHop() string
+	GetNextHopLinkLocal() string
 	IsIPv6NLRI() bool
 	IsNextHopIPv6() bool
 }
@@ -46,6 +54,18 @@ func getNLRIMessageType(afi uint16, safi uint8) int {
 	// 2 IP (IP version 6) : 128 MPLS-labeled VPN address
 	case afi == 2 && safi == 128:
 		return 19
+	// 1 IP (IP version 4) or 2 IP (IP version 6) : 5 MCAST-VPN
+	case (afi == 1 || afi == 2) && safi == 5:
+		return 28
+	// 1 IP (IP version 4) or 2 IP (IP version 6) : 129 Multicast for BGP/MPLS IP VPNs
+	case (afi == 1 || afi == 2) && safi == 129:
+		return 29
+	// 1 IP (IP version 4) or 2 IP (IP version 6) : 76 Classful Transport
+	case (afi == 1 || afi == 2) && safi == 76:
+		return 31
+	// 1 IP (IP version 4) : 132 Route Target constrains
+	case afi == 1 && safi == 132:
+		return 30
 	// AFI of 25 (L2VPN) and a SAFI of 65 (VPLS)
 	case afi == 25 && safi == 65:
 		return 23
@@ -66,10 +86,10 @@ func getNLRIMessageType(afi uint16, safi uint8) int {
 		return 27
 		// AFI 1 and SAFI 134 FlowSpec VPNv4
 	case afi == 1 && safi == 134:
-		return 27
+		return 32
 		// AFI 2 and SAFI 134 FlowSpec VPNv6
 	case afi == 2 && safi == 134:
-		return 27
+		return 32
 	}
 
 	return 0
@@ -11,9 +11,12 @@ import (
 	"github.com/sbezverk/gobmp/pkg/flowspec"
 	"github.com/sbezverk/gobmp/pkg/l3vpn"
 	"github.com/sbezverk/gobmp/pkg/ls"
+	"github.com/sbezverk/gobmp/pkg/mvpn"
+	"github.com/sbezverk/gobmp/pkg/rtc"
 	"github.com/sbezverk/gobmp/pkg/srpolicy"
 	"github.com/sbezverk/gobmp/pkg/tools"
 	"github.com/sbezverk/gobmp/pkg/unicast"
+	"github.com/sbezverk/gobmp/pkg/vpls"
 )
 
 // MPReachNLRI defines an MP Reach NLRI object
@@ -26,6 +29,9 @@ type MPReachNLRI struct {
 	// When BGP update carries Prefix SID attribute 40, the processing of some AFI/SAFI NLRIs
 	// may differ from the standard processing.
 	SRv6 bool
+	// ExtendedNextHop is set when Extended Next Hop capability with IPv6 next hop is negotiated
+	// for the AFI/SAFI of NLRI, rfc8950
+	ExtendedNextHop bool
 }
 
 // GetAFISAFIType returns underlaying NLRI's type based on AFI/SAFI
@@ -40,7 +46,7 @@ func (mp *MPReachNLRI) IsIPv6NLRI() bool {
 
 // IsNextHopIPv6 return true if the next hop is IPv6 address, otherwise it returns flase
 func (mp *MPReachNLRI) IsNextHopIPv6() bool {
-	// https://tools.ietf.org/id/draft-mishra-bess-ipv4nlri-ipv6nh-use-cases-00.html#rfc.section.3
+	// IPv4 NLRI carry IPv6 next hop of 16 or 32 bytes, or 24 or 48 bytes for VPN-IPv4, rfc8950
 	switch mp.NextHopAddressLength {
 	case 16:
 		fallthrough
@@ -55,28 +61,71 @@ func (mp *MPReachNLRI) IsNextHopIPv6() bool {
 	}
 }
 
-// GetNextHop return a string representation of the next hop ip address.
-func (mp *MPReachNLRI) GetNextHop() string {
+// nextHops returns global and link local next hop addresses, nil is returned for an address which is not present.
+func (mp *MPReachNLRI) nextHops() (net.IP, net.IP, error) {
+	var global, linkLocal net.IP
+	b := mp.NextHopAddress
 	switch mp.NextHopAddressLength {
 	case 4:
 		// IPv4
-		return net.IP(mp.NextHopAddress).To4().String()
+		global = net.IP(b).To4()
 	case 12:
 		// RD (8 bytes) + IPv4
-		return net.IP(mp.NextHopAddress[8:]).To4().String()
+		global = net.IP(b[8:]).To4()
 	case 16:
-		// IPv6
-		return net.IP(mp.NextHopAddress).To16().String()
+		// IPv6, a link local address is sent alone when no global address exists
+		global = net.IP(b)
 	case 24:
 		// RD (8 bytes) + IPv6
-		return net.IP(mp.NextHopAddress[8:]).To16().String()
+		global = net.IP(b[8:])
 	case 32:
 		// IPv6 + Link Local IPv6
 		// https://tools.ietf.org/html/rfc2545#section-3
-		return net.IP(mp.NextHopAddress[:16]).To16().String() + "," + net.IP(mp.NextHopAddress[16:]).To16().String()
+		global = net.IP(b[:16])
+		linkLocal = net.IP(b[16:])
+	case 48:
+		// RD (8 bytes) + IPv6 + RD (8 bytes) + Link Local IPv6, rfc8950
+		global = net.IP(b[8:24])
+		linkLocal = net.IP(b[32:])
+	default:
+		return nil, nil, fmt.Errorf("invalid next hop length %d", mp.NextHopAddressLength)
+	}
+	if len(global) == net.IPv6len {
+		if global.IsLinkLocalUnicast() && linkLocal == nil {
+			linkLocal = global
+		}
+		// Link local only next hop is sent with unspecified or link local global address
+		if global.IsUnspecified() || global.IsLinkLocalUnicast() {
+			global = nil
+		}
 	}
 
-	return "invalid"
+	return global, linkLocal, nil
+}
+
+// GetNextHop return a string representation of the global next hop ip address, when only link local
+// next hop is present, link local next hop is returned.
+func (mp *MPReachNLRI) GetNextHop() string {
+	global, linkLocal, err := mp.nextHops()
+	if err != nil {
+		return "invalid"
+	}
+	if global == nil {
+		return linkLocal.String()
+	}
+
+	return global.String()
+}
+
+// GetNextHopLinkLocal return a string representation of the link local next hop ip address,
+// empty string is returned if link local next hop is not present.
+func (mp *MPReachNLRI) GetNextHopLinkLocal() string {
+	_, linkLocal, err := mp.nextHops()
+	if err != nil || linkLocal == nil {
+		return ""
+	}
+
+	return linkLocal.String()
 }
 
 // GetNLRI71 check for presense of NLRI 71 in the NLRI 14 NLRI data and if exists, instantiate NLRI71 object
@@ -109,7 +158,23 @@ func (mp *MPReachNLRI) GetNLRI73() (*srpolicy.NLRI73, error) {
 
 // GetNLRIL3VPN check for presense of NLRI L3VPN AFI 1 and SAFI 128 in the NLRI 14 NLRI data and if exists, instantiate L3VPN object
 func (mp *MPReachNLRI) GetNLRIL3VPN() (*base.MPNLRI, error) {
-	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 128 {
+	// SAFI 129 VPN multicast routes share the encoding with SAFI 128
+	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && (mp.SubAddressFamilyID == 128 || mp.SubAddressFamilyID == 129) {
+		nlri, err := l3vpn.UnmarshalL3VPNNLRI(mp.NLRI, mp.SRv6)
+		if err != nil {
+			return nil, err
+		}
+		return nlri, nil
+	}
+
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
+
+// GetNLRIClassfulTransport check for presense of NLRI Classful Transport AFI 1 or 2 and SAFI 76 in the NLRI 14 NLRI data and if exists, instantiate Classful Transport object
+func (mp *MPReachNLRI) GetNLRIClassfulTransport() (*base.MPNLRI, error) {
+	// Classful Transport routes share the encoding with SAFI 128
+	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 76 {
 		nlri, err := l3vpn.UnmarshalL3VPNNLRI(mp.NLRI, mp.SRv6)
 		if err != nil {
 			return nil, err
@@ -135,6 +200,48 @@ func (mp *MPReachNLRI) GetNLRIEVPN() (*evpn.Route, error) {
 	return nil, fmt.Errorf("not found")
 }
 
+// GetNLRIVPLS check for presense of NLRI VPLS AFI 25 and SAFI 65 in the NLRI 14 NLRI data and if exists, instantiate VPLS object
+func (mp *MPReachNLRI) GetNLRIVPLS() (*vpls.Route, error) {
+	if mp.AddressFamilyID == 25 && mp.SubAddressFamilyID == 65 {
+		route, err := vpls.UnmarshalVPLSNLRI(mp.NLRI)
+		if err != nil {
+			return nil, err
+		}
+		return route, nil
+	}
+
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
+
+// GetNLRIMVPN check for presense of NLRI MCAST-VPN AFI 1 or 2 and SAFI 5 in the NLRI 14 NLRI data and if exists, instantiate MVPN object
+func (mp *MPReachNLRI) GetNLRIMVPN() (*mvpn.Route, error) {
+	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 5 {
+		route, err := mvpn.UnmarshalMVPNNLRI(mp.NLRI)
+		if err != nil {
+			return nil, err
+		}
+		return route, nil
+	}
+
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
+
+// GetNLRIRTC check for presense of NLRI Route Target Constraint AFI 1 and SAFI 132 in the NLRI 14 NLRI data and if exists, instantiate RTC object
+func (mp *MPReachNLRI) GetNLRIRTC() (*rtc.Route, error) {
+	if mp.AddressFamilyID == 1 && mp.SubAddressFamilyID == 132 {
+		route, err := rtc.UnmarshalRTCNLRI(mp.NLRI)
+		if err != nil {
+			return nil, err
+		}
+		return route, nil
+	}
+
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
+
 // GetNLRIUnicast check for presense of NLRI EVPN AFI 1 or 2  and SAFI 1 in the NLRI 14 NLRI data and if exists, instantiate Unicast object
 func (mp *MPReachNLRI) GetNLRIUnicast() (*base.MPNLRI, error) {
 	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 1 {
@@ -163,11 +270,20 @@ func (mp *MPReachNLRI) GetNLRILU() (*base.MPNLRI, error) {
 	return nil, fmt.Errorf("not found")
 }
 
-// GetFlowspecNLRI checks for presense of NLRI 133 IPv4 Flowspec in the NLRI 14 NLRI data and if exists, instantiate NLRI object
+// GetFlowspecNLRI checks for presense of NLRI 133 Flowspec or NLRI 134 VPN Flowspec in the NLRI 14 NLRI data and if exists, instantiate NLRI object
 func (mp *MPReachNLRI) GetFlowspecNLRI() (*flowspec.NLRI, error) {
 	if mp.SubAddressFamilyID == 133 {
+		if mp.AddressFamilyID == 2 {
+			return flowspec.UnmarshalIPv6FlowspecNLRI(mp.NLRI)
+		}
 		return flowspec.UnmarshalFlowspecNLRI(mp.NLRI)
 	}
+	if mp.SubAddressFamilyID == 134 {
+		if mp.AddressFamilyID == 2 {
+			return flowspec.UnmarshalIPv6VPNFlowspecNLRI(mp.NLRI)
+		}
+		return flowspec.UnmarshalVPNFlowspecNLRI(mp.NLRI)
+	}
 
 	// TODO return new type of errors to be able to check for the code
 	return nil, fmt.Errorf("not found")
@@ -175,11 +291,17 @@ func (mp *MPReachNLRI) GetFlowspecNLRI() (*flowspec.NLRI, error) {
 
 // UnmarshalMPReachNLRI builds MP Reach NLRI attributes
 func UnmarshalMPReachNLRI(b []byte, srv6 bool) (MPNLRI, error) {
+	return unmarshalMPReachNLRI(b, srv6, nil)
+}
+
+// unmarshalMPReachNLRI builds MP Reach NLRI attributes, caps are capabilities negotiated by the peers,
+// when nil, Extended Next Hop capability is not known.
+func unmarshalMPReachNLRI(b []byte, srv6 bool, caps *NegotiatedCapabilities) (MPNLRI, error) {
 	if glog.V(6) {
 		glog.Infof("MPReachNLRI Raw: %s", tools.MessageHex(b))
 	}
-	if len(b) == 0 {
-		return nil, fmt.Errorf("NLRI length is 0")
+	if len(b) < 4 {
+		return nil, fmt.Errorf("NLRI length is %d", len(b))
 	}
 	mp := MPReachNLRI{
 		SRv6: srv6,
@@ -191,6 +313,10 @@ func UnmarshalMPReachNLRI(b []byte, srv6 bool) (MPNLRI, error) {
 	p++
 	mp.NextHopAddressLength = uint8(b[p])
 	p++
+	// Next Hop is followed by the reserved byte
+	if p+int(mp.NextHopAddressLength)+1 > len(b) {
+		return nil, fmt.Errorf("invalid next hop length %d", mp.NextHopAddressLength)
+	}
 	mp.NextHopAddress = make([]byte, mp.NextHopAddressLength)
 	copy(mp.NextHopAddress, b[p:p+int(mp.NextHopAddressLength)])
 	p += int(mp.NextHopAddressLength)
@@ -198,6 +324,12 @@ func UnmarshalMPReachNLRI(b []byte, srv6 bool) (MPNLRI, error) {
 	p++
 	mp.NLRI = make([]byte, len(b[p:]))
 	copy(mp.NLRI, b[p:])
+	if caps != nil {
+		mp.ExtendedNextHop = caps.HasExtendedNextHop(mp.AddressFamilyID, mp.SubAddressFamilyID, 2)
+		if mp.AddressFamilyID == 1 && mp.IsNextHopIPv6() && !mp.ExtendedNextHop {
+			glog.Warningf("IPv4 NLRI of SAFI %d carries IPv6 next hop without negotiated Extended Next Hop capability", mp.SubAddressFamilyID)
+		}
+	}
 
 	return &mp, nil
 }
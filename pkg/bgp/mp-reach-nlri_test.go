@@ -0,0 +1,267 @@
+package bgp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMPReachNLRINextHop(t *testing.T) {
+	enh := &NegotiatedCapabilities{
+		ExtendedNextHop: []*ExtendedNextHopTuple{
+			{NLRIAFI: 1, NLRISAFI: 1, NextHopAFI: 2},
+			{NLRIAFI: 1, NLRISAFI: 128, NextHopAFI: 2},
+		},
+	}
+	tests := []struct {
+		name            string
+		input           []byte
+		caps            *NegotiatedCapabilities
+		ipv6            bool
+		nextHop         string
+		linkLocal       string
+		extendedNextHop bool
+	}{
+		{
+			name:    "ipv4 unicast with ipv4 next hop",
+			input:   []byte{0x00, 0x01, 0x01, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x18, 0x0a, 0x00, 0x00},
+			caps:    enh,
+			ipv6:    false,
+			nextHop: "10.0.0.1",
+			// Capability is negotiated, but IPv4 next hop is still allowed
+			extendedNextHop: true,
+		},
+		{
+			name:            "ipv4 unicast with ipv6 global next hop",
+			input:           []byte{0x00, 0x01, 0x01, 0x10, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x18, 0x0a, 0x00, 0x00},
+			caps:            enh,
+			ipv6:            true,
+			nextHop:         "2001:db8::1",
+			extendedNextHop: true,
+		},
+		{
+			name:            "ipv4 unicast with ipv6 global and link local next hop",
+			input:           []byte{0x00, 0x01, 0x01, 0x20, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0xfe, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x18, 0x0a, 0x00, 0x00},
+			caps:            enh,
+			ipv6:            true,
+			nextHop:         "2001:db8::1",
+			linkLocal:       "fe80::1",
+			extendedNextHop: true,
+		},
+		{
+			name:            "ipv4 unicast with ipv6 link local only next hop",
+			input:           []byte{0x00, 0x01, 0x01, 0x10, 0xfe, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x18, 0x0a, 0x00, 0x00},
+			caps:            enh,
+			ipv6:            true,
+			nextHop:         "fe80::1",
+			linkLocal:       "fe80::1",
+			extendedNextHop: true,
+		},
+		{
+			name:            "ipv4 unicast with unspecified global and link local next hop",
+			input:           []byte{0x00, 0x01, 0x01, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xfe, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x18, 0x0a, 0x00, 0x00},
+			caps:            enh,
+			ipv6:            true,
+			nextHop:         "fe80::1",
+			linkLocal:       "fe80::1",
+			extendedNextHop: true,
+		},
+		{
+			name:    "ipv4 unicast with ipv6 next hop and unknown capabilities",
+			input:   []byte{0x00, 0x01, 0x01, 0x10, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x18, 0x0a, 0x00, 0x00},
+			ipv6:    true,
+			nextHop: "2001:db8::1",
+		},
+		{
+			name:            "vpn-ipv4 with ipv4 next hop",
+			input:           []byte{0x00, 0x01, 0x80, 0x0c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x01, 0x00},
+			caps:            enh,
+			ipv6:            false,
+			nextHop:         "10.0.0.1",
+			extendedNextHop: true,
+		},
+		{
+			name:            "vpn-ipv4 with 24 bytes ipv6 next hop",
+			input:           []byte{0x00, 0x01, 0x80, 0x18, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00},
+			caps:            enh,
+			ipv6:            true,
+			nextHop:         "2001:db8::1",
+			extendedNextHop: true,
+		},
+		{
+			name:            "vpn-ipv4 with 48 bytes ipv6 next hop",
+			input:           []byte{0x00, 0x01, 0x80, 0x30, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xfe, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00},
+			caps:            enh,
+			ipv6:            true,
+			nextHop:         "2001:db8::1",
+			linkLocal:       "fe80::1",
+			extendedNextHop: true,
+		},
+		{
+			name:    "vpn-ipv4 with 24 bytes ipv6 next hop without capability",
+			input:   []byte{0x00, 0x01, 0x80, 0x18, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00},
+			caps:    &NegotiatedCapabilities{},
+			ipv6:    true,
+			nextHop: "2001:db8::1",
+		},
+		{
+			name:    "invalid next hop length",
+			input:   []byte{0x00, 0x01, 0x01, 0x05, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x00},
+			ipv6:    false,
+			nextHop: "invalid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nlri, err := unmarshalMPReachNLRI(tt.input, false, tt.caps)
+			if err != nil {
+				t.Fatalf("failed with error: %+v", err)
+			}
+			mp := nlri.(*MPReachNLRI)
+			if got := mp.IsNextHopIPv6(); got != tt.ipv6 {
+				t.Errorf("expected IPv6 next hop %t got %t", tt.ipv6, got)
+			}
+			if got := mp.GetNextHop(); got != tt.nextHop {
+				t.Errorf("expected next hop %s got %s", tt.nextHop, got)
+			}
+			if got := mp.GetNextHopLinkLocal(); got != tt.linkLocal {
+				t.Errorf("expected link local next hop %s got %s", tt.linkLocal, got)
+			}
+			if mp.ExtendedNextHop != tt.extendedNextHop {
+				t.Errorf("expected extended next hop %t got %t", tt.extendedNextHop, mp.ExtendedNextHop)
+			}
+		})
+	}
+}
+
+func TestUpdateMPReachNLRICapabilities(t *testing.T) {
+	// Update with MP_REACH_NLRI of IPv4 Unicast carrying IPv6 next hop 2001:db8::1
+	input := []byte{0x00, 0x00, 0x00, 0x1c, 0x80, 0x0e, 0x19,
+		0x00, 0x01, 0x01, 0x10, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x18, 0x0a, 0x00, 0x00}
+	tests := []struct {
+		name            string
+		caps            *NegotiatedCapabilities
+		extendedNextHop bool
+	}{
+		{
+			name: "extended next hop negotiated",
+			caps: &NegotiatedCapabilities{
+				AS4:             true,
+				ExtendedNextHop: []*ExtendedNextHopTuple{{NLRIAFI: 1, NLRISAFI: 1, NextHopAFI: 2}},
+			},
+			extendedNextHop: true,
+		},
+		{
+			name: "extended next hop not negotiated",
+			caps: &NegotiatedCapabilities{AS4: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := UnmarshalBGPUpdate(input, tt.caps)
+			if err != nil {
+				t.Fatalf("failed with error: %+v", err)
+			}
+			if len(u.PathAttributes) != 1 {
+				t.Fatalf("expected 1 attribute but got %d", len(u.PathAttributes))
+			}
+			nlri, err := u.UnmarshalMPReachNLRI(u.PathAttributes[0].Attribute)
+			if err != nil {
+				t.Fatalf("failed with error: %+v", err)
+			}
+			if got := nlri.(*MPReachNLRI).ExtendedNextHop; got != tt.extendedNextHop {
+				t.Errorf("expected extended next hop %t got %t", tt.extendedNextHop, got)
+			}
+		})
+	}
+}
+
+func TestGetNLRIL3VPN(t *testing.T) {
+	// Labeled VPN route 2001:db8::/32 with RD 65000:100 and label 100
+	vpnv6 := []byte{0x78, 0x00, 0x06, 0x41, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x20, 0x01, 0x0d, 0xb8}
+	// Labeled VPN route 10.0.0.0/24 with RD 65000:100 and label 100
+	vpnv4 := []byte{0x70, 0x00, 0x06, 0x41, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x0a, 0x00, 0x00}
+	reach := func(afi, safi byte, nh []byte, nlri []byte) []byte {
+		b := append([]byte{0x00, afi, safi, byte(len(nh))}, nh...)
+		return append(append(b, 0x00), nlri...)
+	}
+	unreach := func(afi, safi byte, nlri []byte) []byte {
+		return append([]byte{0x00, afi, safi}, nlri...)
+	}
+	nh4 := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x01}
+	nh6 := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}
+	tests := []struct {
+		name   string
+		reach  bool
+		input  []byte
+		prefix []byte
+		length uint8
+	}{
+		{
+			name:   "vpnv6 unicast reach",
+			reach:  true,
+			input:  reach(2, 128, nh6, vpnv6),
+			prefix: []byte{0x20, 0x01, 0x0d, 0xb8},
+			length: 32,
+		},
+		{
+			name:   "vpnv6 unicast unreach",
+			input:  unreach(2, 128, vpnv6),
+			prefix: []byte{0x20, 0x01, 0x0d, 0xb8},
+			length: 32,
+		},
+		{
+			name:   "vpnv4 multicast reach",
+			reach:  true,
+			input:  reach(1, 129, nh4, vpnv4),
+			prefix: []byte{0x0a, 0x00, 0x00},
+			length: 24,
+		},
+		{
+			name:   "vpnv4 multicast unreach",
+			input:  unreach(1, 129, vpnv4),
+			prefix: []byte{0x0a, 0x00, 0x00},
+			length: 24,
+		},
+		{
+			name:   "vpnv6 multicast reach",
+			reach:  true,
+			input:  reach(2, 129, nh6, vpnv6),
+			prefix: []byte{0x20, 0x01, 0x0d, 0xb8},
+			length: 32,
+		},
+		{
+			name:   "vpnv6 multicast unreach",
+			input:  unreach(2, 129, vpnv6),
+			prefix: []byte{0x20, 0x01, 0x0d, 0xb8},
+			length: 32,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mp MPNLRI
+			var err error
+			if tt.reach {
+				mp, err = UnmarshalMPReachNLRI(tt.input, false)
+			} else {
+				mp, err = UnmarshalMPUnReachNLRI(tt.input)
+			}
+			if err != nil {
+				t.Fatalf("failed with error: %+v", err)
+			}
+			nlri, err := mp.GetNLRIL3VPN()
+			if err != nil {
+				t.Fatalf("failed to get L3VPN NLRI with error: %+v", err)
+			}
+			if len(nlri.NLRI) != 1 {
+				t.Fatalf("expected 1 route but got %d", len(nlri.NLRI))
+			}
+			r := nlri.NLRI[0]
+			if r.Length != tt.length || !reflect.DeepEqual(r.Prefix, tt.prefix) {
+				t.Errorf("expected prefix %v/%d but got %v/%d", tt.prefix, tt.length, r.Prefix, r.Length)
+			}
+			if r.RD == nil || r.RD.String() != "65000:100" {
+				t.Errorf("expected rd 65000:100 but got %+v", r.RD)
+			}
+		})
+	}
+}
@@ -10,9 +10,12 @@ import (
 	"github.com/sbezverk/gobmp/pkg/flowspec"
 	"github.com/sbezverk/gobmp/pkg/l3vpn"
 	"github.com/sbezverk/gobmp/pkg/ls"
+	"github.com/sbezverk/gobmp/pkg/mvpn"
+	"github.com/sbezverk/gobmp/pkg/rtc"
 	"github.com/sbezverk/gobmp/pkg/srpolicy"
 	"github.com/sbezverk/gobmp/pkg/tools"
 	"github.com/sbezverk/gobmp/pkg/unicast"
+	"github.com/sbezverk/gobmp/pkg/vpls"
 )
 
 // MPUnReachNLRI defines an MP UnReach NLRI object
@@ -37,6 +40,11 @@ func (mp *MPUnReachNLRI) GetNextHop() string {
 	return ""
 }
 
+// GetNextHopLinkLocal return a string representation of the link local next hop ip address.
+func (mp *MPUnReachNLRI) GetNextHopLinkLocal() string {
+	return ""
+}
+
 // IsNextHopIPv6 return true if the next hop is IPv6 address, otherwise it returns flase.
 // in case of MP_UNREACH_NLRI there is no Next Hope field and this func should not be used.
 func (mp *MPUnReachNLRI) IsNextHopIPv6() bool {
@@ -73,7 +81,23 @@ func (mp *MPUnReachNLRI) GetNLRI73() (*srpolicy.NLRI73, error) {
 
 // GetNLRIL3VPN check for presense of NLRI L3VPN AFI 1 and SAFI 128 in the NLRI 14 NLRI data and if exists, instantiate L3VPN object
 func (mp *MPUnReachNLRI) GetNLRIL3VPN() (*base.MPNLRI, error) {
-	if mp.AddressFamilyID == 1 && mp.SubAddressFamilyID == 128 {
+	// SAFI 129 VPN multicast routes share the encoding with SAFI 128
+	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && (mp.SubAddressFamilyID == 128 || mp.SubAddressFamilyID == 129) {
+		nlri, err := l3vpn.UnmarshalL3VPNNLRI(mp.WithdrawnRoutes)
+		if err != nil {
+			return nil, err
+		}
+		return nlri, nil
+	}
+
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
+
+// GetNLRIClassfulTransport check for presense of NLRI Classful Transport AFI 1 or 2 and SAFI 76 in the NLRI 15 NLRI data and if exists, instantiate Classful Transport object
+func (mp *MPUnReachNLRI) GetNLRIClassfulTransport() (*base.MPNLRI, error) {
+	// Classful Transport routes share the encoding with SAFI 128
+	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 76 {
 		nlri, err := l3vpn.UnmarshalL3VPNNLRI(mp.WithdrawnRoutes)
 		if err != nil {
 			return nil, err
@@ -99,6 +123,48 @@ func (mp *MPUnReachNLRI) GetNLRIEVPN() (*evpn.Route, error) {
 	return nil, fmt.Errorf("not found")
 }
 
+// GetNLRIVPLS check for presense of NLRI VPLS AFI 25 and SAFI 65 in the NLRI 15 NLRI data and if exists, instantiate VPLS object
+func (mp *MPUnReachNLRI) GetNLRIVPLS() (*vpls.Route, error) {
+	if mp.AddressFamilyID == 25 && mp.SubAddressFamilyID == 65 {
+		route, err := vpls.UnmarshalVPLSNLRI(mp.WithdrawnRoutes)
+		if err != nil {
+			return nil, err
+		}
+		return route, nil
+	}
+
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
+
+// GetNLRIMVPN check for presense of NLRI MCAST-VPN AFI 1 or 2 and SAFI 5 in the NLRI 15 NLRI data and if exists, instantiate MVPN object
+func (mp *MPUnReachNLRI) GetNLRIMVPN() (*mvpn.Route, error) {
+	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 5 {
+		route, err := mvpn.UnmarshalMVPNNLRI(mp.WithdrawnRoutes)
+		if err != nil {
+			return nil, err
+		}
+		return route, nil
+	}
+
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
+
+// GetNLRIRTC check for presense of NLRI Route Target Constraint AFI 1 and SAFI 132 in the NLRI 15 NLRI data and if exists, instantiate RTC object
+func (mp *MPUnReachNLRI) GetNLRIRTC() (*rtc.Route, error) {
+	if mp.AddressFamilyID == 1 && mp.SubAddressFamilyID == 132 {
+		route, err := rtc.UnmarshalRTCNLRI(mp.WithdrawnRoutes)
+		if err != nil {
+			return nil, err
+		}
+		return route, nil
+	}
+
+	// TODO return new type of errors to be able to check for the code
+	return nil, fmt.Errorf("not found")
+}
+
 // GetNLRIUnicast check for presense of NLRI EVPN AFI 1 or 2  and SAFI 1 in the NLRI 14 NLRI data and if exists, instantiate Unicast object
 func (mp *MPUnReachNLRI) GetNLRIUnicast() (*base.MPNLRI, error) {
 	if (mp.AddressFamilyID == 1 || mp.AddressFamilyID == 2) && mp.SubAddressFamilyID == 1 {
@@ -127,11 +193,20 @@ func (mp *MPUnReachNLRI) GetNLRILU() (*base.MPNLRI, error) {
 	return nil, fmt.Errorf("not found")
 }
 
-// GetFlowspecNLRI checks for presense of NLRI 133 IPv4 Flowspec in the NLRI 15 NLRI data and if exists, instantiate NLRI object
+// GetFlowspecNLRI checks for presense of NLRI 133 Flowspec or NLRI 134 VPN Flowspec in the NLRI 15 NLRI data and if exists, instantiate NLRI object
 func (mp *MPUnReachNLRI) GetFlowspecNLRI() (*flowspec.NLRI, error) {
 	if mp.SubAddressFamilyID == 133 {
+		if mp.AddressFamilyID == 2 {
+			return flowspec.UnmarshalIPv6FlowspecNLRI(mp.WithdrawnRoutes)
+		}
 		return flowspec.UnmarshalFlowspecNLRI(mp.WithdrawnRoutes)
 	}
+	if mp.SubAddressFamilyID == 134 {
+		if mp.AddressFamilyID == 2 {
+			return flowspec.UnmarshalIPv6VPNFlowspecNLRI(mp.WithdrawnRoutes)
+		}
+		return flowspec.UnmarshalVPNFlowspecNLRI(mp.WithdrawnRoutes)
+	}
 
 	// TODO return new type of errors to be able to check for the code
 	return nil, fmt.Errorf("not found")
@@ -142,8 +217,8 @@ func UnmarshalMPUnReachNLRI(b []byte) (MPNLRI, error) {
 	if glog.V(6) {
 		glog.Infof("MPUnReachNLRI Raw: %s", tools.MessageHex(b))
 	}
-	if len(b) == 0 {
-		return nil, fmt.Errorf("NLRI length is 0")
+	if len(b) < 3 {
+		return nil, fmt.Errorf("NLRI length is %d", len(b))
 	}
 	mp := MPUnReachNLRI{}
 	p := 0
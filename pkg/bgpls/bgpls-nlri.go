@@ -705,18 +705,86 @@ func (ls *NLRI) GetSRAdjacencySID() ([]*sr.AdjacencySIDTLV, error) {
 	return adjs, nil
 }
 
-// UnmarshalBGPLSNLRI builds Prefix NLRI object
+// GetSRBindingSID returns SR Binding SID of SR Policy Candidate Path
+func (ls *NLRI) GetSRBindingSID() (*SRBindingSID, error) {
+	for _, tlv := range ls.LS {
+		if tlv.Type != BindingSIDType {
+			continue
+		}
+		return UnmarshalSRBindingSID(tlv.Value)
+	}
+
+	return nil, nil
+}
+
+// GetSRCandidatePathState returns operational state of SR Policy Candidate Path
+func (ls *NLRI) GetSRCandidatePathState() (*SRCandidatePathState, error) {
+	for _, tlv := range ls.LS {
+		if tlv.Type != SRCandidatePathStateType {
+			continue
+		}
+		return UnmarshalSRCandidatePathState(tlv.Value)
+	}
+
+	return nil, nil
+}
+
+// GetSRCandidatePathName returns symbolic name of SR Policy Candidate Path
+func (ls *NLRI) GetSRCandidatePathName() string {
+	for _, tlv := range ls.LS {
+		if tlv.Type != SRCandidatePathNameType {
+			continue
+		}
+		n, _ := UnmarshalSRCandidatePathName(tlv.Value)
+		return n.SymbolicName
+	}
+
+	return ""
+}
+
+// GetSRCandidatePathConstraints returns constraints of SR Policy Candidate Path
+func (ls *NLRI) GetSRCandidatePathConstraints() (*SRCandidatePathConstraints, error) {
+	for _, tlv := range ls.LS {
+		if tlv.Type != SRCandidatePathConstraintsType {
+			continue
+		}
+		return UnmarshalSRCandidatePathConstraints(tlv.Value)
+	}
+
+	return nil, nil
+}
+
+// GetSRSegmentList returns all Segment Lists of SR Policy Candidate Path
+func (ls *NLRI) GetSRSegmentList() ([]*SRSegmentList, error) {
+	sls := make([]*SRSegmentList, 0)
+	// Candidate Path carries a Segment List TLV per SID-List
+	for _, tlv := range ls.LS {
+		if tlv.Type != SRSegmentListType {
+			continue
+		}
+		sl, err := UnmarshalSRSegmentList(tlv.Value)
+		if err != nil {
+			return nil, err
+		}
+		sls = append(sls, sl)
+	}
+
+	return sls, nil
+}
+
+// UnmarshalBGPLSNLRI builds BGP-LS attribute object, malformed attribute is reported with
+// base.DecodeError requiring the attribute to be discarded.
 func UnmarshalBGPLSNLRI(b []byte) (*NLRI, error) {
 	if glog.V(6) {
 		glog.Infof("BGPLSNLRI Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) == 0 {
-		return nil, fmt.Errorf("NLRI length is 0")
+		return nil, &base.DecodeError{AttributeType: 29, Reason: "attribute length is 0", Action: base.AttributeDiscard}
 	}
 	bgpls := NLRI{}
 	ls, err := UnmarshalBGPLSTLV(b)
 	if err != nil {
-		return nil, err
+		return nil, &base.DecodeError{AttributeType: 29, Reason: err.Error(), Action: base.AttributeDiscard}
 	}
 	bgpls.LS = ls
 
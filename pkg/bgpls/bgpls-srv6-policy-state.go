@@ -68,6 +68,9 @@ func UnmarshalSRBindingSID(b []byte) (*SRBindingSID, error) {
 	// TODO (sbezverk) the beahaviour for B FLag
 	if bsid.FlagD {
 		// BSID is ipv6 address
+		if p+16 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to decode SR Binding SID TLV")
+		}
 		bsid.BSID, err = UnmarshalSRv6SID(b[p : p+16])
 		if err != nil {
 			return nil, err
@@ -232,9 +235,13 @@ func UnmarshalSRCandidatePathConstraintsSubTLV(b []byte) (map[uint16]SRCandidate
 	s := make(map[uint16]SRCandidatePathConstraintsSubTLV)
 	p := 0
 	for p < len(b) {
+		if p+4 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to decode SR Candidate Path Constraints Sub TLV")
+		}
 		t := binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
 		l := binary.BigEndian.Uint16(b[p : p+2])
+		p += 2
 		if p+int(l) > len(b) {
 			return nil, fmt.Errorf("not enough bytes to decode SR Candidate Path Constraints Sub TLV")
 		}
@@ -559,7 +566,11 @@ type SRSegmentList struct {
 	MTID   uint16                         `json:"mtid"`
 	Algo   uint8                          `json:"algo"`
 	Weight uint32                         `json:"weight"`
-	SubTLV map[uint16]SRSegmentListSubTLV `json:"subtlv,omitempty"`
+	SubTLV map[uint16]SRSegmentListSubTLV `json:"-"`
+	// Segments and Metrics keep all Sub TLVs in the order they were advertised,
+	// SubTLV map holds only the last Sub TLV of each type.
+	Segments []*SRSegment           `json:"segments,omitempty"`
+	Metrics  []*SRSegmentListMetric `json:"metrics,omitempty"`
 }
 
 // UnmarshalSRSegmentList instantiates SRSegmentList from a slice of bytes
@@ -596,29 +607,62 @@ func UnmarshalSRSegmentList(b []byte) (*SRSegmentList, error) {
 	if p+4 > len(b) {
 		return s, nil
 	}
-	ss, err := UnmarshalSRSegmentListSubTLV(b[p:])
+	ss, err := unmarshalSRSegmentListSubTLVs(b[p:])
 	if err != nil {
 		return nil, err
 	}
-	s.SubTLV = ss
+	s.SubTLV = make(map[uint16]SRSegmentListSubTLV)
+	for _, stlv := range ss {
+		switch v := stlv.(type) {
+		case *SRSegment:
+			s.SubTLV[SRSegmentType] = v
+			s.Segments = append(s.Segments, v)
+		case *SRSegmentListMetric:
+			s.SubTLV[SRSegmentListMetricType] = v
+			s.Metrics = append(s.Metrics, v)
+		}
+	}
 
 	return s, nil
 }
 
 // UnmarshalSRSegmentListSubTLV instantiates a map of SR Segment List Sub TLVs from a slice of bytes
 func UnmarshalSRSegmentListSubTLV(b []byte) (map[uint16]SRSegmentListSubTLV, error) {
+	ss, err := unmarshalSRSegmentListSubTLVs(b)
+	if err != nil {
+		return nil, err
+	}
+	s := make(map[uint16]SRSegmentListSubTLV)
+	for _, stlv := range ss {
+		switch stlv.(type) {
+		case *SRSegment:
+			s[SRSegmentType] = stlv
+		case *SRSegmentListMetric:
+			s[SRSegmentListMetricType] = stlv
+		}
+	}
+
+	return s, nil
+}
+
+// unmarshalSRSegmentListSubTLVs returns SR Segment List Sub TLVs in the order they are found in a slice of bytes
+func unmarshalSRSegmentListSubTLVs(b []byte) ([]SRSegmentListSubTLV, error) {
 	if glog.V(6) {
 		glog.Infof("SR Segment List Sub TLV Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) < 4 {
 		return nil, fmt.Errorf("not enough bytes to decode SR Segment List Sub TLV")
 	}
-	s := make(map[uint16]SRSegmentListSubTLV)
+	s := make([]SRSegmentListSubTLV, 0)
 	p := 0
 	for p < len(b) {
+		if p+4 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to decode SR Segment List Sub TLV")
+		}
 		t := binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
 		l := binary.BigEndian.Uint16(b[p : p+2])
+		p += 2
 		if p+int(l) > len(b) {
 			return nil, fmt.Errorf("not enough bytes to decode SR Segment List Sub TLV")
 		}
@@ -628,13 +672,13 @@ func UnmarshalSRSegmentListSubTLV(b []byte) (map[uint16]SRSegmentListSubTLV, err
 			if err != nil {
 				return nil, err
 			}
-			s[SRSegmentType] = stlv
+			s = append(s, stlv)
 		case SRSegmentListMetricType:
 			stlv, err := UnmarshalSRSegmentListMetric(b[p : p+int(l)])
 			if err != nil {
 				return nil, err
 			}
-			s[SRSegmentListMetricType] = stlv
+			s = append(s, stlv)
 		}
 		p += int(l)
 	}
@@ -854,9 +898,13 @@ func UnmarshalSRSegmentSubTLV(b []byte) (map[uint16]SRSegmentSubTLV, error) {
 	s := make(map[uint16]SRSegmentSubTLV)
 	p := 0
 	for p < len(b) {
+		if p+4 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to decode SR Segment Sub TLV")
+		}
 		t := binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
 		l := binary.BigEndian.Uint16(b[p : p+2])
+		p += 2
 		if p+int(l) > len(b) {
 			return nil, fmt.Errorf("not enough bytes to decode SR Segment Sub TLV")
 		}
@@ -906,6 +954,9 @@ func UnmarshalSRSegment(b []byte) (SRSegmentListSubTLV, error) {
 	case SegmentType1:
 		s.Segment = SegmentType1
 		if s.FlagS {
+			if p+4+4 > len(b) {
+				return nil, fmt.Errorf("not enough bytes to decode SR Segment Sub TLV")
+			}
 			s.SID, err = UnmarshalMPLSLabelSID(b[p+4 : p+4+4])
 			if err != nil {
 				return nil, err
@@ -914,6 +965,9 @@ func UnmarshalSRSegment(b []byte) (SRSegmentListSubTLV, error) {
 	case SegmentType2:
 		s.Segment = SegmentType2
 		if s.FlagS {
+			if p+4+16 > len(b) {
+				return nil, fmt.Errorf("not enough bytes to decode SR Segment Sub TLV")
+			}
 			s.SID, err = UnmarshalSRv6SID(b[p+4 : p+4+16])
 			if err != nil {
 				return nil, err
@@ -940,8 +994,12 @@ func UnmarshalSRSegment(b []byte) (SRSegmentListSubTLV, error) {
 	default:
 		return nil, fmt.Errorf("unknown segment type %d", t)
 	}
-	// Adjust pointer by 4 bytes (Segment Type, Reserved and 2 bytes of Flags) + length of SID
-	p += 4 + s.SID.Len()
+	// Adjust pointer by 4 bytes (Segment Type, Reserved and 2 bytes of Flags) + length of SID,
+	// SID is present only when Flag S is set
+	p += 4
+	if s.SID != nil {
+		p += s.SID.Len()
+	}
 	// Check if the descriptor flag is set, if true then process descriptor
 	if s.FlagA {
 		if p >= len(b) {
@@ -0,0 +1,90 @@
+package bgpls
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalSRSegmentList(t *testing.T) {
+	input := []byte{
+		// Flags, MTID, Algorithm and Weight 1
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+		// Segment Type 1, label 16001
+		0x04, 0xb6, 0x00, 0x08, 0x01, 0x00, 0x80, 0x00, 0x03, 0xe8, 0x10, 0x00,
+		// Segment Type 1, label 16002
+		0x04, 0xb6, 0x00, 0x08, 0x01, 0x00, 0x80, 0x00, 0x03, 0xe8, 0x20, 0x00,
+		// IGP Metric 30
+		0x04, 0xb7, 0x00, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x1e,
+	}
+	expect := &SRSegmentList{
+		Weight: 1,
+		Segments: []*SRSegment{
+			{
+				Segment: SegmentType1,
+				FlagS:   true,
+				SID:     &MPLSLabelSID{Label: 16001},
+			},
+			{
+				Segment: SegmentType1,
+				FlagS:   true,
+				SID:     &MPLSLabelSID{Label: 16002},
+			},
+		},
+		Metrics: []*SRSegmentListMetric{
+			{
+				Metric: SRMetricIGP,
+				Value:  30,
+			},
+		},
+	}
+	expect.SubTLV = map[uint16]SRSegmentListSubTLV{
+		SRSegmentType:           expect.Segments[1],
+		SRSegmentListMetricType: expect.Metrics[0],
+	}
+	result, err := UnmarshalSRSegmentList(input)
+	if err != nil {
+		t.Fatalf("supposed to succeed but failed with error: %+v", err)
+	}
+	if !reflect.DeepEqual(expect, result) {
+		t.Fatalf("expected %+v does not match computed %+v", expect, result)
+	}
+}
+
+func TestUnmarshalSRCandidatePathConstraintsSubTLV(t *testing.T) {
+	input := []byte{
+		// Bandwidth Constraint 1000
+		0x04, 0xba, 0x00, 0x04, 0x00, 0x00, 0x03, 0xe8,
+		// Disjoint Group Constraint, S and N requested, group 100
+		0x04, 0xbb, 0x00, 0x08, 0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x64,
+	}
+	expect := map[uint16]SRCandidatePathConstraintsSubTLV{
+		SRBandwidthConstraintType: &SRBandwidthConstraint{Bandwidth: 1000},
+		SRDisjointGroupConstraintType: &SRDisjointGroupConstraint{
+			RequestFlagS:    true,
+			RequestFlagN:    true,
+			DisjointGroupID: 100,
+		},
+	}
+	result, err := UnmarshalSRCandidatePathConstraintsSubTLV(input)
+	if err != nil {
+		t.Fatalf("supposed to succeed but failed with error: %+v", err)
+	}
+	if !reflect.DeepEqual(expect, result) {
+		t.Fatalf("expected %+v does not match computed %+v", expect, result)
+	}
+}
+
+func TestUnmarshalSRSegmentSubTLV(t *testing.T) {
+	// Two Sub TLVs of unassigned types, no Sub TLVs are currently defined
+	input := []byte{
+		0xff, 0x00, 0x00, 0x02, 0x00, 0x00,
+		0xff, 0x01, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00,
+	}
+	result, err := UnmarshalSRSegmentSubTLV(input)
+	if err != nil {
+		t.Fatalf("supposed to succeed but failed with error: %+v", err)
+	}
+	if len(result) != 0 {
+		t.Fatalf("expected no Sub TLVs but got %+v", result)
+	}
+}
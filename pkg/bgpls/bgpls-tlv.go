@@ -2,6 +2,7 @@ package bgpls
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
@@ -22,11 +23,17 @@ func UnmarshalBGPLSTLV(b []byte) ([]TLV, error) {
 	}
 	lstlvs := make([]TLV, 0)
 	for p := 0; p < len(b); {
+		if p+4 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal BGP-LS TLV")
+		}
 		lstlv := TLV{}
 		lstlv.Type = binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
 		lstlv.Length = binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
+		if p+int(lstlv.Length) > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal BGP-LS TLV type %d of length %d", lstlv.Type, lstlv.Length)
+		}
 		lstlv.Value = make([]byte, lstlv.Length)
 		copy(lstlv.Value, b[p:p+int(lstlv.Length)])
 		p += int(lstlv.Length)
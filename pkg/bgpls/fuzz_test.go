@@ -0,0 +1,138 @@
+//go:build go1.18
+// +build go1.18
+
+package bgpls
+
+import "testing"
+
+func FuzzUnmarshalBGPLSTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalBGPLSTLV(b)
+	})
+}
+
+func FuzzUnmarshalFlexAlgoDefinition(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalFlexAlgoDefinition(b)
+	})
+}
+
+func FuzzUnmarshalFlexAlgoPrefixMetric(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalFlexAlgoPrefixMetric(b)
+	})
+}
+
+func FuzzUnmarshalSRBindingSID(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRBindingSID(b)
+	})
+}
+
+func FuzzUnmarshalSRCandidatePathState(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRCandidatePathState(b)
+	})
+}
+
+func FuzzUnmarshalSRCandidatePathName(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRCandidatePathName(b)
+	})
+}
+
+func FuzzUnmarshalSRCandidatePathConstraints(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRCandidatePathConstraints(b)
+	})
+}
+
+func FuzzUnmarshalSRCandidatePathConstraintsSubTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRCandidatePathConstraintsSubTLV(b)
+	})
+}
+
+func FuzzUnmarshalSRAffinityConstraint(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRAffinityConstraint(b)
+	})
+}
+
+func FuzzUnmarshalSRSRLGConstraint(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRSRLGConstraint(b)
+	})
+}
+
+func FuzzUnmarshalSRBandwidthConstraint(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRBandwidthConstraint(b)
+	})
+}
+
+func FuzzUnmarshalSRDisjointGroupConstraint(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRDisjointGroupConstraint(b)
+	})
+}
+
+func FuzzUnmarshalSRSegmentList(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRSegmentList(b)
+	})
+}
+
+func FuzzUnmarshalSRSegmentListSubTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRSegmentListSubTLV(b)
+	})
+}
+
+func FuzzUnmarshalMPLSLabelSID(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalMPLSLabelSID(b)
+	})
+}
+
+func FuzzUnmarshalSRv6SID(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRv6SID(b)
+	})
+}
+
+func FuzzUnmarshalSRType1Descriptor(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRType1Descriptor(b)
+	})
+}
+
+func FuzzUnmarshalSRSegmentSubTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRSegmentSubTLV(b)
+	})
+}
+
+func FuzzUnmarshalSRSegment(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRSegment(b)
+	})
+}
+
+func FuzzUnmarshalSRSegmentListMetric(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRSegmentListMetric(b)
+	})
+}
+
+func FuzzUnmarshalBGPLSNLRI(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalBGPLSNLRI(b)
+	})
+}
+
+func FuzzUnmarshalAppSpecLinkAttr(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalAppSpecLinkAttr(b)
+	})
+}
@@ -24,6 +24,9 @@ func UnmarshalCommonHeader(b []byte) (*CommonHeader, error) {
 	if glog.V(6) {
 		glog.Infof("BMP CommonHeader Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < CommonHeaderLength {
+		return nil, fmt.Errorf("invalid length of common header %d", len(b))
+	}
 	ch := &CommonHeader{}
 	if b[0] != 3 {
 		return nil, fmt.Errorf("invalid version in common header, expected 3 found %d", b[0])
@@ -56,4 +56,27 @@ const (
 	FlowspecV4Msg = 164
 	// FlowspecV6Msg defines BMP Route Monitoring message carrying Flowspec NLRI
 	FlowspecV6Msg = 166
+	// UpdateErrorMsg defines a message carrying errors found in BGP Update of BMP Route Monitoring message
+	UpdateErrorMsg = 17
+	// SessionErrorMsg defines a message reporting BMP session reset by the collector
+	SessionErrorMsg = 18
+	// VPLSMsg defines BMP Route Monitoring message carrying VPLS NLRI
+	VPLSMsg = 19
+	// LSTEPolicyMsg defines BMP Route Monitoring message carrying BGP-LS TE Policy NLRI
+	LSTEPolicyMsg = 20
+	// MVPNMsg defines BMP Route Monitoring message carrying MCAST-VPN and VPN multicast NLRI
+	MVPNMsg = 21
+	// RTCMsg defines BMP Route Monitoring message carrying Route Target Constraint NLRI
+	RTCMsg = 22
+	// TransportMsg defines BMP Route Monitoring message carrying Labeled Unicast and Classful Transport NLRI
+	TransportMsg = 23
+	// FlowspecVPNMsg defines BMP Route Monitoring message carrying VPN Flowspec NLRI
+	FlowspecVPNMsg = 24
+	// FlowspecVPNV4Msg defines BMP Route Monitoring message carrying VPNv4 Flowspec NLRI
+	FlowspecVPNV4Msg = 244
+	// FlowspecVPNV6Msg defines BMP Route Monitoring message carrying VPNv6 Flowspec NLRI
+	FlowspecVPNV6Msg = 246
 )
+
+// DefaultMaxMessageLength defines the default maximum length of BMP message accepted from the router
+const DefaultMaxMessageLength = 1024 * 1024
@@ -0,0 +1,70 @@
+//go:build go1.18
+// +build go1.18
+
+package bmp
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+)
+
+func FuzzUnmarshalTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalTLV(b)
+	})
+}
+
+func FuzzUnmarshalInitiationMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalInitiationMessage(b)
+	})
+}
+
+func FuzzUnmarshalBMPStatsReportMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalBMPStatsReportMessage(b)
+	})
+}
+
+func FuzzUnmarshalCommonHeader(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalCommonHeader(b)
+	})
+}
+
+func FuzzUnmarshalPeerUpMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalPeerUpMessage(b)
+	})
+}
+
+func FuzzUnmarshalPerPeerHeader(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalPerPeerHeader(b)
+	})
+}
+
+func FuzzUnmarshalBMPRouteMonitorMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte, as4 bool) {
+		UnmarshalBMPRouteMonitorMessage(b, &bgp.NegotiatedCapabilities{AS4: as4})
+	})
+}
+
+func FuzzUnmarshalPeerDownMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalPeerDownMessage(b)
+	})
+}
+
+func FuzzUnmarshalRouteMonitorTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalRouteMonitorTLV(b)
+	})
+}
+
+func FuzzUnmarshalPathStatus(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalPathStatus(b)
+	})
+}
@@ -8,6 +8,13 @@ import (
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
 
+const (
+	// StringTLV defines Informational TLV type carrying free form UTF-8 string
+	StringTLV = 0
+	// VRFTableNameTLV defines Informational TLV type carrying VRF or table name per rfc9069
+	VRFTableNameTLV = 3
+)
+
 // InformationalTLV defines Informational TLV per rfc7854
 type InformationalTLV struct {
 	InformationType   int16
@@ -22,11 +29,14 @@ func UnmarshalTLV(b []byte) ([]InformationalTLV, error) {
 	}
 	tlvs := make([]InformationalTLV, 0)
 	for i := 0; i < len(b); {
+		if i+4 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal tlv")
+		}
 		// Extracting TLV type 2 bytes
 		t := int16(binary.BigEndian.Uint16(b[i : i+2]))
 		// Extracting TLV length
 		l := int16(binary.BigEndian.Uint16(b[i+2 : i+4]))
-		if l > int16(len(b)-(i+4)) {
+		if l < 0 || int(l) > len(b)-(i+4) {
 			return nil, fmt.Errorf("invalid tlv length %d", l)
 		}
 		v := b[i+4 : i+4+int(l)]
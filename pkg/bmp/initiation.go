@@ -22,6 +22,9 @@ func UnmarshalInitiationMessage(b []byte) (*InitiationMessage, error) {
 		TLV: make([]InformationalTLV, 0),
 	}
 	for i := 0; i < len(b); {
+		if i+4 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal tlv")
+		}
 		// Extracting TLV type 2 bytes
 		t := int16(binary.BigEndian.Uint16(b[i : i+2]))
 		switch t {
@@ -33,7 +36,7 @@ func UnmarshalInitiationMessage(b []byte) (*InitiationMessage, error) {
 		}
 		// Extracting TLV length
 		l := int16(binary.BigEndian.Uint16(b[i+2 : i+4]))
-		if l > int16(len(b)-(i+4)) {
+		if l < 0 || int(l) > len(b)-(i+4) {
 			return nil, fmt.Errorf("invalid tlv length %d", l)
 		}
 		v := b[i+4 : i+4+int(l)]
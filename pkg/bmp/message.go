@@ -6,3 +6,9 @@ type Message struct {
 	PeerHeader *PerPeerHeader
 	Payload    interface{}
 }
+
+// SessionError defines an error which caused the collector to reset BMP session with the router
+type SessionError struct {
+	RouterIP string
+	Reason   string
+}
@@ -1,33 +1,130 @@
 package bmp
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
 
+// PeerDownReasons defines names of Peer Down reason codes per rfc7854 and rfc9069
+var PeerDownReasons = map[uint8]string{
+	1: "Local system closed, NOTIFICATION PDU follows",
+	2: "Local system closed, no NOTIFICATION PDU follows",
+	3: "Remote system closed, NOTIFICATION PDU follows",
+	4: "Remote system closed, no data",
+	5: "Peer de-configured",
+	6: "Local system closed, TLV data follows",
+}
+
+// FSMEvents defines names of BGP FSM events per rfc4271 section 8.1
+var FSMEvents = map[uint16]string{
+	1:  "ManualStart",
+	2:  "ManualStop",
+	3:  "AutomaticStart",
+	4:  "ManualStart_with_PassiveTcpEstablishment",
+	5:  "AutomaticStart_with_PassiveTcpEstablishment",
+	6:  "AutomaticStart_with_DampPeerOscillations",
+	7:  "AutomaticStart_with_DampPeerOscillations_and_PassiveTcpEstablishment",
+	8:  "AutomaticStop",
+	9:  "ConnectRetryTimer_Expires",
+	10: "HoldTimer_Expires",
+	11: "KeepaliveTimer_Expires",
+	12: "DelayOpenTimer_Expires",
+	13: "IdleHoldTimer_Expires",
+	14: "TcpConnection_Valid",
+	15: "Tcp_CR_Invalid",
+	16: "Tcp_CR_Acked",
+	17: "TcpConnectionConfirmed",
+	18: "TcpConnectionFails",
+	19: "BGPOpen",
+	20: "BGPOpen with DelayOpenTimer running",
+	21: "BGPHeaderErr",
+	22: "BGPOpenMsgErr",
+	23: "OpenCollisionDump",
+	24: "NotifMsgVerErr",
+	25: "NotifMsg",
+	26: "KeepAliveMsg",
+	27: "UpdateMsg",
+	28: "UpdateMsgErr",
+}
+
 // PeerDownMessage defines BMPPeerDownMessage per rfc7854
 type PeerDownMessage struct {
 	Reason uint8
 	Data   []byte
+	// Notification is BGP Notification PDU carried with reasons 1 and 3
+	Notification *bgp.NotificationMessage
+	// FSMEvent is the event code which caused the session to close with reason 2
+	FSMEvent uint16
+	// TLV is information carried with reason 6 per rfc9069
+	TLV []InformationalTLV
+}
+
+// ReasonString returns the name of Peer Down reason
+func (p *PeerDownMessage) ReasonString() string {
+	if r, ok := PeerDownReasons[p.Reason]; ok {
+		return r
+	}
+
+	return fmt.Sprintf("Unknown reason %d", p.Reason)
 }
 
-// UnmarshalPeerDownMessage processes Peer Down message and returns BMPPeerDownMessage object
+// FSMEventString returns the name of FSM event, for Peer Down messages without FSM event an empty string is returned
+func (p *PeerDownMessage) FSMEventString() string {
+	if p.FSMEvent == 0 {
+		return ""
+	}
+	if e, ok := FSMEvents[p.FSMEvent]; ok {
+		return e
+	}
+
+	return fmt.Sprintf("Unknown event %d", p.FSMEvent)
+}
+
+// UnmarshalPeerDownMessage processes Peer Down message and returns BMPPeerDownMessage object,
+// data of unknown reason codes is preserved without decoding.
 func UnmarshalPeerDownMessage(b []byte) (*PeerDownMessage, error) {
 	if glog.V(6) {
 		glog.Infof("BMP Peer Down Message Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 1 {
+		return nil, fmt.Errorf("invalid length of Peer Down message %d", len(b))
+	}
 	pdw := &PeerDownMessage{
 		Data: make([]byte, len(b)-1),
 	}
 	p := 0
 	pdw.Reason = b[p]
 	p++
-	if pdw.Reason < 1 || pdw.Reason > 5 {
+	if pdw.Reason == 0 {
 		return nil, fmt.Errorf("invalid reason code %d in Peer Down message", pdw.Reason)
 	}
 	copy(pdw.Data, b[p:])
+	// Malformed data does not prevent reporting the peer down, the data is preserved as is
+	var err error
+	switch pdw.Reason {
+	case 1, 3:
+		// Skip 16 bytes of the marker
+		if len(pdw.Data) < 16 {
+			err = fmt.Errorf("not enough bytes to unmarshal BGP Notification Message")
+			break
+		}
+		pdw.Notification, err = bgp.UnmarshalBGPNotificationMessage(pdw.Data[16:])
+	case 2:
+		if len(pdw.Data) < 2 {
+			err = fmt.Errorf("invalid length of FSM event %d", len(pdw.Data))
+			break
+		}
+		pdw.FSMEvent = binary.BigEndian.Uint16(pdw.Data[0:2])
+	case 6:
+		pdw.TLV, err = UnmarshalTLV(pdw.Data)
+	}
+	if err != nil {
+		glog.Warningf("fail to unmarshal data of Peer Down message with reason %d with error: %+v", pdw.Reason, err)
+	}
 
 	return pdw, nil
 }
@@ -0,0 +1,165 @@
+package bmp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+)
+
+var bgpMarker = []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+func TestUnmarshalPeerDownMessage(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        []byte
+		fail         bool
+		expect       *PeerDownMessage
+		reason       string
+		notification string
+		shutdown     string
+		event        string
+	}{
+		{
+			name:  "local notification hold timer expired",
+			input: append(append([]byte{0x01}, bgpMarker...), 0x00, 0x15, 0x03, 0x04, 0x00),
+			expect: &PeerDownMessage{
+				Reason: 1,
+				Data:   append(append([]byte{}, bgpMarker...), 0x00, 0x15, 0x03, 0x04, 0x00),
+				Notification: &bgp.NotificationMessage{
+					Length:    21,
+					Type:      3,
+					ErrorCode: 4,
+					Data:      []byte{},
+				},
+			},
+			reason:       "Local system closed, NOTIFICATION PDU follows",
+			notification: "Hold Timer Expired",
+		},
+		{
+			name: "remote administrative shutdown with communication",
+			input: append(append([]byte{0x03}, bgpMarker...), 0x00, 0x21, 0x03, 0x06, 0x02,
+				0x0b, 'm', 'a', 'i', 'n', 't', 'e', 'n', 'a', 'n', 'c', 'e'),
+			expect: &PeerDownMessage{
+				Reason: 3,
+				Data: append(append([]byte{}, bgpMarker...), 0x00, 0x21, 0x03, 0x06, 0x02,
+					0x0b, 'm', 'a', 'i', 'n', 't', 'e', 'n', 'a', 'n', 'c', 'e'),
+				Notification: &bgp.NotificationMessage{
+					Length:       33,
+					Type:         3,
+					ErrorCode:    6,
+					ErrorSubcode: 2,
+					Data:         []byte{0x0b, 'm', 'a', 'i', 'n', 't', 'e', 'n', 'a', 'n', 'c', 'e'},
+				},
+			},
+			reason:       "Remote system closed, NOTIFICATION PDU follows",
+			notification: "Cease: Administrative Shutdown",
+			shutdown:     "maintenance",
+		},
+		{
+			name:  "notification of extended message",
+			input: append(append([]byte{0x01}, bgpMarker...), 0x80, 0x10, 0x03, 0x06, 0x03),
+			expect: &PeerDownMessage{
+				Reason: 1,
+				Data:   append(append([]byte{}, bgpMarker...), 0x80, 0x10, 0x03, 0x06, 0x03),
+				Notification: &bgp.NotificationMessage{
+					Length:       32784,
+					Type:         3,
+					ErrorCode:    6,
+					ErrorSubcode: 3,
+					Data:         []byte{},
+				},
+			},
+			reason:       "Local system closed, NOTIFICATION PDU follows",
+			notification: "Cease: Peer De-configured",
+		},
+		{
+			name:  "local fsm event",
+			input: []byte{0x02, 0x00, 0x0a},
+			expect: &PeerDownMessage{
+				Reason:   2,
+				Data:     []byte{0x00, 0x0a},
+				FSMEvent: 10,
+			},
+			reason: "Local system closed, no NOTIFICATION PDU follows",
+			event:  "HoldTimer_Expires",
+		},
+		{
+			name:  "peer de-configured",
+			input: []byte{0x05},
+			expect: &PeerDownMessage{
+				Reason: 5,
+				Data:   []byte{},
+			},
+			reason: "Peer de-configured",
+		},
+		{
+			name:  "loc-rib instance down with table name",
+			input: []byte{0x06, 0x00, 0x03, 0x00, 0x06, 'g', 'l', 'o', 'b', 'a', 'l'},
+			expect: &PeerDownMessage{
+				Reason: 6,
+				Data:   []byte{0x00, 0x03, 0x00, 0x06, 'g', 'l', 'o', 'b', 'a', 'l'},
+				TLV: []InformationalTLV{
+					{InformationType: 3, InformationLength: 6, Information: []byte{'g', 'l', 'o', 'b', 'a', 'l'}},
+				},
+			},
+			reason: "Local system closed, TLV data follows",
+		},
+		{
+			name:  "unknown reason",
+			input: []byte{0x07, 0x01, 0x02},
+			expect: &PeerDownMessage{
+				Reason: 7,
+				Data:   []byte{0x01, 0x02},
+			},
+			reason: "Unknown reason 7",
+		},
+		{
+			name:  "malformed notification",
+			input: []byte{0x01, 0xff, 0xff},
+			expect: &PeerDownMessage{
+				Reason: 1,
+				Data:   []byte{0xff, 0xff},
+			},
+			reason: "Local system closed, NOTIFICATION PDU follows",
+		},
+		{
+			name:  "reserved reason",
+			input: []byte{0x00},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalPeerDownMessage(tt.input)
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("expected to succeed but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("Differences: %+v", deep.Equal(tt.expect, got))
+			}
+			if r := got.ReasonString(); r != tt.reason {
+				t.Errorf("expected reason %q but got %q", tt.reason, r)
+			}
+			if e := got.FSMEventString(); e != tt.event {
+				t.Errorf("expected FSM event %q but got %q", tt.event, e)
+			}
+			if got.Notification == nil {
+				return
+			}
+			if n := got.Notification.String(); n != tt.notification {
+				t.Errorf("expected notification %q but got %q", tt.notification, n)
+			}
+			if s, _ := got.Notification.ShutdownCommunication(); s != tt.shutdown {
+				t.Errorf("expected shutdown communication %q but got %q", tt.shutdown, s)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package bmp
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/bgp"
@@ -18,6 +19,30 @@ type PeerUpMessage struct {
 	Information  []InformationalTLV
 }
 
+// GetInformation returns strings of String TLVs in the order they are carried in Peer Up message
+func (pu *PeerUpMessage) GetInformation() []string {
+	var info []string
+	for _, tlv := range pu.Information {
+		if tlv.InformationType == StringTLV {
+			info = append(info, string(tlv.Information))
+		}
+	}
+
+	return info
+}
+
+// GetTableName returns the name carried in VRF/Table Name TLV, an empty string is returned when
+// Peer Up message does not carry it.
+func (pu *PeerUpMessage) GetTableName() string {
+	for _, tlv := range pu.Information {
+		if tlv.InformationType == VRFTableNameTLV {
+			return string(tlv.Information)
+		}
+	}
+
+	return ""
+}
+
 // UnmarshalPeerUpMessage processes Peer Up message and returns BMPPeerUpMessage object
 func UnmarshalPeerUpMessage(b []byte) (*PeerUpMessage, error) {
 	if glog.V(6) {
@@ -30,6 +55,10 @@ func UnmarshalPeerUpMessage(b []byte) (*PeerUpMessage, error) {
 		ReceivedOpen: &bgp.OpenMessage{},
 		Information:  make([]InformationalTLV, 0),
 	}
+	// Local Address, Local and Remote ports and the marker of Sent Open message
+	if len(b) < 54 {
+		return nil, fmt.Errorf("invalid length of Peer Up message %d", len(b))
+	}
 	p := 0
 	copy(pu.LocalAddress, b[:16])
 	p += 16
@@ -40,6 +69,10 @@ func UnmarshalPeerUpMessage(b []byte) (*PeerUpMessage, error) {
 	// Skip first marker 16 bytes
 	p += 16
 	l1 := int16(binary.BigEndian.Uint16(b[p : p+2]))
+	// Sent Open must fit and be followed by the marker and the length of Received Open
+	if l1 < 29 || p+int(l1)-16+18 > len(b) {
+		return nil, fmt.Errorf("invalid length of Sent Open message %d", l1)
+	}
 	pu.SentOpen, err = bgp.UnmarshalBGPOpenMessage(b[p : p+int(l1-16)])
 	if err != nil {
 		return nil, err
@@ -49,6 +82,9 @@ func UnmarshalPeerUpMessage(b []byte) (*PeerUpMessage, error) {
 	// Skip second marker
 	p += 16
 	l2 := int16(binary.BigEndian.Uint16(b[p : p+2]))
+	if l2 < 29 || p+int(l2)-16 > len(b) {
+		return nil, fmt.Errorf("invalid length of Received Open message %d", l2)
+	}
 	pu.ReceivedOpen, err = bgp.UnmarshalBGPOpenMessage(b[p : p+int(l2-16)])
 	if err != nil {
 		return nil, err
@@ -58,11 +94,20 @@ func UnmarshalPeerUpMessage(b []byte) (*PeerUpMessage, error) {
 	if len(b) > int(p) {
 		// Since pointer p does not point to the end of buffer,
 		// then processing Informational TLVs
-		tlvs, err := UnmarshalTLV(b[p : len(b)-int(p)])
+		tlvs, err := UnmarshalTLV(b[p:])
 		if err != nil {
 			return nil, err
 		}
-		pu.Information = tlvs
+		for _, tlv := range tlvs {
+			// VRF/Table Name is limited to 255 bytes per rfc9069 section 5.1, invalid TLV is discarded
+			// while the peer is still reported
+			if tlv.InformationType == VRFTableNameTLV && (tlv.InformationLength == 0 || tlv.InformationLength > 255) {
+				glog.Warningf("discarding VRF/Table Name TLV of invalid length %d", tlv.InformationLength)
+				continue
+			}
+			pu.Information = append(pu.Information, tlv)
+		}
 	}
+
 	return pu, nil
 }
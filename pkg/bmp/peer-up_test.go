@@ -0,0 +1,219 @@
+package bmp
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+)
+
+// xrPeerUp is Peer Up message body of IOS-XR router without Informational TLVs,
+// it follows Per Peer Header.
+var xrPeerUp = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 192, 168, 80, 128, 0, 179, 131, 152, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 0, 91, 1, 4, 19, 206, 0, 90, 192, 168, 8, 8, 62, 2, 6, 1, 4, 0, 1, 0, 1, 2, 6, 1, 4, 0, 1, 0, 4, 2, 6, 1, 4, 0, 1, 0, 128, 2, 2, 128, 0, 2, 2, 2, 0, 2, 6, 65, 4, 0, 0, 19, 206, 2, 20, 5, 18, 0, 1, 0, 1, 0, 2, 0, 1, 0, 2, 0, 2, 0, 1, 0, 128, 0, 2, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 0, 75, 1, 4, 19, 206, 0, 90, 57, 112, 1, 254, 46, 2, 44, 2, 0, 1, 4, 0, 1, 0, 1, 1, 4, 0, 2, 0, 1, 1, 4, 0, 1, 0, 4, 1, 4, 0, 2, 0, 4, 1, 4, 0, 1, 0, 128, 1, 4, 0, 2, 0, 128, 65, 4, 0, 0, 19, 206}
+
+func peerUpWithTLV(tlv ...byte) []byte {
+	return append(append([]byte{}, xrPeerUp...), tlv...)
+}
+
+func TestUnmarshalPeerUpMessageInformation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		fail  bool
+		info  []string
+		table string
+	}{
+		{
+			name:  "no informational tlvs",
+			input: xrPeerUp,
+		},
+		{
+			name: "string tlvs",
+			input: peerUpWithTLV(
+				0x00, 0x00, 0x00, 0x0b, 'u', 'p', 's', 't', 'r', 'e', 'a', 'm', ' ', 't', '1',
+				0x00, 0x00, 0x00, 0x06, 'l', 'o', 'n', 'd', 'o', 'n'),
+			info: []string{"upstream t1", "london"},
+		},
+		{
+			name:  "string tlv longer than open messages",
+			input: peerUpWithTLV(append([]byte{0x00, 0x00, 0x00, 0xfa}, bytes.Repeat([]byte{'a'}, 250)...)...),
+			info:  []string{strings.Repeat("a", 250)},
+		},
+		{
+			name:  "loc-rib table name",
+			input: peerUpWithTLV(0x00, 0x03, 0x00, 0x06, 'g', 'l', 'o', 'b', 'a', 'l'),
+			table: "global",
+		},
+		{
+			name: "vrf name with string tlv",
+			input: peerUpWithTLV(
+				0x00, 0x03, 0x00, 0x08, 'c', 'u', 's', 't', 'o', 'm', 'e', 'r',
+				0x00, 0x00, 0x00, 0x03, 'p', 'e', '1'),
+			info:  []string{"pe1"},
+			table: "customer",
+		},
+		{
+			name:  "unknown tlv",
+			input: peerUpWithTLV(0x00, 0x80, 0x00, 0x02, 0x01, 0x02),
+		},
+		{
+			name:  "empty table name is discarded",
+			input: peerUpWithTLV(0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 'p', 'e', '1'),
+			info:  []string{"pe1"},
+		},
+		{
+			name:  "table name longer than 255 bytes is discarded",
+			input: peerUpWithTLV(append([]byte{0x00, 0x03, 0x01, 0x00}, bytes.Repeat([]byte{'v'}, 256)...)...),
+		},
+		{
+			name:  "truncated tlv",
+			input: peerUpWithTLV(0x00, 0x00, 0x00, 0x08, 'p', 'e'),
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pu, err := UnmarshalPeerUpMessage(tt.input)
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("expected to succeed but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if got := pu.GetInformation(); !reflect.DeepEqual(got, tt.info) {
+				t.Errorf("expected information %v but got %v", tt.info, got)
+			}
+			if got := pu.GetTableName(); got != tt.table {
+				t.Errorf("expected table name %q but got %q", tt.table, got)
+			}
+			if pu.SentOpen.MyAS != 5070 || pu.ReceivedOpen.MyAS != 5070 {
+				t.Errorf("expected Open messages of AS 5070 but got %d and %d", pu.SentOpen.MyAS, pu.ReceivedOpen.MyAS)
+			}
+		})
+	}
+}
+
+// openMessage builds BGP Open message with marker, caps are Capabilities each carried in
+// its own Optional Parameter unless combined is set.
+func openMessage(as uint16, id []byte, combined bool, caps ...[]byte) []byte {
+	params := []byte{}
+	if combined {
+		c := bytes.Join(caps, nil)
+		params = append([]byte{0x02, byte(len(c))}, c...)
+	} else {
+		for _, c := range caps {
+			params = append(append(params, 0x02, byte(len(c))), c...)
+		}
+	}
+	b := bytes.Repeat([]byte{0xff}, 16)
+	l := 29 + len(params)
+	b = append(b, byte(l>>8), byte(l), 0x01, 0x04, byte(as>>8), byte(as), 0x00, 0xb4)
+	b = append(b, id...)
+	b = append(b, byte(len(params)))
+
+	return append(b, params...)
+}
+
+func peerUpMessage(local []byte, sent, received []byte, tlvs ...byte) []byte {
+	b := make([]byte, 16)
+	copy(b[16-len(local):], local)
+	b = append(b, 0x00, 0xb3, 0xc3, 0x50)
+	b = append(b, sent...)
+	b = append(b, received...)
+
+	return append(b, tlvs...)
+}
+
+func TestUnmarshalPeerUpMessageVendors(t *testing.T) {
+	mpIPv4 := []byte{0x01, 0x04, 0x00, 0x01, 0x00, 0x01}
+	mpIPv6 := []byte{0x01, 0x04, 0x00, 0x02, 0x00, 0x01}
+	mpVPNv4 := []byte{0x01, 0x04, 0x00, 0x01, 0x00, 0x80}
+	mpEVPN := []byte{0x01, 0x04, 0x00, 0x19, 0x00, 0x46}
+	routeRefresh := []byte{0x02, 0x00}
+	ciscoRouteRefresh := []byte{0x80, 0x00}
+	enhancedRouteRefresh := []byte{0x46, 0x00}
+	gracefulRestart := []byte{0x40, 0x02, 0x00, 0x78}
+	as4 := func(as uint32) []byte {
+		return []byte{0x41, 0x04, byte(as >> 24), byte(as >> 16), byte(as >> 8), byte(as)}
+	}
+	tests := []struct {
+		name   string
+		input  []byte
+		as4    int32
+		mp     int
+		info   []string
+		table  string
+		remote uint16
+	}{
+		{
+			// Modeled on Junos, every capability in its own Optional Parameter and 4 bytes AS with AS_TRANS
+			name: "junos",
+			input: peerUpMessage([]byte{192, 168, 0, 1},
+				openMessage(23456, []byte{192, 168, 0, 1}, false, mpIPv4, mpIPv6, routeRefresh, gracefulRestart, as4(400001)),
+				openMessage(23456, []byte{192, 168, 0, 2}, false, mpIPv4, mpIPv6, routeRefresh, as4(400002)),
+				0x00, 0x00, 0x00, 0x0a, 'j', 'u', 'n', 'o', 's', '-', 'p', 'e', 'e', 'r',
+				0x00, 0x03, 0x00, 0x06, 'i', 'n', 'e', 't', '.', '0'),
+			as4:    400001,
+			mp:     2,
+			info:   []string{"junos-peer"},
+			table:  "inet.0",
+			remote: 50000,
+		},
+		{
+			// Modeled on IOS-XE, both the standard and the pre-standard Route Refresh capabilities
+			name: "ios-xe",
+			input: peerUpMessage([]byte{10, 0, 0, 1},
+				openMessage(65001, []byte{10, 0, 0, 1}, false, mpIPv4, mpVPNv4, ciscoRouteRefresh, routeRefresh, enhancedRouteRefresh, as4(65001)),
+				openMessage(65002, []byte{10, 0, 0, 2}, false, mpIPv4, mpVPNv4, ciscoRouteRefresh, routeRefresh, as4(65002))),
+			as4:    65001,
+			mp:     2,
+			remote: 50000,
+		},
+		{
+			// Modeled on SR OS, all capabilities in a single Optional Parameter and VRF name,
+			// the empty VRF/Table Name TLV is discarded
+			name: "sr os",
+			input: peerUpMessage([]byte{10, 0, 0, 3},
+				openMessage(65003, []byte{10, 0, 0, 3}, true, mpIPv4, mpEVPN, routeRefresh, gracefulRestart, as4(65003)),
+				openMessage(65003, []byte{10, 0, 0, 4}, true, mpIPv4, mpEVPN, routeRefresh, as4(65003)),
+				0x00, 0x03, 0x00, 0x00,
+				0x00, 0x03, 0x00, 0x07, 'v', 'p', 'r', 'n', '1', '0', '0'),
+			as4:    65003,
+			mp:     2,
+			table:  "vprn100",
+			remote: 50000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pu, err := UnmarshalPeerUpMessage(tt.input)
+			if err != nil {
+				t.Fatalf("expected to succeed but failed with error: %+v", err)
+			}
+			if pu.RemotePort != tt.remote {
+				t.Errorf("expected remote port %d but got %d", tt.remote, pu.RemotePort)
+			}
+			if as, ok := pu.SentOpen.Is4BytesASCapable(); !ok || as != tt.as4 {
+				t.Errorf("expected 4 bytes AS %d but got %d", tt.as4, as)
+			}
+			caps := bgp.NegotiateCapabilities(pu.SentOpen.GetCapabilities(), pu.ReceivedOpen.GetCapabilities())
+			if !caps.AS4 || !caps.RouteRefresh {
+				t.Errorf("expected 4 bytes AS and Route Refresh to be negotiated but got %+v", caps)
+			}
+			if len(caps.Multiprotocol) != tt.mp {
+				t.Errorf("expected %d negotiated address families but got %d", tt.mp, len(caps.Multiprotocol))
+			}
+			if got := pu.GetInformation(); !reflect.DeepEqual(got, tt.info) {
+				t.Errorf("expected information %v but got %v", tt.info, got)
+			}
+			if got := pu.GetTableName(); got != tt.table {
+				t.Errorf("expected table name %q but got %q", tt.table, got)
+			}
+		})
+	}
+}
@@ -72,6 +72,9 @@ func UnmarshalPerPeerHeader(b []byte) (*PerPeerHeader, error) {
 	if glog.V(6) {
 		glog.Infof("BMP Per Peer Header Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < PerPeerHeaderLength {
+		return nil, fmt.Errorf("invalid length of per peer header %d", len(b))
+	}
 	pph := &PerPeerHeader{
 		PeerDistinguisher: make([]byte, 8), // newPeerDistinguisher(),
 		PeerAddress:       make([]byte, 16),
@@ -0,0 +1,142 @@
+package bmp
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/tools"
+)
+
+const (
+	// GroupTLV defines Route Monitoring TLV type grouping NLRIs of BGP Update
+	GroupTLV = 0
+	// PathMarkingTLV defines Route Monitoring TLV type carrying the status of the path
+	PathMarkingTLV = 2
+)
+
+// RouteMonitorTLV defines TLV following BGP Update in BMP Route Monitoring message per
+// draft-ietf-grow-bmp-tlv, Index refers to NLRI of BGP Update or, when Group is set, to Group TLV.
+type RouteMonitorTLV struct {
+	Type uint16
+	// Enterprise is set for TLVs of enterprise specific types, such TLVs are not decoded
+	Enterprise bool
+	Group      bool
+	Index      uint16
+	Value      []byte
+}
+
+// UnmarshalRouteMonitorTLV builds a slice of Route Monitoring TLVs, the length of TLV is the length of the value.
+func UnmarshalRouteMonitorTLV(b []byte) ([]*RouteMonitorTLV, error) {
+	if glog.V(6) {
+		glog.Infof("BMP Route Monitoring TLV Raw: %s", tools.MessageHex(b))
+	}
+	tlvs := make([]*RouteMonitorTLV, 0)
+	for p := 0; p < len(b); {
+		if p+6 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal route monitoring tlv")
+		}
+		t := binary.BigEndian.Uint16(b[p : p+2])
+		l := int(binary.BigEndian.Uint16(b[p+2 : p+4]))
+		i := binary.BigEndian.Uint16(b[p+4 : p+6])
+		p += 6
+		if p+l > len(b) {
+			return nil, fmt.Errorf("invalid route monitoring tlv length %d", l)
+		}
+		tlv := &RouteMonitorTLV{
+			Type:       t & 0x7fff,
+			Enterprise: t&0x8000 == 0x8000,
+			Group:      i&0x8000 == 0x8000,
+			Index:      i & 0x7fff,
+			Value:      make([]byte, l),
+		}
+		copy(tlv.Value, b[p:p+l])
+		tlvs = append(tlvs, tlv)
+		p += l
+	}
+
+	return tlvs, nil
+}
+
+// pathStatuses defines names of path status bits per draft-ietf-grow-bmp-path-marking-tlv
+var pathStatuses = []struct {
+	bit  uint32
+	name string
+}{
+	{0x00000001, "invalid"},
+	{0x00000002, "best"},
+	{0x00000004, "non-selected"},
+	{0x00000008, "primary"},
+	{0x00000010, "backup"},
+	{0x00000020, "non-installed"},
+	{0x00000040, "best-external"},
+	{0x00000080, "add-path"},
+	{0x00000100, "filtered-inbound"},
+	{0x00000200, "filtered-outbound"},
+	{0x00000400, "stale"},
+	{0x00000800, "suppressed"},
+}
+
+// pathStatusReasons defines names of reason codes of path status
+var pathStatusReasons = map[uint16]string{
+	0x0001: "invalid for super network",
+	0x0002: "invalid for dampening",
+	0x0003: "invalid for damping history",
+	0x0004: "invalid for policy deny",
+}
+
+// PathStatus defines the status of the path and the optional reason of the status
+type PathStatus struct {
+	Status    uint32
+	Reason    uint16
+	HasReason bool
+}
+
+// UnmarshalPathStatus builds PathStatus object from the value of Path Marking TLV
+func UnmarshalPathStatus(b []byte) (*PathStatus, error) {
+	if len(b) != 4 && len(b) != 6 {
+		return nil, fmt.Errorf("invalid length of path status %d", len(b))
+	}
+	ps := &PathStatus{
+		Status: binary.BigEndian.Uint32(b[0:4]),
+	}
+	if len(b) == 6 {
+		ps.Reason = binary.BigEndian.Uint16(b[4:6])
+		ps.HasReason = true
+	}
+
+	return ps, nil
+}
+
+// StatusStrings returns names of all status bits set, unknown bits are returned in hex
+func (ps *PathStatus) StatusStrings() []string {
+	if ps.Status == 0 {
+		return []string{"unknown"}
+	}
+	s := make([]string, 0)
+	rest := ps.Status
+	for _, st := range pathStatuses {
+		if ps.Status&st.bit == st.bit {
+			s = append(s, st.name)
+			rest &^= st.bit
+		}
+	}
+	if rest != 0 {
+		s = append(s, fmt.Sprintf("0x%08x", rest))
+	}
+
+	return s
+}
+
+// ReasonString returns the name of the reason of path status, an empty string is returned when
+// the reason is not present.
+func (ps *PathStatus) ReasonString() string {
+	if !ps.HasReason {
+		return ""
+	}
+	if r, ok := pathStatusReasons[ps.Reason]; ok {
+		return r
+	}
+
+	return fmt.Sprintf("unknown reason %d", ps.Reason)
+}
@@ -1,6 +1,7 @@
 package bmp
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/golang/glog"
@@ -11,36 +12,116 @@ import (
 // RouteMonitor defines a structure of BMP Route Monitoring message
 type RouteMonitor struct {
 	Update *bgp.Update
+	// TLV lists TLVs following BGP Update
+	TLV []*RouteMonitorTLV
 }
 
-// UnmarshalBMPRouteMonitorMessage builds BMP Route Monitor object
-func UnmarshalBMPRouteMonitorMessage(b []byte) (*RouteMonitor, error) {
+// PathStatus returns the status of the path of NLRI with index, index is the position of NLRI
+// within MP_REACH_NLRI attribute or NLRI field carrying it. nil is returned when the router
+// did not mark the path.
+func (rm *RouteMonitor) PathStatus(index int) *PathStatus {
+	for _, tlv := range rm.TLV {
+		if tlv.Enterprise || tlv.Type != PathMarkingTLV {
+			continue
+		}
+		if tlv.Group {
+			if !rm.inGroup(tlv.Index, index) {
+				continue
+			}
+		} else if int(tlv.Index) != index {
+			continue
+		}
+		ps, err := UnmarshalPathStatus(tlv.Value)
+		if err != nil {
+			glog.Warningf("fail to unmarshal path status with error: %+v", err)
+			continue
+		}
+		return ps
+	}
+
+	return nil
+}
+
+// inGroup returns true when Group TLV with group index lists NLRI with index
+func (rm *RouteMonitor) inGroup(group uint16, index int) bool {
+	for _, tlv := range rm.TLV {
+		if tlv.Enterprise || tlv.Type != GroupTLV || !tlv.Group || tlv.Index != group {
+			continue
+		}
+		for p := 0; p+2 <= len(tlv.Value); p += 2 {
+			if int(binary.BigEndian.Uint16(tlv.Value[p:p+2])) == index {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// UnmarshalBMPRouteMonitorMessage builds BMP Route Monitor object, caps are capabilities
+// negotiated by the monitored peer, when nil, 4 bytes AS and Extended Message capabilities are assumed.
+func UnmarshalBMPRouteMonitorMessage(b []byte, caps *bgp.NegotiatedCapabilities) (*RouteMonitor, error) {
 	if glog.V(6) {
 		glog.Infof("BMP Route Monitor Message Raw: %s length: %d", tools.MessageHex(b), len(b))
 	}
 	rm := RouteMonitor{}
 	// 16 bytes marker + 2 bytes update length + 1 byte of type
-	if len(b) < 19 {
+	if len(b) < bgp.BGPHeaderLength {
 		return nil, fmt.Errorf("malformed route monitor message")
 	}
 	p := 0
 	// Skip 16 bytes of a marker
 	p += 16
-	// Skip 2 bytes of the update length
+	l := int(binary.BigEndian.Uint16(b[p : p+2]))
+	if l < bgp.BGPHeaderLength || l > len(b) {
+		return nil, fmt.Errorf("invalid BGP message length %d", l)
+	}
 	p += 2
+	max := bgp.BGPMaxExtendedMessageLength
+	if caps != nil {
+		max = caps.MaxMessageLength()
+	}
+	if l > max {
+		// Bad Message Length, the message cannot be processed, passing the error on for diagnostics
+		rm.Update = &bgp.Update{
+			BaseAttributes: &bgp.BaseAttributes{},
+			Errors: []*bgp.DecodeError{
+				{Reason: fmt.Sprintf("message length %d exceeds maximum %d", l, max), Action: bgp.SessionReset},
+			},
+		}
+		return &rm, nil
+	}
 	// Getting update type, currently only type 2 is processed
 	t := b[p]
 	p++
 	switch t {
 	case 2:
 		// Update type
-		u, err := bgp.UnmarshalBGPUpdate(b[p:])
+		u, err := bgp.UnmarshalBGPUpdate(b[p:l], caps)
 		if err != nil {
-			return nil, err
+			de, ok := err.(*bgp.DecodeError)
+			if !ok {
+				return nil, err
+			}
+			// Update cannot be processed, passing the error on for diagnostics
+			u = &bgp.Update{
+				BaseAttributes: &bgp.BaseAttributes{},
+				Errors:         []*bgp.DecodeError{de},
+			}
 		}
 		rm.Update = u
 	default:
 	}
+	// TLVs follow BGP message per draft-ietf-grow-bmp-tlv, malformed TLVs do not prevent
+	// processing of the update.
+	if l < len(b) {
+		tlvs, err := UnmarshalRouteMonitorTLV(b[l:])
+		if err != nil {
+			glog.Warningf("fail to unmarshal Route Monitoring TLVs with error: %+v", err)
+		} else {
+			rm.TLV = tlvs
+		}
+	}
 
 	return &rm, nil
 }
@@ -0,0 +1,137 @@
+package bmp
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+)
+
+// bgpWithdraw returns BGP Update message withdrawing n /32 prefixes
+func bgpWithdraw(n int) []byte {
+	l := bgp.BGPHeaderLength + 2 + n*5 + 2
+	b := make([]byte, l)
+	for i := 0; i < 16; i++ {
+		b[i] = 0xff
+	}
+	binary.BigEndian.PutUint16(b[16:18], uint16(l))
+	b[18] = 2
+	binary.BigEndian.PutUint16(b[19:21], uint16(n*5))
+	for i := 0; i < n; i++ {
+		p := 21 + i*5
+		b[p] = 32
+		binary.BigEndian.PutUint32(b[p+1:p+5], uint32(0x0a000000+i))
+	}
+
+	return b
+}
+
+func TestUnmarshalBMPRouteMonitorMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []byte
+		caps      *bgp.NegotiatedCapabilities
+		fail      bool
+		withdrawn int
+		action    bgp.ErrorAction
+	}{
+		{
+			name:      "update within 4096 bytes",
+			input:     bgpWithdraw(100),
+			caps:      &bgp.NegotiatedCapabilities{AS4: true},
+			withdrawn: 100,
+		},
+		{
+			name:      "extended message negotiated",
+			input:     bgpWithdraw(10000),
+			caps:      &bgp.NegotiatedCapabilities{AS4: true, ExtendedMessage: true},
+			withdrawn: 10000,
+		},
+		{
+			name:      "extended message without capabilities",
+			input:     bgpWithdraw(10000),
+			withdrawn: 10000,
+		},
+		{
+			name:   "extended message not negotiated",
+			input:  bgpWithdraw(1000),
+			caps:   &bgp.NegotiatedCapabilities{AS4: true},
+			action: bgp.SessionReset,
+		},
+		{
+			name:  "length exceeds the message",
+			input: bgpWithdraw(10)[:50],
+			caps:  &bgp.NegotiatedCapabilities{AS4: true},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm, err := UnmarshalBMPRouteMonitorMessage(tt.input, tt.caps)
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("expected to succeed but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if got := len(rm.Update.WithdrawnRoutes); got != tt.withdrawn {
+				t.Errorf("expected %d withdrawn routes but got %d", tt.withdrawn, got)
+			}
+			if got := rm.Update.ErrorAction(); got != tt.action {
+				t.Errorf("expected error action %s but got %s", tt.action, got)
+			}
+		})
+	}
+}
+
+func TestRouteMonitorPathStatus(t *testing.T) {
+	tlvs := []byte{
+		// Path Marking TLV for NLRI 0, best and primary
+		0x00, 0x02, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a,
+		// Group TLV 1 of NLRIs 1 and 3
+		0x00, 0x00, 0x00, 0x04, 0x80, 0x01, 0x00, 0x01, 0x00, 0x03,
+		// Path Marking TLV for group 1, invalid with policy deny reason
+		0x00, 0x02, 0x00, 0x06, 0x80, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x04,
+		// Enterprise specific TLV
+		0x80, 0x02, 0x00, 0x04, 0x00, 0x02, 0x00, 0x00, 0x00, 0x02,
+	}
+	rm, err := UnmarshalBMPRouteMonitorMessage(append(bgpWithdraw(4), tlvs...), nil)
+	if err != nil {
+		t.Fatalf("expected to succeed but failed with error: %+v", err)
+	}
+	if len(rm.TLV) != 4 {
+		t.Fatalf("expected 4 TLVs but got %d", len(rm.TLV))
+	}
+	if got := len(rm.Update.WithdrawnRoutes); got != 4 {
+		t.Fatalf("expected 4 withdrawn routes but got %d", got)
+	}
+	tests := []struct {
+		index  int
+		status []string
+		reason string
+	}{
+		{index: 0, status: []string{"best", "primary"}},
+		{index: 1, status: []string{"invalid"}, reason: "invalid for policy deny"},
+		{index: 2},
+		{index: 3, status: []string{"invalid"}, reason: "invalid for policy deny"},
+	}
+	for _, tt := range tests {
+		ps := rm.PathStatus(tt.index)
+		if ps == nil {
+			if tt.status != nil {
+				t.Errorf("expected path status %v for NLRI %d but got none", tt.status, tt.index)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(ps.StatusStrings(), tt.status) {
+			t.Errorf("expected path status %v for NLRI %d but got %v", tt.status, tt.index, ps.StatusStrings())
+		}
+		if ps.ReasonString() != tt.reason {
+			t.Errorf("expected reason %q for NLRI %d but got %q", tt.reason, tt.index, ps.ReasonString())
+		}
+	}
+}
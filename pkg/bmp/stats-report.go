@@ -19,6 +19,9 @@ func UnmarshalBMPStatsReportMessage(b []byte) (*StatsReport, error) {
 	if glog.V(6) {
 		glog.Infof("BMP Stats Report Message Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("invalid length of Stats Report %d", len(b))
+	}
 	sr := StatsReport{}
 	p := 0
 	l := int32(binary.BigEndian.Uint32(b[p : p+4]))
@@ -0,0 +1,95 @@
+package evpn
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ESI Types
+// https://tools.ietf.org/html/rfc7432#section-5
+const (
+	ESIType0 uint8 = iota
+	ESIType1
+	ESIType2
+	ESIType3
+	ESIType4
+	ESIType5
+)
+
+// ESIInfo defines decoded fields of Ethernet Segment Identifier according to its type
+type ESIInfo struct {
+	Type     uint8  `json:"type"`
+	TypeName string `json:"type_name,omitempty"`
+	// Value is set for Type 0 ESI, operator configured arbitrary value
+	Value string `json:"value,omitempty"`
+	// SystemMAC is set for Type 1 (CE LACP System MAC), Type 2 (Root Bridge MAC) and Type 3 ESI
+	SystemMAC string `json:"system_mac,omitempty"`
+	// PortKey is set for Type 1 ESI, CE LACP Port Key
+	PortKey uint16 `json:"port_key,omitempty"`
+	// Priority is set for Type 2 ESI, Root Bridge Priority
+	Priority uint16 `json:"priority,omitempty"`
+	// RouterID is set for Type 4 ESI
+	RouterID string `json:"router_id,omitempty"`
+	// AS is set for Type 5 ESI
+	AS uint32 `json:"as,omitempty"`
+	// Discriminator is set for Type 3, 4 and 5 ESI
+	Discriminator uint32 `json:"discriminator,omitempty"`
+}
+
+var esiTypeNames = map[uint8]string{
+	ESIType0: "Arbitrary",
+	ESIType1: "LACP",
+	ESIType2: "MST",
+	ESIType3: "MAC",
+	ESIType4: "Router ID",
+	ESIType5: "AS",
+}
+
+// String returns ESI in the form of colon separated hex bytes
+func (esi *ESI) String() string {
+	var s strings.Builder
+	for i, b := range esi {
+		if i > 0 {
+			s.WriteString(":")
+		}
+		fmt.Fprintf(&s, "%02x", b)
+	}
+
+	return s.String()
+}
+
+// GetInfo decodes ESI fields according to ESI type, nil is returned for unknown types
+func (esi *ESI) GetInfo() *ESIInfo {
+	t := esi[0]
+	name, ok := esiTypeNames[t]
+	if !ok {
+		return nil
+	}
+	info := &ESIInfo{
+		Type:     t,
+		TypeName: name,
+	}
+	switch t {
+	case ESIType0:
+		info.Value = fmt.Sprintf("%x", esi[1:10])
+	case ESIType1:
+		info.SystemMAC = net.HardwareAddr(esi[1:7]).String()
+		info.PortKey = binary.BigEndian.Uint16(esi[7:9])
+	case ESIType2:
+		info.SystemMAC = net.HardwareAddr(esi[1:7]).String()
+		info.Priority = binary.BigEndian.Uint16(esi[7:9])
+	case ESIType3:
+		info.SystemMAC = net.HardwareAddr(esi[1:7]).String()
+		info.Discriminator = uint32(esi[7])<<16 | uint32(esi[8])<<8 | uint32(esi[9])
+	case ESIType4:
+		info.RouterID = net.IP(esi[1:5]).String()
+		info.Discriminator = binary.BigEndian.Uint32(esi[5:9])
+	case ESIType5:
+		info.AS = binary.BigEndian.Uint32(esi[1:5])
+		info.Discriminator = binary.BigEndian.Uint32(esi[5:9])
+	}
+
+	return info
+}
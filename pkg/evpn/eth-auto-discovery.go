@@ -1,6 +1,10 @@
 package evpn
 
-import "github.com/sbezverk/gobmp/pkg/base"
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
 
 // EthAutoDiscovery defines a structure of Route type 1
 // (Ethernet Auto Discovery route type)
@@ -52,10 +56,25 @@ func (t *EthAutoDiscovery) getLabel() []*base.Label {
 	return t.Label
 }
 
+func (t *EthAutoDiscovery) getMulticastSource() []byte {
+	return nil
+}
+
+func (t *EthAutoDiscovery) getMulticastGroup() []byte {
+	return nil
+}
+
+func (t *EthAutoDiscovery) getOriginatorIP() []byte {
+	return nil
+}
+
 // UnmarshalEVPNEthAutoDiscovery instantiates new instance of a Ethernet Auto Discovery route type object
 func UnmarshalEVPNEthAutoDiscovery(b []byte) (*EthAutoDiscovery, error) {
 	var err error
 	t := EthAutoDiscovery{}
+	if len(b) < 22 {
+		return nil, fmt.Errorf("invalid length of Ethernet Auto Discovery route %d", len(b))
+	}
 	p := 0
 	t.RD, err = base.MakeRD(b[p : p+8])
 	if err != nil {
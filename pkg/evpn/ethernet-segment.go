@@ -1,6 +1,10 @@
 package evpn
 
-import "github.com/sbezverk/gobmp/pkg/base"
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
 
 // EthernetSegment defines a structure of Route type 4
 // (Ethernet Segment Route)
@@ -52,10 +56,25 @@ func (t *EthernetSegment) getLabel() []*base.Label {
 	return nil
 }
 
+func (t *EthernetSegment) getMulticastSource() []byte {
+	return nil
+}
+
+func (t *EthernetSegment) getMulticastGroup() []byte {
+	return nil
+}
+
+func (t *EthernetSegment) getOriginatorIP() []byte {
+	return nil
+}
+
 // UnmarshalEVPNEthernetSegment instantiates new instance of an Ethernet Segment Route object
 func UnmarshalEVPNEthernetSegment(b []byte) (*EthernetSegment, error) {
 	var err error
 	t := EthernetSegment{}
+	if len(b) < 19 {
+		return nil, fmt.Errorf("invalid length of Ethernet Segment route %d", len(b))
+	}
 	p := 0
 	t.RD, err = base.MakeRD(b[p : p+8])
 	if err != nil {
@@ -70,6 +89,9 @@ func UnmarshalEVPNEthernetSegment(b []byte) (*EthernetSegment, error) {
 	t.IPAddrLength = b[p]
 	p++
 	l := int(t.IPAddrLength / 8)
+	if p+l > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal IP address of length %d", l)
+	}
 	if t.IPAddrLength != 0 {
 		t.IPAddr = make([]byte, l)
 		copy(t.IPAddr, b[p:p+l])
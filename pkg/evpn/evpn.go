@@ -20,6 +20,9 @@ type RouteTypeSpec interface {
 	getIPLength() *uint8
 	getGWAddress() []byte
 	getLabel() []*base.Label
+	getMulticastSource() []byte
+	getMulticastGroup() []byte
+	getOriginatorIP() []byte
 }
 
 // Route defines a collection of EVPN NLRI objects of the same type
@@ -33,6 +36,9 @@ type NLRI struct {
 	RouteType uint8
 	Length    uint8
 	RouteTypeSpec
+	// Err is set when the route is malformed, such route is preserved as opaque route
+	// and must be treated as withdrawn.
+	Err *base.DecodeError
 }
 
 // GetEVPNRouteType returns the type of EVPN route
@@ -90,13 +96,29 @@ func (n *NLRI) GetEVPNLabel() []uint32 {
 	return label
 }
 
-// UnmarshalEVPNNLRI instantiates an EVPN NLRI object
+// GetEVPNMulticastSource returns Multicast Source address, empty slice is returned for the wildcard source
+func (n *NLRI) GetEVPNMulticastSource() []byte {
+	return n.RouteTypeSpec.getMulticastSource()
+}
+
+// GetEVPNMulticastGroup returns Multicast Group address, empty slice is returned for the wildcard group
+func (n *NLRI) GetEVPNMulticastGroup() []byte {
+	return n.RouteTypeSpec.getMulticastGroup()
+}
+
+// GetEVPNOriginatorIP returns Originating Router's IP address of multicast routes
+func (n *NLRI) GetEVPNOriginatorIP() []byte {
+	return n.RouteTypeSpec.getOriginatorIP()
+}
+
+// UnmarshalEVPNNLRI instantiates an EVPN NLRI object, base.DecodeError is returned when routes cannot
+// be delimited, malformed routes of known length are marked with the error.
 func UnmarshalEVPNNLRI(b []byte) (*Route, error) {
 	if glog.V(6) {
 		glog.Infof("EVPN NLRI Raw: %s", tools.MessageHex(b))
 	}
 	if len(b) == 0 {
-		return nil, fmt.Errorf("NLRI length is 0")
+		return nil, &base.DecodeError{Reason: "NLRI length is 0", Action: base.AFISAFIDisable}
 	}
 	r := Route{
 		Route: make([]*NLRI, 0),
@@ -104,39 +126,31 @@ func UnmarshalEVPNNLRI(b []byte) (*Route, error) {
 	for p := 0; p < len(b); {
 		var err error
 		n := &NLRI{}
+		if p+2 > len(b) {
+			return nil, &base.DecodeError{Offset: p, Reason: "not enough bytes to unmarshal EVPN NLRI", Action: base.AFISAFIDisable}
+		}
 		n.RouteType = b[p]
 		p++
 		n.Length = b[p]
 		p++
 		l := int(n.Length)
-		switch n.RouteType {
-		case 1:
-			n.RouteTypeSpec, err = UnmarshalEVPNEthAutoDiscovery(b[p : p+l])
-			if err != nil {
-				return nil, err
-			}
-		case 2:
-			n.RouteTypeSpec, err = UnmarshalEVPNMACIPAdvertisement(b[p : p+l])
-			if err != nil {
-				return nil, err
+		if p+l > len(b) {
+			return nil, &base.DecodeError{
+				Offset: p - 2,
+				Reason: fmt.Sprintf("not enough bytes to unmarshal EVPN route type %d of length %d", n.RouteType, l),
+				Action: base.AFISAFIDisable,
 			}
-		case 3:
-			n.RouteTypeSpec, err = UnmarshalEVPNInclusiveMulticastEthTag(b[p : p+l])
-			if err != nil {
-				return nil, err
-			}
-		case 4:
-			n.RouteTypeSpec, err = UnmarshalEVPNEthernetSegment(b[p : p+l])
-			if err != nil {
-				return nil, err
-			}
-		case 5:
-			n.RouteTypeSpec, err = UnmarshalEVPNIPPrefix(b[p : p+l])
-			if err != nil {
-				return nil, err
+		}
+		if n.RouteTypeSpec, err = unmarshalRouteTypeSpec(n.RouteType, b[p:p+l]); err != nil {
+			// Length of the route is known, malformed route is preserved as opaque route the same way
+			// as the route of unknown type, and the following routes are still processed
+			glog.Warningf("malformed EVPN route type %d with error: %+v, preserving it as opaque route", n.RouteType, err)
+			n.RouteTypeSpec = UnmarshalEVPNOpaque(b[p : p+l])
+			n.Err = &base.DecodeError{
+				Offset: p - 2,
+				Reason: fmt.Sprintf("malformed EVPN route type %d: %s", n.RouteType, err),
+				Action: base.TreatAsWithdraw,
 			}
-		default:
-			return nil, fmt.Errorf("unknown route type %d", n.RouteType)
 		}
 		r.Route = append(r.Route, n)
 		p += l
@@ -145,6 +159,38 @@ func UnmarshalEVPNNLRI(b []byte) (*Route, error) {
 	return &r, nil
 }
 
+// unmarshalRouteTypeSpec instantiates route type specific object, unknown route types are
+// preserved as Opaque routes.
+func unmarshalRouteTypeSpec(routeType uint8, b []byte) (RouteTypeSpec, error) {
+	switch routeType {
+	case 1:
+		return UnmarshalEVPNEthAutoDiscovery(b)
+	case 2:
+		return UnmarshalEVPNMACIPAdvertisement(b)
+	case 3:
+		return UnmarshalEVPNInclusiveMulticastEthTag(b)
+	case 4:
+		return UnmarshalEVPNEthernetSegment(b)
+	case 5:
+		return UnmarshalEVPNIPPrefix(b)
+	case 6:
+		return UnmarshalEVPNSelectiveMulticastEthTag(b)
+	case 7:
+		return UnmarshalEVPNMulticastSynch(b, false)
+	case 8:
+		return UnmarshalEVPNMulticastSynch(b, true)
+	case 9:
+		return UnmarshalEVPNPerRegionIPMSIAD(b)
+	case 10:
+		return UnmarshalEVPNSPMSIAD(b)
+	case 11:
+		return UnmarshalEVPNLeafAD(b)
+	}
+	glog.Warningf("unknown EVPN route type %d, preserving it as opaque route", routeType)
+
+	return UnmarshalEVPNOpaque(b), nil
+}
+
 // ESI defines 10 bytes of Ethernet Segment Identifier
 type ESI [10]byte
 
@@ -14,6 +14,18 @@ func TestUnmarshalEVPNNLRI(t *testing.T) {
 	mac, _ := MakeMACAddress([]byte{0x00, 0x81, 0xc4, 0xbc, 0x77, 0x8a})
 	mac2, _ := MakeMACAddress([]byte{0x00, 0x81, 0xc4, 0xbc, 0x77, 0x8a})
 	rd, _ := base.MakeRD([]byte{0x00, 0x01, 0xac, 0x1f, 0x65, 0x06, 0x00, 0x00})
+	rd2, _ := base.MakeRD([]byte{0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64})
+	spmsi := &NLRI{
+		RouteType: 10,
+		Length:    27,
+		RouteTypeSpec: &SPMSIAD{
+			RD:           rd2,
+			EthTag:       []byte{0, 0, 0, 0},
+			Source:       []byte{192, 168, 1, 1},
+			Group:        []byte{232, 1, 1, 1},
+			OriginatorIP: []byte{10, 0, 0, 1},
+		},
+	}
 	tests := []struct {
 		name   string
 		input  []byte
@@ -173,6 +185,150 @@ func TestUnmarshalEVPNNLRI(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "type 5 route nlri",
+			input: []byte{0x05, 0x22, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x18, 0x0a, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06, 0x41},
+			expect: &Route{
+				Route: []*NLRI{
+					{
+						RouteType: 5,
+						Length:    34,
+						RouteTypeSpec: &IPPrefix{
+							RD:           rd2,
+							ESI:          esi,
+							EthTag:       []byte{0, 0, 0, 0},
+							IPAddrLength: 24,
+							IPAddr:       []byte{10, 1, 1, 0},
+							GWIPAddr:     []byte{0, 0, 0, 0},
+							Label: []*base.Label{
+								{
+									Value: 100,
+									BoS:   true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "type 6 route nlri with wildcard source",
+			input: []byte{0x06, 0x18, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00, 0x20, 0xe8, 0x01, 0x01, 0x01, 0x20, 0x0a, 0x00, 0x00, 0x01, 0x04},
+			expect: &Route{
+				Route: []*NLRI{
+					{
+						RouteType: 6,
+						Length:    24,
+						RouteTypeSpec: &SelectiveMulticastEthTag{
+							RD:           rd2,
+							EthTag:       []byte{0, 0, 0, 0},
+							Source:       []byte{},
+							Group:        []byte{232, 1, 1, 1},
+							OriginatorIP: []byte{10, 0, 0, 1},
+							Flags:        IGMPFlagV3,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "type 7 and type 8 route nlri",
+			input: []byte{0x07, 0x26, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x20, 0xc0, 0xa8, 0x01, 0x01, 0x20, 0xe8, 0x01, 0x01, 0x01, 0x20, 0x0a, 0x00, 0x00, 0x01, 0x04, 0x08, 0x2b, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x20, 0xc0, 0xa8, 0x01, 0x01, 0x20, 0xe8, 0x01, 0x01, 0x01, 0x20, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x05, 0x0a, 0x04},
+			expect: &Route{
+				Route: []*NLRI{
+					{
+						RouteType: 7,
+						Length:    38,
+						RouteTypeSpec: &MulticastSynch{
+							RD:           rd2,
+							ESI:          esi,
+							EthTag:       []byte{0, 0, 0, 0},
+							Source:       []byte{192, 168, 1, 1},
+							Group:        []byte{232, 1, 1, 1},
+							OriginatorIP: []byte{10, 0, 0, 1},
+							Flags:        IGMPFlagV3,
+						},
+					},
+					{
+						RouteType: 8,
+						Length:    43,
+						RouteTypeSpec: &MulticastSynch{
+							RD:              rd2,
+							ESI:             esi,
+							EthTag:          []byte{0, 0, 0, 0},
+							Source:          []byte{192, 168, 1, 1},
+							Group:           []byte{232, 1, 1, 1},
+							OriginatorIP:    []byte{10, 0, 0, 1},
+							SequenceNumber:  5,
+							MaxResponseTime: 10,
+							Flags:           IGMPFlagV3,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "unknown route type followed by type 9 route nlri",
+			input: []byte{0x20, 0x03, 0x01, 0x02, 0x03, 0x09, 0x14, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x01},
+			expect: &Route{
+				Route: []*NLRI{
+					{
+						RouteType:     0x20,
+						Length:        3,
+						RouteTypeSpec: &Opaque{Value: []byte{0x01, 0x02, 0x03}},
+					},
+					{
+						RouteType: 9,
+						Length:    20,
+						RouteTypeSpec: &PerRegionIPMSIAD{
+							RD:       rd2,
+							EthTag:   []byte{0, 0, 0, 0},
+							RegionID: []byte{0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x01},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "malformed type 2 route followed by type 9 route nlri",
+			input: []byte{0x02, 0x03, 0x01, 0x02, 0x03, 0x09, 0x14, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x01},
+			expect: &Route{
+				Route: []*NLRI{
+					{
+						RouteType:     2,
+						Length:        3,
+						RouteTypeSpec: &Opaque{Value: []byte{0x01, 0x02, 0x03}},
+						Err:           &base.DecodeError{Reason: "malformed EVPN route type 2: invalid length of MAC/IP Advertisement route 3", Action: base.TreatAsWithdraw},
+					},
+					{
+						RouteType: 9,
+						Length:    20,
+						RouteTypeSpec: &PerRegionIPMSIAD{
+							RD:       rd2,
+							EthTag:   []byte{0, 0, 0, 0},
+							RegionID: []byte{0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x01},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "type 10 and type 11 route nlri",
+			input: []byte{0x0a, 0x1b, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0x00, 0x20, 0xc0, 0xa8, 0x01, 0x01, 0x20, 0xe8, 0x01, 0x01, 0x01, 0x20, 0x0a, 0x00, 0x00, 0x01, 0x0b, 0x21, 0x0a, 0x1b, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0x00, 0x20, 0xc0, 0xa8, 0x01, 0x01, 0x20, 0xe8, 0x01, 0x01, 0x01, 0x20, 0x0a, 0x00, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x02},
+			expect: &Route{
+				Route: []*NLRI{
+					spmsi,
+					{
+						RouteType: 11,
+						Length:    33,
+						RouteTypeSpec: &LeafAD{
+							RouteKey:     spmsi,
+							OriginatorIP: []byte{10, 0, 0, 2},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -186,3 +342,107 @@ func TestUnmarshalEVPNNLRI(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalEVPNNLRIError(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		offset int
+	}{
+		{
+			name:   "truncated route header",
+			input:  []byte{0x20, 0x03, 0x01, 0x02, 0x03, 0x09},
+			offset: 5,
+		},
+		{
+			name:   "route exceeds nlri",
+			input:  []byte{0x20, 0x03, 0x01, 0x02, 0x03, 0x09, 0x14, 0x00, 0x00},
+			offset: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UnmarshalEVPNNLRI(tt.input)
+			de, ok := err.(*base.DecodeError)
+			if !ok {
+				t.Fatalf("expected decode error but got %+v", err)
+			}
+			if de.Action != base.AFISAFIDisable || de.Offset != tt.offset {
+				t.Fatalf("expected %s at offset %d but got %s at offset %d", base.AFISAFIDisable, tt.offset, de.Action, de.Offset)
+			}
+		})
+	}
+}
+
+func TestUnmarshalEVPNIPPrefix(t *testing.T) {
+	rd := []byte{0x00, 0x01, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x64}
+	esi := make([]byte, 10)
+	tag := []byte{0x00, 0x00, 0x00, 0x0a}
+	label := []byte{0x00, 0x06, 0x41}
+	route := func(parts ...[]byte) []byte {
+		b := []byte{}
+		for _, p := range parts {
+			b = append(b, p...)
+		}
+		return b
+	}
+	tests := []struct {
+		name   string
+		input  []byte
+		fail   bool
+		length uint8
+		addr   []byte
+		gw     []byte
+	}{
+		{
+			name:   "ipv4 prefix",
+			input:  route(rd, esi, tag, []byte{0x18}, []byte{0x0a, 0x00, 0x00, 0x00}, []byte{0x0a, 0x00, 0x00, 0x01}, label),
+			length: 24,
+			addr:   []byte{0x0a, 0x00, 0x00, 0x00},
+			gw:     []byte{0x0a, 0x00, 0x00, 0x01},
+		},
+		{
+			name:   "ipv6 prefix",
+			input:  route(rd, esi, tag, []byte{0x40}, []byte{0x20, 0x01, 0x0d, 0xb8, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, make([]byte, 16), label),
+			length: 64,
+			addr:   []byte{0x20, 0x01, 0x0d, 0xb8, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			gw:     make([]byte, 16),
+		},
+		{
+			name:  "ipv4 prefix length exceeds address",
+			input: route(rd, esi, tag, []byte{0x21}, []byte{0x0a, 0x00, 0x00, 0x00}, []byte{0x0a, 0x00, 0x00, 0x01}, label),
+			fail:  true,
+		},
+		{
+			name:  "invalid route length",
+			input: route(rd, esi, tag, []byte{0x18}, []byte{0x0a, 0x00, 0x00}, []byte{0x0a, 0x00, 0x00, 0x01}, label),
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := UnmarshalEVPNIPPrefix(tt.input)
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("supposed to succeed but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if !reflect.DeepEqual(r.EthTag, tag) {
+				t.Errorf("expected ethernet tag %v but got %v", tag, r.EthTag)
+			}
+			if r.IPAddrLength != tt.length || !reflect.DeepEqual(r.IPAddr, tt.addr) {
+				t.Errorf("expected prefix %v/%d but got %v/%d", tt.addr, tt.length, r.IPAddr, r.IPAddrLength)
+			}
+			if !reflect.DeepEqual(r.GWIPAddr, tt.gw) {
+				t.Errorf("expected gateway %v but got %v", tt.gw, r.GWIPAddr)
+			}
+			if len(r.Label) != 1 || r.Label[0].Value != 100 {
+				t.Errorf("expected label 100 but got %+v", r.Label)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package evpn
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// MACMobility defines MAC Mobility Extended Community
+// https://tools.ietf.org/html/rfc7432#section-7.7
+type MACMobility struct {
+	Sticky         bool   `json:"sticky"`
+	SequenceNumber uint32 `json:"sequence_number"`
+}
+
+// UnmarshalMACMobility instantiates MAC Mobility object from 6 bytes of Extended Community value
+func UnmarshalMACMobility(b []byte) (*MACMobility, error) {
+	if len(b) != 6 {
+		return nil, fmt.Errorf("invalid length of MAC Mobility extended community %d", len(b))
+	}
+	return &MACMobility{
+		Sticky:         b[0]&0x01 != 0,
+		SequenceNumber: binary.BigEndian.Uint32(b[2:6]),
+	}, nil
+}
+
+// ESILabel defines ESI Label Extended Community
+// https://tools.ietf.org/html/rfc7432#section-7.5
+type ESILabel struct {
+	SingleActive bool   `json:"single_active"`
+	Label        uint32 `json:"label"`
+}
+
+// UnmarshalESILabel instantiates ESI Label object from 6 bytes of Extended Community value
+func UnmarshalESILabel(b []byte) (*ESILabel, error) {
+	if len(b) != 6 {
+		return nil, fmt.Errorf("invalid length of ESI Label extended community %d", len(b))
+	}
+	l, err := base.MakeLabel(b[3:6])
+	if err != nil {
+		return nil, err
+	}
+	return &ESILabel{
+		SingleActive: b[0]&0x01 != 0,
+		Label:        l.Value,
+	}, nil
+}
+
+// Layer2Attributes defines EVPN Layer 2 Attributes Extended Community
+// https://tools.ietf.org/html/rfc8214#section-3.1
+type Layer2Attributes struct {
+	Backup      bool   `json:"backup"`
+	Primary     bool   `json:"primary"`
+	ControlWord bool   `json:"control_word"`
+	MTU         uint16 `json:"mtu"`
+}
+
+// UnmarshalLayer2Attributes instantiates Layer 2 Attributes object from 6 bytes of Extended Community value
+func UnmarshalLayer2Attributes(b []byte) (*Layer2Attributes, error) {
+	if len(b) != 6 {
+		return nil, fmt.Errorf("invalid length of Layer 2 Attributes extended community %d", len(b))
+	}
+	flags := binary.BigEndian.Uint16(b[0:2])
+	return &Layer2Attributes{
+		Backup:      flags&0x0001 != 0,
+		Primary:     flags&0x0002 != 0,
+		ControlWord: flags&0x0004 != 0,
+		MTU:         binary.BigEndian.Uint16(b[2:4]),
+	}, nil
+}
+
+// DFElectionTypes defines names of DF Election algorithms
+// https://www.iana.org/assignments/bgp-extended-communities/bgp-extended-communities.xhtml#evpn-df-types
+var DFElectionTypes = map[uint8]string{
+	0: "Default",
+	1: "HRW",
+	2: "Highest-Preference",
+	3: "Lowest-Preference",
+	4: "Weighted HRW",
+}
+
+// DFElection defines DF Election Extended Community
+// https://tools.ietf.org/html/rfc8584#section-2.2
+type DFElection struct {
+	Type     uint8  `json:"type"`
+	TypeName string `json:"type_name,omitempty"`
+	// ACDF is set when AC-Influenced DF Election capability is signaled
+	ACDF   bool   `json:"ac_df"`
+	Bitmap uint16 `json:"bitmap"`
+}
+
+// UnmarshalDFElection instantiates DF Election object from 6 bytes of Extended Community value
+func UnmarshalDFElection(b []byte) (*DFElection, error) {
+	if len(b) != 6 {
+		return nil, fmt.Errorf("invalid length of DF Election extended community %d", len(b))
+	}
+	// DF Type occupies 5 low order bits of the first byte, the high order bits are reserved
+	df := &DFElection{
+		Type:   b[0] & 0x1f,
+		Bitmap: binary.BigEndian.Uint16(b[1:3]),
+	}
+	df.TypeName = DFElectionTypes[df.Type]
+	df.ACDF = df.Bitmap&0x4000 != 0
+
+	return df, nil
+}
+
+// EncapsulationTypes defines names of BGP Tunnel Encapsulation types carried by Encapsulation Extended Community
+// https://www.iana.org/assignments/bgp-parameters/bgp-parameters.xhtml#tunnel-types
+var EncapsulationTypes = map[uint16]string{
+	1:  "L2TPv3",
+	2:  "GRE",
+	7:  "IP in IP",
+	8:  "VXLAN",
+	9:  "NVGRE",
+	10: "MPLS",
+	11: "MPLS in GRE",
+	12: "VXLAN GPE",
+	13: "MPLS in UDP",
+	14: "IPv6 Tunnel",
+	15: "SR TE Policy",
+	16: "Bare",
+	19: "Geneve",
+}
+
+// GetEncapsulationName returns the name of Tunnel Encapsulation type carried by Encapsulation Extended Community
+// https://tools.ietf.org/html/rfc9012#section-4.1
+func GetEncapsulationName(t uint16) string {
+	if n, ok := EncapsulationTypes[t]; ok {
+		return n
+	}
+
+	return fmt.Sprintf("Unknown(%d)", t)
+}
@@ -0,0 +1,126 @@
+package evpn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalEVPNExtCommunity(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []byte
+		unmarshal func([]byte) (interface{}, error)
+		expect    interface{}
+		fail      bool
+	}{
+		{
+			name:      "mac mobility sticky",
+			input:     []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x05},
+			unmarshal: func(b []byte) (interface{}, error) { return UnmarshalMACMobility(b) },
+			expect:    &MACMobility{Sticky: true, SequenceNumber: 5},
+		},
+		{
+			name:      "mac mobility invalid length",
+			input:     []byte{0x00, 0x00, 0x00, 0x05},
+			unmarshal: func(b []byte) (interface{}, error) { return UnmarshalMACMobility(b) },
+			fail:      true,
+		},
+		{
+			name:      "esi label single-active",
+			input:     []byte{0x01, 0x00, 0x00, 0x00, 0x3e, 0x81},
+			unmarshal: func(b []byte) (interface{}, error) { return UnmarshalESILabel(b) },
+			expect:    &ESILabel{SingleActive: true, Label: 1000},
+		},
+		{
+			name:      "layer 2 attributes",
+			input:     []byte{0x00, 0x06, 0x05, 0xdc, 0x00, 0x00},
+			unmarshal: func(b []byte) (interface{}, error) { return UnmarshalLayer2Attributes(b) },
+			expect:    &Layer2Attributes{Primary: true, ControlWord: true, MTU: 1500},
+		},
+		{
+			name:      "df election highest preference with ac-df",
+			input:     []byte{0x02, 0x40, 0x00, 0x00, 0x00, 0x00},
+			unmarshal: func(b []byte) (interface{}, error) { return UnmarshalDFElection(b) },
+			expect:    &DFElection{Type: 2, TypeName: "Highest-Preference", ACDF: true, Bitmap: 0x4000},
+		},
+		{
+			name:      "df election lowest preference with reserved bits set",
+			input:     []byte{0xe3, 0x00, 0x00, 0x00, 0x00, 0x00},
+			unmarshal: func(b []byte) (interface{}, error) { return UnmarshalDFElection(b) },
+			expect:    &DFElection{Type: 3, TypeName: "Lowest-Preference"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.unmarshal(tt.input)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("expected %+v does not match computed %+v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestESIInfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  ESI
+		str    string
+		expect *ESIInfo
+	}{
+		{
+			name:   "type 0",
+			input:  ESI{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99},
+			str:    "00:11:22:33:44:55:66:77:88:99",
+			expect: &ESIInfo{Type: ESIType0, TypeName: "Arbitrary", Value: "112233445566778899"},
+		},
+		{
+			name:   "type 1",
+			input:  ESI{0x01, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x00, 0x0a, 0x00},
+			str:    "01:00:11:22:33:44:55:00:0a:00",
+			expect: &ESIInfo{Type: ESIType1, TypeName: "LACP", SystemMAC: "00:11:22:33:44:55", PortKey: 10},
+		},
+		{
+			name:   "type 2",
+			input:  ESI{0x02, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x80, 0x00, 0x00},
+			expect: &ESIInfo{Type: ESIType2, TypeName: "MST", SystemMAC: "00:11:22:33:44:55", Priority: 32768},
+		},
+		{
+			name:   "type 3",
+			input:  ESI{0x03, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x01, 0x00, 0x01},
+			expect: &ESIInfo{Type: ESIType3, TypeName: "MAC", SystemMAC: "00:11:22:33:44:55", Discriminator: 65537},
+		},
+		{
+			name:   "type 4",
+			input:  ESI{0x04, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x64, 0x00},
+			expect: &ESIInfo{Type: ESIType4, TypeName: "Router ID", RouterID: "10.0.0.1", Discriminator: 100},
+		},
+		{
+			name:   "type 5",
+			input:  ESI{0x05, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00},
+			expect: &ESIInfo{Type: ESIType5, TypeName: "AS", AS: 65000, Discriminator: 100},
+		},
+		{
+			name:  "unknown type",
+			input: ESI{0x06},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.str != "" && tt.str != tt.input.String() {
+				t.Fatalf("expected string %s does not match computed %s", tt.str, tt.input.String())
+			}
+			if got := tt.input.GetInfo(); !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("expected %+v does not match computed %+v", tt.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+//go:build go1.18
+// +build go1.18
+
+package evpn
+
+import "testing"
+
+func FuzzUnmarshalEVPNEthAutoDiscovery(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalEVPNEthAutoDiscovery(b)
+	})
+}
+
+func FuzzUnmarshalEVPNIPPrefix(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalEVPNIPPrefix(b)
+	})
+}
+
+func FuzzUnmarshalEVPNNLRI(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalEVPNNLRI(b)
+	})
+}
+
+func FuzzUnmarshalEVPNInclusiveMulticastEthTag(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalEVPNInclusiveMulticastEthTag(b)
+	})
+}
+
+func FuzzUnmarshalEVPNMACIPAdvertisement(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalEVPNMACIPAdvertisement(b)
+	})
+}
+
+func FuzzUnmarshalEVPNEthernetSegment(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalEVPNEthernetSegment(b)
+	})
+}
+
+func FuzzUnmarshalEVPNSelectiveMulticastEthTag(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalEVPNSelectiveMulticastEthTag(b)
+	})
+}
+
+func FuzzUnmarshalEVPNMulticastSynch(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte, leave bool) {
+		UnmarshalEVPNMulticastSynch(b, leave)
+	})
+}
+
+func FuzzUnmarshalEVPNSPMSIAD(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalEVPNSPMSIAD(b)
+	})
+}
+
+func FuzzUnmarshalEVPNLeafAD(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalEVPNLeafAD(b)
+	})
+}
@@ -1,6 +1,10 @@
 package evpn
 
-import "github.com/sbezverk/gobmp/pkg/base"
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
 
 // InclusiveMulticastEthTag defines a structure of Route type 3
 // (Inclusive Multicast Ethernet Tag Route type)
@@ -51,10 +55,25 @@ func (t *InclusiveMulticastEthTag) getLabel() []*base.Label {
 	return nil
 }
 
+func (t *InclusiveMulticastEthTag) getMulticastSource() []byte {
+	return nil
+}
+
+func (t *InclusiveMulticastEthTag) getMulticastGroup() []byte {
+	return nil
+}
+
+func (t *InclusiveMulticastEthTag) getOriginatorIP() []byte {
+	return nil
+}
+
 // UnmarshalEVPNInclusiveMulticastEthTag instantiates new instance of an Inclusive Multicast Ethernet Tag Route type object
 func UnmarshalEVPNInclusiveMulticastEthTag(b []byte) (*InclusiveMulticastEthTag, error) {
 	var err error
 	t := InclusiveMulticastEthTag{}
+	if len(b) < 13 {
+		return nil, fmt.Errorf("invalid length of Inclusive Multicast Ethernet Tag route %d", len(b))
+	}
 	p := 0
 	t.RD, err = base.MakeRD(b[p : p+8])
 	if err != nil {
@@ -67,6 +86,9 @@ func UnmarshalEVPNInclusiveMulticastEthTag(b []byte) (*InclusiveMulticastEthTag,
 	t.IPAddrLength = b[p]
 	p++
 	l := int(t.IPAddrLength / 8)
+	if p+l > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal IP address of length %d", l)
+	}
 	if t.IPAddrLength != 0 {
 		t.IPAddr = make([]byte, l)
 		copy(t.IPAddr, b[p:p+l])
@@ -1,6 +1,10 @@
 package evpn
 
-import "github.com/sbezverk/gobmp/pkg/base"
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
 
 // IPPrefix defines a structure of Route type 5
 // (IP Prefix route)
@@ -28,7 +32,7 @@ func (t *IPPrefix) getESI() *ESI {
 }
 
 func (t *IPPrefix) getTag() []byte {
-	return nil
+	return t.EthTag
 }
 
 func (t *IPPrefix) getMAC() *MACAddress {
@@ -55,10 +59,33 @@ func (t *IPPrefix) getLabel() []*base.Label {
 	return t.Label
 }
 
+func (t *IPPrefix) getMulticastSource() []byte {
+	return nil
+}
+
+func (t *IPPrefix) getMulticastGroup() []byte {
+	return nil
+}
+
+func (t *IPPrefix) getOriginatorIP() []byte {
+	return nil
+}
+
 // UnmarshalEVPNIPPrefix instantiates new IP Prefix route type object
 func UnmarshalEVPNIPPrefix(b []byte) (*IPPrefix, error) {
 	var err error
 	t := IPPrefix{}
+	// IP Prefix and Gateway IP are 4 bytes each for IPv4 route of 34 bytes and 16 bytes each
+	// for IPv6 route of 58 bytes regardless of the prefix length, rfc9136
+	var l int
+	switch len(b) {
+	case 34:
+		l = 4
+	case 58:
+		l = 16
+	default:
+		return nil, fmt.Errorf("invalid length of IP Prefix route %d", len(b))
+	}
 	p := 0
 	t.RD, err = base.MakeRD(b[p : p+8])
 	if err != nil {
@@ -72,9 +99,12 @@ func UnmarshalEVPNIPPrefix(b []byte) (*IPPrefix, error) {
 	p += 10
 	t.EthTag = make([]byte, 4)
 	copy(t.EthTag, b[p:p+4])
+	p += 4
 	t.IPAddrLength = b[p]
 	p++
-	l := int(t.IPAddrLength / 8)
+	if int(t.IPAddrLength) > l*8 {
+		return nil, fmt.Errorf("invalid IP Prefix length %d", t.IPAddrLength)
+	}
 	t.IPAddr = make([]byte, l)
 	copy(t.IPAddr, b[p:p+l])
 	p += l
@@ -0,0 +1,103 @@
+package evpn
+
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// LeafAD defines a structure of Route type 11
+// (Leaf A-D route)
+type LeafAD struct {
+	// RouteKey is the route Leaf A-D route is sent in response to
+	RouteKey     *NLRI
+	OriginatorIP []byte
+}
+
+// GetRouteTypeSpec returns the instance of the Leaf A-D route object
+func (t *LeafAD) GetRouteTypeSpec() interface{} {
+	return t
+}
+
+func (t *LeafAD) getRD() string {
+	return t.RouteKey.getRD()
+}
+
+func (t *LeafAD) getESI() *ESI {
+	return nil
+}
+
+func (t *LeafAD) getTag() []byte {
+	return t.RouteKey.getTag()
+}
+
+func (t *LeafAD) getMAC() *MACAddress {
+	return nil
+}
+
+func (t *LeafAD) getMACLength() *uint8 {
+	return nil
+}
+
+func (t *LeafAD) getIPAddress() []byte {
+	return nil
+}
+
+func (t *LeafAD) getIPLength() *uint8 {
+	return nil
+}
+
+func (t *LeafAD) getGWAddress() []byte {
+	return nil
+}
+
+func (t *LeafAD) getLabel() []*base.Label {
+	return nil
+}
+
+func (t *LeafAD) getMulticastSource() []byte {
+	return t.RouteKey.getMulticastSource()
+}
+
+func (t *LeafAD) getMulticastGroup() []byte {
+	return t.RouteKey.getMulticastGroup()
+}
+
+func (t *LeafAD) getOriginatorIP() []byte {
+	return t.OriginatorIP
+}
+
+// UnmarshalEVPNLeafAD instantiates new Leaf A-D route object, Route Key is followed by
+// Originating Router's IP address of 4 or 16 bytes.
+func UnmarshalEVPNLeafAD(b []byte) (*LeafAD, error) {
+	var err error
+	t := LeafAD{}
+	if len(b) < 6 {
+		return nil, fmt.Errorf("invalid length of Leaf A-D route %d", len(b))
+	}
+	p := 0
+	k := &NLRI{
+		RouteType: b[p],
+		Length:    b[p+1],
+	}
+	p += 2
+	l := int(k.Length)
+	if p+l > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal Leaf A-D route key of length %d", l)
+	}
+	if k.RouteTypeSpec, err = unmarshalRouteTypeSpec(k.RouteType, b[p:p+l]); err != nil {
+		return nil, err
+	}
+	p += l
+	switch len(b) - p {
+	case 4:
+	case 16:
+	default:
+		return nil, fmt.Errorf("invalid length of Leaf A-D originating router's ip address %d", len(b)-p)
+	}
+	t.RouteKey = k
+	t.OriginatorIP = make([]byte, len(b)-p)
+	copy(t.OriginatorIP, b[p:])
+
+	return &t, nil
+}
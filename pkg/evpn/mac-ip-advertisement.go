@@ -1,6 +1,10 @@
 package evpn
 
-import "github.com/sbezverk/gobmp/pkg/base"
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
 
 // MACIPAdvertisement defines a structure of Route type 2
 // (MAC IP Advertisement route)
@@ -56,10 +60,25 @@ func (t *MACIPAdvertisement) getLabel() []*base.Label {
 	return t.Label
 }
 
+func (t *MACIPAdvertisement) getMulticastSource() []byte {
+	return nil
+}
+
+func (t *MACIPAdvertisement) getMulticastGroup() []byte {
+	return nil
+}
+
+func (t *MACIPAdvertisement) getOriginatorIP() []byte {
+	return nil
+}
+
 // UnmarshalEVPNMACIPAdvertisement instantiates new instance of a Ethernet Auto Discovery route type object
 func UnmarshalEVPNMACIPAdvertisement(b []byte) (*MACIPAdvertisement, error) {
 	var err error
 	t := MACIPAdvertisement{}
+	if len(b) < 23 {
+		return nil, fmt.Errorf("invalid length of MAC/IP Advertisement route %d", len(b))
+	}
 	p := 0
 	t.RD, err = base.MakeRD(b[p : p+8])
 	if err != nil {
@@ -77,6 +96,10 @@ func UnmarshalEVPNMACIPAdvertisement(b []byte) (*MACIPAdvertisement, error) {
 	t.MACAddrLength = b[p]
 	p++
 	l := int(t.MACAddrLength / 8)
+	// MAC address is followed by at least IP address length
+	if p+l >= len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal MAC address of length %d", l)
+	}
 	if l != 0 {
 		t.MACAddr, err = MakeMACAddress(b[p : p+l])
 		if err != nil {
@@ -87,12 +110,18 @@ func UnmarshalEVPNMACIPAdvertisement(b []byte) (*MACIPAdvertisement, error) {
 	t.IPAddrLength = b[p]
 	p++
 	l = int(t.IPAddrLength / 8)
+	if p+l > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal IP address of length %d", l)
+	}
 	if t.IPAddrLength != 0 {
 		t.IPAddr = make([]byte, l)
 		copy(t.IPAddr, b[p:p+l])
 		p += l
 	}
 	for i := 0; p < len(b); i++ {
+		if p+3 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal label")
+		}
 		l, err := base.MakeLabel(b[p : p+3])
 		if err != nil {
 			return nil, err
@@ -0,0 +1,126 @@
+package evpn
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// MulticastSynch defines a structure of Route types 7 and 8
+// (Multicast Membership Report Synch and Multicast Leave Synch routes)
+type MulticastSynch struct {
+	RD           *base.RD
+	ESI          *ESI
+	EthTag       []byte
+	Source       []byte
+	Group        []byte
+	OriginatorIP []byte
+	// SequenceNumber and MaxResponseTime are carried only by Leave Synch route
+	SequenceNumber  uint32
+	MaxResponseTime uint8
+	Flags           uint8
+}
+
+// GetRouteTypeSpec returns the instance of the Multicast Synch route object
+func (t *MulticastSynch) GetRouteTypeSpec() interface{} {
+	return t
+}
+
+func (t *MulticastSynch) getRD() string {
+	return t.RD.String()
+}
+
+func (t *MulticastSynch) getESI() *ESI {
+	return t.ESI
+}
+
+func (t *MulticastSynch) getTag() []byte {
+	return t.EthTag
+}
+
+func (t *MulticastSynch) getMAC() *MACAddress {
+	return nil
+}
+
+func (t *MulticastSynch) getMACLength() *uint8 {
+	return nil
+}
+
+func (t *MulticastSynch) getIPAddress() []byte {
+	return nil
+}
+
+func (t *MulticastSynch) getIPLength() *uint8 {
+	return nil
+}
+
+func (t *MulticastSynch) getGWAddress() []byte {
+	return nil
+}
+
+func (t *MulticastSynch) getLabel() []*base.Label {
+	return nil
+}
+
+func (t *MulticastSynch) getMulticastSource() []byte {
+	return t.Source
+}
+
+func (t *MulticastSynch) getMulticastGroup() []byte {
+	return t.Group
+}
+
+func (t *MulticastSynch) getOriginatorIP() []byte {
+	return t.OriginatorIP
+}
+
+// UnmarshalEVPNMulticastSynch instantiates new Multicast Membership Report Synch route object,
+// or Multicast Leave Synch route object when leave is true.
+func UnmarshalEVPNMulticastSynch(b []byte, leave bool) (*MulticastSynch, error) {
+	var err error
+	t := MulticastSynch{}
+	if len(b) < 26 {
+		return nil, fmt.Errorf("invalid length of Multicast Synch route %d", len(b))
+	}
+	p := 0
+	if t.RD, err = base.MakeRD(b[p : p+8]); err != nil {
+		return nil, err
+	}
+	p += 8
+	if t.ESI, err = MakeESI(b[p : p+10]); err != nil {
+		return nil, err
+	}
+	p += 10
+	t.EthTag = make([]byte, 4)
+	copy(t.EthTag, b[p:p+4])
+	p += 4
+	l := 0
+	if t.Source, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if t.Group, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if t.OriginatorIP, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if leave {
+		if p+5 > len(b) {
+			return nil, fmt.Errorf("invalid length of Multicast Leave Synch route %d", len(b))
+		}
+		t.SequenceNumber = binary.BigEndian.Uint32(b[p : p+4])
+		p += 4
+		t.MaxResponseTime = b[p]
+		p++
+	}
+	if p+1 != len(b) {
+		return nil, fmt.Errorf("invalid length of Multicast Synch route %d", len(b))
+	}
+	t.Flags = b[p]
+
+	return &t, nil
+}
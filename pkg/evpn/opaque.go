@@ -0,0 +1,74 @@
+package evpn
+
+import (
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// Opaque defines a structure of a route of unknown type, the route is preserved
+// without decoding
+type Opaque struct {
+	Value []byte
+}
+
+// GetRouteTypeSpec returns the instance of the Opaque route object
+func (t *Opaque) GetRouteTypeSpec() interface{} {
+	return t
+}
+
+func (t *Opaque) getRD() string {
+	return ""
+}
+
+func (t *Opaque) getESI() *ESI {
+	return nil
+}
+
+func (t *Opaque) getTag() []byte {
+	return nil
+}
+
+func (t *Opaque) getMAC() *MACAddress {
+	return nil
+}
+
+func (t *Opaque) getMACLength() *uint8 {
+	return nil
+}
+
+func (t *Opaque) getIPAddress() []byte {
+	return nil
+}
+
+func (t *Opaque) getIPLength() *uint8 {
+	return nil
+}
+
+func (t *Opaque) getGWAddress() []byte {
+	return nil
+}
+
+func (t *Opaque) getLabel() []*base.Label {
+	return nil
+}
+
+func (t *Opaque) getMulticastSource() []byte {
+	return nil
+}
+
+func (t *Opaque) getMulticastGroup() []byte {
+	return nil
+}
+
+func (t *Opaque) getOriginatorIP() []byte {
+	return nil
+}
+
+// UnmarshalEVPNOpaque instantiates new Opaque route object
+func UnmarshalEVPNOpaque(b []byte) *Opaque {
+	t := Opaque{
+		Value: make([]byte, len(b)),
+	}
+	copy(t.Value, b)
+
+	return &t
+}
@@ -0,0 +1,90 @@
+package evpn
+
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// PerRegionIPMSIAD defines a structure of Route type 9
+// (Per-Region I-PMSI A-D route)
+type PerRegionIPMSIAD struct {
+	RD     *base.RD
+	EthTag []byte
+	// RegionID is encoded as Extended Community
+	RegionID []byte
+}
+
+// GetRouteTypeSpec returns the instance of the Per-Region I-PMSI A-D route object
+func (t *PerRegionIPMSIAD) GetRouteTypeSpec() interface{} {
+	return t
+}
+
+func (t *PerRegionIPMSIAD) getRD() string {
+	return t.RD.String()
+}
+
+func (t *PerRegionIPMSIAD) getESI() *ESI {
+	return nil
+}
+
+func (t *PerRegionIPMSIAD) getTag() []byte {
+	return t.EthTag
+}
+
+func (t *PerRegionIPMSIAD) getMAC() *MACAddress {
+	return nil
+}
+
+func (t *PerRegionIPMSIAD) getMACLength() *uint8 {
+	return nil
+}
+
+func (t *PerRegionIPMSIAD) getIPAddress() []byte {
+	return nil
+}
+
+func (t *PerRegionIPMSIAD) getIPLength() *uint8 {
+	return nil
+}
+
+func (t *PerRegionIPMSIAD) getGWAddress() []byte {
+	return nil
+}
+
+func (t *PerRegionIPMSIAD) getLabel() []*base.Label {
+	return nil
+}
+
+func (t *PerRegionIPMSIAD) getMulticastSource() []byte {
+	return nil
+}
+
+func (t *PerRegionIPMSIAD) getMulticastGroup() []byte {
+	return nil
+}
+
+func (t *PerRegionIPMSIAD) getOriginatorIP() []byte {
+	return nil
+}
+
+// UnmarshalEVPNPerRegionIPMSIAD instantiates new Per-Region I-PMSI A-D route object
+func UnmarshalEVPNPerRegionIPMSIAD(b []byte) (*PerRegionIPMSIAD, error) {
+	var err error
+	t := PerRegionIPMSIAD{}
+	if len(b) != 20 {
+		return nil, fmt.Errorf("invalid length of Per-Region I-PMSI A-D route %d", len(b))
+	}
+	p := 0
+	if t.RD, err = base.MakeRD(b[p : p+8]); err != nil {
+		return nil, err
+	}
+	p += 8
+	t.EthTag = make([]byte, 4)
+	copy(t.EthTag, b[p:p+4])
+	p += 4
+	t.RegionID = make([]byte, 8)
+	copy(t.RegionID, b[p:p+8])
+
+	return &t, nil
+}
@@ -0,0 +1,116 @@
+package evpn
+
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// IGMP/MLD Flags of Selective Multicast Ethernet Tag and Multicast Synch routes
+// https://tools.ietf.org/html/rfc9251#section-9.1
+const (
+	IGMPFlagV1      = 0x01
+	IGMPFlagV2      = 0x02
+	IGMPFlagV3      = 0x04
+	IGMPFlagExclude = 0x08
+)
+
+// SelectiveMulticastEthTag defines a structure of Route type 6
+// (Selective Multicast Ethernet Tag route)
+type SelectiveMulticastEthTag struct {
+	RD           *base.RD
+	EthTag       []byte
+	Source       []byte
+	Group        []byte
+	OriginatorIP []byte
+	Flags        uint8
+}
+
+// GetRouteTypeSpec returns the instance of the Selective Multicast Ethernet Tag route object
+func (t *SelectiveMulticastEthTag) GetRouteTypeSpec() interface{} {
+	return t
+}
+
+func (t *SelectiveMulticastEthTag) getRD() string {
+	return t.RD.String()
+}
+
+func (t *SelectiveMulticastEthTag) getESI() *ESI {
+	return nil
+}
+
+func (t *SelectiveMulticastEthTag) getTag() []byte {
+	return t.EthTag
+}
+
+func (t *SelectiveMulticastEthTag) getMAC() *MACAddress {
+	return nil
+}
+
+func (t *SelectiveMulticastEthTag) getMACLength() *uint8 {
+	return nil
+}
+
+func (t *SelectiveMulticastEthTag) getIPAddress() []byte {
+	return nil
+}
+
+func (t *SelectiveMulticastEthTag) getIPLength() *uint8 {
+	return nil
+}
+
+func (t *SelectiveMulticastEthTag) getGWAddress() []byte {
+	return nil
+}
+
+func (t *SelectiveMulticastEthTag) getLabel() []*base.Label {
+	return nil
+}
+
+func (t *SelectiveMulticastEthTag) getMulticastSource() []byte {
+	return t.Source
+}
+
+func (t *SelectiveMulticastEthTag) getMulticastGroup() []byte {
+	return t.Group
+}
+
+func (t *SelectiveMulticastEthTag) getOriginatorIP() []byte {
+	return t.OriginatorIP
+}
+
+// UnmarshalEVPNSelectiveMulticastEthTag instantiates new Selective Multicast Ethernet Tag route object
+func UnmarshalEVPNSelectiveMulticastEthTag(b []byte) (*SelectiveMulticastEthTag, error) {
+	var err error
+	t := SelectiveMulticastEthTag{}
+	if len(b) < 16 {
+		return nil, fmt.Errorf("invalid length of Selective Multicast Ethernet Tag route %d", len(b))
+	}
+	p := 0
+	if t.RD, err = base.MakeRD(b[p : p+8]); err != nil {
+		return nil, err
+	}
+	p += 8
+	t.EthTag = make([]byte, 4)
+	copy(t.EthTag, b[p:p+4])
+	p += 4
+	l := 0
+	if t.Source, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if t.Group, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if t.OriginatorIP, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if p+1 != len(b) {
+		return nil, fmt.Errorf("invalid length of Selective Multicast Ethernet Tag route %d", len(b))
+	}
+	t.Flags = b[p]
+
+	return &t, nil
+}
@@ -0,0 +1,105 @@
+package evpn
+
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// SPMSIAD defines a structure of Route type 10
+// (S-PMSI A-D route)
+type SPMSIAD struct {
+	RD           *base.RD
+	EthTag       []byte
+	Source       []byte
+	Group        []byte
+	OriginatorIP []byte
+}
+
+// GetRouteTypeSpec returns the instance of the S-PMSI A-D route object
+func (t *SPMSIAD) GetRouteTypeSpec() interface{} {
+	return t
+}
+
+func (t *SPMSIAD) getRD() string {
+	return t.RD.String()
+}
+
+func (t *SPMSIAD) getESI() *ESI {
+	return nil
+}
+
+func (t *SPMSIAD) getTag() []byte {
+	return t.EthTag
+}
+
+func (t *SPMSIAD) getMAC() *MACAddress {
+	return nil
+}
+
+func (t *SPMSIAD) getMACLength() *uint8 {
+	return nil
+}
+
+func (t *SPMSIAD) getIPAddress() []byte {
+	return nil
+}
+
+func (t *SPMSIAD) getIPLength() *uint8 {
+	return nil
+}
+
+func (t *SPMSIAD) getGWAddress() []byte {
+	return nil
+}
+
+func (t *SPMSIAD) getLabel() []*base.Label {
+	return nil
+}
+
+func (t *SPMSIAD) getMulticastSource() []byte {
+	return t.Source
+}
+
+func (t *SPMSIAD) getMulticastGroup() []byte {
+	return t.Group
+}
+
+func (t *SPMSIAD) getOriginatorIP() []byte {
+	return t.OriginatorIP
+}
+
+// UnmarshalEVPNSPMSIAD instantiates new S-PMSI A-D route object
+func UnmarshalEVPNSPMSIAD(b []byte) (*SPMSIAD, error) {
+	var err error
+	t := SPMSIAD{}
+	if len(b) < 15 {
+		return nil, fmt.Errorf("invalid length of S-PMSI A-D route %d", len(b))
+	}
+	p := 0
+	if t.RD, err = base.MakeRD(b[p : p+8]); err != nil {
+		return nil, err
+	}
+	p += 8
+	t.EthTag = make([]byte, 4)
+	copy(t.EthTag, b[p:p+4])
+	p += 4
+	l := 0
+	if t.Source, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if t.Group, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if t.OriginatorIP, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if p != len(b) {
+		return nil, fmt.Errorf("invalid length of S-PMSI A-D route %d", len(b))
+	}
+
+	return &t, nil
+}
@@ -1,35 +1,102 @@
 package filer
 
 import (
+	"compress/gzip"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/golang/glog"
+	"github.com/klauspost/compress/zstd"
 	"github.com/sbezverk/gobmp/pkg/pub"
 )
 
+const (
+	// segmentTimeFormat defines the format of the timestamp appended to the name of a rotated segment,
+	// the format sorts lexicographically in the chronological order.
+	segmentTimeFormat = "20060102T150405.000000000"
+	gzipExt           = ".gz"
+	zstdExt           = ".zst"
+	tmpExt            = ".tmp"
+)
+
+const (
+	// CompressNone defines no compression of rotated segments
+	CompressNone = "none"
+	// CompressGzip defines gzip compression of rotated segments
+	CompressGzip = "gzip"
+	// CompressZstd defines zstd compression of rotated segments
+	CompressZstd = "zstd"
+)
+
 // MsgOut defines structure of the message stored in the file.
 type MsgOut struct {
 	Type  int    `json:"type,omitempty"`
 	Key   []byte `json:"key,omitempty"`
 	Value []byte `json:"value,omitempty"`
+	// Timestamp is the time the message was stored in nanoseconds since Unix epoch
+	Timestamp int64 `json:"timestamp,omitempty"`
+}
+
+// Config defines rotation, compression and retention parameters of the messages file,
+// zero value of any parameter disables the corresponding functionality.
+type Config struct {
+	// MaxSize is the size in bytes after which the messages file gets rotated.
+	MaxSize int64
+	// RotateInterval is the time after which the messages file gets rotated.
+	RotateInterval time.Duration
+	// Compress defines compression of rotated segments, "none", "gzip" or "zstd".
+	Compress string
+	// MaxSegments is the maximum number of rotated segments to keep.
+	MaxSegments int
+	// MaxAge is the maximum age of a rotated segment to keep.
+	MaxAge time.Duration
 }
 
 type pubfiler struct {
-	file *os.File
+	sync.Mutex
+	name   string
+	config Config
+	file   *os.File
+	size   int64
+	opened time.Time
+	// rotated queues rotated segments for compression and retention, a single worker
+	// processes them in the rotation order, one at a time.
+	rotated []string
+	kick    chan struct{}
+	done    chan struct{}
 }
 
 func (p *pubfiler) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
 	m := MsgOut{
-		Type:  msgType,
-		Key:   msgHash,
-		Value: msg,
+		Type:      msgType,
+		Key:       msgHash,
+		Value:     msg,
+		Timestamp: time.Now().UnixNano(),
 	}
 	b, err := json.Marshal(&m)
 	if err != nil {
 		return err
 	}
 	b = append(b, '\n')
-	_, err = p.file.Write(b)
+	p.Lock()
+	defer p.Unlock()
+	if p.file == nil {
+		return fmt.Errorf("messages file %s is closed", p.name)
+	}
+	if p.needRotation(int64(len(b))) {
+		if err := p.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := p.file.Write(b)
+	p.size += int64(n)
 	if err != nil {
 		return err
 	}
@@ -38,18 +105,315 @@ func (p *pubfiler) PublishMessage(msgType int, msgHash []byte, msg []byte) error
 }
 
 func (p *pubfiler) Stop() {
-	p.file.Close()
+	p.Lock()
+	if p.file != nil {
+		if err := p.file.Close(); err != nil {
+			glog.Errorf("fail to close messages file %s with error: %+v", p.name, err)
+		}
+		p.file = nil
+	}
+	// The file is nil already when rotation failed to reopen it, the worker still needs to be stopped
+	if p.kick != nil {
+		close(p.kick)
+		p.kick = nil
+	}
+	p.Unlock()
+	<-p.done
+}
+
+// needRotation returns true when writing l bytes would exceed configured size or
+// when the current file was opened longer than the rotation interval ago.
+func (p *pubfiler) needRotation(l int64) bool {
+	if p.size == 0 {
+		return false
+	}
+	if p.config.MaxSize > 0 && p.size+l > p.config.MaxSize {
+		return true
+	}
+	if p.config.RotateInterval > 0 && time.Since(p.opened) >= p.config.RotateInterval {
+		return true
+	}
+
+	return false
+}
+
+// rotate closes the current file, renames it into a segment and opens a new file,
+// compression and retention of segments are done in background by the worker.
+func (p *pubfiler) rotate() error {
+	if err := p.file.Close(); err != nil {
+		return fmt.Errorf("fail to close messages file %s with error: %+v", p.name, err)
+	}
+	p.file = nil
+	segment := p.name + "." + time.Now().UTC().Format(segmentTimeFormat)
+	if err := os.Rename(p.name, segment); err != nil {
+		return fmt.Errorf("fail to rename messages file %s to %s with error: %+v", p.name, segment, err)
+	}
+	if err := p.open(); err != nil {
+		return err
+	}
+	p.rotated = append(p.rotated, segment)
+	select {
+	case p.kick <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// worker compresses rotated segments and applies retention after each of them, so retention
+// never races with compression of a newer segment. The worker exits when the filer is stopped
+// and all queued segments are processed.
+func (p *pubfiler) worker(kick <-chan struct{}) {
+	defer close(p.done)
+	for range kick {
+		for {
+			p.Lock()
+			if len(p.rotated) == 0 {
+				p.Unlock()
+				break
+			}
+			segment := p.rotated[0]
+			p.rotated = p.rotated[1:]
+			p.Unlock()
+			if err := compressSegment(segment, p.config.Compress); err != nil {
+				glog.Errorf("fail to compress segment %s with error: %+v", segment, err)
+			}
+			if err := p.purge(); err != nil {
+				glog.Errorf("fail to apply retention to segments of %s with error: %+v", p.name, err)
+			}
+		}
+	}
 }
 
-// NewFiler returns a new instance of message filer
-func NewFiler(file string) pub.Publisher {
-	f, err := os.Create(file)
+// open opens the messages file for appending, preserving already stored messages.
+func (p *pubfiler) open() error {
+	f, err := os.OpenFile(p.name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	p.file = f
+	p.size = fi.Size()
+	p.opened = time.Now()
+
+	return nil
+}
+
+// purge removes rotated segments exceeding configured count or age.
+func (p *pubfiler) purge() error {
+	if p.config.MaxSegments <= 0 && p.config.MaxAge <= 0 {
+		return nil
+	}
+	segments, err := rotatedSegments(p.name)
 	if err != nil {
+		return err
+	}
+	for i, s := range segments {
+		remove := false
+		if p.config.MaxSegments > 0 && i < len(segments)-p.config.MaxSegments {
+			remove = true
+		}
+		if p.config.MaxAge > 0 {
+			if t, err := segmentTime(p.name, s); err == nil && time.Since(t) > p.config.MaxAge {
+				remove = true
+			}
+		}
+		if !remove {
+			continue
+		}
+		if err := os.Remove(s); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compressSegment compresses segment with requested compression and removes the original segment.
+func compressSegment(segment string, compress string) error {
+	var ext string
+	switch strings.ToLower(compress) {
+	case "", CompressNone:
 		return nil
+	case CompressGzip:
+		ext = gzipExt
+	case CompressZstd:
+		ext = zstdExt
+	default:
+		return fmt.Errorf("unsupported compression %s", compress)
+	}
+	src, err := os.Open(segment)
+	if err != nil {
+		return err
 	}
+	defer src.Close()
+	tmp := segment + ext + tmpExt
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	var w io.WriteCloser
+	switch ext {
+	case gzipExt:
+		w = gzip.NewWriter(dst)
+	case zstdExt:
+		if w, err = zstd.NewWriter(dst); err != nil {
+			dst.Close()
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		w.Close()
+		dst.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := w.Close(); err != nil {
+		dst.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, segment+ext); err != nil {
+		return err
+	}
+
+	return os.Remove(segment)
+}
+
+// rotatedSegments returns rotated segments of the messages file sorted from the oldest to the newest.
+func rotatedSegments(file string) ([]string, error) {
+	matches, err := filepath.Glob(file + ".*")
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if _, err := segmentTime(file, m); err != nil {
+			continue
+		}
+		segments = append(segments, m)
+	}
+	sort.Strings(segments)
+
+	return segments, nil
+}
+
+// segmentTime recovers the rotation time from the name of a segment, names of files which are not
+// segments of the messages file, including segments being compressed, return error.
+func segmentTime(file, segment string) (time.Time, error) {
+	s := strings.TrimPrefix(segment, file+".")
+	if s == segment {
+		return time.Time{}, fmt.Errorf("%s is not a segment of %s", segment, file)
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, gzipExt), zstdExt)
+
+	return time.Parse(segmentTimeFormat, s)
+}
+
+// Segments returns all files storing messages for the messages file, rotated segments
+// from the oldest to the newest followed by the messages file itself when it exists.
+func Segments(file string) ([]string, error) {
+	segments, err := rotatedSegments(file)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(file); err == nil {
+		segments = append(segments, file)
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no messages file or segments found for %s", file)
+	}
+
+	return segments, nil
+}
+
+type segmentReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *segmentReader) Close() error {
+	var err error
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		if e := r.closers[i].Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+type zstdCloser struct {
+	*zstd.Decoder
+}
+
+func (z zstdCloser) Close() error {
+	z.Decoder.Close()
+	return nil
+}
+
+// OpenSegment opens a messages file or a rotated segment for reading,
+// gzip and zstd compressed segments are transparently decompressed.
+func OpenSegment(name string) (io.ReadCloser, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	r := &segmentReader{
+		Reader:  f,
+		closers: []io.Closer{f},
+	}
+	switch filepath.Ext(name) {
+	case gzipExt:
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		r.Reader = gz
+		r.closers = append(r.closers, gz)
+	case zstdExt:
+		zd, err := zstd.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		r.Reader = zd
+		r.closers = append(r.closers, zstdCloser{zd})
+	}
+
+	return r, nil
+}
+
+// NewFiler returns a new instance of message filer, when config is nil,
+// the messages file is never rotated.
+func NewFiler(file string, config *Config) (pub.Publisher, error) {
 	pw := pubfiler{
-		file: f,
+		name: file,
+		kick: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	if config != nil {
+		pw.config = *config
+	}
+	switch strings.ToLower(pw.config.Compress) {
+	case "", CompressNone, CompressGzip, CompressZstd:
+	default:
+		return nil, fmt.Errorf("unsupported compression %s", pw.config.Compress)
+	}
+	if err := pw.open(); err != nil {
+		return nil, err
 	}
+	go pw.worker(pw.kick)
 
-	return &pw
+	return &pw, nil
 }
@@ -0,0 +1,158 @@
+package filer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilerRotation(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       *Config
+		messages     int
+		expectSegs   int
+		expectFirst  int
+		expectRecord int
+	}{
+		{
+			name:         "no rotation",
+			config:       nil,
+			messages:     10,
+			expectSegs:   1,
+			expectFirst:  0,
+			expectRecord: 10,
+		},
+		{
+			name:         "size rotation without compression",
+			config:       &Config{MaxSize: 100},
+			messages:     10,
+			expectSegs:   10,
+			expectFirst:  0,
+			expectRecord: 10,
+		},
+		{
+			name:         "size rotation with gzip",
+			config:       &Config{MaxSize: 100, Compress: CompressGzip},
+			messages:     10,
+			expectSegs:   10,
+			expectFirst:  0,
+			expectRecord: 10,
+		},
+		{
+			name:         "size rotation with zstd",
+			config:       &Config{MaxSize: 100, Compress: CompressZstd},
+			messages:     10,
+			expectSegs:   10,
+			expectFirst:  0,
+			expectRecord: 10,
+		},
+		{
+			name:         "size rotation with retention by count",
+			config:       &Config{MaxSize: 100, Compress: CompressGzip, MaxSegments: 3},
+			messages:     10,
+			expectSegs:   4,
+			expectFirst:  6,
+			expectRecord: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "filer")
+			if err != nil {
+				t.Fatalf("failed to create temporary directory with error: %+v", err)
+			}
+			defer os.RemoveAll(dir)
+			name := filepath.Join(dir, "messages.json")
+			p, err := NewFiler(name, tt.config)
+			if err != nil {
+				t.Fatalf("failed to create filer with error: %+v", err)
+			}
+			for i := 0; i < tt.messages; i++ {
+				if err := p.PublishMessage(i, []byte(fmt.Sprintf("key-%d", i)), []byte(fmt.Sprintf("message number %06d", i))); err != nil {
+					t.Fatalf("failed to publish message with error: %+v", err)
+				}
+			}
+			p.Stop()
+			segments, err := Segments(name)
+			if err != nil {
+				t.Fatalf("failed to get segments with error: %+v", err)
+			}
+			if len(segments) != tt.expectSegs {
+				t.Fatalf("expected %d segments but got %d: %+v", tt.expectSegs, len(segments), segments)
+			}
+			records := make([]MsgOut, 0)
+			for _, s := range segments {
+				r, err := OpenSegment(s)
+				if err != nil {
+					t.Fatalf("failed to open segment %s with error: %+v", s, err)
+				}
+				scanner := bufio.NewScanner(r)
+				for scanner.Scan() {
+					m := MsgOut{}
+					if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+						t.Fatalf("failed to unmarshal message with error: %+v", err)
+					}
+					records = append(records, m)
+				}
+				if err := scanner.Err(); err != nil {
+					t.Fatalf("failed to read segment %s with error: %+v", s, err)
+				}
+				r.Close()
+			}
+			if len(records) != tt.expectRecord {
+				t.Fatalf("expected %d messages but got %d", tt.expectRecord, len(records))
+			}
+			for i, m := range records {
+				if m.Type != tt.expectFirst+i {
+					t.Fatalf("expected message of type %d but got %d", tt.expectFirst+i, m.Type)
+				}
+			}
+		})
+	}
+}
+
+func TestFilerAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filer")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory with error: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "messages.json")
+	for i := 0; i < 2; i++ {
+		p, err := NewFiler(name, nil)
+		if err != nil {
+			t.Fatalf("failed to create filer with error: %+v", err)
+		}
+		if err := p.PublishMessage(i, nil, []byte("message")); err != nil {
+			t.Fatalf("failed to publish message with error: %+v", err)
+		}
+		p.Stop()
+	}
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatalf("failed to read messages file with error: %+v", err)
+	}
+	lines := 0
+	for _, c := range b {
+		if c == '\n' {
+			lines++
+		}
+	}
+	if lines != 2 {
+		t.Fatalf("expected 2 messages after restart but got %d", lines)
+	}
+}
+
+func TestNewFilerError(t *testing.T) {
+	if _, err := NewFiler(filepath.Join("/nonexistent", "dir", "messages.json"), nil); err == nil {
+		t.Fatalf("expected to fail but succeeded")
+	}
+	if _, err := NewFiler(filepath.Join(os.TempDir(), "messages.json"), &Config{Compress: "lz4"}); err == nil {
+		t.Fatalf("expected to fail with unsupported compression but succeeded")
+	}
+}
@@ -0,0 +1,175 @@
+package flowspec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ActionType defines the type of Flowspec action
+type ActionType string
+
+const (
+	// ActionTrafficRateBytes defines traffic-rate-bytes action, rate limit in bytes per second
+	ActionTrafficRateBytes ActionType = "traffic-rate-bytes"
+	// ActionTrafficRatePackets defines traffic-rate-packets action, rate limit in packets per second
+	ActionTrafficRatePackets ActionType = "traffic-rate-packets"
+	// ActionTrafficAction defines traffic-action action, carries Terminal and Sample flags
+	ActionTrafficAction ActionType = "traffic-action"
+	// ActionRedirect defines redirect to VRF action
+	ActionRedirect ActionType = "redirect"
+	// ActionTrafficMarking defines traffic-marking action, carries DSCP value
+	ActionTrafficMarking ActionType = "traffic-marking"
+	// ActionRedirectIPNextHop defines redirect to IP next hop action
+	ActionRedirectIPNextHop ActionType = "redirect-ip-nh"
+)
+
+// Action defines Flowspec action carried by Extended Community
+// https://tools.ietf.org/html/rfc8955#section-7
+type Action struct {
+	Type ActionType `json:"type"`
+	// Rate is set for traffic-rate actions, 0 means the traffic must be discarded
+	Rate *float32 `json:"rate,omitempty"`
+	// Terminal and Sample are set for traffic-action
+	Terminal bool `json:"terminal,omitempty"`
+	Sample   bool `json:"sample,omitempty"`
+	// RouteTarget is set for redirect action
+	RouteTarget string `json:"route_target,omitempty"`
+	// DSCP is set for traffic-marking action
+	DSCP *uint8 `json:"dscp,omitempty"`
+	// NextHop and Copy are set for redirect to IP next hop action, when NextHop is not carried
+	// by the Extended Community, the next hop of the route is used.
+	NextHop string `json:"next_hop,omitempty"`
+	Copy    bool   `json:"copy,omitempty"`
+}
+
+// UnmarshalTrafficRate instantiates traffic-rate-bytes or traffic-rate-packets action from 6 bytes of Extended Community value
+func UnmarshalTrafficRate(b []byte, packets bool) (*Action, error) {
+	if len(b) != 6 {
+		return nil, fmt.Errorf("invalid length of Flowspec traffic-rate %d", len(b))
+	}
+	a := &Action{
+		Type: ActionTrafficRateBytes,
+	}
+	if packets {
+		a.Type = ActionTrafficRatePackets
+	}
+	// First 2 bytes carry informational AS or ID, followed by IEEE floating point rate
+	r := math.Float32frombits(binary.BigEndian.Uint32(b[2:6]))
+	a.Rate = &r
+
+	return a, nil
+}
+
+// UnmarshalTrafficAction instantiates traffic-action action from 6 bytes of Extended Community value
+func UnmarshalTrafficAction(b []byte) (*Action, error) {
+	if len(b) != 6 {
+		return nil, fmt.Errorf("invalid length of Flowspec traffic-action %d", len(b))
+	}
+
+	return &Action{
+		Type:     ActionTrafficAction,
+		Sample:   b[5]&0x02 != 0,
+		Terminal: b[5]&0x01 != 0,
+	}, nil
+}
+
+// UnmarshalTrafficMarking instantiates traffic-marking action from 6 bytes of Extended Community value
+func UnmarshalTrafficMarking(b []byte) (*Action, error) {
+	if len(b) != 6 {
+		return nil, fmt.Errorf("invalid length of Flowspec traffic-marking %d", len(b))
+	}
+	dscp := b[5] & 0x3f
+
+	return &Action{
+		Type: ActionTrafficMarking,
+		DSCP: &dscp,
+	}, nil
+}
+
+// UnmarshalRedirectIPNextHop instantiates redirect to IP next hop action from Extended Community value,
+// the value is either 6 bytes of IPv4 address and flags, or flags only when the next hop of the route is used.
+// https://tools.ietf.org/html/draft-ietf-idr-flowspec-redirect-ip-02
+func UnmarshalRedirectIPNextHop(b []byte) (*Action, error) {
+	if len(b) < 2 {
+		return nil, fmt.Errorf("invalid length of Flowspec redirect to IP next hop %d", len(b))
+	}
+	a := &Action{
+		Type: ActionRedirectIPNextHop,
+		Copy: b[len(b)-1]&0x01 != 0,
+	}
+	if len(b) == 6 {
+		a.NextHop = net.IP(b[0:4]).String()
+	}
+
+	return a, nil
+}
+
+// String returns a text representation of Flowspec action
+func (a *Action) String() string {
+	switch a.Type {
+	case ActionTrafficRateBytes:
+		return "rate-limit " + formatRate(a.Rate)
+	case ActionTrafficRatePackets:
+		return "rate-limit-pps " + formatRate(a.Rate)
+	case ActionTrafficAction:
+		s := "traffic-action"
+		if a.Terminal {
+			s += " terminal"
+		}
+		if a.Sample {
+			s += " sample"
+		}
+		return s
+	case ActionRedirect:
+		return "redirect " + a.RouteTarget
+	case ActionTrafficMarking:
+		if a.DSCP == nil {
+			return "set-dscp"
+		}
+		return fmt.Sprintf("set-dscp %d", *a.DSCP)
+	case ActionRedirectIPNextHop:
+		s := "redirect-nh"
+		if a.NextHop != "" {
+			s += " " + a.NextHop
+		}
+		if a.Copy {
+			s += " copy"
+		}
+		return s
+	}
+
+	return string(a.Type)
+}
+
+func formatRate(r *float32) string {
+	if r == nil {
+		return ""
+	}
+	return strconv.FormatFloat(float64(*r), 'f', -1, 32)
+}
+
+// RuleString returns a canonical text representation of Flowspec rule, match components
+// are followed by actions, for example "dst 10.0.0.0/24 proto =6 dport =80 -> rate-limit 0"
+func RuleString(spec []Spec, actions []*Action) string {
+	m := make([]string, 0, len(spec))
+	for _, s := range spec {
+		if s == nil {
+			continue
+		}
+		m = append(m, s.String())
+	}
+	r := strings.Join(m, " ")
+	if len(actions) == 0 {
+		return r
+	}
+	a := make([]string, 0, len(actions))
+	for _, action := range actions {
+		a = append(a, action.String())
+	}
+
+	return r + " -> " + strings.Join(a, ", ")
+}
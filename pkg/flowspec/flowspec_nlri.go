@@ -6,8 +6,11 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net"
+	"strings"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/base"
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
 
@@ -15,11 +18,14 @@ import (
 type Spec interface {
 	MarshalJSON() ([]byte, error)
 	UnmarshalJSON([]byte) error
+	String() string
 }
 
 // NLRI defines Flowspec NLRI structure
 type NLRI struct {
-	Length   uint16
+	Length uint16
+	// RD is set for VPN Flowspec NLRI of SAFI 134
+	RD       *base.RD
 	Spec     []Spec
 	SpecHash string
 }
@@ -57,10 +63,58 @@ const (
 	Type11 SpecType = 11
 	// Type12 defines Flowspec Specification type for Fragment
 	Type12 SpecType = 12
+	// Type13 defines Flowspec Specification type for IPv6 Flow Label
+	Type13 SpecType = 13
 )
 
+var specNames = map[SpecType]string{
+	Type1:  "dst",
+	Type2:  "src",
+	Type3:  "proto",
+	Type4:  "port",
+	Type5:  "dport",
+	Type6:  "sport",
+	Type7:  "icmp-type",
+	Type8:  "icmp-code",
+	Type9:  "tcp-flags",
+	Type10: "pkt-len",
+	Type11: "dscp",
+	Type12: "fragment",
+	Type13: "flow-label",
+}
+
+func getSpecName(t uint8) string {
+	if n, ok := specNames[SpecType(t)]; ok {
+		return n
+	}
+	return fmt.Sprintf("type-%d", t)
+}
+
 // UnmarshalFlowspecNLRI creates an instance of Flowspec NLRI from a slice of bytes
 func UnmarshalFlowspecNLRI(b []byte) (*NLRI, error) {
+	return unmarshalFlowspecNLRI(b, false, false)
+}
+
+// UnmarshalIPv6FlowspecNLRI creates an instance of IPv6 Flowspec NLRI from a slice of bytes,
+// IPv6 Prefix components carry the offset of the pattern.
+// https://tools.ietf.org/html/rfc8956#section-3
+func UnmarshalIPv6FlowspecNLRI(b []byte) (*NLRI, error) {
+	return unmarshalFlowspecNLRI(b, true, false)
+}
+
+// UnmarshalVPNFlowspecNLRI creates an instance of VPN Flowspec NLRI from a slice of bytes,
+// Route Distinguisher precedes the Flowspec components.
+// https://tools.ietf.org/html/rfc8955#section-8
+func UnmarshalVPNFlowspecNLRI(b []byte) (*NLRI, error) {
+	return unmarshalFlowspecNLRI(b, false, true)
+}
+
+// UnmarshalIPv6VPNFlowspecNLRI creates an instance of IPv6 VPN Flowspec NLRI from a slice of bytes
+func UnmarshalIPv6VPNFlowspecNLRI(b []byte) (*NLRI, error) {
+	return unmarshalFlowspecNLRI(b, true, true)
+}
+
+func unmarshalFlowspecNLRI(b []byte, ipv6, vpn bool) (*NLRI, error) {
 	if glog.V(5) {
 		glog.Infof("Flowspec NLRI Raw: %s", tools.MessageHex(b))
 	}
@@ -70,8 +124,11 @@ func UnmarshalFlowspecNLRI(b []byte) (*NLRI, error) {
 	fs := &NLRI{}
 	p := 0
 	if b[p]&0xf0 == 0xf0 {
-		// NLRI length is encoded into 2 bytes
-		binary.BigEndian.PutUint16(b[p:p+2], fs.Length)
+		// NLRI length is encoded into 2 bytes, the high nibble of the first byte is 0xf
+		if len(b) < 2 {
+			return nil, fmt.Errorf("not enough bytes to unmarshal Flowspec NLRI length")
+		}
+		fs.Length = binary.BigEndian.Uint16(b[p:p+2]) & 0x0fff
 		p += 2
 	} else {
 		// Otherwise it is encoded in the single byte
@@ -81,6 +138,17 @@ func UnmarshalFlowspecNLRI(b []byte) (*NLRI, error) {
 	if p+int(fs.Length) != len(b) {
 		return nil, fmt.Errorf("invalid length encoded length %d does not match with slice length %d", fs.Length, len(b))
 	}
+	if vpn {
+		if p+8 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal VPN Flowspec NLRI route distinguisher")
+		}
+		rd, err := base.MakeRD(b[p : p+8])
+		if err != nil {
+			return nil, err
+		}
+		fs.RD = rd
+		p += 8
+	}
 	for p < len(b) {
 		t := b[p]
 		l := 0
@@ -90,7 +158,19 @@ func UnmarshalFlowspecNLRI(b []byte) (*NLRI, error) {
 		case Type1:
 			fallthrough
 		case Type2:
-			spec, l, err = makePrefixSpec(b[p:])
+			if ipv6 {
+				spec, l, err = makeIPv6PrefixSpec(b[p:])
+			} else {
+				spec, l, err = makePrefixSpec(b[p:])
+			}
+			if err != nil {
+				return nil, err
+			}
+		case Type13:
+			if !ipv6 {
+				return nil, fmt.Errorf("Flowspec type: %+v is valid only for IPv6", t)
+			}
+			spec, l, err = makeGenericSpec(b[p:])
 			if err != nil {
 				return nil, err
 			}
@@ -116,7 +196,10 @@ func UnmarshalFlowspecNLRI(b []byte) (*NLRI, error) {
 		case Type9:
 			fallthrough
 		case Type12:
-			return nil, fmt.Errorf("not implemented Flowspec type: %+v", t)
+			spec, l, err = makeBitmaskSpec(b[p:])
+			if err != nil {
+				return nil, err
+			}
 		default:
 			return nil, fmt.Errorf("unknown Flowspec type: %+v", t)
 		}
@@ -129,6 +212,10 @@ func UnmarshalFlowspecNLRI(b []byte) (*NLRI, error) {
 	if err != nil {
 		return nil, err
 	}
+	if fs.RD != nil {
+		// Route Distinguisher makes the same spec unique per VRF
+		sp = append([]byte(fs.RD.String()), sp...)
+	}
 	s := md5.Sum(sp)
 	fs.SpecHash = hex.EncodeToString(s[:])
 
@@ -143,6 +230,11 @@ type Operator struct {
 	LTBit  bool
 	GTBit  bool
 	EQBit  bool
+	// Bitmask is set for the operator of TCP flags and Fragment components, which carries
+	// NOT and Match bits instead of numeric comparison bits.
+	Bitmask  bool
+	NOTBit   bool
+	MatchBit bool
 }
 
 // UnmarshalFlowspecOperator creates an instance of Operator object from a byte
@@ -169,6 +261,61 @@ func UnmarshalFlowspecOperator(b byte) (*Operator, error) {
 	return o, nil
 }
 
+// UnmarshalFlowspecBitmaskOperator creates an instance of bitmask Operator object from a byte
+// https://tools.ietf.org/html/rfc8955#section-4.2.1.2
+func UnmarshalFlowspecBitmaskOperator(b byte) (*Operator, error) {
+	o := &Operator{Bitmask: true}
+	if b&0x80 == 0x80 {
+		o.EOLBit = true
+	}
+	if b&0x40 == 0x40 {
+		o.ANDBit = true
+	}
+	l := (b & 0x30) >> 4
+	o.Length = 1 << l
+	if b&0x02 == 0x02 {
+		o.NOTBit = true
+	}
+	if b&0x01 == 0x01 {
+		o.MatchBit = true
+	}
+
+	return o, nil
+}
+
+// String returns a text representation of numeric operator comparison bits or of bitmask
+// operator NOT and Match bits, "=" requires all bits of the value to be set, otherwise any.
+func (o *Operator) String() string {
+	if o.Bitmask {
+		s := ""
+		if o.NOTBit {
+			s += "!"
+		}
+		if o.MatchBit {
+			s += "="
+		}
+		return s
+	}
+	switch {
+	case o.LTBit && o.GTBit && o.EQBit:
+		return "true"
+	case o.LTBit && o.GTBit:
+		return "!="
+	case o.LTBit && o.EQBit:
+		return "<="
+	case o.GTBit && o.EQBit:
+		return ">="
+	case o.LTBit:
+		return "<"
+	case o.GTBit:
+		return ">"
+	case o.EQBit:
+		return "="
+	}
+
+	return "false"
+}
+
 // MarshalJSON returns a binary representation of Flowspec Operator structure
 func (o *Operator) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
@@ -178,13 +325,18 @@ func (o *Operator) MarshalJSON() ([]byte, error) {
 		LTBit  bool  `json:"less_than,omitempty"`
 		GTBit  bool  `json:"greater_than,omitempty"`
 		EQBit  bool  `json:"equal,omitempty"`
+		// NOT and Match bits of bitmask operator
+		NOTBit   bool `json:"not,omitempty"`
+		MatchBit bool `json:"match,omitempty"`
 	}{
-		EOLBit: o.EOLBit,
-		ANDBit: o.ANDBit,
-		Length: o.Length,
-		LTBit:  o.LTBit,
-		GTBit:  o.GTBit,
-		EQBit:  o.EQBit,
+		EOLBit:   o.EOLBit,
+		ANDBit:   o.ANDBit,
+		Length:   o.Length,
+		LTBit:    o.LTBit,
+		GTBit:    o.GTBit,
+		EQBit:    o.EQBit,
+		NOTBit:   o.NOTBit,
+		MatchBit: o.MatchBit,
 	})
 
 }
@@ -205,11 +357,17 @@ type PrefixSpec struct {
 	SpecType     uint8  `json:"type"`
 	PrefixLength uint8  `json:"prefix_len"`
 	Prefix       []byte `json:"prefix"`
+	// Offset is the number of leading bits of IPv6 address skipped by the pattern carried in Prefix
+	Offset uint8 `json:"offset,omitempty"`
+	IPv6   bool  `json:"-"`
 }
 
 func makePrefixSpec(b []byte) (Spec, int, error) {
 	s := &PrefixSpec{}
 	p := 0
+	if len(b) < 2 {
+		return nil, 0, fmt.Errorf("not enough bytes to unmarshal Flowspec prefix spec")
+	}
 	s.SpecType = b[p]
 	p++
 	s.PrefixLength = b[p]
@@ -218,6 +376,9 @@ func makePrefixSpec(b []byte) (Spec, int, error) {
 		l++
 	}
 	p++
+	if p+l > len(b) {
+		return nil, 0, fmt.Errorf("not enough bytes to unmarshal Flowspec prefix of length %d", s.PrefixLength)
+	}
 	s.Prefix = make([]byte, l)
 	copy(s.Prefix, b[p:p+l])
 	p += int(l)
@@ -225,6 +386,61 @@ func makePrefixSpec(b []byte) (Spec, int, error) {
 	return s, p, nil
 }
 
+func makeIPv6PrefixSpec(b []byte) (Spec, int, error) {
+	s := &PrefixSpec{IPv6: true}
+	p := 0
+	if len(b) < 3 {
+		return nil, 0, fmt.Errorf("not enough bytes to unmarshal Flowspec IPv6 prefix spec")
+	}
+	s.SpecType = b[p]
+	p++
+	s.PrefixLength = b[p]
+	p++
+	s.Offset = b[p]
+	p++
+	if s.PrefixLength > 128 || s.Offset > s.PrefixLength {
+		return nil, 0, fmt.Errorf("invalid Flowspec IPv6 prefix length %d and offset %d", s.PrefixLength, s.Offset)
+	}
+	// Only bits between the offset and the prefix length are carried
+	bits := int(s.PrefixLength - s.Offset)
+	l := bits / 8
+	if bits%8 != 0 {
+		l++
+	}
+	if p+l > len(b) {
+		return nil, 0, fmt.Errorf("not enough bytes to unmarshal Flowspec IPv6 prefix of length %d", s.PrefixLength)
+	}
+	s.Prefix = make([]byte, l)
+	copy(s.Prefix, b[p:p+l])
+	p += l
+
+	return s, p, nil
+}
+
+// String returns a text representation of FlowSPec PrefixSpec
+func (t *PrefixSpec) String() string {
+	addr := make([]byte, 4)
+	if t.IPv6 {
+		addr = make([]byte, 16)
+	}
+	// Pattern bits are placed into the address starting from the offset bit
+	for i := 0; i < int(t.PrefixLength)-int(t.Offset); i++ {
+		bit := int(t.Offset) + i
+		if i/8 >= len(t.Prefix) || bit/8 >= len(addr) {
+			break
+		}
+		if t.Prefix[i/8]&(0x80>>uint(i%8)) != 0 {
+			addr[bit/8] |= 0x80 >> uint(bit%8)
+		}
+	}
+	s := fmt.Sprintf("%s %s/%d", getSpecName(t.SpecType), net.IP(addr).String(), t.PrefixLength)
+	if t.Offset != 0 {
+		s += fmt.Sprintf(" offset %d", t.Offset)
+	}
+
+	return s
+}
+
 // UnmarshalJSON unmarshals a slice of bytes into a new FlowSPec PrefixSpec
 func (t *PrefixSpec) UnmarshalJSON(b []byte) error {
 	s := &PrefixSpec{}
@@ -242,10 +458,12 @@ func (t *PrefixSpec) MarshalJSON() ([]byte, error) {
 		SpecType     uint8  `json:"type"`
 		PrefixLength uint8  `json:"prefix_len"`
 		Prefix       []byte `json:"prefix"`
+		Offset       uint8  `json:"offset,omitempty"`
 	}{
 		SpecType:     t.SpecType,
 		PrefixLength: t.PrefixLength,
 		Prefix:       t.Prefix,
+		Offset:       t.Offset,
 	})
 }
 
@@ -279,13 +497,22 @@ func (o *OpVal) MarshalJSON() ([]byte, error) {
 
 // UnmarshalOpVal creates a slice of Operator/Value pairs
 func UnmarshalOpVal(b []byte) ([]*OpVal, error) {
+	return unmarshalOpVal(b, UnmarshalFlowspecOperator)
+}
+
+// UnmarshalBitmaskOpVal creates a slice of bitmask Operator/Value pairs
+func UnmarshalBitmaskOpVal(b []byte) ([]*OpVal, error) {
+	return unmarshalOpVal(b, UnmarshalFlowspecBitmaskOperator)
+}
+
+func unmarshalOpVal(b []byte, unmarshalOperator func(byte) (*Operator, error)) ([]*OpVal, error) {
 	opvals := make([]*OpVal, 0)
 	p := 0
 	// Skip type
 	p++
 	eol := false
 	for !eol && p < len(b) {
-		o, err := UnmarshalFlowspecOperator(b[p])
+		o, err := unmarshalOperator(b[p])
 		if err != nil {
 			return nil, err
 		}
@@ -308,19 +535,28 @@ func UnmarshalOpVal(b []byte) ([]*OpVal, error) {
 	return opvals, nil
 }
 
-// GenericSpec defines a structure of Flowspec Types (3,4,5,6,7,8,10,11) specs.
+// GenericSpec defines a structure of Flowspec Types (3,4,5,6,7,8,10,11,13) specs with numeric operators
+// and of Types (9,12) specs with bitmask operators.
 type GenericSpec struct {
 	SpecType uint8    `json:"type,omitempty"`
 	OpVal    []*OpVal `json:"op_val_pairs,omitempty"`
 }
 
 func makeGenericSpec(b []byte) (Spec, int, error) {
+	return makeSpec(b, UnmarshalOpVal)
+}
+
+func makeBitmaskSpec(b []byte) (Spec, int, error) {
+	return makeSpec(b, UnmarshalBitmaskOpVal)
+}
+
+func makeSpec(b []byte, unmarshalOpVal func([]byte) ([]*OpVal, error)) (Spec, int, error) {
 	s := &GenericSpec{}
 	var err error
 	p := 0
 	s.SpecType = b[p]
 	p++
-	s.OpVal, err = UnmarshalOpVal(b)
+	s.OpVal, err = unmarshalOpVal(b)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -338,6 +574,66 @@ func makeGenericSpec(b []byte) (Spec, int, error) {
 	return s, p, nil
 }
 
+// String returns a text representation of FlowSPec GenericSpec, Operator/Value pairs are joined
+// with "&" when AND bit is set and with "|" otherwise.
+func (t *GenericSpec) String() string {
+	var s strings.Builder
+	s.WriteString(getSpecName(t.SpecType))
+	s.WriteString(" ")
+	for i, ov := range t.OpVal {
+		if ov == nil || ov.Op == nil {
+			continue
+		}
+		if i > 0 {
+			if ov.Op.ANDBit {
+				s.WriteString("&")
+			} else {
+				s.WriteString("|")
+			}
+		}
+		var v uint64
+		for _, b := range ov.Val {
+			v = v<<8 | uint64(b)
+		}
+		if ov.Op.Bitmask {
+			fmt.Fprintf(&s, "%s%s", ov.Op.String(), bitmaskString(SpecType(t.SpecType), v))
+			continue
+		}
+		fmt.Fprintf(&s, "%s%d", ov.Op.String(), v)
+	}
+
+	return s.String()
+}
+
+var tcpFlags = []string{"fin", "syn", "rst", "push", "ack", "urgent", "ece", "cwr"}
+
+// https://tools.ietf.org/html/rfc8955#section-4.2.2.12
+var fragmentFlags = []string{"dont-fragment", "is-fragment", "first-fragment", "last-fragment"}
+
+// bitmaskString returns names of bits set in the value of TCP flags and Fragment components joined with "+",
+// bits without name are rendered as a hex number.
+func bitmaskString(t SpecType, v uint64) string {
+	var names []string
+	switch t {
+	case Type9:
+		names = tcpFlags
+	case Type12:
+		names = fragmentFlags
+	}
+	m := make([]string, 0)
+	for i, n := range names {
+		if v&(1<<uint(i)) != 0 {
+			m = append(m, n)
+			v &^= 1 << uint(i)
+		}
+	}
+	if v != 0 || len(m) == 0 {
+		m = append(m, fmt.Sprintf("0x%x", v))
+	}
+
+	return strings.Join(m, "+")
+}
+
 // UnmarshalJSON unmarshals a slice of bytes into a new FlowSPec GenericSpec
 func (t *GenericSpec) UnmarshalJSON(b []byte) error {
 	s := &GenericSpec{}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/base"
 )
 
 func TestUnmarshalFlowspecNLRI(t *testing.T) {
@@ -54,6 +55,31 @@ func TestUnmarshalFlowspecNLRI(t *testing.T) {
 			},
 			fail: false,
 		},
+		{
+			name:  "Type 9 (TCP flags)",
+			input: []byte{0x03, 0x09, 0x82, 0x02},
+			expect: &NLRI{
+				Length: 3,
+				Spec: []Spec{
+					&GenericSpec{
+						SpecType: 9,
+						OpVal: []*OpVal{
+							{
+								Op: &Operator{
+									EOLBit:  true,
+									Length:  1,
+									Bitmask: true,
+									NOTBit:  true,
+								},
+								Val: []byte{0x02},
+							},
+						},
+					},
+				},
+				SpecHash: "9f4f57befa372aeb08abaf269d74f813",
+			},
+			fail: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -68,3 +94,160 @@ func TestUnmarshalFlowspecNLRI(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalIPv6FlowspecNLRI(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		expect string
+		fail   bool
+	}{
+		{
+			name:   "destination prefix without offset and next header",
+			input:  []byte{0x0a, 0x01, 0x20, 0x00, 0x20, 0x01, 0x0d, 0xb8, 0x03, 0x81, 0x11},
+			expect: "dst 2001:db8::/32 proto =17",
+		},
+		{
+			name:   "source prefix with offset",
+			input:  []byte{0x07, 0x02, 0x40, 0x20, 0x00, 0x01, 0x00, 0x02},
+			expect: "src 0:0:1:2::/64 offset 32",
+		},
+		{
+			name:   "flow label",
+			input:  []byte{0x06, 0x0d, 0xa1, 0x00, 0x00, 0x00, 0x0a},
+			expect: "flow-label =10",
+		},
+		{
+			name:  "offset exceeds prefix length",
+			input: []byte{0x03, 0x01, 0x20, 0x40},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalIPv6FlowspecNLRI(tt.input)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if s := RuleString(got.Spec, nil); s != tt.expect {
+				t.Fatalf("expected rule %q does not match computed %q", tt.expect, s)
+			}
+		})
+	}
+}
+
+func TestRuleString(t *testing.T) {
+	rate := float32(0)
+	dscp := uint8(10)
+	tests := []struct {
+		name    string
+		input   []byte
+		actions []*Action
+		expect  string
+	}{
+		{
+			name:    "destination prefix, protocol and port with discard",
+			input:   []byte{0x0b, 0x01, 0x18, 0x0a, 0x00, 0x00, 0x03, 0x81, 0x06, 0x05, 0x81, 0x50},
+			actions: []*Action{{Type: ActionTrafficRateBytes, Rate: &rate}},
+			expect:  "dst 10.0.0.0/24 proto =6 dport =80 -> rate-limit 0",
+		},
+		{
+			name:  "port range and packet length",
+			input: []byte{0x0d, 0x04, 0x13, 0x04, 0x00, 0x55, 0x08, 0x00, 0x81, 0x0a, 0x0a, 0x92, 0x05, 0xdc},
+			actions: []*Action{
+				{Type: ActionTrafficMarking, DSCP: &dscp},
+				{Type: ActionRedirect, RouteTarget: "65000:100"},
+				{Type: ActionRedirectIPNextHop, NextHop: "192.168.1.1", Copy: true},
+			},
+			expect: "port >=1024&<=2048|=10 pkt-len >1500 -> set-dscp 10, redirect 65000:100, redirect-nh 192.168.1.1 copy",
+		},
+		{
+			name:   "tcp flags and fragment",
+			input:  []byte{0x08, 0x09, 0x01, 0x12, 0xc2, 0x04, 0x0c, 0x81, 0x04},
+			expect: "tcp-flags =syn+ack&!rst fragment =first-fragment",
+		},
+		{
+			name:   "not tcp flag and 2 bytes tcp flags",
+			input:  []byte{0x06, 0x09, 0x02, 0x02, 0x90, 0x01, 0x00},
+			expect: "tcp-flags !syn|0x100",
+		},
+		{
+			name:   "any of fragment bits",
+			input:  []byte{0x03, 0x0c, 0x80, 0x03},
+			expect: "fragment dont-fragment+is-fragment",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, err := UnmarshalFlowspecNLRI(tt.input)
+			if err != nil {
+				t.Fatalf("failed with error: %+v", err)
+			}
+			if s := RuleString(fs.Spec, tt.actions); s != tt.expect {
+				t.Fatalf("expected rule %q does not match computed %q", tt.expect, s)
+			}
+		})
+	}
+}
+
+func TestUnmarshalVPNFlowspecNLRI(t *testing.T) {
+	spec := []byte{0x02, 0x18, 0x0a, 0x00, 0x07}
+	tests := []struct {
+		name   string
+		input  []byte
+		expect *base.RD
+		fail   bool
+	}{
+		{
+			name:   "rd type 0 and source prefix",
+			input:  append([]byte{0x0d, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64}, spec...),
+			expect: &base.RD{Type: 0, Value: []byte{0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64}},
+		},
+		{
+			name:   "rd type 1 and source prefix",
+			input:  append([]byte{0x0d, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x64}, spec...),
+			expect: &base.RD{Type: 1, Value: []byte{0x0a, 0x00, 0x00, 0x01, 0x00, 0x64}},
+		},
+		{
+			name:  "truncated rd",
+			input: []byte{0x04, 0x00, 0x00, 0xfd, 0xe8},
+			fail:  true,
+		},
+	}
+	plain, err := UnmarshalFlowspecNLRI(append([]byte{0x05}, spec...))
+	if err != nil {
+		t.Fatalf("failed with error: %+v", err)
+	}
+	hashes := map[string]bool{plain.SpecHash: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalVPNFlowspecNLRI(tt.input)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.expect, got.RD) {
+				t.Fatalf("expected RD %+v does not match computed %+v", tt.expect, got.RD)
+			}
+			if !reflect.DeepEqual(plain.Spec, got.Spec) {
+				t.Fatalf("expected spec %+v does not match computed %+v", plain.Spec, got.Spec)
+			}
+			// The same spec in different VRFs must not share the hash
+			if hashes[got.SpecHash] {
+				t.Fatalf("spec hash %s is not unique", got.SpecHash)
+			}
+			hashes[got.SpecHash] = true
+		})
+	}
+}
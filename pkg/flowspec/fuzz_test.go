@@ -0,0 +1,40 @@
+//go:build go1.18
+// +build go1.18
+
+package flowspec
+
+import "testing"
+
+func FuzzUnmarshalFlowspecNLRI(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalFlowspecNLRI(b)
+	})
+}
+
+func FuzzUnmarshalIPv6FlowspecNLRI(f *testing.F) {
+	f.Add([]byte{0x07, 0x02, 0x40, 0x20, 0x00, 0x01, 0x00, 0x02})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		if fs, err := UnmarshalIPv6FlowspecNLRI(b); err == nil {
+			RuleString(fs.Spec, nil)
+		}
+	})
+}
+
+func FuzzUnmarshalVPNFlowspecNLRI(f *testing.F) {
+	f.Add([]byte{0x0d, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x02, 0x18, 0x0a, 0x00, 0x07})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalVPNFlowspecNLRI(b)
+	})
+}
+
+func FuzzUnmarshalFlowspecOperator(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b byte) {
+		UnmarshalFlowspecOperator(b)
+	})
+}
+
+func FuzzUnmarshalOpVal(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalOpVal(b)
+	})
+}
@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/bmp"
 	"github.com/sbezverk/gobmp/pkg/message"
 	"github.com/sbezverk/gobmp/pkg/parser"
 	"github.com/sbezverk/gobmp/pkg/pub"
+	"github.com/sbezverk/gobmp/pkg/recorder"
 )
 
 // BMPServer defines methods to manage BMP Server
@@ -20,12 +22,16 @@ type BMPServer interface {
 
 type bmpServer struct {
 	splitAF         bool
+	splitLU         bool
 	intercept       bool
 	publisher       pub.Publisher
+	recorder        recorder.Recorder
 	sourcePort      int
 	destinationPort int
 	incoming        net.Listener
 	stop            chan struct{}
+	// maxMessageLength is the maximum length of BMP message accepted from the router
+	maxMessageLength int
 }
 
 func (srv *bmpServer) Start() {
@@ -67,8 +73,19 @@ func (srv *bmpServer) bmpWorker(client net.Conn) {
 		defer server.Close()
 		glog.V(5).Infof("connection to destination server %v established, start intercepting", server.RemoteAddr())
 	}
+	var capture recorder.Session
+	if srv.recorder != nil {
+		if capture, err = srv.recorder.NewSession(client.RemoteAddr()); err != nil {
+			glog.Errorf("fail to start raw BMP capture for client %+v with error: %+v", client.RemoteAddr(), err)
+		}
+	}
+	defer func() {
+		if capture != nil {
+			capture.Close()
+		}
+	}()
 	var producerQueue chan bmp.Message
-	prod := message.NewProducer(srv.publisher, srv.splitAF)
+	prod := message.NewProducer(srv.publisher, srv.splitAF, srv.splitLU)
 	prodStop := make(chan struct{})
 	producerQueue = make(chan bmp.Message)
 	// Starting messages producer per client with dedicated work queue
@@ -95,6 +112,14 @@ func (srv *bmpServer) bmpWorker(client net.Conn) {
 			glog.Errorf("fail to recover BMP message Common Header with error: %+v", err)
 			continue
 		}
+		// Message length is not trusted for allocation, the session is reset when it is out of the range
+		if l := int(header.MessageLength); l < bmp.CommonHeaderLength || l > srv.maxMessageLength {
+			reason := fmt.Sprintf("BMP message length %d is out of range %d-%d", l, bmp.CommonHeaderLength, srv.maxMessageLength)
+			glog.Errorf("resetting session with client %+v: %s", client.RemoteAddr(), reason)
+			host, _, _ := net.SplitHostPort(client.RemoteAddr().String())
+			producerQueue <- bmp.Message{Payload: &bmp.SessionError{RouterIP: host, Reason: reason}}
+			return
+		}
 		// Allocating space for the message body
 		msg := make([]byte, int(header.MessageLength)-bmp.CommonHeaderLength)
 		if _, err := io.ReadFull(client, msg); err != nil {
@@ -105,6 +130,14 @@ func (srv *bmpServer) bmpWorker(client net.Conn) {
 		fullMsg := make([]byte, int(header.MessageLength))
 		copy(fullMsg, headerMsg)
 		copy(fullMsg[bmp.CommonHeaderLength:], msg)
+		// Storing raw message before parsing, when the capture fails, the session continues without it
+		if capture != nil {
+			if err := capture.Record(time.Now(), fullMsg); err != nil {
+				glog.Errorf("fail to capture raw BMP message from client %+v with error: %+v", client.RemoteAddr(), err)
+				capture.Close()
+				capture = nil
+			}
+		}
 		// Sending information to the server only in intercept mode
 		if srv.intercept {
 			if _, err := server.Write(fullMsg); err != nil {
@@ -117,21 +150,30 @@ func (srv *bmpServer) bmpWorker(client net.Conn) {
 }
 
 // NewBMPServer instantiates a new instance of BMP Server
-func NewBMPServer(sPort, dPort int, intercept bool, p pub.Publisher, splitAF bool) (BMPServer, error) {
+// rec is optional, when it is not nil, raw BMP messages of every session get captured.
+// maxMsgLen is the maximum length of BMP message, when 0, bmp.DefaultMaxMessageLength is used.
+// When splitLU is true, labeled unicast routes are published as transport messages.
+func NewBMPServer(sPort, dPort int, intercept bool, p pub.Publisher, splitAF, splitLU bool, rec recorder.Recorder, maxMsgLen int) (BMPServer, error) {
+	if maxMsgLen <= 0 {
+		maxMsgLen = bmp.DefaultMaxMessageLength
+	}
 	incoming, err := net.Listen("tcp", fmt.Sprintf(":%d", sPort))
 	if err != nil {
 		glog.Errorf("fail to setup listener on port %d with error: %+v", sPort, err)
 		return nil, err
 	}
-	bmp := bmpServer{
-		stop:            make(chan struct{}),
-		sourcePort:      sPort,
-		destinationPort: dPort,
-		intercept:       intercept,
-		publisher:       p,
-		incoming:        incoming,
-		splitAF:         splitAF,
+	srv := bmpServer{
+		stop:             make(chan struct{}),
+		sourcePort:       sPort,
+		destinationPort:  dPort,
+		intercept:        intercept,
+		publisher:        p,
+		recorder:         rec,
+		incoming:         incoming,
+		splitAF:          splitAF,
+		splitLU:          splitLU,
+		maxMessageLength: maxMsgLen,
 	}
 
-	return &bmp, nil
+	return &srv, nil
 }
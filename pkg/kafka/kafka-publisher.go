@@ -16,24 +16,34 @@ import (
 
 // Define constants for each topic name
 const (
-	peerTopic              = "gobmp.parsed.peer"
-	unicastMessageTopic    = "gobmp.parsed.unicast_prefix"
-	unicastMessageV4Topic  = "gobmp.parsed.unicast_prefix_v4"
-	unicastMessageV6Topic  = "gobmp.parsed.unicast_prefix_v6"
-	lsNodeMessageTopic     = "gobmp.parsed.ls_node"
-	lsLinkMessageTopic     = "gobmp.parsed.ls_link"
-	l3vpnMessageTopic      = "gobmp.parsed.l3vpn"
-	l3vpnMessageV4Topic    = "gobmp.parsed.l3vpn_v4"
-	l3vpnMessageV6Topic    = "gobmp.parsed.l3vpn_v6"
-	lsPrefixMessageTopic   = "gobmp.parsed.ls_prefix"
-	lsSRv6SIDMessageTopic  = "gobmp.parsed.ls_srv6_sid"
-	evpnMessageTopic       = "gobmp.parsed.evpn"
-	srPolicyMessageTopic   = "gobmp.parsed.sr_policy"
-	srPolicyMessageV4Topic = "gobmp.parsed.sr_policy_v4"
-	srPolicyMessageV6Topic = "gobmp.parsed.sr_policy_v6"
-	flowspecMessageTopic   = "gobmp.parsed.flowspec"
-	flowspecMessageV4Topic = "gobmp.parsed.flowspec_v4"
-	flowspecMessageV6Topic = "gobmp.parsed.flowspec_v6"
+	peerTopic                 = "gobmp.parsed.peer"
+	unicastMessageTopic       = "gobmp.parsed.unicast_prefix"
+	unicastMessageV4Topic     = "gobmp.parsed.unicast_prefix_v4"
+	unicastMessageV6Topic     = "gobmp.parsed.unicast_prefix_v6"
+	lsNodeMessageTopic        = "gobmp.parsed.ls_node"
+	lsLinkMessageTopic        = "gobmp.parsed.ls_link"
+	l3vpnMessageTopic         = "gobmp.parsed.l3vpn"
+	l3vpnMessageV4Topic       = "gobmp.parsed.l3vpn_v4"
+	l3vpnMessageV6Topic       = "gobmp.parsed.l3vpn_v6"
+	lsPrefixMessageTopic      = "gobmp.parsed.ls_prefix"
+	lsSRv6SIDMessageTopic     = "gobmp.parsed.ls_srv6_sid"
+	evpnMessageTopic          = "gobmp.parsed.evpn"
+	srPolicyMessageTopic      = "gobmp.parsed.sr_policy"
+	srPolicyMessageV4Topic    = "gobmp.parsed.sr_policy_v4"
+	srPolicyMessageV6Topic    = "gobmp.parsed.sr_policy_v6"
+	flowspecMessageTopic      = "gobmp.parsed.flowspec"
+	flowspecMessageV4Topic    = "gobmp.parsed.flowspec_v4"
+	flowspecMessageV6Topic    = "gobmp.parsed.flowspec_v6"
+	updateErrorTopic          = "gobmp.parsed.update_error"
+	sessionErrorTopic         = "gobmp.parsed.session_error"
+	vplsMessageTopic          = "gobmp.parsed.vpls"
+	lsTEPolicyMessageTopic    = "gobmp.parsed.ls_te_policy"
+	mvpnMessageTopic          = "gobmp.parsed.mvpn"
+	rtcMessageTopic           = "gobmp.parsed.rtc"
+	transportMessageTopic     = "gobmp.parsed.transport"
+	flowspecVPNMessageTopic   = "gobmp.parsed.flowspec_vpn"
+	flowspecVPNMessageV4Topic = "gobmp.parsed.flowspec_vpn_v4"
+	flowspecVPNMessageV6Topic = "gobmp.parsed.flowspec_vpn_v6"
 )
 
 var (
@@ -65,6 +75,16 @@ var (
 		flowspecMessageTopic,
 		flowspecMessageV4Topic,
 		flowspecMessageV6Topic,
+		updateErrorTopic,
+		sessionErrorTopic,
+		vplsMessageTopic,
+		lsTEPolicyMessageTopic,
+		mvpnMessageTopic,
+		rtcMessageTopic,
+		transportMessageTopic,
+		flowspecVPNMessageTopic,
+		flowspecVPNMessageV4Topic,
+		flowspecVPNMessageV6Topic,
 	}
 )
 
@@ -113,6 +133,26 @@ func (p *publisher) PublishMessage(t int, key []byte, msg []byte) error {
 		return p.produceMessage(flowspecMessageV4Topic, key, msg)
 	case bmp.FlowspecV6Msg:
 		return p.produceMessage(flowspecMessageV6Topic, key, msg)
+	case bmp.UpdateErrorMsg:
+		return p.produceMessage(updateErrorTopic, key, msg)
+	case bmp.SessionErrorMsg:
+		return p.produceMessage(sessionErrorTopic, key, msg)
+	case bmp.VPLSMsg:
+		return p.produceMessage(vplsMessageTopic, key, msg)
+	case bmp.LSTEPolicyMsg:
+		return p.produceMessage(lsTEPolicyMessageTopic, key, msg)
+	case bmp.MVPNMsg:
+		return p.produceMessage(mvpnMessageTopic, key, msg)
+	case bmp.RTCMsg:
+		return p.produceMessage(rtcMessageTopic, key, msg)
+	case bmp.TransportMsg:
+		return p.produceMessage(transportMessageTopic, key, msg)
+	case bmp.FlowspecVPNMsg:
+		return p.produceMessage(flowspecVPNMessageTopic, key, msg)
+	case bmp.FlowspecVPNV4Msg:
+		return p.produceMessage(flowspecVPNMessageV4Topic, key, msg)
+	case bmp.FlowspecVPNV6Msg:
+		return p.produceMessage(flowspecVPNMessageV6Topic, key, msg)
 	}
 
 	return fmt.Errorf("not implemented")
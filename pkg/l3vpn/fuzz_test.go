@@ -0,0 +1,12 @@
+//go:build go1.18
+// +build go1.18
+
+package l3vpn
+
+import "testing"
+
+func FuzzUnmarshalL3VPNNLRI(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte, srv6 bool) {
+		UnmarshalL3VPNNLRI(b, srv6)
+	})
+}
@@ -29,7 +29,7 @@ func UnmarshalL3VPNNLRI(b []byte, srv6 ...bool) (*base.MPNLRI, error) {
 		up := base.Route{
 			Label: make([]*base.Label, 0),
 		}
-		if b[p] == 0x0 && len(b) > 4 {
+		if b[p] == 0x0 && p+4 < len(b) {
 			up.PathID = binary.BigEndian.Uint32(b[p : p+4])
 			p += 4
 		}
@@ -38,6 +38,9 @@ func UnmarshalL3VPNNLRI(b []byte, srv6 ...bool) (*base.MPNLRI, error) {
 		// Next 3 bytes are a part of Compatibility field 0x800000
 		// then it is MP_UNREACH_NLRI and no Label information is present
 		compatibilityField := 0
+		if p+3 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal label")
+		}
 		if bytes.Equal([]byte{0x80, 0x00, 0x00}, b[p:p+3]) {
 			up.Label = nil
 			compatibilityField = 3
@@ -46,7 +49,7 @@ func UnmarshalL3VPNNLRI(b []byte, srv6 ...bool) (*base.MPNLRI, error) {
 			// Otherwise getting labels
 			up.Label = make([]*base.Label, 0)
 			bos := false
-			for !bos && p < len(b) {
+			for !bos && p+3 <= len(b) {
 				l, err := base.MakeLabel(b[p:p+3], srv6Flag)
 				if err != nil {
 					return nil, err
@@ -61,6 +64,9 @@ func UnmarshalL3VPNNLRI(b []byte, srv6 ...bool) (*base.MPNLRI, error) {
 				}
 			}
 		}
+		if p+8 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal route distinguisher")
+		}
 		rd, err := base.MakeRD(b[p : p+8])
 		if err != nil {
 			return nil, err
@@ -73,6 +79,9 @@ func UnmarshalL3VPNNLRI(b []byte, srv6 ...bool) (*base.MPNLRI, error) {
 		if up.Length%8 != 0 {
 			l++
 		}
+		if l < 0 || p+l > len(b) {
+			return nil, fmt.Errorf("invalid prefix length %d", up.Length)
+		}
 		up.Prefix = make([]byte, l)
 		copy(up.Prefix, b[p:p+l])
 		p += l
@@ -0,0 +1,12 @@
+//go:build go1.18
+// +build go1.18
+
+package ls
+
+import "testing"
+
+func FuzzUnmarshalLSNLRI71(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalLSNLRI71(b)
+	})
+}
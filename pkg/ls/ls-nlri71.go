@@ -40,11 +40,18 @@ func UnmarshalLSNLRI71(b []byte) (*NLRI71, error) {
 		NLRI: make([]Element, 0),
 	}
 	for p := 0; p < len(b); {
+		if p+4 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal Link State NLRI")
+		}
 		el := Element{}
 		el.Type = binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
 		el.Length = binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
+		// Truncated value is tolerated only for unknown NLRI types which are kept as raw bytes
+		if p+int(el.Length) > len(b) && el.Type >= 1 && el.Type <= 6 {
+			return nil, fmt.Errorf("not enough bytes to unmarshal Link State NLRI type %d of length %d", el.Type, el.Length)
+		}
 
 		switch el.Type {
 		case 1:
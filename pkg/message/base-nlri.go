@@ -4,13 +4,14 @@ import (
 	"fmt"
 	"net"
 
+	"github.com/sbezverk/gobmp/pkg/base"
 	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/bmp"
 )
 
-// nlri process base nlri information found and bgp update message and returns
-// a slice of UnicatPrefix.
-func (p *producer) nlri(op int, ph *bmp.PerPeerHeader, update *bgp.Update) ([]UnicastPrefix, error) {
+// nlri process base nlri information, either withdrawn routes or nlri, found in bgp update message
+// and returns a slice of UnicatPrefix.
+func (p *producer) nlri(op int, ph *bmp.PerPeerHeader, update *bgp.Update, routes []base.Route) ([]UnicastPrefix, error) {
 	var operation string
 	switch op {
 	case 0:
@@ -21,7 +22,7 @@ func (p *producer) nlri(op int, ph *bmp.PerPeerHeader, update *bgp.Update) ([]Un
 		return nil, fmt.Errorf("unknown operation %d", op)
 	}
 	prfxs := make([]UnicastPrefix, 0)
-	for _, pr := range update.NLRI {
+	for _, pr := range routes {
 		prfx := UnicastPrefix{
 			Action:         operation,
 			RouterHash:     p.speakerHash,
@@ -33,10 +34,7 @@ func (p *producer) nlri(op int, ph *bmp.PerPeerHeader, update *bgp.Update) ([]Un
 			PathID:         int32(pr.PathID),
 			BaseAttributes: update.BaseAttributes,
 		}
-		if ases := update.BaseAttributes.ASPath; len(ases) != 0 {
-			// Last element in AS_PATH would be the AS of the origin
-			prfx.OriginAS = int32(ases[len(ases)-1])
-		}
+		prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
 		if ph.FlagV {
 			// IPv6 specific conversions
 			prfx.IsIPv4 = false
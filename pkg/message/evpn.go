@@ -7,6 +7,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/evpn"
 )
 
 // evpn process MP_REACH_NLRI AFI 25 SAFI 70 update message and returns
@@ -15,10 +16,20 @@ func (p *producer) evpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *
 	if glog.V(6) {
 		glog.Infof("All attributes in evpn update: %+v", update.GetAllAttributeID())
 	}
-	evpn, err := nlri.GetNLRIEVPN()
+	route, err := nlri.GetNLRIEVPN()
 	if err != nil {
+		if de, ok := err.(*bgp.DecodeError); ok {
+			de.AttributeType = mpAttributeType(nlri)
+			p.produceUpdateError(ph, de.Action, []*bgp.DecodeError{de})
+		}
 		return nil, err
 	}
+	// Extended Communities are common for all routes of the update
+	exts, _ := update.GetExtCommunity()
+	isSRv6 := false
+	if psid, err := update.GetAttrPrefixSID(); err == nil && psid != nil {
+		isSRv6 = psid.SRv6L2Service != nil || psid.SRv6L3Service != nil
+	}
 	prfxs := make([]EVPNPrefix, 0)
 	var operation string
 	switch op {
@@ -29,7 +40,8 @@ func (p *producer) evpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *
 	default:
 		return nil, fmt.Errorf("unknown operation %d", op)
 	}
-	for _, e := range evpn.Route {
+	errs := make([]*bgp.DecodeError, 0)
+	for _, e := range route.Route {
 		prfx := EVPNPrefix{
 			Action:         operation,
 			RouterHash:     p.speakerHash,
@@ -40,10 +52,7 @@ func (p *producer) evpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *
 			Nexthop:        nlri.GetNextHop(),
 			BaseAttributes: update.BaseAttributes,
 		}
-		if ases := update.BaseAttributes.ASPath; len(ases) != 0 {
-			// Last element in AS_PATH would be the AS of the origin
-			prfx.OriginAS = int32(ases[len(ases)-1])
-		}
+		prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
 		if ph.FlagV {
 			// IPv6 specific conversions
 			prfx.IsIPv4 = false
@@ -57,10 +66,16 @@ func (p *producer) evpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *
 		}
 		// Do not want to panic on nil pointer
 		if e != nil {
+			if e.Err != nil {
+				// Malformed route is reported as withdrawn while other routes of the update are processed
+				prfx.Action = "del"
+				de := *e.Err
+				de.AttributeType = mpAttributeType(nlri)
+				errs = append(errs, &de)
+			}
 			prfx.VPNRD = e.GetEVPNRD()
 			prfx.RouteType = e.GetEVPNRouteType()
-			esi := e.GetEVPNESI()
-			if esi != nil {
+			if esi := e.GetEVPNESI(); esi != nil {
 				// TODO Change 10 for a const for ESI length
 				for i := 0; i < 10; i++ {
 					prfx.ESI += fmt.Sprintf("%02d", esi[i])
@@ -69,24 +84,18 @@ func (p *producer) evpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *
 						prfx.ESI += ":"
 					}
 				}
+				prfx.ESIHex = esi.String()
+				prfx.ESIInfo = esi.GetInfo()
 			}
 			prfx.EthTag = e.GetEVPNTAG()
 			if ip := e.GetEVPNIPLength(); ip != nil {
 				prfx.IPLength = *ip
-				gw := e.GetEVPNGWAddr()
-				// IPv4 should have IPLength set to 32
-				if prfx.IPLength == 32 {
-					prfx.IPAddress = net.IP(e.GetEVPNIPAddr()).To4().String()
-					if gw != nil {
-						prfx.GWAddress = net.IP(gw).To4().String()
-					}
+				// IP Prefix route carries the address field of the full length regardless of the prefix length
+				if addr := e.GetEVPNIPAddr(); len(addr) == 4 || len(addr) == 16 {
+					prfx.IPAddress = net.IP(addr).String()
 				}
-				// Processing IPv6 IP and GW
-				if prfx.IPLength == 128 {
-					prfx.IPAddress = net.IP(e.GetEVPNIPAddr()).To16().String()
-					if gw != nil {
-						prfx.GWAddress = net.IP(gw).To16().String()
-					}
+				if gw := e.GetEVPNGWAddr(); len(gw) == 4 || len(gw) == 16 {
+					prfx.GWAddress = net.IP(gw).String()
 				}
 			}
 			if mac := e.GetEVPNMACLength(); mac != nil {
@@ -100,9 +109,72 @@ func (p *producer) evpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *
 				}
 			}
 			prfx.Labels = e.GetEVPNLabel()
+			prfx.MulticastSource = multicastAddress(e.GetEVPNMulticastSource())
+			prfx.MulticastGroup = multicastAddress(e.GetEVPNMulticastGroup())
+			if ip := e.GetEVPNOriginatorIP(); ip != nil {
+				prfx.OriginatorIP = net.IP(ip).String()
+			}
+			switch t := e.RouteTypeSpec.(type) {
+			case *evpn.SelectiveMulticastEthTag:
+				prfx.IGMPFlags = t.Flags
+			case *evpn.MulticastSynch:
+				prfx.IGMPFlags = t.Flags
+				prfx.SequenceNumber = t.SequenceNumber
+				prfx.MaxResponseTime = t.MaxResponseTime
+			case *evpn.PerRegionIPMSIAD:
+				if exts, err := bgp.UnmarshalBGPExtCommunity(t.RegionID); err == nil {
+					prfx.RegionID = exts[0].String()
+				}
+			case *evpn.LeafAD:
+				prfx.RouteKeyType = t.RouteKey.RouteType
+			case *evpn.Opaque:
+				prfx.OpaqueRoute = t.Value
+			}
+		}
+		setEVPNExtCommunities(&prfx, exts)
+		if isSRv6 {
+			prfx.Encapsulation = append(prfx.Encapsulation, "SRv6")
 		}
 		prfxs = append(prfxs, prfx)
 	}
+	if len(errs) != 0 {
+		p.produceUpdateError(ph, bgp.TreatAsWithdraw, errs)
+	}
 
 	return prfxs, nil
 }
+
+// setEVPNExtCommunities populates EVPN prefix with the values of EVPN related Extended Communities
+func setEVPNExtCommunities(prfx *EVPNPrefix, exts []bgp.ExtCommunity) {
+	for _, ext := range exts {
+		if t, ok := ext.GetEncapsulationType(); ok {
+			prfx.Encapsulation = append(prfx.Encapsulation, evpn.GetEncapsulationName(t))
+			continue
+		}
+		if ext.IsDefaultGateway() {
+			prfx.DefaultGateway = true
+			continue
+		}
+		if ext.Type != 0x06 || ext.SubType == nil {
+			continue
+		}
+		var err error
+		switch *ext.SubType {
+		case 0x00:
+			prfx.MACMobility, err = evpn.UnmarshalMACMobility(ext.Value)
+		case 0x01:
+			prfx.ESILabel, err = evpn.UnmarshalESILabel(ext.Value)
+		case 0x02:
+			prfx.ESImportRT = net.HardwareAddr(ext.Value).String()
+		case 0x03:
+			prfx.RouterMAC = net.HardwareAddr(ext.Value).String()
+		case 0x04:
+			prfx.Layer2Attributes, err = evpn.UnmarshalLayer2Attributes(ext.Value)
+		case 0x06:
+			prfx.DFElection, err = evpn.UnmarshalDFElection(ext.Value)
+		}
+		if err != nil {
+			glog.Warningf("failed to decode EVPN extended community of subtype %d with error: %+v", *ext.SubType, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestEVPNMACIPAdvertisement(t *testing.T) {
+	attrs := []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+		// MP_REACH_NLRI L2VPN EVPN, next hop 10.0.0.1
+		0x80, 0x0e, 0x2c, 0x00, 0x19, 0x46, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00,
+		// MAC/IP Advertisement route, RD 10.0.0.1:100, Type 1 ESI
+		0x02, 0x21, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x64,
+		0x01, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x00, 0x0a, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x30, 0x00, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0x00, 0x00, 0x06, 0x41,
+		// Extended Communities: VXLAN encapsulation, sticky MAC Mobility and Router's MAC
+		0xc0, 0x10, 0x18,
+		0x03, 0x0c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08,
+		0x06, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x05,
+		0x06, 0x03, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55,
+	}
+	msgs := produceUpdate(t, false, attrs)
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message but got %d", len(msgs))
+	}
+	if msgs[0].msgType != bmp.EVPNMsg {
+		t.Errorf("expected message type %d but got %d", bmp.EVPNMsg, msgs[0].msgType)
+	}
+	checkFields(t, msgs[0].msg, `{
+		"action": "add",
+		"vpn_rd": "10.0.0.1:100",
+		"route_type": 2,
+		"mac": "00:aa:bb:cc:dd:ee",
+		"eth_segment_id": "01:00:17:34:51:68:85:00:10:00",
+		"eth_segment_id_hex": "01:00:11:22:33:44:55:00:0a:00",
+		"esi_info": {"type": 1, "type_name": "LACP", "system_mac": "00:11:22:33:44:55", "port_key": 10},
+		"encapsulation": ["VXLAN"],
+		"mac_mobility": {"sticky": true, "sequence_number": 5},
+		"router_mac": "00:11:22:33:44:55"
+	}`)
+}
+
+func TestEVPNMalformedRoute(t *testing.T) {
+	attrs := []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+		// MP_REACH_NLRI L2VPN EVPN, next hop 10.0.0.1
+		0x80, 0x0e, 0x31, 0x00, 0x19, 0x46, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00,
+		// MAC/IP Advertisement route of invalid length
+		0x02, 0x03, 0x01, 0x02, 0x03,
+		// MAC/IP Advertisement route, RD 10.0.0.1:100, Type 1 ESI
+		0x02, 0x21, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x64,
+		0x01, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x00, 0x0a, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x30, 0x00, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0x00, 0x00, 0x06, 0x41,
+	}
+	msgs := produceUpdate(t, false, attrs)
+	if len(msgs) != 3 {
+		t.Fatalf("expected 3 messages but got %d", len(msgs))
+	}
+	// Malformed route is withdrawn, the following route is still advertised
+	if msgs[0].msgType != bmp.UpdateErrorMsg {
+		t.Errorf("expected message type %d but got %d", bmp.UpdateErrorMsg, msgs[0].msgType)
+	}
+	checkFields(t, msgs[0].msg, `{"action": "treat-as-withdraw", "errors": [{"attribute_type": 14, "offset": 0,
+		"reason": "malformed EVPN route type 2: invalid length of MAC/IP Advertisement route 3", "action": "treat-as-withdraw"}]}`)
+	checkFields(t, msgs[1].msg, `{"action": "del", "route_type": 2}`)
+	checkFields(t, msgs[2].msg, `{"action": "add", "route_type": 2, "mac": "00:aa:bb:cc:dd:ee"}`)
+}
+
+func TestEVPNTruncatedNLRI(t *testing.T) {
+	attrs := []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+		// MP_REACH_NLRI L2VPN EVPN, next hop 10.0.0.1, route exceeds NLRI
+		0x80, 0x0e, 0x0e, 0x00, 0x19, 0x46, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00,
+		0x02, 0x21, 0x00, 0x01, 0x0a,
+	}
+	msgs := produceUpdate(t, false, attrs)
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message but got %d", len(msgs))
+	}
+	if msgs[0].msgType != bmp.UpdateErrorMsg {
+		t.Errorf("expected message type %d but got %d", bmp.UpdateErrorMsg, msgs[0].msgType)
+	}
+	checkFields(t, msgs[0].msg, `{"action": "afi-safi-disable", "errors": [{"attribute_type": 14, "offset": 0,
+		"reason": "not enough bytes to unmarshal EVPN route type 2 of length 33", "action": "afi-safi-disable"}]}`)
+}
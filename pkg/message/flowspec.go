@@ -11,7 +11,7 @@ import (
 	"github.com/sbezverk/gobmp/pkg/flowspec"
 )
 
-// unicast process nlri 14 afi 1/2 safi 1 messages and generates UnicastPrefix messages
+// flowspec process nlri 14 afi 1/2 safi 133 and 134 messages and generates Flowspec messages
 func (p *producer) flowspec(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *bgp.Update) ([]*Flowspec, error) {
 	var operation string
 	switch op {
@@ -36,10 +36,7 @@ func (p *producer) flowspec(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, upda
 		SpecHash:       fsnlri.GetSpecHash(),
 	}
 
-	if ases := update.BaseAttributes.ASPath; len(ases) != 0 {
-		// Last element in AS_PATH would be the AS of the origin
-		fs.OriginAS = int32(ases[len(ases)-1])
-	}
+	fs.OriginAS = int32(update.BaseAttributes.OriginAS)
 	if ph.FlagV {
 		// Peer is IPv6
 		fs.PeerIP = net.IP(ph.PeerAddress).To16().String()
@@ -49,6 +46,10 @@ func (p *producer) flowspec(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, upda
 	}
 	fs.Nexthop = nlri.GetNextHop()
 	fs.Spec = fsnlri.Spec
+	if fsnlri.RD != nil {
+		fs.VPNRD = fsnlri.RD.String()
+		fs.VPNRDType = fsnlri.RD.Type
+	}
 	if nlri.IsIPv6NLRI() {
 		// IPv6 specific conversions
 		fs.IsIPv4 = false
@@ -56,15 +57,38 @@ func (p *producer) flowspec(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, upda
 		// IPv4 specific conversions
 		fs.IsIPv4 = true
 	}
-	if nlri.IsNextHopIPv6() {
-		fs.IsNexthopIPv4 = false
-	} else {
-		fs.IsNexthopIPv4 = true
-	}
+	fs.IsNexthopIPv4 = isNextHopIPv4(nlri)
+	fs.Actions = getFlowspecActions(update, fs.Nexthop)
+	fs.Rule = flowspec.RuleString(fs.Spec, fs.Actions)
 
 	return []*Flowspec{fs}, nil
 }
 
+// getFlowspecActions returns Flowspec actions carried by Extended Communities of the update,
+// redirect to IP next hop action without the address is redirected to the next hop of the route.
+func getFlowspecActions(update *bgp.Update, nh string) []*flowspec.Action {
+	exts, err := update.GetExtCommunity()
+	if err != nil {
+		return nil
+	}
+	actions := make([]*flowspec.Action, 0)
+	for _, ext := range exts {
+		a, ok := ext.GetFlowspecAction()
+		if !ok {
+			continue
+		}
+		if a.Type == flowspec.ActionRedirectIPNextHop && a.NextHop == "" {
+			a.NextHop = nh
+		}
+		actions = append(actions, a)
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+
+	return actions
+}
+
 func (fs *Flowspec) UnmarshalJSON(b []byte) error {
 	o := Flowspec{}
 	var objmap map[string]json.RawMessage
@@ -100,6 +124,36 @@ func (fs *Flowspec) UnmarshalJSON(b []byte) error {
 	if err := json.Unmarshal(objmap["timestamp"], &o.Timestamp); err != nil {
 		return err
 	}
+	if rd, ok := objmap["vpn_rd"]; ok {
+		if err := json.Unmarshal(rd, &o.VPNRD); err != nil {
+			return err
+		}
+	}
+	if t, ok := objmap["vpn_rd_type"]; ok {
+		if err := json.Unmarshal(t, &o.VPNRDType); err != nil {
+			return err
+		}
+	}
+	if a, ok := objmap["actions"]; ok {
+		if err := json.Unmarshal(a, &o.Actions); err != nil {
+			return err
+		}
+	}
+	if r, ok := objmap["rule"]; ok {
+		if err := json.Unmarshal(r, &o.Rule); err != nil {
+			return err
+		}
+	}
+	if ps, ok := objmap["path_status"]; ok {
+		if err := json.Unmarshal(ps, &o.PathStatus); err != nil {
+			return err
+		}
+	}
+	if r, ok := objmap["path_status_reason"]; ok {
+		if err := json.Unmarshal(r, &o.PathStatusReason); err != nil {
+			return err
+		}
+	}
 	if s, ok := objmap["spec"]; ok {
 		var specs []map[string]interface{}
 		if err := json.Unmarshal(s, &specs); err != nil {
@@ -116,14 +170,26 @@ func (fs *Flowspec) UnmarshalJSON(b []byte) error {
 					return err
 				}
 				o.Spec = append(o.Spec, s)
-			case flowspec.Type3:
+			case flowspec.Type3, flowspec.Type4, flowspec.Type5, flowspec.Type6, flowspec.Type7,
+				flowspec.Type8, flowspec.Type10, flowspec.Type11, flowspec.Type13:
+				s, err := makeGenericSpec(spec)
+				if err != nil {
+					return err
+				}
+				o.Spec = append(o.Spec, s)
+			case flowspec.Type9, flowspec.Type12:
 				s, err := makeGenericSpec(spec)
 				if err != nil {
 					return err
 				}
+				for _, ov := range s.(*flowspec.GenericSpec).OpVal {
+					if ov.Op != nil {
+						ov.Op.Bitmask = true
+					}
+				}
 				o.Spec = append(o.Spec, s)
 			default:
-				glog.Errorf("Unknown type: %+v", spec["type"].(flowspec.SpecType))
+				glog.Errorf("Unknown type: %+v", spec["type"])
 			}
 		}
 	}
@@ -144,6 +210,9 @@ func makePrefixSpec(spec map[string]interface{}) (flowspec.Spec, error) {
 		s.Prefix = make([]byte, len(p.(string)))
 		copy(s.Prefix, []byte(p.(string)))
 	}
+	if p, ok := spec["offset"]; ok {
+		s.Offset = uint8(p.(float64))
+	}
 
 	return s, nil
 }
@@ -189,6 +258,12 @@ func makeOpValPair(src []interface{}) ([]*flowspec.OpVal, error) {
 			if e, ok := p.(map[string]interface{})["equal"]; ok {
 				op.EQBit = e.(bool)
 			}
+			if e, ok := p.(map[string]interface{})["not"]; ok {
+				op.NOTBit = e.(bool)
+			}
+			if e, ok := p.(map[string]interface{})["match"]; ok {
+				op.MatchBit = e.(bool)
+			}
 			o.Op = op
 		}
 		ovp[i] = o
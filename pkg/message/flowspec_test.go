@@ -0,0 +1,159 @@
+package message
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/flowspec"
+)
+
+func TestFlowspecTopics(t *testing.T) {
+	// Traffic rate 0 action Extended Community
+	drop := []byte{0xc0, 0x10, 0x08, 0x80, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	tests := []struct {
+		name    string
+		attrs   []byte
+		drop    bool
+		splitAF bool
+		msgType int
+		expect  string
+	}{
+		{
+			name: "ipv4 flowspec",
+			attrs: []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+				0x80, 0x0e, 0x0b, 0x00, 0x01, 0x85, 0x00, 0x00, 0x05, 0x01, 0x18, 0x0a, 0x00, 0x00},
+			drop:    true,
+			msgType: bmp.FlowspecMsg,
+			expect:  `{"action": "add", "vpn_rd_type": 0, "actions": [{"type": "traffic-rate-bytes", "rate": 0}], "rule": "dst 10.0.0.0/24 -> rate-limit 0"}`,
+		},
+		{
+			name: "vpnv4 flowspec",
+			attrs: []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+				0x80, 0x0e, 0x13, 0x00, 0x01, 0x86, 0x00, 0x00, 0x0d, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x01, 0x18, 0x0a, 0x00, 0x00},
+			drop:    true,
+			msgType: bmp.FlowspecVPNMsg,
+			expect:  `{"action": "add", "vpn_rd": "65000:100", "vpn_rd_type": 0, "rule": "dst 10.0.0.0/24 -> rate-limit 0"}`,
+		},
+		{
+			name: "vpnv4 flowspec split by address family",
+			attrs: []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+				0x80, 0x0e, 0x13, 0x00, 0x01, 0x86, 0x00, 0x00, 0x0d, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x64, 0x01, 0x18, 0x0a, 0x00, 0x00},
+			drop:    true,
+			splitAF: true,
+			msgType: bmp.FlowspecVPNV4Msg,
+			expect:  `{"action": "add", "vpn_rd": "10.0.0.1:100", "vpn_rd_type": 1}`,
+		},
+		{
+			name: "vpnv6 flowspec withdraw split by address family",
+			attrs: []byte{
+				0x80, 0x0f, 0x13, 0x00, 0x02, 0x86, 0x0f, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x01, 0x20, 0x00, 0x20, 0x01, 0x0d, 0xb8},
+			splitAF: true,
+			msgType: bmp.FlowspecVPNV6Msg,
+			expect:  `{"action": "del", "is_ipv4": false, "is_nexthop_ipv4": false, "vpn_rd": "65000:100", "vpn_rd_type": 0, "rule": "dst 2001:db8::/32"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := tt.attrs
+			if tt.drop {
+				attrs = append(append([]byte{}, tt.attrs...), drop...)
+			}
+			msgs := produceUpdate(t, tt.splitAF, attrs)
+			if len(msgs) != 1 {
+				t.Fatalf("expected 1 message but got %d", len(msgs))
+			}
+			if msgs[0].msgType != tt.msgType {
+				t.Errorf("expected message type %d but got %d", tt.msgType, msgs[0].msgType)
+			}
+			checkFields(t, msgs[0].msg, tt.expect)
+		})
+	}
+}
+
+func TestFlowspecActions(t *testing.T) {
+	attrs := []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+		// Traffic action with terminal and sample, redirect to 65000:100, traffic marking DSCP 46,
+		// redirect to IP next hop 192.168.0.1 and redirect to IP next hop of the route with copy
+		0xc0, 0x10, 0x28,
+		0x80, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03,
+		0x80, 0x08, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64,
+		0x80, 0x09, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2e,
+		0x01, 0x0c, 0xc0, 0xa8, 0x00, 0x01, 0x00, 0x00,
+		0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+		0x80, 0x0e, 0x0f, 0x00, 0x01, 0x85, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x05, 0x01, 0x18, 0x0a, 0x00, 0x00}
+	msgs := produceUpdate(t, false, attrs)
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message but got %d", len(msgs))
+	}
+	checkFields(t, msgs[0].msg, `{"nexthop": "10.0.0.1",
+		"actions": [
+			{"type": "traffic-action", "terminal": true, "sample": true},
+			{"type": "redirect", "route_target": "65000:100"},
+			{"type": "traffic-marking", "dscp": 46},
+			{"type": "redirect-ip-nh", "next_hop": "192.168.0.1"},
+			{"type": "redirect-ip-nh", "next_hop": "10.0.0.1", "copy": true}
+		],
+		"rule": "dst 10.0.0.0/24 -> traffic-action terminal sample, redirect 65000:100, set-dscp 46, redirect-nh 192.168.0.1, redirect-nh 10.0.0.1 copy"}`)
+}
+
+func TestFlowspecUnmarshalPathMarking(t *testing.T) {
+	fs := &Flowspec{
+		Action:         "add",
+		RouterIP:       "10.0.0.1",
+		BaseAttributes: &bgp.BaseAttributes{},
+		PeerASN:        65000,
+		Timestamp:      "Oct 19 18:00:10.000000",
+		Nexthop:        "10.0.0.1",
+		SpecHash:       "hash",
+		PathMarking:    PathMarking{PathStatus: []string{"best"}, PathStatusReason: "invalid for policy deny"},
+	}
+	b, err := json.Marshal(fs)
+	if err != nil {
+		t.Fatalf("failed to marshal flowspec with error: %+v", err)
+	}
+	got := &Flowspec{}
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatalf("failed to unmarshal flowspec with error: %+v", err)
+	}
+	if !reflect.DeepEqual(fs.PathMarking, got.PathMarking) {
+		t.Fatalf("expected path marking %+v but got %+v", fs.PathMarking, got.PathMarking)
+	}
+}
+
+func TestFlowspecUnmarshalBitmaskOperator(t *testing.T) {
+	// TCP flags not syn and fragment first-fragment
+	nlri, err := flowspec.UnmarshalFlowspecNLRI([]byte{0x06, 0x09, 0x82, 0x02, 0x0c, 0x81, 0x04})
+	if err != nil {
+		t.Fatalf("failed to unmarshal flowspec nlri with error: %+v", err)
+	}
+	fs := &Flowspec{
+		Action:         "add",
+		RouterIP:       "10.0.0.1",
+		BaseAttributes: &bgp.BaseAttributes{},
+		PeerASN:        65000,
+		Timestamp:      "Oct 19 18:00:10.000000",
+		Nexthop:        "10.0.0.1",
+		SpecHash:       nlri.SpecHash,
+		Spec:           nlri.Spec,
+	}
+	b, err := json.Marshal(fs)
+	if err != nil {
+		t.Fatalf("failed to marshal flowspec with error: %+v", err)
+	}
+	got := &Flowspec{}
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatalf("failed to unmarshal flowspec with error: %+v", err)
+	}
+	if len(got.Spec) != len(fs.Spec) {
+		t.Fatalf("expected %d specs but got %d", len(fs.Spec), len(got.Spec))
+	}
+	for i, s := range got.Spec {
+		expect := fs.Spec[i].(*flowspec.GenericSpec).OpVal[0].Op
+		if op := s.(*flowspec.GenericSpec).OpVal[0].Op; !reflect.DeepEqual(expect, op) {
+			t.Errorf("expected operator %+v but got %+v", expect, op)
+		}
+	}
+}
@@ -40,10 +40,9 @@ func (p *producer) l3vpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update
 			BaseAttributes: update.BaseAttributes,
 		}
 
-		if ases := update.BaseAttributes.ASPath; len(ases) != 0 {
-			// Last element in AS_PATH would be the AS of the origin
-			prfx.OriginAS = int32(ases[len(ases)-1])
-		}
+		prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
+		prfx.NexthopLinkLocal = nlri.GetNextHopLinkLocal()
+		prfx.IsExtendedNexthop = isExtendedNextHop(nlri)
 		if nlri.IsIPv6NLRI() {
 			// IPv6 specific conversions
 			prfx.IsIPv4 = false
@@ -57,11 +56,7 @@ func (p *producer) l3vpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update
 			copy(p, e.Prefix)
 			prfx.Prefix = net.IP(p).To4().String()
 		}
-		if nlri.IsNextHopIPv6() {
-			prfx.IsNexthopIPv4 = false
-		} else {
-			prfx.IsNexthopIPv4 = true
-		}
+		prfx.IsNexthopIPv4 = isNextHopIPv4(nlri)
 		if ph.FlagV {
 			prfx.PeerIP = net.IP(ph.PeerAddress).To16().String()
 
@@ -0,0 +1,45 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestL3VPNNexthop(t *testing.T) {
+	tests := []struct {
+		name    string
+		attrs   []byte
+		msgType int
+		expect  string
+	}{
+		{
+			name: "vpnv4 with ipv6 next hop",
+			attrs: []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+				0x80, 0x0e, 0x2c, 0x00, 0x01, 0x80, 0x18, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00,
+				0x70, 0x00, 0x06, 0x41, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x0a, 0x00, 0x00},
+			msgType: bmp.L3VPNV4Msg,
+			expect:  `{"action": "add", "prefix": "10.0.0.0", "nexthop": "2001:db8::1", "is_nexthop_ipv4": false}`,
+		},
+		{
+			name: "vpnv6 withdraw",
+			attrs: []byte{0x80, 0x0f, 0x13, 0x00, 0x02, 0x80,
+				0x78, 0x00, 0x06, 0x41, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x20, 0x01, 0x0d, 0xb8},
+			msgType: bmp.L3VPNV6Msg,
+			expect:  `{"action": "del", "prefix": "2001:db8::", "is_nexthop_ipv4": false}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := produceUpdate(t, true, tt.attrs)
+			if len(msgs) != 1 {
+				t.Fatalf("expected 1 message but got %d", len(msgs))
+			}
+			if msgs[0].msgType != tt.msgType {
+				t.Errorf("expected message type %d but got %d", tt.msgType, msgs[0].msgType)
+			}
+			checkFields(t, msgs[0].msg, tt.expect)
+		})
+	}
+}
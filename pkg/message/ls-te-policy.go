@@ -0,0 +1,85 @@
+package message
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/te"
+)
+
+func (p *producer) lsTEPolicy(nlri5 *te.NLRI, nextHop string, op int, ph *bmp.PerPeerHeader, update *bgp.Update) (*LSTEPolicy, error) {
+	var operation string
+	switch op {
+	case 0:
+		operation = "add"
+	case 1:
+		operation = "del"
+	default:
+		return nil, fmt.Errorf("unknown operation %d", op)
+	}
+	msg := LSTEPolicy{
+		Action:     operation,
+		RouterHash: p.speakerHash,
+		RouterIP:   p.speakerIP,
+		PeerHash:   ph.GetPeerHash(),
+		PeerASN:    ph.PeerAS,
+		Timestamp:  ph.GetPeerTimestamp(),
+		DomainID:   nlri5.GetIdentifier(),
+	}
+	msg.Nexthop = nextHop
+	msg.PeerIP = ph.GetPeerAddrString()
+	msg.ProtocolID = nlri5.ProtocolID
+	msg.Protocol = nlri5.GetProtocolID()
+	msg.HeadEndHash = nlri5.HeadEndHash
+	if nlri5.HeadEnd != nil {
+		msg.HeadEndASN = nlri5.HeadEnd.GetASN()
+		msg.HeadEndLSID = nlri5.HeadEnd.GetLSID()
+		if id := nlri5.HeadEnd.GetBGPRouterID(); id != nil {
+			msg.HeadEndRouterID = net.IP(id).String()
+		}
+	}
+	if pd := nlri5.Policy; pd != nil {
+		var err error
+		if msg.TunnelID, err = pd.GetTunnelID(); err != nil {
+			glog.Errorf("failed to get Tunnel ID of TE Policy with error: %+v", err)
+		}
+		if msg.LSPID, err = pd.GetLSPID(); err != nil {
+			glog.Errorf("failed to get LSP ID of TE Policy with error: %+v", err)
+		}
+		if addr, err := pd.GetTunnelHeadEndAddr(); err == nil && addr != nil {
+			msg.TunnelHeadEndAddr = net.IP(addr).String()
+		}
+		if addr, err := pd.GetTunnelTailEndAddr(); err == nil && addr != nil {
+			msg.TunnelTailEndAddr = net.IP(addr).String()
+		}
+		if msg.CandidatePath, err = pd.GetPolicyCandidatePathDescriptor(); err != nil {
+			glog.Errorf("failed to get Candidate Path Descriptor of TE Policy with error: %+v", err)
+		}
+		if tlv, ok := pd.TLV[te.LocalMPLSCrossConnectType]; ok {
+			if msg.LocalMPLSCrossConnect, err = te.UnmarshalLocalMPLSCrossConnect(tlv.Value); err != nil {
+				glog.Errorf("failed to get Local MPLS Cross Connect of TE Policy with error: %+v", err)
+			}
+		}
+	}
+	ls, err := update.GetNLRI29()
+	if err == nil {
+		if msg.BindingSID, err = ls.GetSRBindingSID(); err != nil {
+			glog.Errorf("failed to get Binding SID of TE Policy with error: %+v", err)
+		}
+		if msg.CandidatePathState, err = ls.GetSRCandidatePathState(); err != nil {
+			glog.Errorf("failed to get Candidate Path State of TE Policy with error: %+v", err)
+		}
+		msg.CandidatePathName = ls.GetSRCandidatePathName()
+		if msg.CandidatePathConstraints, err = ls.GetSRCandidatePathConstraints(); err != nil {
+			glog.Errorf("failed to get Candidate Path Constraints of TE Policy with error: %+v", err)
+		}
+		if msg.SegmentList, err = ls.GetSRSegmentList(); err != nil {
+			glog.Errorf("failed to get Segment Lists of TE Policy with error: %+v", err)
+		}
+	}
+
+	return &msg, nil
+}
@@ -0,0 +1,109 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestLSTEPolicy(t *testing.T) {
+	nlri := []byte{
+		// NLRI Type 5 TE Policy, Protocol ID Segment Routing and Identifier
+		0x00, 0x05, 0x00, 0x3f,
+		0x09,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// Head-End Node Descriptor, AS 65000 and BGP Router ID 10.0.0.1
+		0x01, 0x00, 0x00, 0x10,
+		0x02, 0x00, 0x00, 0x04, 0x00, 0x00, 0xfd, 0xe8,
+		0x02, 0x04, 0x00, 0x04, 0x0a, 0x00, 0x00, 0x01,
+		// Tunnel ID 5
+		0x02, 0x26, 0x00, 0x02, 0x00, 0x05,
+		// Policy Candidate Path Descriptor
+		0x02, 0x2a, 0x00, 0x18,
+		0x02, 0x00, 0x00, 0x00,
+		0x0a, 0x00, 0x00, 0x02,
+		0x00, 0x00, 0x00, 0x64,
+		0x00, 0x00, 0xfd, 0xe8,
+		0x0a, 0x00, 0x00, 0x01,
+		0x00, 0x00, 0x00, 0x01,
+	}
+	tests := []struct {
+		name   string
+		attrs  []byte
+		expect string
+	}{
+		{
+			name: "te policy with candidate path name",
+			attrs: append([]byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+				// BGP-LS Attribute with SR Candidate Path Name "cp1"
+				0x80, 0x1d, 0x07, 0x04, 0xb3, 0x00, 0x03, 'c', 'p', '1',
+				0x80, 0x0e, 0x4c, 0x40, 0x04, 0x47, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00}, nlri...),
+			expect: `{"action": "add", "nexthop": "10.0.0.1", "protocol_id": 9, "headend_asn": 65000, "headend_router_id": "10.0.0.1",
+				"tunnel_id": 5, "candidate_path_name": "cp1"}`,
+		},
+		{
+			name:   "te policy withdraw",
+			attrs:  append([]byte{0x80, 0x0f, 0x46, 0x40, 0x04, 0x47}, nlri...),
+			expect: `{"action": "del", "protocol_id": 9, "headend_asn": 65000, "tunnel_id": 5}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := produceUpdate(t, false, tt.attrs)
+			if len(msgs) != 1 {
+				t.Fatalf("expected 1 message but got %d", len(msgs))
+			}
+			if msgs[0].msgType != bmp.LSTEPolicyMsg {
+				t.Errorf("expected message type %d but got %d", bmp.LSTEPolicyMsg, msgs[0].msgType)
+			}
+			checkFields(t, msgs[0].msg, tt.expect)
+			cp, ok := msgs[0].msg["candidate_path"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("candidate_path is missing")
+			}
+			if cp["color"] != float64(100) {
+				t.Errorf("expected candidate path color 100 but got %v", cp["color"])
+			}
+		})
+	}
+}
+
+func TestLSTEPolicyMalformedAttribute(t *testing.T) {
+	attrs := []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+		// BGP-LS Attribute with truncated TLV
+		0x80, 0x1d, 0x03, 0x04, 0xb3, 0x00,
+		0x80, 0x0e, 0x4c, 0x40, 0x04, 0x47, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00,
+		// NLRI Type 5 TE Policy, Protocol ID Segment Routing and Identifier
+		0x00, 0x05, 0x00, 0x3f,
+		0x09,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// Head-End Node Descriptor, AS 65000 and BGP Router ID 10.0.0.1
+		0x01, 0x00, 0x00, 0x10,
+		0x02, 0x00, 0x00, 0x04, 0x00, 0x00, 0xfd, 0xe8,
+		0x02, 0x04, 0x00, 0x04, 0x0a, 0x00, 0x00, 0x01,
+		// Tunnel ID 5
+		0x02, 0x26, 0x00, 0x02, 0x00, 0x05,
+		// Policy Candidate Path Descriptor
+		0x02, 0x2a, 0x00, 0x18,
+		0x02, 0x00, 0x00, 0x00,
+		0x0a, 0x00, 0x00, 0x02,
+		0x00, 0x00, 0x00, 0x64,
+		0x00, 0x00, 0xfd, 0xe8,
+		0x0a, 0x00, 0x00, 0x01,
+		0x00, 0x00, 0x00, 0x01,
+	}
+	msgs := produceUpdate(t, false, attrs)
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages but got %d", len(msgs))
+	}
+	// Malformed BGP-LS Attribute is discarded and the route is still advertised
+	if msgs[0].msgType != bmp.UpdateErrorMsg {
+		t.Errorf("expected message type %d but got %d", bmp.UpdateErrorMsg, msgs[0].msgType)
+	}
+	checkFields(t, msgs[0].msg, `{"action": "attribute-discard", "errors": [{"attribute_type": 29, "offset": 11,
+		"reason": "not enough bytes to unmarshal BGP-LS TLV", "action": "attribute-discard"}]}`)
+	if msgs[1].msgType != bmp.LSTEPolicyMsg {
+		t.Errorf("expected message type %d but got %d", bmp.LSTEPolicyMsg, msgs[1].msgType)
+	}
+	checkFields(t, msgs[1].msg, `{"action": "add", "protocol_id": 9, "tunnel_id": 5, "candidate_path_name": null}`)
+}
@@ -47,10 +47,7 @@ func (p *producer) unicast(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, updat
 			PathID:         int32(e.PathID),
 			BaseAttributes: update.BaseAttributes,
 		}
-		if ases := update.BaseAttributes.ASPath; len(ases) != 0 {
-			// Last element in AS_PATH would be the AS of the origin
-			prfx.OriginAS = int32(ases[len(ases)-1])
-		}
+		prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
 		if ph.FlagV {
 			// Peer is IPv6
 			prfx.PeerIP = net.IP(ph.PeerAddress).To16().String()
@@ -59,17 +56,18 @@ func (p *producer) unicast(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, updat
 			prfx.PeerIP = net.IP(ph.PeerAddress[12:]).To4().String()
 		}
 		prfx.Nexthop = nlri.GetNextHop()
+		prfx.NexthopLinkLocal = nlri.GetNextHopLinkLocal()
+		prfx.IsNexthopIPv4 = isNextHopIPv4(nlri)
+		prfx.IsExtendedNexthop = isExtendedNextHop(nlri)
 		if nlri.IsIPv6NLRI() {
 			// IPv6 specific conversions
 			prfx.IsIPv4 = false
-			prfx.IsNexthopIPv4 = false
 			a := make([]byte, 16)
 			copy(a, e.Prefix)
 			prfx.Prefix = net.IP(a).To16().String()
 		} else {
 			// IPv4 specific conversions
 			prfx.IsIPv4 = true
-			prfx.IsNexthopIPv4 = true
 			a := make([]byte, 4)
 			copy(a, e.Prefix)
 			prfx.Prefix = net.IP(a).To4().String()
@@ -88,3 +86,24 @@ func (p *producer) unicast(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, updat
 
 	return prfxs, nil
 }
+
+// isNextHopIPv4 returns true when the next hop is IPv4 address, IPv4 NLRI may carry IPv6 next hop, rfc8950.
+// MP_UNREACH_NLRI carries no next hop, the address family of NLRI is used instead.
+func isNextHopIPv4(nlri bgp.MPNLRI) bool {
+	if _, ok := nlri.(*bgp.MPReachNLRI); ok {
+		return !nlri.IsNextHopIPv6()
+	}
+
+	return !nlri.IsIPv6NLRI()
+}
+
+// isExtendedNextHop returns true when IPv4 NLRI carries IPv6 next hop and Extended Next Hop capability
+// is negotiated for the AFI/SAFI of NLRI, rfc8950.
+func isExtendedNextHop(nlri bgp.MPNLRI) bool {
+	mp, ok := nlri.(*bgp.MPReachNLRI)
+	if !ok {
+		return false
+	}
+
+	return mp.ExtendedNextHop && !mp.IsIPv6NLRI() && mp.IsNextHopIPv6()
+}
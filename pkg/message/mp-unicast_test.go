@@ -0,0 +1,92 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestUnicastNexthop(t *testing.T) {
+	ipv4IPv6NH := []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+		0x80, 0x0e, 0x19, 0x00, 0x01, 0x01, 0x10, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x18, 0x0a, 0x00, 0x00}
+	// Extended Next Hop capability for IPv4 unicast NLRI with IPv6 next hop
+	enh := &bgp.NegotiatedCapabilities{
+		ExtendedNextHop: []*bgp.ExtendedNextHopTuple{{NLRIAFI: 1, NLRISAFI: 1, NextHopAFI: 2}},
+	}
+	tests := []struct {
+		name              string
+		caps              *bgp.NegotiatedCapabilities
+		attrs             []byte
+		msgType           int
+		action            string
+		prefix            string
+		nexthop           string
+		isNexthopIPv4     bool
+		isExtendedNexthop bool
+	}{
+		{
+			name:          "ipv4 unicast with ipv6 next hop",
+			attrs:         ipv4IPv6NH,
+			msgType:       bmp.UnicastPrefixV4Msg,
+			action:        "add",
+			prefix:        "10.0.0.0",
+			nexthop:       "2001:db8::1",
+			isNexthopIPv4: false,
+		},
+		{
+			name:              "ipv4 unicast with ipv6 next hop and extended next hop capability",
+			caps:              enh,
+			attrs:             ipv4IPv6NH,
+			msgType:           bmp.UnicastPrefixV4Msg,
+			action:            "add",
+			prefix:            "10.0.0.0",
+			nexthop:           "2001:db8::1",
+			isNexthopIPv4:     false,
+			isExtendedNexthop: true,
+		},
+		{
+			name:          "ipv4 unicast withdraw",
+			attrs:         []byte{0x80, 0x0f, 0x07, 0x00, 0x01, 0x01, 0x18, 0x0a, 0x00, 0x00},
+			msgType:       bmp.UnicastPrefixV4Msg,
+			action:        "del",
+			prefix:        "10.0.0.0",
+			isNexthopIPv4: true,
+		},
+		{
+			name:          "ipv6 unicast withdraw",
+			attrs:         []byte{0x80, 0x0f, 0x08, 0x00, 0x02, 0x01, 0x20, 0x20, 0x01, 0x0d, 0xb8},
+			msgType:       bmp.UnicastPrefixV6Msg,
+			action:        "del",
+			prefix:        "2001:db8::",
+			isNexthopIPv4: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := runUpdateCaps(t, &producer{splitAF: true}, tt.caps, tt.attrs, nil)
+			if len(msgs) != 1 {
+				t.Fatalf("expected 1 message but got %d", len(msgs))
+			}
+			if msgs[0].msgType != tt.msgType {
+				t.Errorf("expected message type %d but got %d", tt.msgType, msgs[0].msgType)
+			}
+			m := msgs[0].msg
+			if m["action"] != tt.action {
+				t.Errorf("expected action %s but got %v", tt.action, m["action"])
+			}
+			if m["prefix"] != tt.prefix {
+				t.Errorf("expected prefix %s but got %v", tt.prefix, m["prefix"])
+			}
+			if nh, _ := m["nexthop"].(string); nh != tt.nexthop {
+				t.Errorf("expected nexthop %s but got %s", tt.nexthop, nh)
+			}
+			if m["is_nexthop_ipv4"] != tt.isNexthopIPv4 {
+				t.Errorf("expected is_nexthop_ipv4 %t but got %v", tt.isNexthopIPv4, m["is_nexthop_ipv4"])
+			}
+			if e, _ := m["is_extended_nexthop"].(bool); e != tt.isExtendedNexthop {
+				t.Errorf("expected is_extended_nexthop %t but got %v", tt.isExtendedNexthop, m["is_extended_nexthop"])
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package message
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/mvpn"
+)
+
+// mvpn process MP_REACH_NLRI AFI 1/2 SAFI 5 and SAFI 129 update message and returns
+// MVPN prefix object.
+func (p *producer) mvpn(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *bgp.Update) ([]MVPNPrefix, error) {
+	if glog.V(6) {
+		glog.Infof("All attributes in mvpn update: %+v", update.GetAllAttributeID())
+	}
+	var operation string
+	switch op {
+	case 0:
+		operation = "add"
+	case 1:
+		operation = "del"
+	default:
+		return nil, fmt.Errorf("unknown operation %d", op)
+	}
+	prfx := MVPNPrefix{
+		Action:         operation,
+		RouterHash:     p.speakerHash,
+		RouterIP:       p.speakerIP,
+		PeerHash:       ph.GetPeerHash(),
+		PeerASN:        ph.PeerAS,
+		Timestamp:      ph.GetPeerTimestamp(),
+		Nexthop:        nlri.GetNextHop(),
+		BaseAttributes: update.BaseAttributes,
+		IsIPv4:         !nlri.IsIPv6NLRI(),
+		IsNexthopIPv4:  isNextHopIPv4(nlri),
+	}
+	prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
+	prfx.PMSITunnel = update.BaseAttributes.PMSITunnel
+	if ph.FlagV {
+		prfx.PeerIP = net.IP(ph.PeerAddress).To16().String()
+	} else {
+		prfx.PeerIP = net.IP(ph.PeerAddress[12:]).To4().String()
+	}
+	prfxs := make([]MVPNPrefix, 0)
+	if nlri.GetAFISAFIType() == 29 {
+		// SAFI 129 VPN multicast routes
+		vpn, err := nlri.GetNLRIL3VPN()
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range vpn.NLRI {
+			m := prfx
+			m.PrefixLen = int32(e.Length)
+			addr := make([]byte, 4)
+			if nlri.IsIPv6NLRI() {
+				addr = make([]byte, 16)
+			}
+			copy(addr, e.Prefix)
+			m.Prefix = net.IP(addr).String()
+			m.Labels = make([]uint32, 0)
+			for _, l := range e.Label {
+				m.Labels = append(m.Labels, l.Value)
+			}
+			if e.RD != nil {
+				m.VPNRD = e.RD.String()
+				m.VPNRDType = e.RD.Type
+			}
+			prfxs = append(prfxs, m)
+		}
+		return prfxs, nil
+	}
+	route, err := nlri.GetNLRIMVPN()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range route.Route {
+		m := prfx
+		m.RouteType = e.RouteType
+		m.RouteTypeName = e.GetRouteTypeString()
+		if rd := e.GetRD(); rd != nil {
+			m.VPNRD = rd.String()
+			m.VPNRDType = rd.Type
+		}
+		if ip := e.GetOriginatorIP(); ip != nil {
+			m.OriginatorIP = net.IP(ip).String()
+		}
+		m.SourceAS = e.GetSourceAS()
+		m.MulticastSource = multicastAddress(e.GetSource())
+		m.MulticastGroup = multicastAddress(e.GetGroup())
+		if l, ok := e.RouteTypeSpec.(*mvpn.LeafADRoute); ok {
+			m.RouteKeyType = l.RouteKey.RouteType
+		}
+		prfxs = append(prfxs, m)
+	}
+
+	return prfxs, nil
+}
+
+// multicastAddress returns a string representation of Multicast Source or Group, "*" for the wildcard
+func multicastAddress(b []byte) string {
+	switch {
+	case b == nil:
+		return ""
+	case len(b) == 0:
+		return "*"
+	}
+	return net.IP(b).String()
+}
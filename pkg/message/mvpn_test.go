@@ -0,0 +1,62 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestMVPN(t *testing.T) {
+	origin := []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00}
+	tests := []struct {
+		name   string
+		attrs  []byte
+		expect string
+	}{
+		{
+			name: "s-pmsi a-d with pmsi tunnel",
+			attrs: append(append([]byte{}, origin...),
+				// PMSI Tunnel Ingress Replication to 10.0.0.1
+				0xc0, 0x16, 0x09, 0x00, 0x06, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x01,
+				0x80, 0x0e, 0x21, 0x00, 0x01, 0x05, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00,
+				0x03, 0x16, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x20, 0xc0, 0xa8, 0x01, 0x01, 0x20, 0xe8, 0x01, 0x01, 0x01, 0x0a, 0x00, 0x00, 0x01),
+			expect: `{"action": "add", "is_ipv4": true, "nexthop": "10.0.0.1", "is_nexthop_ipv4": true, "vpn_rd": "65000:100", "vpn_rd_type": 0,
+				"route_type": 3, "route_type_name": "S-PMSI A-D", "originator_ip": "10.0.0.1",
+				"multicast_source": "192.168.1.1", "multicast_group": "232.1.1.1",
+				"pmsi_tunnel": {"leaf_info_required": false, "tunnel_type": "Ingress Replication", "label": 0, "tunnel_id": "10.0.0.1"}}`,
+		},
+		{
+			name: "leaf a-d with s-pmsi a-d route key",
+			attrs: append(append([]byte{}, origin...),
+				0x80, 0x0e, 0x27, 0x00, 0x01, 0x05, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00,
+				0x04, 0x1c, 0x03, 0x16, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x20, 0xc0, 0xa8, 0x01, 0x01, 0x20, 0xe8, 0x01, 0x01, 0x01, 0x0a, 0x00, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x02),
+			expect: `{"action": "add", "route_type": 4, "route_key_type": 3, "originator_ip": "10.0.0.2"}`,
+		},
+		{
+			name: "shared tree join withdraw",
+			attrs: []byte{0x80, 0x0f, 0x17, 0x00, 0x01, 0x05,
+				0x06, 0x12, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x20, 0xe0, 0x01, 0x01, 0x01},
+			expect: `{"action": "del", "is_nexthop_ipv4": true, "route_type": 6, "source_as": 65000,
+				"multicast_source": "*", "multicast_group": "224.1.1.1"}`,
+		},
+		{
+			name: "ipv4 vpn multicast",
+			attrs: append(append([]byte{}, origin...),
+				0x80, 0x0e, 0x18, 0x00, 0x01, 0x81, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00,
+				0x70, 0x00, 0x06, 0x41, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0xe8, 0x01, 0x01),
+			expect: `{"action": "add", "is_ipv4": true, "vpn_rd": "65000:100", "prefix": "232.1.1.0", "prefix_len": 24, "labels": [100]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := produceUpdate(t, false, tt.attrs)
+			if len(msgs) != 1 {
+				t.Fatalf("expected 1 message but got %d", len(msgs))
+			}
+			if msgs[0].msgType != bmp.MVPNMsg {
+				t.Errorf("expected message type %d but got %d", bmp.MVPNMsg, msgs[0].msgType)
+			}
+			checkFields(t, msgs[0].msg, tt.expect)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net"
 
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/bmp"
 )
 
@@ -14,21 +15,16 @@ func (p *producer) producePeerMessage(op int, msg bmp.Message) {
 		glog.Errorf("perPeerHeader is missing, cannot construct PeerStateChange message")
 		return
 	}
-	peerUpMsg, ok := msg.Payload.(*bmp.PeerUpMessage)
-	if !ok {
-		glog.Errorf("got invalid Payload type in bmp.Message %+v", msg.Payload)
-		return
-	}
-	action := "add"
-	if op == peerDown {
-		action = "del"
-	}
-
 	var m PeerStateChange
 
 	if op == peerUP {
+		peerUpMsg, ok := msg.Payload.(*bmp.PeerUpMessage)
+		if !ok {
+			glog.Errorf("got invalid Payload type in bmp.Message %+v", msg.Payload)
+			return
+		}
 		m = PeerStateChange{
-			Action:         action,
+			Action:         "add",
 			RemoteASN:      msg.PeerHeader.PeerAS,
 			PeerRD:         msg.PeerHeader.GetPeerDistinguisherString(),
 			RemotePort:     int(peerUpMsg.RemotePort),
@@ -70,6 +66,9 @@ func (p *producer) producePeerMessage(op int, msg bmp.Message) {
 		}
 		m.AdvCapabilities = peerUpMsg.SentOpen.GetCapabilities()
 		m.RcvCapabilities = peerUpMsg.ReceivedOpen.GetCapabilities()
+		m.NegotiatedCapabilities = bgp.NegotiateCapabilities(m.AdvCapabilities, m.RcvCapabilities)
+		m.Info = peerUpMsg.GetInformation()
+		m.TableName = peerUpMsg.GetTableName()
 	} else {
 		peerDownMsg, ok := msg.Payload.(*bmp.PeerDownMessage)
 		if !ok {
@@ -77,13 +76,30 @@ func (p *producer) producePeerMessage(op int, msg bmp.Message) {
 			return
 		}
 		m = PeerStateChange{
-			Action:     "down",
-			RouterIP:   p.speakerIP,
-			RouterHash: p.speakerHash,
-			BMPReason:  int(peerDownMsg.Reason),
-			RemoteASN:  msg.PeerHeader.PeerAS,
-			PeerRD:     msg.PeerHeader.GetPeerDistinguisherString(),
-			Timestamp:  msg.PeerHeader.GetPeerTimestamp(),
+			Action:        "down",
+			RouterIP:      p.speakerIP,
+			RouterHash:    p.speakerHash,
+			BMPReason:     int(peerDownMsg.Reason),
+			BMPReasonText: peerDownMsg.ReasonString(),
+			RemoteASN:     msg.PeerHeader.PeerAS,
+			PeerRD:        msg.PeerHeader.GetPeerDistinguisherString(),
+			Timestamp:     msg.PeerHeader.GetPeerTimestamp(),
+			FSMEvent:      int(peerDownMsg.FSMEvent),
+			FSMEventText:  peerDownMsg.FSMEventString(),
+		}
+		if n := peerDownMsg.Notification; n != nil {
+			m.BMPErrorCode = int(n.ErrorCode)
+			m.BMPErrorCodeText = n.ErrorCodeString()
+			m.BMPErrorSubCode = int(n.ErrorSubcode)
+			m.BMPErrorSubCodeText = n.ErrorSubcodeString()
+			m.ErrorText = n.String()
+			m.ShutdownCommunication, _ = n.ShutdownCommunication()
+		}
+		for _, tlv := range peerDownMsg.TLV {
+			// VRF/Table Name TLV of Loc-RIB instance per rfc9069
+			if tlv.InformationType == bmp.VRFTableNameTLV {
+				m.TableName = string(tlv.Information)
+			}
 		}
 		if msg.PeerHeader.FlagV {
 			m.IsIPv4 = false
@@ -96,7 +112,6 @@ func (p *producer) producePeerMessage(op int, msg bmp.Message) {
 		}
 		m.InfoData = make([]byte, len(peerDownMsg.Data))
 		copy(m.InfoData, peerDownMsg.Data)
-
 	}
 	if err := p.marshalAndPublish(&m, bmp.PeerStateChangeMsg, []byte(m.RouterHash), false); err != nil {
 		glog.Errorf("failed to process peer message with error: %+v", err)
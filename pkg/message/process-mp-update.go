@@ -6,9 +6,12 @@ import (
 	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/bmp"
 	"github.com/sbezverk/gobmp/pkg/srv6"
+	"github.com/sbezverk/gobmp/pkg/te"
 )
 
-func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPeerHeader, update *bgp.Update) {
+// processMPUpdate produces messages for NLRIs of MP_REACH_NLRI or MP_UNREACH_NLRI attribute, rm is the Route Monitoring
+// message carrying the update, it supplies path status of advertised NLRIs.
+func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPeerHeader, update *bgp.Update, rm *bmp.RouteMonitor) {
 	labeled := false
 	labeledSet := false
 	switch nlri.GetAFISAFIType() {
@@ -39,12 +42,18 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 			labeledSet = true
 			labeled = true
 		}
+		if labeled && p.splitLU {
+			// Labeled Unicast routes go into Transport topic along with Classful Transport routes
+			p.processTransport(nlri, operation, ph, update, rm, false)
+			return
+		}
 		msgs, err := p.unicast(nlri, operation, ph, update, labeled)
 		if err != nil {
 			return
 		}
 		// Loop through and publish all collected messages
-		for _, m := range msgs {
+		for i, m := range msgs {
+			m.PathStatus, m.PathStatusReason = pathStatus(rm, operation, i)
 			topicType := bmp.UnicastPrefixMsg
 			if p.splitAF {
 				if m.IsIPv4 {
@@ -66,7 +75,8 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 			glog.Errorf("failed to produce l3vpn messages with error: %+v", err)
 			return
 		}
-		for _, m := range msgs {
+		for i, m := range msgs {
+			m.PathStatus, m.PathStatusReason = pathStatus(rm, operation, i)
 			topicType := bmp.L3VPNMsg
 			if p.splitAF {
 				if m.IsIPv4 {
@@ -80,13 +90,27 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 				return
 			}
 		}
+	case 23:
+		msgs, err := p.vpls(nlri, operation, ph, update)
+		if err != nil {
+			glog.Errorf("failed to produce vpls messages with error: %+v", err)
+			return
+		}
+		for i, msg := range msgs {
+			msg.PathStatus, msg.PathStatusReason = pathStatus(rm, operation, i)
+			if err := p.marshalAndPublish(&msg, bmp.VPLSMsg, []byte(msg.RouterHash), false); err != nil {
+				glog.Errorf("failed to process VPLS message with error: %+v", err)
+				return
+			}
+		}
 	case 24:
 		msgs, err := p.evpn(nlri, operation, ph, update)
 		if err != nil {
 			glog.Errorf("failed to produce evpn messages with error: %+v", err)
 			return
 		}
-		for _, msg := range msgs {
+		for i, msg := range msgs {
+			msg.PathStatus, msg.PathStatusReason = pathStatus(rm, operation, i)
 			if err := p.marshalAndPublish(&msg, bmp.EVPNMsg, []byte(msg.RouterHash), false); err != nil {
 				glog.Errorf("failed to process EVPNP message with error: %+v", err)
 				return
@@ -100,7 +124,8 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 			glog.Errorf("failed to produce srpolicy messages with error: %+v", err)
 			return
 		}
-		for _, m := range msgs {
+		for i, m := range msgs {
+			m.PathStatus, m.PathStatusReason = pathStatus(rm, operation, i)
 			topicType := bmp.SRPolicyMsg
 			if p.splitAF {
 				if m.IsIPv4 {
@@ -115,18 +140,25 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 			}
 		}
 	case 27:
+		fallthrough
+	case 32:
 		msgs, err := p.flowspec(nlri, operation, ph, update)
 		if err != nil {
 			glog.Errorf("failed to produce flowspec messages with error: %+v", err)
 			return
 		}
-		for _, m := range msgs {
-			topicType := bmp.FlowspecMsg
+		for i, m := range msgs {
+			m.PathStatus, m.PathStatusReason = pathStatus(rm, operation, i)
+			topicType, v4Type, v6Type := bmp.FlowspecMsg, bmp.FlowspecV4Msg, bmp.FlowspecV6Msg
+			if nlri.GetAFISAFIType() == 32 {
+				// VPN Flowspec is published to its own topics
+				topicType, v4Type, v6Type = bmp.FlowspecVPNMsg, bmp.FlowspecVPNV4Msg, bmp.FlowspecVPNV6Msg
+			}
 			if p.splitAF {
 				if m.IsIPv4 {
-					topicType = bmp.FlowspecV4Msg
+					topicType = v4Type
 				} else {
-					topicType = bmp.FlowspecV6Msg
+					topicType = v6Type
 				}
 			}
 			if err := p.marshalAndPublish(&m, topicType, []byte(m.SpecHash), false); err != nil {
@@ -134,19 +166,65 @@ func (p *producer) processMPUpdate(nlri bgp.MPNLRI, operation int, ph *bmp.PerPe
 				return
 			}
 		}
+	case 28:
+		fallthrough
+	case 29:
+		msgs, err := p.mvpn(nlri, operation, ph, update)
+		if err != nil {
+			glog.Errorf("failed to produce mvpn messages with error: %+v", err)
+			return
+		}
+		for i, msg := range msgs {
+			msg.PathStatus, msg.PathStatusReason = pathStatus(rm, operation, i)
+			if err := p.marshalAndPublish(&msg, bmp.MVPNMsg, []byte(msg.RouterHash), false); err != nil {
+				glog.Errorf("failed to process MVPN message with error: %+v", err)
+				return
+			}
+		}
+	case 30:
+		msgs, err := p.rtc(nlri, operation, ph, update)
+		if err != nil {
+			glog.Errorf("failed to produce rtc messages with error: %+v", err)
+			return
+		}
+		for i, msg := range msgs {
+			msg.PathStatus, msg.PathStatusReason = pathStatus(rm, operation, i)
+			if err := p.marshalAndPublish(&msg, bmp.RTCMsg, []byte(msg.RouterHash), false); err != nil {
+				glog.Errorf("failed to process RTC message with error: %+v", err)
+				return
+			}
+		}
+	case 31:
+		p.processTransport(nlri, operation, ph, update, rm, true)
 	case 71:
-		p.processNLRI71SubTypes(nlri, operation, ph, update)
+		p.processNLRI71SubTypes(nlri, operation, ph, update, rm)
 	}
 }
 
-func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp.PerPeerHeader, update *bgp.Update) {
+// processTransport produces and publishes Transport messages for Labeled Unicast and Classful Transport routes
+func (p *producer) processTransport(nlri bgp.MPNLRI, operation int, ph *bmp.PerPeerHeader, update *bgp.Update, rm *bmp.RouteMonitor, ct bool) {
+	msgs, err := p.transport(nlri, operation, ph, update, ct)
+	if err != nil {
+		glog.Errorf("failed to produce transport messages with error: %+v", err)
+		return
+	}
+	for i, msg := range msgs {
+		msg.PathStatus, msg.PathStatusReason = pathStatus(rm, operation, i)
+		if err := p.marshalAndPublish(&msg, bmp.TransportMsg, []byte(msg.RouterHash), false); err != nil {
+			glog.Errorf("failed to process Transport message with error: %+v", err)
+			return
+		}
+	}
+}
+
+func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp.PerPeerHeader, update *bgp.Update, rm *bmp.RouteMonitor) {
 	// NLRI 71 carries 6 known sub type
 	ls, err := nlri.GetNLRI71()
 	if err != nil {
 		glog.Errorf("failed to NLRI 71 with error: %+v", err)
 		return
 	}
-	for _, e := range ls.NLRI {
+	for i, e := range ls.NLRI {
 		// ipv4Flag used to differentiate between IPv4 and IPv6 Prefix NLRI messages
 		ipv4Flag := false
 		switch e.Type {
@@ -161,6 +239,7 @@ func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp
 				glog.Errorf("failed to produce ls_node message with error: %+v", err)
 				continue
 			}
+			msg.PathStatus, msg.PathStatusReason = pathStatus(rm, operation, i)
 			if err := p.marshalAndPublish(&msg, bmp.LSNodeMsg, []byte(msg.RouterHash), false); err != nil {
 				glog.Errorf("failed to process LSNode message with error: %+v", err)
 				continue
@@ -176,6 +255,7 @@ func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp
 				glog.Errorf("failed to produce ls_link message with error: %+v", err)
 				continue
 			}
+			msg.PathStatus, msg.PathStatusReason = pathStatus(rm, operation, i)
 			if err := p.marshalAndPublish(&msg, bmp.LSLinkMsg, []byte(msg.RouterHash), false); err != nil {
 				glog.Errorf("failed to process LSLink message with error: %+v", err)
 				continue
@@ -194,10 +274,27 @@ func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp
 				glog.Errorf("failed to produce ls_prefix message with error: %+v", err)
 				continue
 			}
+			msg.PathStatus, msg.PathStatusReason = pathStatus(rm, operation, i)
 			if err := p.marshalAndPublish(&msg, bmp.LSPrefixMsg, []byte(msg.RouterHash), false); err != nil {
 				glog.Errorf("failed to process LSPrefix message with error: %+v", err)
 				continue
 			}
+		case 5:
+			t, ok := e.LS.(*te.NLRI)
+			if !ok {
+				glog.Errorf("failed to produce ls_te_policy message, unexpected NLRI type %T", e.LS)
+				continue
+			}
+			msg, err := p.lsTEPolicy(t, nlri.GetNextHop(), operation, ph, update)
+			if err != nil {
+				glog.Errorf("failed to produce ls_te_policy message with error: %+v", err)
+				continue
+			}
+			msg.PathStatus, msg.PathStatusReason = pathStatus(rm, operation, i)
+			if err := p.marshalAndPublish(&msg, bmp.LSTEPolicyMsg, []byte(msg.RouterHash), false); err != nil {
+				glog.Errorf("failed to process LSTEPolicy message with error: %+v", err)
+				continue
+			}
 		case 6:
 			s, ok := e.LS.(*srv6.SIDNLRI)
 			if !ok {
@@ -209,6 +306,7 @@ func (p *producer) processNLRI71SubTypes(nlri bgp.MPNLRI, operation int, ph *bmp
 				glog.Errorf("failed to produce ls_srv6_sid message with error: %+v", err)
 				continue
 			}
+			msg.PathStatus, msg.PathStatusReason = pathStatus(rm, operation, i)
 			if err := p.marshalAndPublish(&msg, bmp.LSSRv6SIDMsg, []byte(msg.RouterHash), false); err != nil {
 				glog.Errorf("failed to process LSSRv6SID message with error: %+v", err)
 				continue
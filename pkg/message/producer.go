@@ -14,6 +14,7 @@ const (
 // Producer defines methods to act as a message producer
 type Producer interface {
 	Producer(queue chan bmp.Message, stop chan struct{})
+	ProduceMessage(msg bmp.Message)
 }
 
 type producer struct {
@@ -23,6 +24,8 @@ type producer struct {
 	as4Capable  bool
 	// If splitAF is set to true, ipv4 and ipv6 messages will go into separate topics
 	splitAF bool
+	// If splitLU is set to true, labeled unicast messages will go into transport topic instead of unicast topics
+	splitLU bool
 }
 
 // Producer dispatches kafka workers upon request received from the channel
@@ -38,6 +41,12 @@ func (p *producer) Producer(queue chan bmp.Message, stop chan struct{}) {
 	}
 }
 
+// ProduceMessage generates and publishes messages for a single BMP message in the calling goroutine,
+// it is used when the order of produced messages must follow the order of BMP messages.
+func (p *producer) ProduceMessage(msg bmp.Message) {
+	p.producingWorker(msg)
+}
+
 func (p *producer) producingWorker(msg bmp.Message) {
 	switch obj := msg.Payload.(type) {
 	case *bmp.PeerUpMessage:
@@ -46,15 +55,18 @@ func (p *producer) producingWorker(msg bmp.Message) {
 		p.producePeerMessage(peerDown, msg)
 	case *bmp.RouteMonitor:
 		p.produceRouteMonitorMessage(msg)
+	case *bmp.SessionError:
+		p.produceSessionError(obj)
 	default:
 		glog.Warningf("got Unknown message %T to push to the producer, ignoring it...", obj)
 	}
 }
 
 // NewProducer instantiates a new instance of a producer with Publisher interface
-func NewProducer(publisher pub.Publisher, splitAF bool) Producer {
+func NewProducer(publisher pub.Publisher, splitAF, splitLU bool) Producer {
 	return &producer{
 		publisher: publisher,
 		splitAF:   splitAF,
+		splitLU:   splitLU,
 	}
 }
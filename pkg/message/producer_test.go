@@ -0,0 +1,95 @@
+package message
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+type published struct {
+	msgType int
+	msg     map[string]interface{}
+}
+
+type testPublisher struct {
+	msgs []published
+}
+
+func (p *testPublisher) PublishMessage(msgType int, msgHash []byte, msg []byte) error {
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(msg, &m); err != nil {
+		return err
+	}
+	p.msgs = append(p.msgs, published{msgType: msgType, msg: m})
+	return nil
+}
+
+func (p *testPublisher) Stop() {}
+
+func testPeerHeader() *bmp.PerPeerHeader {
+	return &bmp.PerPeerHeader{
+		PeerDistinguisher: make([]byte, 8),
+		PeerAddress:       []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 192, 168, 0, 1},
+		PeerAS:            65001,
+		PeerBGPID:         []byte{192, 168, 0, 1},
+		PeerTimestamp:     make([]byte, 8),
+	}
+}
+
+// produceUpdate runs BGP Update through the route monitor producer and returns published messages,
+// attrs is the path attributes part of the update without Withdrawn Routes.
+func produceUpdate(t *testing.T, splitAF bool, attrs []byte) []published {
+	t.Helper()
+	return runUpdate(t, &producer{splitAF: splitAF}, attrs, nil)
+}
+
+// produceUpdateNLRI is produceUpdate with NLRI field of the update and Route Monitoring TLVs following the update
+func produceUpdateNLRI(t *testing.T, splitAF bool, attrs []byte, nlri []byte, tlvs ...*bmp.RouteMonitorTLV) []published {
+	t.Helper()
+	return runUpdate(t, &producer{splitAF: splitAF}, attrs, nlri, tlvs...)
+}
+
+// runUpdate runs BGP Update through the route monitor producer p configured by the test
+func runUpdate(t *testing.T, p *producer, attrs []byte, nlri []byte, tlvs ...*bmp.RouteMonitorTLV) []published {
+	t.Helper()
+	return runUpdateCaps(t, p, nil, attrs, nlri, tlvs...)
+}
+
+// runUpdateCaps is runUpdate of BGP Update decoded with capabilities negotiated by the peer
+func runUpdateCaps(t *testing.T, p *producer, caps *bgp.NegotiatedCapabilities, attrs []byte, nlri []byte, tlvs ...*bmp.RouteMonitorTLV) []published {
+	t.Helper()
+	b := []byte{0x00, 0x00, byte(len(attrs) >> 8), byte(len(attrs))}
+	b = append(b, attrs...)
+	b = append(b, nlri...)
+	u, err := bgp.UnmarshalBGPUpdate(b, caps)
+	if err != nil {
+		t.Fatalf("failed to unmarshal update with error: %+v", err)
+	}
+	pub := &testPublisher{}
+	p.publisher = pub
+	p.speakerIP = "10.0.0.1"
+	p.speakerHash = "speaker"
+	p.produceRouteMonitorMessage(bmp.Message{
+		PeerHeader: testPeerHeader(),
+		Payload:    &bmp.RouteMonitor{Update: u, TLV: tlvs},
+	})
+
+	return pub.msgs
+}
+
+// checkFields verifies that the message carries all fields of expect, expect is a JSON object
+func checkFields(t *testing.T, m map[string]interface{}, expect string) {
+	t.Helper()
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(expect), &fields); err != nil {
+		t.Fatalf("failed to unmarshal expected fields with error: %+v", err)
+	}
+	for k, v := range fields {
+		if !reflect.DeepEqual(v, m[k]) {
+			t.Errorf("field %s: expected %v but got %v", k, v, m[k])
+		}
+	}
+}
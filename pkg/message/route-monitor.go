@@ -30,56 +30,127 @@ func (p *producer) produceRouteMonitorMessage(msg bmp.Message) {
 		glog.Errorf("route monitor message is nil")
 		return
 	}
-	if routeMonitorMsg.Update == nil {
+	update := routeMonitorMsg.Update
+	if update == nil {
 		return
 	}
-	if len(routeMonitorMsg.Update.PathAttributes) == 0 {
-		// There is no Path Attributes, just return
-		return
-	}
-	// Using first attribute type to select which nlri processor to call
-	switch routeMonitorMsg.Update.PathAttributes[0].AttributeType {
-	case 14:
-		nlri, err := bgp.UnmarshalMPReachNLRI(routeMonitorMsg.Update.PathAttributes[0].Attribute, routeMonitorMsg.Update.HasPrefixSID())
-		if err != nil {
-			glog.Errorf("failed to process MP_REACH_NLRI with error: %+v", err)
-		}
-		p.processMPUpdate(nlri, AddPrefix, msg.PeerHeader, routeMonitorMsg.Update)
-	case 15:
-		// MP_UNREACH_NLRI
-		nlri, err := bgp.UnmarshalMPUnReachNLRI(routeMonitorMsg.Update.PathAttributes[0].Attribute)
-		if err != nil {
-			glog.Errorf("failed to process MP_UNREACH_NLRI with error: %+v", err)
-		}
-		p.processMPUpdate(nlri, DelPrefix, msg.PeerHeader, routeMonitorMsg.Update)
-	default:
-		t := bmp.UnicastPrefixMsg
-		if p.splitAF {
-			t = bmp.UnicastPrefixV4Msg
+	if len(update.Errors) != 0 {
+		p.produceUpdateError(msg.PeerHeader, update.ErrorAction(), update.Errors)
+		if update.ErrorAction() == bgp.SessionReset {
+			// Routes of the update cannot be located
+			return
 		}
-		// Original BGP's NLRI messages processing
-		msgs := make([]UnicastPrefix, 0)
-		if routeMonitorMsg.Update.WithdrawnRoutesLength != 0 {
-			msg, err := p.nlri(DelPrefix, msg.PeerHeader, routeMonitorMsg.Update)
+	}
+	// Routes advertised by the update with treat-as-withdraw error are reported as withdrawn
+	reach := AddPrefix
+	if update.IsTreatAsWithdraw() {
+		reach = DelPrefix
+	}
+	for _, attr := range update.PathAttributes {
+		switch attr.AttributeType {
+		case 14:
+			nlri, err := update.UnmarshalMPReachNLRI(attr.Attribute)
 			if err != nil {
-				glog.Errorf("failed to produce original NLRI Withdraw message with error: %+v", err)
-				return
+				glog.Errorf("failed to process MP_REACH_NLRI with error: %+v", err)
+				p.produceUpdateError(msg.PeerHeader, bgp.AFISAFIDisable, []*bgp.DecodeError{
+					{AttributeType: attr.AttributeType, Offset: attr.Offset, Reason: err.Error(), Action: bgp.AFISAFIDisable},
+				})
+				continue
 			}
-			msgs = append(msgs, msg...)
+			p.processMPUpdate(nlri, reach, msg.PeerHeader, update, routeMonitorMsg)
+		case 15:
+			nlri, err := bgp.UnmarshalMPUnReachNLRI(attr.Attribute)
+			if err != nil {
+				glog.Errorf("failed to process MP_UNREACH_NLRI with error: %+v", err)
+				p.produceUpdateError(msg.PeerHeader, bgp.AFISAFIDisable, []*bgp.DecodeError{
+					{AttributeType: attr.AttributeType, Offset: attr.Offset, Reason: err.Error(), Action: bgp.AFISAFIDisable},
+				})
+				continue
+			}
+			p.processMPUpdate(nlri, DelPrefix, msg.PeerHeader, update, routeMonitorMsg)
 		}
-		msg, err := p.nlri(AddPrefix, msg.PeerHeader, routeMonitorMsg.Update)
+	}
+	t := bmp.UnicastPrefixMsg
+	if p.splitAF {
+		t = bmp.UnicastPrefixV4Msg
+	}
+	// Original BGP's NLRI messages processing
+	msgs := make([]UnicastPrefix, 0)
+	if len(update.WithdrawnRoutes) != 0 {
+		m, err := p.nlri(DelPrefix, msg.PeerHeader, update, update.WithdrawnRoutes)
 		if err != nil {
 			glog.Errorf("failed to produce original NLRI Withdraw message with error: %+v", err)
 			return
 		}
-		msgs = append(msgs, msg...)
-		// Loop through and publish all collected messages
-		for _, m := range msgs {
-			if err := p.marshalAndPublish(&m, t, []byte(m.RouterHash), false); err != nil {
-				glog.Errorf("failed to process Unicast Prefix message with error: %+v", err)
-				return
-			}
+		msgs = append(msgs, m...)
+	}
+	if len(update.NLRI) != 0 {
+		m, err := p.nlri(reach, msg.PeerHeader, update, update.NLRI)
+		if err != nil {
+			glog.Errorf("failed to produce original NLRI Update message with error: %+v", err)
+			return
+		}
+		for i := range m {
+			m[i].PathStatus, m[i].PathStatusReason = pathStatus(routeMonitorMsg, reach, i)
 		}
+		msgs = append(msgs, m...)
+	}
+	// Loop through and publish all collected messages
+	for _, m := range msgs {
+		if err := p.marshalAndPublish(&m, t, []byte(m.RouterHash), false); err != nil {
+			glog.Errorf("failed to process Unicast Prefix message with error: %+v", err)
+			return
+		}
+	}
+}
+
+// pathStatus returns names of path status and its reason the router reported for NLRI with index,
+// only advertised paths are marked.
+func pathStatus(rm *bmp.RouteMonitor, op int, index int) ([]string, string) {
+	if op != AddPrefix {
+		return nil, ""
+	}
+	ps := rm.PathStatus(index)
+	if ps == nil {
+		return nil, ""
+	}
+
+	return ps.StatusStrings(), ps.ReasonString()
+}
+
+// mpAttributeType returns the type code of the path attribute carrying nlri
+func mpAttributeType(nlri bgp.MPNLRI) uint8 {
+	if _, ok := nlri.(*bgp.MPUnReachNLRI); ok {
+		return 15
+	}
+
+	return 14
+}
+
+// produceUpdateError publishes errors found in BGP Update of the peer, action is the most severe
+// error handling action applied to the update.
+func (p *producer) produceUpdateError(ph *bmp.PerPeerHeader, action bgp.ErrorAction, errs []*bgp.DecodeError) {
+	m := UpdateError{
+		Action:     action.String(),
+		RouterHash: p.speakerHash,
+		RouterIP:   p.speakerIP,
+		PeerHash:   ph.GetPeerHash(),
+		PeerIP:     ph.GetPeerAddrString(),
+		PeerASN:    ph.PeerAS,
+		Timestamp:  ph.GetPeerTimestamp(),
+		Errors:     make([]*DecodeError, 0, len(errs)),
+	}
+	for _, err := range errs {
+		glog.Warningf("peer %s sent malformed update: %+v", m.PeerIP, err)
+		m.Errors = append(m.Errors, &DecodeError{
+			AttributeType: err.AttributeType,
+			Offset:        err.Offset,
+			Reason:        err.Reason,
+			Action:        err.Action.String(),
+		})
+	}
+	if err := p.marshalAndPublish(&m, bmp.UpdateErrorMsg, []byte(m.RouterHash), false); err != nil {
+		glog.Errorf("failed to process Update Error message with error: %+v", err)
 	}
 }
 
@@ -0,0 +1,64 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestPathStatus(t *testing.T) {
+	// ORIGIN, AS_PATH and NEXT_HOP 10.0.0.1
+	attrs := []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00, 0x40, 0x03, 0x04, 0x0a, 0x00, 0x00, 0x01}
+	nlri := []byte{0x18, 0x0a, 0x00, 0x00, 0x18, 0x0a, 0x00, 0x01, 0x18, 0x0a, 0x00, 0x02}
+	tlvs := []*bmp.RouteMonitorTLV{
+		// First prefix is best
+		{Type: bmp.PathMarkingTLV, Index: 0, Value: []byte{0x00, 0x00, 0x00, 0x02}},
+		// Group 1 lists second and third prefixes
+		{Type: bmp.GroupTLV, Group: true, Index: 1, Value: []byte{0x00, 0x01, 0x00, 0x02}},
+		// Paths of group 1 are invalid for policy deny
+		{Type: bmp.PathMarkingTLV, Group: true, Index: 1, Value: []byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x04}},
+	}
+	tests := []struct {
+		name   string
+		attrs  []byte
+		nlri   []byte
+		expect []string
+	}{
+		{
+			name:  "marked prefixes of nlri field",
+			attrs: attrs,
+			nlri:  nlri,
+			expect: []string{
+				`{"prefix": "10.0.0.0", "path_status": ["best"]}`,
+				`{"prefix": "10.0.1.0", "path_status": ["invalid"], "path_status_reason": "invalid for policy deny"}`,
+				`{"prefix": "10.0.2.0", "path_status": ["invalid"], "path_status_reason": "invalid for policy deny"}`,
+			},
+		},
+		{
+			name: "marked prefix of mp_reach_nlri",
+			attrs: []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+				0x80, 0x0e, 0x1a, 0x00, 0x02, 0x01, 0x10, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x20, 0x20, 0x01, 0x0d, 0xb8},
+			expect: []string{
+				`{"prefix": "2001:db8::", "path_status": ["best"]}`,
+			},
+		},
+		{
+			name:  "withdrawn prefix is not marked",
+			attrs: []byte{0x80, 0x0f, 0x08, 0x00, 0x02, 0x01, 0x20, 0x20, 0x01, 0x0d, 0xb8},
+			expect: []string{
+				`{"action": "del", "prefix": "2001:db8::", "path_status": null, "path_status_reason": null}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := produceUpdateNLRI(t, false, tt.attrs, tt.nlri, tlvs...)
+			if len(msgs) != len(tt.expect) {
+				t.Fatalf("expected %d messages but got %d", len(tt.expect), len(msgs))
+			}
+			for i, m := range msgs {
+				checkFields(t, m.msg, tt.expect[i])
+			}
+		})
+	}
+}
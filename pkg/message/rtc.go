@@ -0,0 +1,79 @@
+package message
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/rtc"
+)
+
+// rtc process MP_REACH_NLRI AFI 1 SAFI 132 update message and returns
+// Route Target Constraint prefix object.
+func (p *producer) rtc(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *bgp.Update) ([]RTCPrefix, error) {
+	if glog.V(6) {
+		glog.Infof("All attributes in rtc update: %+v", update.GetAllAttributeID())
+	}
+	var operation string
+	switch op {
+	case 0:
+		operation = "add"
+	case 1:
+		operation = "del"
+	default:
+		return nil, fmt.Errorf("unknown operation %d", op)
+	}
+	route, err := nlri.GetNLRIRTC()
+	if err != nil {
+		return nil, err
+	}
+	prfx := RTCPrefix{
+		Action:         operation,
+		RouterHash:     p.speakerHash,
+		RouterIP:       p.speakerIP,
+		PeerHash:       ph.GetPeerHash(),
+		PeerASN:        ph.PeerAS,
+		Timestamp:      ph.GetPeerTimestamp(),
+		Nexthop:        nlri.GetNextHop(),
+		BaseAttributes: update.BaseAttributes,
+		IsNexthopIPv4:  isNextHopIPv4(nlri),
+	}
+	prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
+	if ph.FlagV {
+		prfx.PeerIP = net.IP(ph.PeerAddress).To16().String()
+	} else {
+		prfx.PeerIP = net.IP(ph.PeerAddress[12:]).To4().String()
+	}
+	prfxs := make([]RTCPrefix, 0)
+	for _, e := range route.NLRI {
+		m := prfx
+		m.PrefixLen = int32(e.Length)
+		m.IsDefault = e.IsDefault()
+		m.RTOriginAS = e.OriginAS
+		if m.RouteTarget, err = routeTarget(e); err != nil {
+			return nil, err
+		}
+		prfxs = append(prfxs, m)
+	}
+
+	return prfxs, nil
+}
+
+// routeTarget returns a string representation of Route Target carried in RTC NLRI
+func routeTarget(n *rtc.NLRI) (string, error) {
+	if n.IsDefault() || n.RouteTargetLength() == 0 {
+		return "", nil
+	}
+	exts, err := bgp.UnmarshalBGPExtCommunity(n.RouteTarget)
+	if err != nil {
+		return "", err
+	}
+	rt := exts[0].String()
+	if l := n.RouteTargetLength(); l < 64 {
+		rt += fmt.Sprintf("/%d", l)
+	}
+
+	return rt, nil
+}
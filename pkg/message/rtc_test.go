@@ -0,0 +1,50 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestRTC(t *testing.T) {
+	tests := []struct {
+		name   string
+		attrs  []byte
+		expect []string
+	}{
+		{
+			name: "route target and default route target",
+			attrs: []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+				0x80, 0x0e, 0x17, 0x00, 0x01, 0x84, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00,
+				0x60, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64,
+				0x00},
+			expect: []string{
+				`{"action": "add", "nexthop": "10.0.0.1", "is_nexthop_ipv4": true, "prefix_len": 96, "is_default": false,
+					"rt_origin_as": 65000, "route_target": "rt=65000:100"}`,
+				`{"action": "add", "prefix_len": 0, "is_default": true, "route_target": null}`,
+			},
+		},
+		{
+			name: "partial route target withdraw",
+			attrs: []byte{0x80, 0x0f, 0x0e, 0x00, 0x01, 0x84,
+				0x50, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00},
+			expect: []string{
+				`{"action": "del", "is_nexthop_ipv4": true, "prefix_len": 80, "rt_origin_as": 65000, "route_target": "rt=65000:0/48"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := produceUpdate(t, false, tt.attrs)
+			if len(msgs) != len(tt.expect) {
+				t.Fatalf("expected %d messages but got %d", len(tt.expect), len(msgs))
+			}
+			for i, m := range msgs {
+				if m.msgType != bmp.RTCMsg {
+					t.Errorf("expected message type %d but got %d", bmp.RTCMsg, m.msgType)
+				}
+				checkFields(t, m.msg, tt.expect[i])
+			}
+		})
+	}
+}
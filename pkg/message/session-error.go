@@ -0,0 +1,31 @@
+package message
+
+import (
+	"crypto/md5"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// produceSessionError publishes the error which caused BMP session reset, when Peer Up message has not
+// been received yet, the router is identified by the address of BMP session.
+func (p *producer) produceSessionError(se *bmp.SessionError) {
+	m := SessionError{
+		Action:     bgp.SessionReset.String(),
+		RouterHash: p.speakerHash,
+		RouterIP:   p.speakerIP,
+		Timestamp:  time.Now().UTC().Format(time.StampMicro),
+		Reason:     se.Reason,
+	}
+	if m.RouterIP == "" {
+		m.RouterIP = se.RouterIP
+		m.RouterHash = fmt.Sprintf("%x", md5.Sum([]byte(se.RouterIP)))
+	}
+	glog.Warningf("session with router %s is reset: %s", m.RouterIP, m.Reason)
+	if err := p.marshalAndPublish(&m, bmp.SessionErrorMsg, []byte(m.RouterHash), false); err != nil {
+		glog.Errorf("failed to process Session Error message with error: %+v", err)
+	}
+}
@@ -9,8 +9,8 @@ import (
 	"github.com/sbezverk/gobmp/pkg/srpolicy"
 )
 
-// evpn process MP_REACH_NLRI AFI 25 SAFI 70 update message and returns
-// EVPN prefix object.
+// srpolicy process MP_REACH_NLRI AFI 1/2 SAFI 73 update message and returns
+// SR Policy object.
 func (p *producer) srpolicy(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *bgp.Update) ([]*SRPolicy, error) {
 	sr, err := nlri.GetNLRI73()
 	if err != nil {
@@ -35,10 +35,7 @@ func (p *producer) srpolicy(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, upda
 		Nexthop:        nlri.GetNextHop(),
 		BaseAttributes: update.BaseAttributes,
 	}
-	if ases := update.BaseAttributes.ASPath; len(ases) != 0 {
-		// Last element in AS_PATH would be the AS of the origin
-		prfx.OriginAS = int32(ases[len(ases)-1])
-	}
+	prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
 	if ph.FlagV {
 		// IPv6 specific conversions
 		prfx.PeerIP = net.IP(ph.PeerAddress).To16().String()
@@ -56,6 +53,8 @@ func (p *producer) srpolicy(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, upda
 	prfx.Color = sr.Color
 	prfx.Endpoint = make([]byte, len(sr.Endpoint))
 	copy(prfx.Endpoint, sr.Endpoint)
+	prfx.EndpointIP = net.IP(sr.Endpoint).String()
+	prfx.PolicyKey = fmt.Sprintf("%d_%s_%d", sr.Color, prfx.EndpointIP, sr.Distinguisher)
 	// Getting SR Policy TLV encapsulated into Tunnel Encapsulate Attribute of type 15
 	tlv, err := srpolicy.UnmarshalSRPolicyTLV(update.BaseAttributes.TunnelEncapAttr)
 	if err != nil {
@@ -77,6 +76,7 @@ func (p *producer) srpolicy(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, upda
 		if len(tlv.SegmentList) != 0 {
 			prfx.SegmentList = tlv.SegmentList
 		}
+		prfx.CandidatePath = tlv.GetCandidatePath()
 	}
 
 	return []*SRPolicy{&prfx}, nil
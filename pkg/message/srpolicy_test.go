@@ -0,0 +1,58 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestSRPolicy(t *testing.T) {
+	// Distinguisher 1, Color 100 and Endpoint 10.0.0.2
+	nlri := []byte{0x60, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x64, 0x0a, 0x00, 0x00, 0x02}
+	tests := []struct {
+		name   string
+		attrs  []byte
+		expect string
+	}{
+		{
+			name: "srv6 sr policy",
+			attrs: append([]byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+				// Tunnel Encapsulation attribute with SR Policy tunnel
+				0xc0, 0x17, 0x47, 0x00, 0x0f, 0x00, 0x43,
+				// SRv6 Binding SID 2001:db8::100 with S-Flag, Endpoint Behavior 71 and SID Structure
+				0x14, 0x1a, 0x80, 0x00, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x00,
+				0x00, 0x47, 0x00, 0x00, 0x20, 0x10, 0x10, 0x00,
+				// Preference 200
+				0x0c, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0xc8,
+				// Policy Name "red"
+				0x82, 0x00, 0x04, 0x00, 'r', 'e', 'd',
+				// Segment List with Type B segment 2001:db8::1
+				0x80, 0x00, 0x15, 0x00, 0x0d, 0x12, 0x00, 0x00, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01,
+				0x80, 0x0e, 0x16, 0x00, 0x01, 0x49, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00}, nlri...),
+			expect: `{"action": "add", "nexthop": "10.0.0.1", "distinguisher": 1, "color": 100, "endpoint_ip": "10.0.0.2",
+				"policy_key": "100_10.0.0.2_1", "policy_name": "red",
+				"candidate_path": {"preference": 200, "bsid": "2001:db8::100", "specified_bsid_only": true, "policy_name": "red",
+					"endpoint_behavior": 71,
+					"sid_structure": {"locator_block_length": 32, "locator_node_length": 16, "function_length": 16, "argument_length": 0},
+					"segment_lists": [{"weight": 1, "segments": [{"segment_type": 13, "type_name": "B", "sid": "2001:db8::1",
+						"flags": {"v_flag": false, "a_flag": false, "s_flag": false, "b_flag": false}}]}]}}`,
+		},
+		{
+			name:   "sr policy withdraw",
+			attrs:  append([]byte{0x80, 0x0f, 0x10, 0x00, 0x01, 0x49}, nlri...),
+			expect: `{"action": "del", "policy_key": "100_10.0.0.2_1", "candidate_path": null}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := produceUpdate(t, false, tt.attrs)
+			if len(msgs) != 1 {
+				t.Fatalf("expected 1 message but got %d", len(msgs))
+			}
+			if msgs[0].msgType != bmp.SRPolicyMsg {
+				t.Errorf("expected message type %d but got %d", bmp.SRPolicyMsg, msgs[0].msgType)
+			}
+			checkFields(t, msgs[0].msg, tt.expect)
+		})
+	}
+}
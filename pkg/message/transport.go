@@ -0,0 +1,102 @@
+package message
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// transport process MP_REACH_NLRI AFI 1/2 SAFI 4 and SAFI 76 update message and returns
+// Transport prefix object, ct is set to true for Classful Transport SAFI 76 routes.
+func (p *producer) transport(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *bgp.Update, ct bool) ([]TransportPrefix, error) {
+	if glog.V(6) {
+		glog.Infof("All attributes in transport update: %+v", update.GetAllAttributeID())
+	}
+	var operation string
+	switch op {
+	case 0:
+		operation = "add"
+	case 1:
+		operation = "del"
+	default:
+		return nil, fmt.Errorf("unknown operation %d", op)
+	}
+	var u *base.MPNLRI
+	var err error
+	if ct {
+		u, err = nlri.GetNLRIClassfulTransport()
+	} else {
+		u, err = nlri.GetNLRILU()
+	}
+	if err != nil {
+		return nil, err
+	}
+	prfx := TransportPrefix{
+		Action:                 operation,
+		RouterHash:             p.speakerHash,
+		RouterIP:               p.speakerIP,
+		PeerHash:               ph.GetPeerHash(),
+		PeerASN:                ph.PeerAS,
+		Timestamp:              ph.GetPeerTimestamp(),
+		Nexthop:                nlri.GetNextHop(),
+		NexthopLinkLocal:       nlri.GetNextHopLinkLocal(),
+		BaseAttributes:         update.BaseAttributes,
+		IsIPv4:                 !nlri.IsIPv6NLRI(),
+		IsNexthopIPv4:          isNextHopIPv4(nlri),
+		IsExtendedNexthop:      isExtendedNextHop(nlri),
+		IsClassfulTransport:    ct,
+		EntropyLabelCapability: update.HasEntropyLabelCapability(),
+		TransportClass:         getTransportClass(update),
+	}
+	prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
+	if ph.FlagV {
+		prfx.PeerIP = net.IP(ph.PeerAddress).To16().String()
+	} else {
+		prfx.PeerIP = net.IP(ph.PeerAddress[12:]).To4().String()
+	}
+	if psid, err := update.GetAttrPrefixSID(); err == nil {
+		prfx.PrefixSID = psid
+	}
+	prfxs := make([]TransportPrefix, 0)
+	for _, e := range u.NLRI {
+		m := prfx
+		m.PrefixLen = int32(e.Length)
+		m.PathID = int32(e.PathID)
+		a := make([]byte, 4)
+		if nlri.IsIPv6NLRI() {
+			a = make([]byte, 16)
+		}
+		copy(a, e.Prefix)
+		m.Prefix = net.IP(a).String()
+		for _, l := range e.Label {
+			m.Labels = append(m.Labels, l.Value)
+		}
+		if e.RD != nil {
+			m.VPNRD = e.RD.String()
+			m.VPNRDType = e.RD.Type
+		}
+		prfxs = append(prfxs, m)
+	}
+
+	return prfxs, nil
+}
+
+// getTransportClass returns Transport Class IDs carried in Transport Class extended communities of the update
+func getTransportClass(update *bgp.Update) []uint32 {
+	exts, err := update.GetExtCommunity()
+	if err != nil {
+		return nil
+	}
+	var tcs []uint32
+	for _, ext := range exts {
+		if id, ok := ext.GetTransportClassID(); ok {
+			tcs = append(tcs, id)
+		}
+	}
+
+	return tcs
+}
@@ -0,0 +1,66 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestTransport(t *testing.T) {
+	origin := []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00}
+	lu := append(append([]byte{}, origin...),
+		0x80, 0x0e, 0x10, 0x00, 0x01, 0x04, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00,
+		0x30, 0x00, 0x06, 0x41, 0x0a, 0x00, 0x00)
+	ct := []byte{0x78, 0x00, 0x06, 0x41, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x0a, 0x00, 0x00, 0x01}
+	tests := []struct {
+		name    string
+		splitLU bool
+		attrs   []byte
+		msgType int
+		expect  string
+	}{
+		{
+			name:    "labeled unicast",
+			attrs:   lu,
+			msgType: bmp.UnicastPrefixMsg,
+			expect:  `{"action": "add", "prefix": "10.0.0.0", "labels": [100]}`,
+		},
+		{
+			name:    "labeled unicast split to transport",
+			splitLU: true,
+			attrs:   lu,
+			msgType: bmp.TransportMsg,
+			expect: `{"action": "add", "is_ipv4": true, "nexthop": "10.0.0.1", "is_nexthop_ipv4": true, "prefix": "10.0.0.0",
+				"labels": [100], "is_classful_transport": false, "entropy_label_capability": false}`,
+		},
+		{
+			name: "classful transport with transport class and entropy label capability",
+			attrs: append(append(append([]byte{}, origin...),
+				// Transport Class 100 extended community and Entropy Label Capability
+				0xc0, 0x10, 0x08, 0x0a, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x64,
+				0xc0, 0x1c, 0x00,
+				0x80, 0x0e, 0x19, 0x00, 0x01, 0x4c, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00), ct...),
+			msgType: bmp.TransportMsg,
+			expect: `{"action": "add", "prefix": "10.0.0.1", "labels": [100], "vpn_rd": "65000:100", "vpn_rd_type": 0,
+				"is_classful_transport": true, "transport_class": [100], "entropy_label_capability": true}`,
+		},
+		{
+			name:    "classful transport withdraw",
+			attrs:   append([]byte{0x80, 0x0f, 0x13, 0x00, 0x01, 0x4c}, ct...),
+			msgType: bmp.TransportMsg,
+			expect:  `{"action": "del", "is_nexthop_ipv4": true, "prefix": "10.0.0.1", "vpn_rd": "65000:100", "is_classful_transport": true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := runUpdate(t, &producer{splitLU: tt.splitLU}, tt.attrs, nil)
+			if len(msgs) != 1 {
+				t.Fatalf("expected 1 message but got %d", len(msgs))
+			}
+			if msgs[0].msgType != tt.msgType {
+				t.Errorf("expected message type %d but got %d", tt.msgType, msgs[0].msgType)
+			}
+			checkFields(t, msgs[0].msg, tt.expect)
+		})
+	}
+}
@@ -4,49 +4,66 @@ import (
 	"github.com/sbezverk/gobmp/pkg/base"
 	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/bgpls"
+	"github.com/sbezverk/gobmp/pkg/evpn"
 	"github.com/sbezverk/gobmp/pkg/flowspec"
 	"github.com/sbezverk/gobmp/pkg/prefixsid"
 	"github.com/sbezverk/gobmp/pkg/sr"
 	"github.com/sbezverk/gobmp/pkg/srpolicy"
 	"github.com/sbezverk/gobmp/pkg/srv6"
+	"github.com/sbezverk/gobmp/pkg/te"
 )
 
 // PeerStateChange defines a message format sent to as a result of BMP Peer Up or Peer Down message
 type PeerStateChange struct {
-	Key              string         `json:"_key,omitempty"`
-	ID               string         `json:"_id,omitempty"`
-	Rev              string         `json:"_rev,omitempty"`
-	Action           string         `json:"action,omitempty"` // Action can be "add" for peer up and "del" for peer down message
-	Sequence         int            `json:"sequence,omitempty"`
-	Hash             string         `json:"hash,omitempty"`
-	RouterHash       string         `json:"router_hash,omitempty"`
-	Name             string         `json:"name,omitempty"`
-	RemoteBGPID      string         `json:"remote_bgp_id,omitempty"`
-	RouterIP         string         `json:"router_ip,omitempty"`
-	Timestamp        string         `json:"timestamp,omitempty"`
-	RemoteASN        int32          `json:"remote_asn,omitempty"`
-	RemoteIP         string         `json:"remote_ip,omitempty"`
-	PeerRD           string         `json:"peer_rd,omitempty"`
-	RemotePort       int            `json:"remote_port,omitempty"`
-	LocalASN         int32          `json:"local_asn,omitempty"`
-	LocalIP          string         `json:"local_ip,omitempty"`
-	LocalPort        int            `json:"local_port,omitempty"`
-	LocalBGPID       string         `json:"local_bgp_id,omitempty"`
-	InfoData         []byte         `json:"info_data,omitempty"`
-	AdvCapabilities  bgp.Capability `json:"adv_cap,omitempty"`
-	RcvCapabilities  bgp.Capability `json:"recv_cap,omitempty"`
-	RemoteHolddown   int            `json:"remote_holddown,omitempty"`
-	AdvHolddown      int            `json:"adv_holddown,omitempty"`
-	BMPReason        int            `json:"bmp_reason,omitempty"`
-	BMPErrorCode     int            `json:"bmp_error_code,omitempty"`
-	BMPErrorSubCode  int            `json:"bmp_error_sub_code,omitempty"`
-	ErrorText        string         `json:"error_text,omitempty"`
-	IsL3VPN          bool           `json:"is_l"`
-	IsPrepolicy      bool           `json:"is_prepolicy"`
-	IsIPv4           bool           `json:"is_ipv4"`
-	IsLocRIB         bool           `json:"is_locrib"`
-	IsLocRIBFiltered bool           `json:"is_locrib_filtered"`
-	TableName        string         `json:"table_name,omitempty"`
+	Key                    string                      `json:"_key,omitempty"`
+	ID                     string                      `json:"_id,omitempty"`
+	Rev                    string                      `json:"_rev,omitempty"`
+	Action                 string                      `json:"action,omitempty"` // Action can be "add" for peer up and "del" for peer down message
+	Sequence               int                         `json:"sequence,omitempty"`
+	Hash                   string                      `json:"hash,omitempty"`
+	RouterHash             string                      `json:"router_hash,omitempty"`
+	Name                   string                      `json:"name,omitempty"`
+	RemoteBGPID            string                      `json:"remote_bgp_id,omitempty"`
+	RouterIP               string                      `json:"router_ip,omitempty"`
+	Timestamp              string                      `json:"timestamp,omitempty"`
+	RemoteASN              int32                       `json:"remote_asn,omitempty"`
+	RemoteIP               string                      `json:"remote_ip,omitempty"`
+	PeerRD                 string                      `json:"peer_rd,omitempty"`
+	RemotePort             int                         `json:"remote_port,omitempty"`
+	LocalASN               int32                       `json:"local_asn,omitempty"`
+	LocalIP                string                      `json:"local_ip,omitempty"`
+	LocalPort              int                         `json:"local_port,omitempty"`
+	LocalBGPID             string                      `json:"local_bgp_id,omitempty"`
+	InfoData               []byte                      `json:"info_data,omitempty"`
+	Info                   []string                    `json:"info,omitempty"`
+	AdvCapabilities        bgp.Capability              `json:"adv_cap,omitempty"`
+	RcvCapabilities        bgp.Capability              `json:"recv_cap,omitempty"`
+	NegotiatedCapabilities *bgp.NegotiatedCapabilities `json:"negotiated_cap,omitempty"`
+	RemoteHolddown         int                         `json:"remote_holddown,omitempty"`
+	AdvHolddown            int                         `json:"adv_holddown,omitempty"`
+	BMPReason              int                         `json:"bmp_reason,omitempty"`
+	BMPReasonText          string                      `json:"bmp_reason_text,omitempty"`
+	BMPErrorCode           int                         `json:"bmp_error_code,omitempty"`
+	BMPErrorCodeText       string                      `json:"bmp_error_code_text,omitempty"`
+	BMPErrorSubCode        int                         `json:"bmp_error_sub_code,omitempty"`
+	BMPErrorSubCodeText    string                      `json:"bmp_error_sub_code_text,omitempty"`
+	ErrorText              string                      `json:"error_text,omitempty"`
+	ShutdownCommunication  string                      `json:"shutdown_communication,omitempty"`
+	FSMEvent               int                         `json:"fsm_event,omitempty"`
+	FSMEventText           string                      `json:"fsm_event_text,omitempty"`
+	IsL3VPN                bool                        `json:"is_l"`
+	IsPrepolicy            bool                        `json:"is_prepolicy"`
+	IsIPv4                 bool                        `json:"is_ipv4"`
+	IsLocRIB               bool                        `json:"is_locrib"`
+	IsLocRIBFiltered       bool                        `json:"is_locrib_filtered"`
+	TableName              string                      `json:"table_name,omitempty"`
+}
+
+// PathMarking defines the status of the path and the reason of the status reported by the router
+// with Path Marking TLV of Route Monitoring message
+type PathMarking struct {
+	PathStatus       []string `json:"path_status,omitempty"`
+	PathStatusReason string   `json:"path_status_reason,omitempty"`
 }
 
 // UnicastPrefix defines a message format sent as a result of BMP Route Monitor message
@@ -76,6 +93,11 @@ type UnicastPrefix struct {
 	IsPrepolicy    bool                `json:"is_prepolicy"`
 	IsAdjRIBIn     bool                `json:"is_adj_rib_in"`
 	PrefixSID      *prefixsid.PSid     `json:"prefix_sid,omitempty"`
+	// NexthopLinkLocal is IPv6 link local next hop, it is sent along with the global next hop or alone
+	NexthopLinkLocal string `json:"nexthop_link_local,omitempty"`
+	// IsExtendedNexthop is set when IPv4 NLRI carries IPv6 next hop with negotiated Extended Next Hop capability
+	IsExtendedNexthop bool `json:"is_extended_nexthop,omitempty"`
+	PathMarking
 }
 
 // LSNode defines a structure of LS Node message
@@ -111,6 +133,7 @@ type LSNode struct {
 	FlexAlgoDefinition  []*bgpls.FlexAlgoDefinition     `json:"flex_algo_definition,omitempty"`
 	IsPrepolicy         bool                            `json:"is_prepolicy"`
 	IsAdjRIBIn          bool                            `json:"is_adj_rib_in"`
+	PathMarking
 }
 
 // LSLink defines a structure of LS link message
@@ -174,6 +197,7 @@ type LSLink struct {
 	UnidirResidualBW      uint32                        `json:"unidir_residual_bw,omitempty"`
 	UnidirAvailableBW     uint32                        `json:"unidir_available_bw,omitempty"`
 	UnidirBWUtilization   uint32                        `json:"unidir_bw_utilization,omitempty"`
+	PathMarking
 }
 
 // L3VPNPrefix defines the structure of Layer 3 VPN message
@@ -205,6 +229,11 @@ type L3VPNPrefix struct {
 	VPNRD          string              `json:"vpn_rd,omitempty"`
 	VPNRDType      uint16              `json:"vpn_rd_type"`
 	PrefixSID      *prefixsid.PSid     `json:"prefix_sid,omitempty"`
+	// NexthopLinkLocal is IPv6 link local next hop, it is sent along with the global next hop or alone
+	NexthopLinkLocal string `json:"nexthop_link_local,omitempty"`
+	// IsExtendedNexthop is set when IPv4 NLRI carries IPv6 next hop with negotiated Extended Next Hop capability
+	IsExtendedNexthop bool `json:"is_extended_nexthop,omitempty"`
+	PathMarking
 }
 
 // LSPrefix defines a structure of LS Prefix message
@@ -246,6 +275,7 @@ type LSPrefix struct {
 	PrefixAttrFlags      uint8                         `json:"prefix_attr_flags"`
 	FlexAlgoPrefixMetric []*bgpls.FlexAlgoPrefixMetric `json:"flex_algo_prefix_metric,omitempty"`
 	SRv6Locator          []*srv6.LocatorTLV            `json:"srv6_locator,omitempty"`
+	PathMarking
 }
 
 // LSSRv6SID defines a structure of LS SRv6 SID message
@@ -286,6 +316,45 @@ type LSSRv6SID struct {
 	SRv6EndpointBehavior *srv6.EndpointBehavior        `json:"srv6_endpoint_behavior,omitempty"`
 	SRv6BGPPeerNodeSID   *srv6.BGPPeerNodeSID          `json:"srv6_bgp_peer_node_sid,omitempty"`
 	SRv6SIDStructure     *srv6.SIDStructure            `json:"srv6_sid_structure,omitempty"`
+	PathMarking
+}
+
+// LSTEPolicy defines a structure of LS TE Policy message
+type LSTEPolicy struct {
+	Key                      string                            `json:"_key,omitempty"`
+	ID                       string                            `json:"_id,omitempty"`
+	Rev                      string                            `json:"_rev,omitempty"`
+	Action                   string                            `json:"action,omitempty"`
+	Sequence                 int                               `json:"sequence,omitempty"`
+	Hash                     string                            `json:"hash,omitempty"`
+	RouterHash               string                            `json:"router_hash,omitempty"`
+	RouterIP                 string                            `json:"router_ip,omitempty"`
+	DomainID                 int64                             `json:"domain_id"`
+	PeerHash                 string                            `json:"peer_hash,omitempty"`
+	PeerIP                   string                            `json:"peer_ip,omitempty"`
+	PeerASN                  int32                             `json:"peer_asn,omitempty"`
+	Timestamp                string                            `json:"timestamp,omitempty"`
+	ProtocolID               base.ProtoID                      `json:"protocol_id,omitempty"`
+	Protocol                 string                            `json:"protocol,omitempty"`
+	Nexthop                  string                            `json:"nexthop,omitempty"`
+	HeadEndHash              string                            `json:"headend_node_hash,omitempty"`
+	HeadEndASN               uint32                            `json:"headend_asn,omitempty"`
+	HeadEndLSID              uint32                            `json:"headend_ls_id,omitempty"`
+	HeadEndRouterID          string                            `json:"headend_router_id,omitempty"`
+	TunnelID                 uint16                            `json:"tunnel_id,omitempty"`
+	LSPID                    uint16                            `json:"lsp_id,omitempty"`
+	TunnelHeadEndAddr        string                            `json:"tunnel_headend_addr,omitempty"`
+	TunnelTailEndAddr        string                            `json:"tunnel_tailend_addr,omitempty"`
+	CandidatePath            *te.PolicyCandidatePathDescriptor `json:"candidate_path,omitempty"`
+	LocalMPLSCrossConnect    *te.LocalMPLSCrossConnect         `json:"local_mpls_cross_connect,omitempty"`
+	BindingSID               *bgpls.SRBindingSID               `json:"binding_sid,omitempty"`
+	CandidatePathState       *bgpls.SRCandidatePathState       `json:"candidate_path_state,omitempty"`
+	CandidatePathName        string                            `json:"candidate_path_name,omitempty"`
+	CandidatePathConstraints *bgpls.SRCandidatePathConstraints `json:"candidate_path_constraints,omitempty"`
+	SegmentList              []*bgpls.SRSegmentList            `json:"segment_list,omitempty"`
+	IsPrepolicy              bool                              `json:"is_prepolicy"`
+	IsAdjRIBIn               bool                              `json:"is_adj_rib_in"`
+	PathMarking
 }
 
 // EVPNPrefix defines the structure of EVPN message
@@ -325,6 +394,184 @@ type EVPNPrefix struct {
 	// TODO Type 3 carries nlri 22
 	// https://tools.ietf.org/html/rfc6514
 	// Add to the message
+	// Multicast routes of types 6 to 11, MulticastSource and MulticastGroup are set to "*" for wildcards
+	MulticastSource string `json:"multicast_source,omitempty"`
+	MulticastGroup  string `json:"multicast_group,omitempty"`
+	OriginatorIP    string `json:"originator_ip,omitempty"`
+	IGMPFlags       uint8  `json:"igmp_flags,omitempty"`
+	SequenceNumber  uint32 `json:"sequence_number,omitempty"`
+	MaxResponseTime uint8  `json:"max_response_time,omitempty"`
+	RegionID        string `json:"region_id,omitempty"`
+	// RouteKeyType is the type of the route Leaf A-D route is sent in response to
+	RouteKeyType uint8 `json:"route_key_type,omitempty"`
+	// OpaqueRoute carries undecoded route of unknown type
+	OpaqueRoute []byte `json:"opaque_route,omitempty"`
+	// ESIHex is Ethernet Segment Identifier in the form of colon separated hex bytes
+	ESIHex string `json:"eth_segment_id_hex,omitempty"`
+	// ESIInfo carries fields of Ethernet Segment Identifier decoded according to its type
+	ESIInfo *evpn.ESIInfo `json:"esi_info,omitempty"`
+	// EVPN Extended Communities carried by the route
+	MACMobility      *evpn.MACMobility      `json:"mac_mobility,omitempty"`
+	ESILabel         *evpn.ESILabel         `json:"esi_label,omitempty"`
+	ESImportRT       string                 `json:"es_import_rt,omitempty"`
+	RouterMAC        string                 `json:"router_mac,omitempty"`
+	DefaultGateway   bool                   `json:"default_gateway,omitempty"`
+	Encapsulation    []string               `json:"encapsulation,omitempty"`
+	Layer2Attributes *evpn.Layer2Attributes `json:"layer2_attributes,omitempty"`
+	DFElection       *evpn.DFElection       `json:"df_election,omitempty"`
+	PathMarking
+}
+
+// VPLSPrefix defines the structure of VPLS message
+type VPLSPrefix struct {
+	Key            string              `json:"_key,omitempty"`
+	ID             string              `json:"_id,omitempty"`
+	Rev            string              `json:"_rev,omitempty"`
+	Action         string              `json:"action,omitempty"` // Action can be "add" or "del"
+	Sequence       int                 `json:"sequence,omitempty"`
+	Hash           string              `json:"hash,omitempty"`
+	RouterHash     string              `json:"router_hash,omitempty"`
+	RouterIP       string              `json:"router_ip,omitempty"`
+	BaseAttributes *bgp.BaseAttributes `json:"base_attrs,omitempty"`
+	PeerHash       string              `json:"peer_hash,omitempty"`
+	PeerIP         string              `json:"peer_ip,omitempty"`
+	PeerASN        int32               `json:"peer_asn,omitempty"`
+	Timestamp      string              `json:"timestamp,omitempty"`
+	IsIPv4         bool                `json:"is_ipv4"`
+	OriginAS       int32               `json:"origin_as,omitempty"`
+	Nexthop        string              `json:"nexthop,omitempty"`
+	IsNexthopIPv4  bool                `json:"is_nexthop_ipv4"`
+	IsPrepolicy    bool                `json:"is_prepolicy"`
+	IsAdjRIBIn     bool                `json:"is_adj_rib_in"`
+	VPNRD          string              `json:"vpn_rd,omitempty"`
+	VPNRDType      uint16              `json:"vpn_rd_type"`
+	// VE ID, VE Block and Label Base are carried in rfc4761 NLRI
+	VEID          uint16 `json:"ve_id,omitempty"`
+	VEBlockOffset uint16 `json:"ve_block_offset,omitempty"`
+	VEBlockSize   uint16 `json:"ve_block_size,omitempty"`
+	LabelBase     uint32 `json:"label_base,omitempty"`
+	// PE Address is carried in rfc6074 BGP-AD NLRI
+	IsBGPAD bool   `json:"is_bgp_ad"`
+	PEAddr  string `json:"pe_addr,omitempty"`
+	// Layer2 Info extended community attributes
+	EncapsulationType     uint8  `json:"encapsulation_type,omitempty"`
+	EncapsulationTypeName string `json:"encapsulation_type_name,omitempty"`
+	ControlWord           bool   `json:"control_word"`
+	SequencedDelivery     bool   `json:"sequenced_delivery"`
+	MTU                   uint16 `json:"mtu,omitempty"`
+	PathMarking
+}
+
+// MVPNPrefix defines the structure of MVPN message
+type MVPNPrefix struct {
+	Key            string              `json:"_key,omitempty"`
+	ID             string              `json:"_id,omitempty"`
+	Rev            string              `json:"_rev,omitempty"`
+	Action         string              `json:"action,omitempty"` // Action can be "add" or "del"
+	Sequence       int                 `json:"sequence,omitempty"`
+	Hash           string              `json:"hash,omitempty"`
+	RouterHash     string              `json:"router_hash,omitempty"`
+	RouterIP       string              `json:"router_ip,omitempty"`
+	BaseAttributes *bgp.BaseAttributes `json:"base_attrs,omitempty"`
+	PeerHash       string              `json:"peer_hash,omitempty"`
+	PeerIP         string              `json:"peer_ip,omitempty"`
+	PeerASN        int32               `json:"peer_asn,omitempty"`
+	Timestamp      string              `json:"timestamp,omitempty"`
+	IsIPv4         bool                `json:"is_ipv4"`
+	OriginAS       int32               `json:"origin_as,omitempty"`
+	Nexthop        string              `json:"nexthop,omitempty"`
+	IsNexthopIPv4  bool                `json:"is_nexthop_ipv4"`
+	IsPrepolicy    bool                `json:"is_prepolicy"`
+	IsAdjRIBIn     bool                `json:"is_adj_rib_in"`
+	VPNRD          string              `json:"vpn_rd,omitempty"`
+	VPNRDType      uint16              `json:"vpn_rd_type"`
+	// RouteType is MCAST-VPN route type of SAFI 5 routes, it is 0 for SAFI 129 VPN multicast routes
+	RouteType     uint8  `json:"route_type,omitempty"`
+	RouteTypeName string `json:"route_type_name,omitempty"`
+	OriginatorIP  string `json:"originator_ip,omitempty"`
+	SourceAS      uint32 `json:"source_as,omitempty"`
+	// MulticastSource and MulticastGroup are set to "*" for wildcards
+	MulticastSource string `json:"multicast_source,omitempty"`
+	MulticastGroup  string `json:"multicast_group,omitempty"`
+	// RouteKeyType is the type of the route Leaf A-D route is sent in response to
+	RouteKeyType uint8 `json:"route_key_type,omitempty"`
+	// Prefix, PrefixLen and Labels are carried by SAFI 129 VPN multicast routes
+	Prefix     string          `json:"prefix,omitempty"`
+	PrefixLen  int32           `json:"prefix_len,omitempty"`
+	Labels     []uint32        `json:"labels,omitempty"`
+	PMSITunnel *bgp.PMSITunnel `json:"pmsi_tunnel,omitempty"`
+	PathMarking
+}
+
+// RTCPrefix defines the structure of Route Target Constraint message
+type RTCPrefix struct {
+	Key            string              `json:"_key,omitempty"`
+	ID             string              `json:"_id,omitempty"`
+	Rev            string              `json:"_rev,omitempty"`
+	Action         string              `json:"action,omitempty"` // Action can be "add" or "del"
+	Sequence       int                 `json:"sequence,omitempty"`
+	Hash           string              `json:"hash,omitempty"`
+	RouterHash     string              `json:"router_hash,omitempty"`
+	RouterIP       string              `json:"router_ip,omitempty"`
+	BaseAttributes *bgp.BaseAttributes `json:"base_attrs,omitempty"`
+	PeerHash       string              `json:"peer_hash,omitempty"`
+	PeerIP         string              `json:"peer_ip,omitempty"`
+	PeerASN        int32               `json:"peer_asn,omitempty"`
+	Timestamp      string              `json:"timestamp,omitempty"`
+	OriginAS       int32               `json:"origin_as,omitempty"`
+	Nexthop        string              `json:"nexthop,omitempty"`
+	IsNexthopIPv4  bool                `json:"is_nexthop_ipv4"`
+	IsPrepolicy    bool                `json:"is_prepolicy"`
+	IsAdjRIBIn     bool                `json:"is_adj_rib_in"`
+	// PrefixLen is the length in bits of RTC NLRI, 0 is the default route matching any Route Target
+	PrefixLen int32 `json:"prefix_len"`
+	IsDefault bool  `json:"is_default"`
+	// RTOriginAS is the Origin AS of RTC NLRI, it is the AS of the speaker interested in the Route Target
+	RTOriginAS uint32 `json:"rt_origin_as,omitempty"`
+	// RouteTarget is rendered as Route Target extended community, when only a part of Route Target
+	// is covered by the prefix, the number of covered bits is appended as "/len"
+	RouteTarget string `json:"route_target,omitempty"`
+	PathMarking
+}
+
+// TransportPrefix defines the structure of Labeled Unicast and Classful Transport message
+type TransportPrefix struct {
+	Key            string              `json:"_key,omitempty"`
+	ID             string              `json:"_id,omitempty"`
+	Rev            string              `json:"_rev,omitempty"`
+	Action         string              `json:"action,omitempty"` // Action can be "add" or "del"
+	Sequence       int                 `json:"sequence,omitempty"`
+	Hash           string              `json:"hash,omitempty"`
+	RouterHash     string              `json:"router_hash,omitempty"`
+	RouterIP       string              `json:"router_ip,omitempty"`
+	BaseAttributes *bgp.BaseAttributes `json:"base_attrs,omitempty"`
+	PeerHash       string              `json:"peer_hash,omitempty"`
+	PeerIP         string              `json:"peer_ip,omitempty"`
+	PeerASN        int32               `json:"peer_asn,omitempty"`
+	Timestamp      string              `json:"timestamp,omitempty"`
+	Prefix         string              `json:"prefix,omitempty"`
+	PrefixLen      int32               `json:"prefix_len,omitempty"`
+	IsIPv4         bool                `json:"is_ipv4"`
+	OriginAS       int32               `json:"origin_as,omitempty"`
+	Nexthop        string              `json:"nexthop,omitempty"`
+	IsNexthopIPv4  bool                `json:"is_nexthop_ipv4"`
+	PathID         int32               `json:"path_id,omitempty"`
+	Labels         []uint32            `json:"labels,omitempty"`
+	IsPrepolicy    bool                `json:"is_prepolicy"`
+	IsAdjRIBIn     bool                `json:"is_adj_rib_in"`
+	PrefixSID      *prefixsid.PSid     `json:"prefix_sid,omitempty"`
+	// IsClassfulTransport is set for SAFI 76 routes, VPNRD and TransportClass are carried by them
+	IsClassfulTransport bool     `json:"is_classful_transport"`
+	VPNRD               string   `json:"vpn_rd,omitempty"`
+	VPNRDType           uint16   `json:"vpn_rd_type"`
+	TransportClass      []uint32 `json:"transport_class,omitempty"`
+	// EntropyLabelCapability is set when the route carries Entropy Label Capability attribute
+	EntropyLabelCapability bool `json:"entropy_label_capability"`
+	// NexthopLinkLocal is IPv6 link local next hop, it is sent along with the global next hop or alone
+	NexthopLinkLocal string `json:"nexthop_link_local,omitempty"`
+	// IsExtendedNexthop is set when IPv4 NLRI carries IPv6 next hop with negotiated Extended Next Hop capability
+	IsExtendedNexthop bool `json:"is_extended_nexthop,omitempty"`
+	PathMarking
 }
 
 // SRPolicy defines the structure of SR Policy message
@@ -361,6 +608,12 @@ type SRPolicy struct {
 	PolicyPathName string                  `json:"policy_path_name,omitempty"`
 	ENLP           *srpolicy.ENLP          `json:"enlp_subtlv,omitempty"`
 	SegmentList    []*srpolicy.SegmentList `json:"segment_list_subtlv,omitempty"`
+	// PolicyKey identifies SR Policy by color, endpoint and distinguisher, it is stable across candidate path updates
+	PolicyKey  string `json:"policy_key,omitempty"`
+	EndpointIP string `json:"endpoint_ip,omitempty"`
+	// CandidatePath carries candidate path attributes with the defaults applied and normalized segment lists
+	CandidatePath *srpolicy.CandidatePath `json:"candidate_path,omitempty"`
+	PathMarking
 }
 
 // Flowspec defines the structure of SR Policy message
@@ -382,4 +635,42 @@ type Flowspec struct {
 	PathID         int32               `json:"path_id,omitempty"`
 	SpecHash       string              `json:"spec_hash,omitempty"`
 	Spec           []flowspec.Spec     `json:"spec,omitempty"`
+	// VPNRD and VPNRDType are set for VPN Flowspec of SAFI 134
+	VPNRD     string `json:"vpn_rd,omitempty"`
+	VPNRDType uint16 `json:"vpn_rd_type"`
+	// Actions are decoded from Flowspec action Extended Communities
+	Actions []*flowspec.Action `json:"actions,omitempty"`
+	// Rule is a text representation of Flowspec match components and actions
+	Rule string `json:"rule,omitempty"`
+	PathMarking
+}
+
+// UpdateError defines a message format sent when BMP Route Monitor message carries a malformed BGP Update,
+// Action is the most severe error handling action applied to the update per rfc7606.
+type UpdateError struct {
+	Action     string         `json:"action,omitempty"`
+	RouterHash string         `json:"router_hash,omitempty"`
+	RouterIP   string         `json:"router_ip,omitempty"`
+	PeerHash   string         `json:"peer_hash,omitempty"`
+	PeerIP     string         `json:"peer_ip,omitempty"`
+	PeerASN    int32          `json:"peer_asn,omitempty"`
+	Timestamp  string         `json:"timestamp,omitempty"`
+	Errors     []*DecodeError `json:"errors,omitempty"`
+}
+
+// SessionError defines a message format sent when the collector resets BMP session with the router
+type SessionError struct {
+	Action     string `json:"action,omitempty"`
+	RouterHash string `json:"router_hash,omitempty"`
+	RouterIP   string `json:"router_ip,omitempty"`
+	Timestamp  string `json:"timestamp,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// DecodeError defines a single error found in BGP Update
+type DecodeError struct {
+	AttributeType uint8  `json:"attribute_type,omitempty"`
+	Offset        int    `json:"offset"`
+	Reason        string `json:"reason,omitempty"`
+	Action        string `json:"action,omitempty"`
 }
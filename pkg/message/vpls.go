@@ -0,0 +1,101 @@
+package message
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/vpls"
+)
+
+// vpls process MP_REACH_NLRI AFI 25 SAFI 65 update message and returns
+// VPLS prefix object.
+func (p *producer) vpls(nlri bgp.MPNLRI, op int, ph *bmp.PerPeerHeader, update *bgp.Update) ([]VPLSPrefix, error) {
+	if glog.V(6) {
+		glog.Infof("All attributes in vpls update: %+v", update.GetAllAttributeID())
+	}
+	route, err := nlri.GetNLRIVPLS()
+	if err != nil {
+		return nil, err
+	}
+	var operation string
+	switch op {
+	case 0:
+		operation = "add"
+	case 1:
+		operation = "del"
+	default:
+		return nil, fmt.Errorf("unknown operation %d", op)
+	}
+	l2info := getLayer2Info(update)
+	prfxs := make([]VPLSPrefix, 0)
+	for _, n := range route.Route {
+		prfx := VPLSPrefix{
+			Action:         operation,
+			RouterHash:     p.speakerHash,
+			RouterIP:       p.speakerIP,
+			PeerHash:       ph.GetPeerHash(),
+			PeerASN:        ph.PeerAS,
+			Timestamp:      ph.GetPeerTimestamp(),
+			Nexthop:        nlri.GetNextHop(),
+			BaseAttributes: update.BaseAttributes,
+		}
+		prfx.OriginAS = int32(update.BaseAttributes.OriginAS)
+		if ph.FlagV {
+			// IPv6 specific conversions
+			prfx.IsIPv4 = false
+			prfx.PeerIP = net.IP(ph.PeerAddress).To16().String()
+		} else {
+			// IPv4 specific conversions
+			prfx.IsIPv4 = true
+			prfx.PeerIP = net.IP(ph.PeerAddress[12:]).To4().String()
+		}
+		prfx.IsNexthopIPv4 = isNextHopIPv4(nlri)
+		if n.RD != nil {
+			prfx.VPNRD = n.GetRD()
+			prfx.VPNRDType = n.RD.Type
+		}
+		if n.IsBGPAD() {
+			prfx.IsBGPAD = true
+			prfx.PEAddr = n.GetPEAddr()
+		} else {
+			prfx.VEID = n.VEID
+			prfx.VEBlockOffset = n.VEBlockOffset
+			prfx.VEBlockSize = n.VEBlockSize
+			prfx.LabelBase = n.LabelBase
+		}
+		if l2info != nil {
+			prfx.EncapsulationType = l2info.EncapsulationType
+			prfx.EncapsulationTypeName = l2info.EncapsulationTypeString()
+			prfx.ControlWord = l2info.ControlWord()
+			prfx.SequencedDelivery = l2info.Sequenced()
+			prfx.MTU = l2info.MTU
+		}
+		prfxs = append(prfxs, prfx)
+	}
+
+	return prfxs, nil
+}
+
+// getLayer2Info returns Layer2 Info extended community (Type 0x80 Sub-Type 0x0a) if present in the update
+func getLayer2Info(update *bgp.Update) *vpls.Layer2Info {
+	exts, err := update.GetExtCommunity()
+	if err != nil {
+		return nil
+	}
+	for _, ext := range exts {
+		if ext.Type != 0x80 || ext.SubType == nil || *ext.SubType != 0x0a {
+			continue
+		}
+		l2info, err := vpls.UnmarshalLayer2Info(ext.Value)
+		if err != nil {
+			glog.Errorf("failed to unmarshal Layer2 Info extended community with error: %+v", err)
+			return nil
+		}
+		return l2info
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+func TestVPLS(t *testing.T) {
+	ve := []byte{0x00, 0x11, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x01, 0x00, 0x01, 0x00, 0x0a, 0x18, 0x6a, 0x01}
+	tests := []struct {
+		name   string
+		attrs  []byte
+		expect string
+	}{
+		{
+			name: "rfc4761 ve nlri with layer2 info",
+			attrs: append([]byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+				0xc0, 0x10, 0x08, 0x80, 0x0a, 0x13, 0x02, 0x05, 0xdc, 0x00, 0x00,
+				0x80, 0x0e, 0x1c, 0x00, 0x19, 0x41, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00}, ve...),
+			expect: `{"action": "add", "nexthop": "10.0.0.1", "is_nexthop_ipv4": true, "vpn_rd": "65000:100", "vpn_rd_type": 0,
+				"ve_id": 1, "ve_block_offset": 1, "ve_block_size": 10, "label_base": 100000, "is_bgp_ad": false,
+				"encapsulation_type": 19, "encapsulation_type_name": "VPLS", "control_word": true, "sequenced_delivery": false, "mtu": 1500}`,
+		},
+		{
+			name: "rfc6074 bgp-ad nlri",
+			attrs: []byte{0x40, 0x01, 0x01, 0x00, 0x40, 0x02, 0x00,
+				0x80, 0x0e, 0x17, 0x00, 0x19, 0x41, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00,
+				0x00, 0x0c, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x64, 0x0a, 0x00, 0x00, 0x01},
+			expect: `{"action": "add", "vpn_rd": "10.0.0.1:100", "vpn_rd_type": 1, "is_bgp_ad": true, "pe_addr": "10.0.0.1"}`,
+		},
+		{
+			name:   "rfc4761 ve nlri withdraw",
+			attrs:  append([]byte{0x80, 0x0f, 0x16, 0x00, 0x19, 0x41}, ve...),
+			expect: `{"action": "del", "is_nexthop_ipv4": true, "vpn_rd": "65000:100", "ve_id": 1, "label_base": 100000}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := produceUpdate(t, false, tt.attrs)
+			if len(msgs) != 1 {
+				t.Fatalf("expected 1 message but got %d", len(msgs))
+			}
+			if msgs[0].msgType != bmp.VPLSMsg {
+				t.Errorf("expected message type %d but got %d", bmp.VPLSMsg, msgs[0].msgType)
+			}
+			checkFields(t, msgs[0].msg, tt.expect)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package mrt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/recorder"
+)
+
+const (
+	// fileTimeFormat defines the format of the timestamp in names of MRT files
+	fileTimeFormat = "20060102.150405"
+	// UpdatesFilePrefix defines the prefix of files with BGP4MP records
+	UpdatesFilePrefix = "updates."
+	// RIBFilePrefix defines the prefix of files with TABLE_DUMP_V2 records
+	RIBFilePrefix = "rib."
+	// FileExt defines the extension of MRT files
+	FileExt = ".mrt"
+	// snapshotQueueLength defines how many RIB snapshots may wait for the writer before
+	// the processing of BMP messages blocks
+	snapshotQueueLength = 4
+)
+
+// Config defines parameters of MRT export
+type Config struct {
+	// Dir is the directory where MRT files are stored in a subdirectory per router.
+	Dir string
+	// Interval defines how often RIB snapshots are written and updates files are rotated,
+	// 0 writes a single updates file and a RIB snapshot when the session closes.
+	Interval time.Duration
+	// PostPolicy selects Adj-RIB-In post-policy routes, otherwise pre-policy routes are exported.
+	PostPolicy bool
+}
+
+type exporter struct {
+	config Config
+}
+
+// session writes MRT files of a single BMP session, intervals are driven by the receive timestamps
+// of BMP messages, RIB snapshot of an interval is taken by the first message of the next interval.
+// Snapshots are encoded while processing the message and written to disk by the writer goroutine,
+// so a slow disk does not stall reading of the BMP session.
+type session struct {
+	dir       string
+	interval  time.Duration
+	router    *router
+	updates   *os.File
+	next      time.Time
+	last      time.Time
+	snapshots chan *snapshot
+	done      chan struct{}
+	// err is the first error of the writer, it is returned by Close
+	err error
+}
+
+// snapshot defines encoded TABLE_DUMP_V2 records of RIB snapshot and the name of its file
+type snapshot struct {
+	name string
+	data []byte
+}
+
+func (e *exporter) NewSession(router net.Addr) (recorder.Session, error) {
+	name := router.String()
+	if host, _, err := net.SplitHostPort(name); err == nil {
+		name = host
+	}
+	if name == "" {
+		name = "unknown"
+	}
+	dir := filepath.Join(e.config.Dir, strings.NewReplacer(":", "_", "%", "_").Replace(name))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &session{
+		dir:       dir,
+		interval:  e.config.Interval,
+		router:    newRouter(e.config.PostPolicy),
+		snapshots: make(chan *snapshot, snapshotQueueLength),
+		done:      make(chan struct{}),
+	}
+	go s.writer()
+
+	return s, nil
+}
+
+// Record processes BMP message received at t, messages which cannot be processed are logged and skipped.
+func (s *session) Record(t time.Time, msg []byte) error {
+	switch {
+	case s.updates == nil:
+		if err := s.openUpdates(s.period(t)); err != nil {
+			return err
+		}
+	case s.interval > 0 && !t.Before(s.next):
+		period := s.period(t)
+		s.snapshotRIB(period)
+		if err := s.updates.Close(); err != nil {
+			return err
+		}
+		if err := s.openUpdates(period); err != nil {
+			return err
+		}
+	}
+	s.last = t
+	b, err := s.router.process(t, msg)
+	if err != nil {
+		glog.Errorf("fail to process BMP message for MRT export to %s with error: %+v", s.dir, err)
+		return nil
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	if _, err := s.updates.Write(b); err != nil {
+		return fmt.Errorf("fail to write MRT records to %s with error: %+v", s.updates.Name(), err)
+	}
+
+	return nil
+}
+
+// Close takes the final RIB snapshot, closes the updates file and waits for the writer to store
+// all snapshots, the first error of the writer is returned when closing the updates file succeeds.
+func (s *session) Close() error {
+	var err error
+	if s.updates != nil {
+		s.snapshotRIB(s.last)
+		err = s.updates.Close()
+		s.updates = nil
+	}
+	if s.snapshots != nil {
+		close(s.snapshots)
+		<-s.done
+		s.snapshots = nil
+	}
+	if err == nil {
+		err = s.err
+	}
+
+	return err
+}
+
+func (s *session) period(t time.Time) time.Time {
+	if s.interval > 0 {
+		return t.Truncate(s.interval)
+	}
+
+	return t
+}
+
+func (s *session) fileName(prefix string, t time.Time) string {
+	return filepath.Join(s.dir, prefix+t.UTC().Format(fileTimeFormat)+FileExt)
+}
+
+func (s *session) openUpdates(period time.Time) error {
+	f, err := os.OpenFile(s.fileName(UpdatesFilePrefix, period), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	s.updates = f
+	s.next = period.Add(s.interval)
+
+	return nil
+}
+
+// snapshotRIB encodes RIB snapshot at time t and queues it for the writer
+func (s *session) snapshotRIB(t time.Time) {
+	s.snapshots <- &snapshot{
+		name: s.fileName(RIBFilePrefix, t),
+		data: s.router.dump(t),
+	}
+}
+
+// writer stores queued RIB snapshots until the queue is closed
+func (s *session) writer() {
+	defer close(s.done)
+	for sn := range s.snapshots {
+		if err := writeRIB(sn); err != nil {
+			glog.Errorf("%+v", err)
+			if s.err == nil {
+				s.err = err
+			}
+		}
+	}
+}
+
+// writeRIB writes RIB snapshot into a temporary file which is renamed when complete
+func writeRIB(sn *snapshot) error {
+	tmp := sn.name + ".tmp"
+	if err := ioutil.WriteFile(tmp, sn.data, 0644); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("fail to write RIB snapshot %s with error: %+v", sn.name, err)
+	}
+	if err := os.Rename(tmp, sn.name); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("fail to rename RIB snapshot %s with error: %+v", sn.name, err)
+	}
+
+	return nil
+}
+
+// NewExporter returns a new instance of MRT exporter, it plugs into the BMP server as a raw BMP
+// messages recorder and writes updates and RIB snapshots of every router in MRT format.
+func NewExporter(config *Config) (recorder.Recorder, error) {
+	if config == nil || config.Dir == "" {
+		return nil, fmt.Errorf("MRT export directory must be specified")
+	}
+	if config.Interval < 0 {
+		return nil, fmt.Errorf("invalid MRT export interval %s", config.Interval)
+	}
+	if err := os.MkdirAll(config.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &exporter{
+		config: *config,
+	}, nil
+}
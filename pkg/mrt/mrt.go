@@ -0,0 +1,237 @@
+package mrt
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+)
+
+// MRT types and subtypes per rfc6396 and rfc8050
+const (
+	// TableDumpV2 defines TABLE_DUMP_V2 MRT type
+	TableDumpV2 = 13
+	// BGP4MPET defines BGP4MP_ET MRT type carrying microsecond timestamps
+	BGP4MPET = 17
+
+	// PeerIndexTable defines TABLE_DUMP_V2 PEER_INDEX_TABLE subtype
+	PeerIndexTable = 1
+	// RIBIPv4Unicast defines TABLE_DUMP_V2 RIB_IPV4_UNICAST subtype
+	RIBIPv4Unicast = 2
+	// RIBIPv6Unicast defines TABLE_DUMP_V2 RIB_IPV6_UNICAST subtype
+	RIBIPv6Unicast = 4
+	// RIBIPv4UnicastAddPath defines TABLE_DUMP_V2 RIB_IPV4_UNICAST_ADDPATH subtype
+	RIBIPv4UnicastAddPath = 8
+	// RIBIPv6UnicastAddPath defines TABLE_DUMP_V2 RIB_IPV6_UNICAST_ADDPATH subtype
+	RIBIPv6UnicastAddPath = 9
+
+	// BGP4MPMessage defines BGP4MP_MESSAGE subtype with 2 bytes AS numbers
+	BGP4MPMessage = 1
+	// BGP4MPMessageAS4 defines BGP4MP_MESSAGE_AS4 subtype with 4 bytes AS numbers
+	BGP4MPMessageAS4 = 4
+	// BGP4MPStateChangeAS4 defines BGP4MP_STATE_CHANGE_AS4 subtype
+	BGP4MPStateChangeAS4 = 5
+)
+
+// BGP FSM states used in BGP4MP_STATE_CHANGE_AS4 records
+const (
+	StateIdle        = 1
+	StateOpenConfirm = 5
+	StateEstablished = 6
+)
+
+const (
+	headerLength = 12
+	afiIPv4      = 1
+	afiIPv6      = 2
+	asTrans      = 23456
+)
+
+// record builds MRT record with the common header, ET records carry microseconds
+// in the first 4 bytes of the message field.
+func record(ts time.Time, t, subtype uint16, body []byte) []byte {
+	l := len(body)
+	et := t == BGP4MPET
+	if et {
+		l += 4
+	}
+	b := make([]byte, headerLength, headerLength+l)
+	binary.BigEndian.PutUint32(b[0:4], uint32(ts.Unix()))
+	binary.BigEndian.PutUint16(b[4:6], t)
+	binary.BigEndian.PutUint16(b[6:8], subtype)
+	binary.BigEndian.PutUint32(b[8:12], uint32(l))
+	if et {
+		us := make([]byte, 4)
+		binary.BigEndian.PutUint32(us, uint32(ts.Nanosecond()/1000))
+		b = append(b, us...)
+	}
+
+	return append(b, body...)
+}
+
+// addressFamily returns AFI and the address of the corresponding length
+func addressFamily(ip net.IP) (uint16, net.IP) {
+	if a := ip.To4(); a != nil {
+		return afiIPv4, a
+	}
+	if ip == nil {
+		return afiIPv4, net.IPv4zero.To4()
+	}
+
+	return afiIPv6, ip.To16()
+}
+
+// bgp4mpHeader builds common part of BGP4MP_MESSAGE and BGP4MP_STATE_CHANGE records,
+// the local address is converted to the family of the peer address.
+func bgp4mpHeader(peerAS, localAS uint32, as4 bool, peerIP, localIP net.IP) []byte {
+	afi, peer := addressFamily(peerIP)
+	local := make(net.IP, len(peer))
+	if localIP != nil {
+		if afi == afiIPv4 && localIP.To4() != nil {
+			copy(local, localIP.To4())
+		} else if afi == afiIPv6 {
+			copy(local, localIP.To16())
+		}
+	}
+	b := make([]byte, 0, 12+2*len(peer))
+	if as4 {
+		b = appendUint32(b, peerAS)
+		b = appendUint32(b, localAS)
+	} else {
+		b = appendUint16(b, as2(peerAS))
+		b = appendUint16(b, as2(localAS))
+	}
+	// Interface index is not known
+	b = appendUint16(b, 0)
+	b = appendUint16(b, afi)
+	b = append(b, peer...)
+	b = append(b, local...)
+
+	return b
+}
+
+// BGP4MPMessageRecord builds BGP4MP_ET record carrying the BGP message received from the peer,
+// as4 defines the encoding of AS numbers in the record and AS_PATH in the message.
+func BGP4MPMessageRecord(ts time.Time, peerAS, localAS uint32, as4 bool, peerIP, localIP net.IP, msg []byte) []byte {
+	subtype := uint16(BGP4MPMessage)
+	if as4 {
+		subtype = BGP4MPMessageAS4
+	}
+	b := bgp4mpHeader(peerAS, localAS, as4, peerIP, localIP)
+
+	return record(ts, BGP4MPET, subtype, append(b, msg...))
+}
+
+// BGP4MPStateChangeRecord builds BGP4MP_ET record with the change of the peer's FSM state
+func BGP4MPStateChangeRecord(ts time.Time, peerAS, localAS uint32, peerIP, localIP net.IP, oldState, newState uint16) []byte {
+	b := bgp4mpHeader(peerAS, localAS, true, peerIP, localIP)
+	b = appendUint16(b, oldState)
+	b = appendUint16(b, newState)
+
+	return record(ts, BGP4MPET, BGP4MPStateChangeAS4, b)
+}
+
+// PeerEntry defines a peer of PEER_INDEX_TABLE
+type PeerEntry struct {
+	BGPID   net.IP
+	Address net.IP
+	AS      uint32
+}
+
+// PeerIndexTableRecord builds TABLE_DUMP_V2 PEER_INDEX_TABLE record, AS numbers of all peers
+// are encoded as 4 bytes.
+func PeerIndexTableRecord(ts time.Time, collectorID net.IP, viewName string, peers []PeerEntry) []byte {
+	b := make([]byte, 0)
+	b = append(b, bgpID(collectorID)...)
+	b = appendUint16(b, uint16(len(viewName)))
+	b = append(b, viewName...)
+	b = appendUint16(b, uint16(len(peers)))
+	for _, p := range peers {
+		// Bit 1 indicates 4 bytes AS number
+		t := byte(0x02)
+		afi, addr := addressFamily(p.Address)
+		if afi == afiIPv6 {
+			// Bit 0 indicates IPv6 peer address
+			t |= 0x01
+		}
+		b = append(b, t)
+		b = append(b, bgpID(p.BGPID)...)
+		b = append(b, addr...)
+		b = appendUint32(b, p.AS)
+	}
+
+	return record(ts, TableDumpV2, PeerIndexTable, b)
+}
+
+// RIBEntry defines a path to a prefix received from the peer with PeerIndex in PEER_INDEX_TABLE
+type RIBEntry struct {
+	PeerIndex      uint16
+	OriginatedTime time.Time
+	PathID         uint32
+	Attributes     []byte
+}
+
+// RIBRecord builds TABLE_DUMP_V2 RIB_IPV4_UNICAST or RIB_IPV6_UNICAST record, the ADDPATH
+// variant of the record is used when addPath is true.
+func RIBRecord(ts time.Time, afi uint16, seq uint32, length uint8, prefix []byte, addPath bool, entries []RIBEntry) []byte {
+	var subtype uint16
+	switch {
+	case afi == afiIPv6 && addPath:
+		subtype = RIBIPv6UnicastAddPath
+	case afi == afiIPv6:
+		subtype = RIBIPv6Unicast
+	case addPath:
+		subtype = RIBIPv4UnicastAddPath
+	default:
+		subtype = RIBIPv4Unicast
+	}
+	b := make([]byte, 0)
+	b = appendUint32(b, seq)
+	b = append(b, length)
+	pl := (int(length) + 7) / 8
+	p := make([]byte, pl)
+	copy(p, prefix)
+	b = append(b, p...)
+	b = appendUint16(b, uint16(len(entries)))
+	for _, e := range entries {
+		b = appendUint16(b, e.PeerIndex)
+		b = appendUint32(b, uint32(e.OriginatedTime.Unix()))
+		if addPath {
+			b = appendUint32(b, e.PathID)
+		}
+		b = appendUint16(b, uint16(len(e.Attributes)))
+		b = append(b, e.Attributes...)
+	}
+
+	return record(ts, TableDumpV2, subtype, b)
+}
+
+func bgpID(id net.IP) []byte {
+	b := make([]byte, 4)
+	if a := id.To4(); a != nil {
+		copy(b, a)
+	}
+
+	return b
+}
+
+func as2(as uint32) uint16 {
+	if as > 0xffff {
+		return asTrans
+	}
+
+	return uint16(as)
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	e := make([]byte, 2)
+	binary.BigEndian.PutUint16(e, v)
+
+	return append(b, e...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	e := make([]byte, 4)
+	binary.BigEndian.PutUint32(e, v)
+
+	return append(b, e...)
+}
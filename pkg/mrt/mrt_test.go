@@ -0,0 +1,238 @@
+package mrt
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/speaker"
+)
+
+type testRecord struct {
+	ts      uint32
+	t       uint16
+	subtype uint16
+	body    []byte
+}
+
+func readRecords(t *testing.T, name string) []testRecord {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatalf("fail to read %s with error: %+v", name, err)
+	}
+	records := make([]testRecord, 0)
+	for p := 0; p < len(b); {
+		if p+headerLength > len(b) {
+			t.Fatalf("truncated MRT header in %s", name)
+		}
+		l := int(binary.BigEndian.Uint32(b[p+8 : p+12]))
+		if p+headerLength+l > len(b) {
+			t.Fatalf("truncated MRT record in %s", name)
+		}
+		records = append(records, testRecord{
+			ts:      binary.BigEndian.Uint32(b[p : p+4]),
+			t:       binary.BigEndian.Uint16(b[p+4 : p+6]),
+			subtype: binary.BigEndian.Uint16(b[p+6 : p+8]),
+			body:    b[p+headerLength : p+headerLength+l],
+		})
+		p += headerLength + l
+	}
+
+	return records
+}
+
+// ribPrefixes returns prefixes and number of entries of RIB records
+func ribPrefixes(records []testRecord) map[string]int {
+	prefixes := make(map[string]int)
+	for _, r := range records {
+		if r.t != TableDumpV2 || r.subtype != RIBIPv4Unicast {
+			continue
+		}
+		l := int(r.body[4])
+		ip := make(net.IP, 4)
+		copy(ip, r.body[5:5+(l+7)/8])
+		n := binary.BigEndian.Uint16(r.body[5+(l+7)/8:])
+		prefixes[(&net.IPNet{IP: ip, Mask: net.CIDRMask(l, 32)}).String()] = int(n)
+	}
+
+	return prefixes
+}
+
+type testAddr string
+
+func (a testAddr) Network() string { return "tcp" }
+func (a testAddr) String() string  { return string(a) }
+
+func TestExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gobmp-mrt")
+	if err != nil {
+		t.Fatalf("fail to create temp directory with error: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+	e, err := NewExporter(&Config{Dir: dir, Interval: time.Minute})
+	if err != nil {
+		t.Fatalf("fail to create exporter with error: %+v", err)
+	}
+	s, err := e.NewSession(testAddr("192.0.2.1:45000"))
+	if err != nil {
+		t.Fatalf("fail to create session with error: %+v", err)
+	}
+	p := &speaker.Peer{
+		Address:  net.ParseIP("198.51.100.1"),
+		AS:       65001,
+		BGPID:    net.ParseIP("198.51.100.1"),
+		LocalAS:  65000,
+		LocalIP:  net.ParseIP("192.0.2.1"),
+		LocalID:  net.ParseIP("192.0.2.1"),
+		HoldTime: 90,
+	}
+	attrs := &speaker.Attributes{
+		ASPath:  []uint32{65001, 65002},
+		Nexthop: p.Address,
+	}
+	r1 := speaker.Route{Prefix: net.ParseIP("10.0.0.0"), Length: 24}
+	r2 := speaker.Route{Prefix: net.ParseIP("10.0.1.0"), Length: 24}
+	start := time.Date(2020, time.June, 1, 10, 0, 10, 0, time.UTC)
+	msgs := []struct {
+		at  time.Duration
+		msg []byte
+	}{
+		{0, speaker.Initiation("r1", "test router")},
+		{time.Second, p.PeerUp(start)},
+		{2 * time.Second, p.RouteMonitor(start, nil, attrs, []speaker.Route{r1, r2})},
+		// Crossing the interval boundary at 10:01
+		{time.Minute, p.RouteMonitor(start, []speaker.Route{r2}, nil, nil)},
+	}
+	for _, m := range msgs {
+		if err := s.Record(start.Add(m.at), m.msg); err != nil {
+			t.Fatalf("fail to record message with error: %+v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("fail to close session with error: %+v", err)
+	}
+	rd := filepath.Join(dir, "192.0.2.1")
+	// First interval: state change and the update
+	updates := readRecords(t, filepath.Join(rd, "updates.20200601.100000.mrt"))
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 records in the first updates file but got %d", len(updates))
+	}
+	if updates[0].t != BGP4MPET || updates[0].subtype != BGP4MPStateChangeAS4 {
+		t.Errorf("expected state change record but got type %d subtype %d", updates[0].t, updates[0].subtype)
+	}
+	u := updates[1]
+	if u.t != BGP4MPET || u.subtype != BGP4MPMessageAS4 {
+		t.Fatalf("expected BGP4MP_MESSAGE_AS4 record but got type %d subtype %d", u.t, u.subtype)
+	}
+	if u.ts != uint32(start.Add(2*time.Second).Unix()) {
+		t.Errorf("expected timestamp %d but got %d", start.Add(2*time.Second).Unix(), u.ts)
+	}
+	// Microseconds, peer AS, local AS, interface index, AFI, peer and local addresses
+	if as := binary.BigEndian.Uint32(u.body[4:8]); as != 65001 {
+		t.Errorf("expected peer AS 65001 but got %d", as)
+	}
+	if as := binary.BigEndian.Uint32(u.body[8:12]); as != 65000 {
+		t.Errorf("expected local AS 65000 but got %d", as)
+	}
+	if !net.IP(u.body[16:20]).Equal(p.Address) || !net.IP(u.body[20:24]).Equal(p.LocalIP) {
+		t.Errorf("expected peer %s and local %s addresses but got %s and %s", p.Address, p.LocalIP, net.IP(u.body[16:20]), net.IP(u.body[20:24]))
+	}
+	if u.body[24] != 0xff || u.body[24+18] != bgpUpdateType {
+		t.Errorf("expected BGP Update message in the record")
+	}
+	// Snapshot at the interval boundary has both prefixes
+	rib := readRecords(t, filepath.Join(rd, "rib.20200601.100100.mrt"))
+	if rib[0].t != TableDumpV2 || rib[0].subtype != PeerIndexTable {
+		t.Fatalf("expected PEER_INDEX_TABLE record first but got type %d subtype %d", rib[0].t, rib[0].subtype)
+	}
+	pit := rib[0].body
+	if !net.IP(pit[0:4]).Equal(net.ParseIP("192.0.2.1")) {
+		t.Errorf("expected collector BGP ID 192.0.2.1 but got %s", net.IP(pit[0:4]))
+	}
+	if vl := binary.BigEndian.Uint16(pit[4:6]); string(pit[6:6+vl]) != "r1" {
+		t.Errorf("expected view name r1 but got %s", string(pit[6:6+vl]))
+	}
+	if expect := map[string]int{"10.0.0.0/24": 1, "10.0.1.0/24": 1}; !reflect.DeepEqual(expect, ribPrefixes(rib)) {
+		t.Errorf("expected RIB %v but got %v", expect, ribPrefixes(rib))
+	}
+	// Second interval has the withdrawal and the final snapshot has a single prefix
+	if updates := readRecords(t, filepath.Join(rd, "updates.20200601.100100.mrt")); len(updates) != 1 {
+		t.Errorf("expected 1 record in the second updates file but got %d", len(updates))
+	}
+	rib = readRecords(t, filepath.Join(rd, "rib.20200601.100110.mrt"))
+	if expect := map[string]int{"10.0.0.0/24": 1}; !reflect.DeepEqual(expect, ribPrefixes(rib)) {
+		t.Errorf("expected final RIB %v but got %v", expect, ribPrefixes(rib))
+	}
+}
+
+func TestASPath4(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		expect []byte
+	}{
+		{
+			name:   "sequence and set",
+			input:  []byte{2, 2, 0xfd, 0xe9, 0xfd, 0xea, 1, 1, 0xfd, 0xeb},
+			expect: []byte{2, 2, 0, 0, 0xfd, 0xe9, 0, 0, 0xfd, 0xea, 1, 1, 0, 0, 0xfd, 0xeb},
+		},
+		{
+			name:   "empty",
+			input:  []byte{},
+			expect: []byte{},
+		},
+		{
+			name:   "malformed",
+			input:  []byte{2, 3, 0xfd, 0xe9},
+			expect: []byte{2, 3, 0xfd, 0xe9},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := asPath4(tt.input); !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("expected %v but got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestExporterSnapshotFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gobmp-mrt")
+	if err != nil {
+		t.Fatalf("fail to create temp directory with error: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+	// Directory in place of the snapshot at the interval boundary makes the snapshot write fail
+	if err := os.MkdirAll(filepath.Join(dir, "192.0.2.1", "rib.20200601.100100.mrt", "busy"), 0755); err != nil {
+		t.Fatalf("fail to create directory with error: %+v", err)
+	}
+	e, err := NewExporter(&Config{Dir: dir, Interval: time.Minute})
+	if err != nil {
+		t.Fatalf("fail to create exporter with error: %+v", err)
+	}
+	s, err := e.NewSession(testAddr("192.0.2.1:45000"))
+	if err != nil {
+		t.Fatalf("fail to create session with error: %+v", err)
+	}
+	start := time.Date(2020, time.June, 1, 10, 0, 10, 0, time.UTC)
+	for _, at := range []time.Duration{0, time.Minute} {
+		if err := s.Record(start.Add(at), speaker.Initiation("r1", "test router")); err != nil {
+			t.Fatalf("expected failed snapshot not to fail processing of messages but got error: %+v", err)
+		}
+	}
+	if err := s.Close(); err == nil {
+		t.Fatalf("expected close to report failed snapshot")
+	}
+	// The session keeps exporting after the failed snapshot
+	if _, err := os.Stat(filepath.Join(dir, "192.0.2.1", "updates.20200601.100100.mrt")); err != nil {
+		t.Errorf("expected updates file of the second interval with error: %+v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "192.0.2.1", "rib.20200601.100110.mrt")); err != nil {
+		t.Errorf("expected final snapshot with error: %+v", err)
+	}
+}
@@ -0,0 +1,420 @@
+package mrt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/bgp"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+const (
+	bgpHeaderLength = 19
+	bgpUpdateType   = 2
+	// bmpRouteMessageOffset defines the offset of BGP message in Route Monitoring, Peer Up and Peer Down messages
+	bmpRouteMessageOffset = bmp.BMP_HEADER_SIZE + bmp.BMP_PEER_HEADER_SIZE
+
+	attrASPath     = 2
+	attrAggregator = 7
+	attrMPReach    = 14
+	attrMPUnreach  = 15
+)
+
+type peer struct {
+	address net.IP
+	as      uint32
+	bgpID   net.IP
+	localAS uint32
+	localIP net.IP
+	// as4 is true when the peer sends AS_PATH with 4 bytes AS numbers
+	as4 bool
+}
+
+type prefixKey struct {
+	afi    uint16
+	length uint8
+	prefix string
+}
+
+type pathKey struct {
+	peer   string
+	pathID uint32
+}
+
+type path struct {
+	originated time.Time
+	attrs      []byte
+}
+
+// router keeps the state of a monitored router needed to build MRT records,
+// it tracks known peers and their Adj-RIB-In.
+type router struct {
+	postPolicy bool
+	name       string
+	id         net.IP
+	peers      map[string]*peer
+	rib        map[prefixKey]map[pathKey]*path
+}
+
+func newRouter(postPolicy bool) *router {
+	return &router{
+		postPolicy: postPolicy,
+		peers:      make(map[string]*peer),
+		rib:        make(map[prefixKey]map[pathKey]*path),
+	}
+}
+
+func peerKey(pph *bmp.PerPeerHeader) string {
+	return string(pph.PeerDistinguisher) + string(pph.PeerAddress)
+}
+
+func (r *router) peer(pph *bmp.PerPeerHeader) *peer {
+	k := peerKey(pph)
+	p, ok := r.peers[k]
+	if !ok {
+		p = &peer{}
+		r.peers[k] = p
+	}
+	p.address = net.ParseIP(pph.GetPeerAddrString())
+	p.as = uint32(pph.PeerAS)
+	p.bgpID = net.IP(pph.PeerBGPID).To4()
+	p.as4 = !pph.FlagA
+
+	return p
+}
+
+// process updates the state of the router with BMP message received at ts and returns
+// BGP4MP records generated by the message.
+func (r *router) process(ts time.Time, msg []byte) ([]byte, error) {
+	if len(msg) < bmp.BMP_HEADER_SIZE {
+		return nil, fmt.Errorf("invalid BMP message length %d", len(msg))
+	}
+	ch, err := bmp.UnmarshalCommonHeader(msg[:bmp.BMP_HEADER_SIZE])
+	if err != nil {
+		return nil, err
+	}
+	if int(ch.MessageLength) != len(msg) {
+		return nil, fmt.Errorf("BMP message length %d does not match the length in the common header %d", len(msg), ch.MessageLength)
+	}
+	switch ch.MessageType {
+	case bmp.InitiationMsg:
+		im, err := bmp.UnmarshalInitiationMessage(msg[bmp.BMP_HEADER_SIZE:])
+		if err != nil {
+			return nil, err
+		}
+		for _, tlv := range im.TLV {
+			// sysName
+			if tlv.InformationType == 2 {
+				r.name = string(tlv.Information)
+			}
+		}
+		return nil, nil
+	case bmp.PeerUpMsg, bmp.PeerDownMsg, bmp.RouteMonitorMsg:
+	default:
+		return nil, nil
+	}
+	if len(msg) < bmpRouteMessageOffset {
+		return nil, fmt.Errorf("invalid BMP message length %d", len(msg))
+	}
+	pph, err := bmp.UnmarshalPerPeerHeader(msg[bmp.BMP_HEADER_SIZE:bmpRouteMessageOffset])
+	if err != nil {
+		return nil, err
+	}
+	switch ch.MessageType {
+	case bmp.PeerUpMsg:
+		p := r.peer(pph)
+		if err := r.peerUp(p, msg[bmpRouteMessageOffset:]); err != nil {
+			return nil, err
+		}
+		return BGP4MPStateChangeRecord(ts, p.as, p.localAS, p.address, p.localIP, StateOpenConfirm, StateEstablished), nil
+	case bmp.PeerDownMsg:
+		k := peerKey(pph)
+		p := r.peer(pph)
+		r.withdrawPeer(k)
+		delete(r.peers, k)
+		return BGP4MPStateChangeRecord(ts, p.as, p.localAS, p.address, p.localIP, StateEstablished, StateIdle), nil
+	}
+	// Route Monitoring message
+	if pph.FlagL != r.postPolicy {
+		return nil, nil
+	}
+	b := msg[bmpRouteMessageOffset:]
+	if len(b) < bgpHeaderLength || int(binary.BigEndian.Uint16(b[16:18])) != len(b) {
+		return nil, fmt.Errorf("invalid BGP message in Route Monitoring message")
+	}
+	if b[18] != bgpUpdateType {
+		return nil, nil
+	}
+	p := r.peer(pph)
+	if err := r.update(ts, peerKey(pph), p, b[bgpHeaderLength:]); err != nil {
+		return nil, err
+	}
+
+	return BGP4MPMessageRecord(ts, p.as, p.localAS, p.as4, p.address, p.localIP, b), nil
+}
+
+// peerUp stores the local address, the local AS and the router's BGP ID found in Peer Up message
+func (r *router) peerUp(p *peer, b []byte) error {
+	// Local address 16 bytes, local and remote ports 2 bytes each, followed by the sent Open message
+	if len(b) < 20+bgpHeaderLength {
+		return fmt.Errorf("invalid Peer Up message length %d", len(b))
+	}
+	local := net.IP(b[:16])
+	if p.address.To4() != nil {
+		local = net.IP(b[12:16]).To4()
+	}
+	p.localIP = local
+	l := int(binary.BigEndian.Uint16(b[36:38]))
+	if l < bgpHeaderLength || 20+l > len(b) {
+		return fmt.Errorf("invalid length %d of sent Open message in Peer Up message", l)
+	}
+	open, err := bgp.UnmarshalBGPOpenMessage(b[36 : 20+l])
+	if err != nil {
+		return err
+	}
+	p.localAS = uint32(open.MyAS)
+	if as, ok := open.Is4BytesASCapable(); ok {
+		p.localAS = uint32(as)
+	}
+	if r.id == nil {
+		r.id = net.IP(open.BGPID).To4()
+	}
+
+	return nil
+}
+
+// update applies BGP Update to Adj-RIB-In of the peer, only IPv4 and IPv6 unicast routes are tracked
+func (r *router) update(ts time.Time, k string, p *peer, b []byte) error {
+	u, err := bgp.UnmarshalBGPUpdate(b, &bgp.NegotiatedCapabilities{AS4: p.as4})
+	if err != nil {
+		return err
+	}
+	for _, rt := range u.WithdrawnRoutes {
+		r.withdraw(afiIPv4, k, rt)
+	}
+	if mp, err := u.GetMPUnReachNLRI(); err == nil {
+		if afi := unicastAFI(mp); afi != 0 {
+			if nlri, err := mp.GetNLRIUnicast(); err == nil {
+				for _, rt := range nlri.NLRI {
+					r.withdraw(afi, k, rt)
+				}
+			}
+		}
+	}
+	// Routes of the update with treat-as-withdraw error are removed from the RIB
+	taw := u.IsTreatAsWithdraw()
+	if len(u.NLRI) != 0 {
+		attrs := ribAttributes(u.PathAttributes, p.as4, nil)
+		for _, rt := range u.NLRI {
+			if taw {
+				r.withdraw(afiIPv4, k, rt)
+				continue
+			}
+			r.advertise(ts, afiIPv4, k, rt, attrs)
+		}
+	}
+	if mp, err := u.GetMPReachNLRI(); err == nil {
+		afi := unicastAFI(mp)
+		reach, ok := mp.(*bgp.MPReachNLRI)
+		if afi != 0 && ok {
+			if nlri, err := mp.GetNLRIUnicast(); err == nil {
+				attrs := ribAttributes(u.PathAttributes, p.as4, reach)
+				for _, rt := range nlri.NLRI {
+					if taw {
+						r.withdraw(afi, k, rt)
+						continue
+					}
+					r.advertise(ts, afi, k, rt, attrs)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func unicastAFI(mp bgp.MPNLRI) uint16 {
+	switch mp.GetAFISAFIType() {
+	case 1:
+		return afiIPv4
+	case 2:
+		return afiIPv6
+	}
+
+	return 0
+}
+
+func routeKey(afi uint16, rt base.Route) prefixKey {
+	return prefixKey{
+		afi:    afi,
+		length: rt.Length,
+		prefix: string(rt.Prefix),
+	}
+}
+
+func (r *router) advertise(ts time.Time, afi uint16, k string, rt base.Route, attrs []byte) {
+	pk := routeKey(afi, rt)
+	paths, ok := r.rib[pk]
+	if !ok {
+		paths = make(map[pathKey]*path)
+		r.rib[pk] = paths
+	}
+	paths[pathKey{peer: k, pathID: rt.PathID}] = &path{
+		originated: ts,
+		attrs:      attrs,
+	}
+}
+
+func (r *router) withdraw(afi uint16, k string, rt base.Route) {
+	pk := routeKey(afi, rt)
+	paths, ok := r.rib[pk]
+	if !ok {
+		return
+	}
+	delete(paths, pathKey{peer: k, pathID: rt.PathID})
+	if len(paths) == 0 {
+		delete(r.rib, pk)
+	}
+}
+
+func (r *router) withdrawPeer(k string) {
+	for pk, paths := range r.rib {
+		for ph := range paths {
+			if ph.peer == k {
+				delete(paths, ph)
+			}
+		}
+		if len(paths) == 0 {
+			delete(r.rib, pk)
+		}
+	}
+}
+
+// dump returns PEER_INDEX_TABLE followed by RIB records of all tracked prefixes
+func (r *router) dump(ts time.Time) []byte {
+	keys := make([]string, 0, len(r.peers))
+	for k := range r.peers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	index := make(map[string]uint16, len(keys))
+	entries := make([]PeerEntry, 0, len(keys))
+	for i, k := range keys {
+		p := r.peers[k]
+		index[k] = uint16(i)
+		entries = append(entries, PeerEntry{
+			BGPID:   p.bgpID,
+			Address: p.address,
+			AS:      p.as,
+		})
+	}
+	b := PeerIndexTableRecord(ts, r.id, r.name, entries)
+	prefixes := make([]prefixKey, 0, len(r.rib))
+	for pk := range r.rib {
+		prefixes = append(prefixes, pk)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		if prefixes[i].afi != prefixes[j].afi {
+			return prefixes[i].afi < prefixes[j].afi
+		}
+		if c := bytes.Compare([]byte(prefixes[i].prefix), []byte(prefixes[j].prefix)); c != 0 {
+			return c < 0
+		}
+		return prefixes[i].length < prefixes[j].length
+	})
+	for seq, pk := range prefixes {
+		rib := make([]RIBEntry, 0, len(r.rib[pk]))
+		addPath := false
+		for ph, pt := range r.rib[pk] {
+			i, ok := index[ph.peer]
+			if !ok {
+				continue
+			}
+			if ph.pathID != 0 {
+				addPath = true
+			}
+			rib = append(rib, RIBEntry{
+				PeerIndex:      i,
+				OriginatedTime: pt.originated,
+				PathID:         ph.pathID,
+				Attributes:     pt.attrs,
+			})
+		}
+		sort.Slice(rib, func(i, j int) bool {
+			if rib[i].PeerIndex != rib[j].PeerIndex {
+				return rib[i].PeerIndex < rib[j].PeerIndex
+			}
+			return rib[i].PathID < rib[j].PathID
+		})
+		b = append(b, RIBRecord(ts, pk.afi, uint32(seq), pk.length, []byte(pk.prefix), addPath, rib)...)
+	}
+
+	return b
+}
+
+// ribAttributes encodes path attributes for RIB entries per rfc6396, AS_PATH and AGGREGATOR are
+// converted to 4 bytes AS numbers, MP_UNREACH_NLRI is dropped and MP_REACH_NLRI is replaced by
+// its abbreviated form carrying only the next hop when reach is not nil, or dropped otherwise.
+func ribAttributes(attrs []bgp.PathAttribute, as4 bool, reach *bgp.MPReachNLRI) []byte {
+	b := make([]byte, 0)
+	for _, attr := range attrs {
+		v := attr.Attribute
+		switch attr.AttributeType {
+		case attrMPUnreach:
+			continue
+		case attrMPReach:
+			if reach == nil {
+				continue
+			}
+			v = append([]byte{reach.NextHopAddressLength}, reach.NextHopAddress...)
+		case attrASPath:
+			if !as4 {
+				v = asPath4(v)
+			}
+		case attrAggregator:
+			if !as4 && len(v) == 6 {
+				v = append([]byte{0, 0}, v...)
+			}
+		}
+		b = append(b, pathAttribute(attr.AttributeTypeFlags, attr.AttributeType, v)...)
+	}
+
+	return b
+}
+
+// asPath4 converts AS_PATH with 2 bytes AS numbers into AS_PATH with 4 bytes AS numbers,
+// malformed AS_PATH is returned unchanged.
+func asPath4(b []byte) []byte {
+	path := make([]byte, 0, len(b)*2)
+	for p := 0; p < len(b); {
+		if p+2 > len(b) {
+			return b
+		}
+		t, n := b[p], int(b[p+1])
+		p += 2
+		if p+2*n > len(b) {
+			return b
+		}
+		path = append(path, t, byte(n))
+		for i := 0; i < n; i++ {
+			path = append(path, 0, 0, b[p], b[p+1])
+			p += 2
+		}
+	}
+
+	return path
+}
+
+func pathAttribute(flags, t byte, v []byte) []byte {
+	if len(v) > 0xff {
+		b := []byte{flags | 0x10, t}
+		return append(appendUint16(b, uint16(len(v))), v...)
+	}
+
+	return append([]byte{flags &^ 0x10, t, byte(len(v))}, v...)
+}
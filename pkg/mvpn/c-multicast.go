@@ -0,0 +1,73 @@
+package mvpn
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// CMulticastRoute defines a structure of Route types 6 and 7
+// (Shared Tree Join and Source Tree Join C-multicast routes)
+type CMulticastRoute struct {
+	RD       *base.RD
+	SourceAS uint32
+	Source   []byte
+	Group    []byte
+}
+
+// GetRouteTypeSpec returns the instance of the C-multicast route object
+func (t *CMulticastRoute) GetRouteTypeSpec() interface{} {
+	return t
+}
+
+func (t *CMulticastRoute) getRD() *base.RD {
+	return t.RD
+}
+
+func (t *CMulticastRoute) getOriginatorIP() []byte {
+	return nil
+}
+
+func (t *CMulticastRoute) getSourceAS() uint32 {
+	return t.SourceAS
+}
+
+func (t *CMulticastRoute) getSource() []byte {
+	return t.Source
+}
+
+func (t *CMulticastRoute) getGroup() []byte {
+	return t.Group
+}
+
+// UnmarshalCMulticast instantiates C-multicast route object, for Shared Tree Join
+// the source is the address of C-RP.
+func UnmarshalCMulticast(b []byte) (*CMulticastRoute, error) {
+	var err error
+	if len(b) < 14 {
+		return nil, fmt.Errorf("invalid length of C-multicast route %d", len(b))
+	}
+	t := CMulticastRoute{}
+	p := 0
+	if t.RD, err = base.MakeRD(b[p : p+8]); err != nil {
+		return nil, err
+	}
+	p += 8
+	t.SourceAS = binary.BigEndian.Uint32(b[p : p+4])
+	p += 4
+	l := 0
+	if t.Source, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if t.Group, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if p != len(b) {
+		return nil, fmt.Errorf("invalid length of C-multicast route %d", len(b))
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,32 @@
+//go:build go1.18
+// +build go1.18
+
+package mvpn
+
+import "testing"
+
+func FuzzUnmarshalMVPNNLRI(f *testing.F) {
+	f.Add([]byte{0x01, 0x0c, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x0a, 0x00, 0x00, 0x01})
+	f.Add([]byte{0x04, 0x1c, 0x03, 0x16, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x20, 0xc0, 0xa8, 0x01, 0x01, 0x20, 0xe8, 0x01, 0x01, 0x01, 0x0a, 0x00, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x02})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalMVPNNLRI(b)
+	})
+}
+
+func FuzzUnmarshalSPMSIAD(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSPMSIAD(b)
+	})
+}
+
+func FuzzUnmarshalLeafAD(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalLeafAD(b)
+	})
+}
+
+func FuzzUnmarshalCMulticast(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalCMulticast(b)
+	})
+}
@@ -0,0 +1,104 @@
+package mvpn
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// IntraASIPMSIADRoute defines a structure of Route type 1
+// (Intra-AS I-PMSI A-D route)
+type IntraASIPMSIADRoute struct {
+	RD           *base.RD
+	OriginatorIP []byte
+}
+
+// GetRouteTypeSpec returns the instance of the Intra-AS I-PMSI A-D route object
+func (t *IntraASIPMSIADRoute) GetRouteTypeSpec() interface{} {
+	return t
+}
+
+func (t *IntraASIPMSIADRoute) getRD() *base.RD {
+	return t.RD
+}
+
+func (t *IntraASIPMSIADRoute) getOriginatorIP() []byte {
+	return t.OriginatorIP
+}
+
+func (t *IntraASIPMSIADRoute) getSourceAS() uint32 {
+	return 0
+}
+
+func (t *IntraASIPMSIADRoute) getSource() []byte {
+	return nil
+}
+
+func (t *IntraASIPMSIADRoute) getGroup() []byte {
+	return nil
+}
+
+// UnmarshalIntraASIPMSIAD instantiates Intra-AS I-PMSI A-D route object
+func UnmarshalIntraASIPMSIAD(b []byte) (*IntraASIPMSIADRoute, error) {
+	var err error
+	if len(b) < 12 {
+		return nil, fmt.Errorf("invalid length of Intra-AS I-PMSI A-D route %d", len(b))
+	}
+	t := IntraASIPMSIADRoute{}
+	if t.RD, err = base.MakeRD(b[:8]); err != nil {
+		return nil, err
+	}
+	if t.OriginatorIP, err = unmarshalOriginatorIP(b[8:]); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// InterASIPMSIADRoute defines a structure of Route type 2
+// (Inter-AS I-PMSI A-D route)
+type InterASIPMSIADRoute struct {
+	RD       *base.RD
+	SourceAS uint32
+}
+
+// GetRouteTypeSpec returns the instance of the Inter-AS I-PMSI A-D route object
+func (t *InterASIPMSIADRoute) GetRouteTypeSpec() interface{} {
+	return t
+}
+
+func (t *InterASIPMSIADRoute) getRD() *base.RD {
+	return t.RD
+}
+
+func (t *InterASIPMSIADRoute) getOriginatorIP() []byte {
+	return nil
+}
+
+func (t *InterASIPMSIADRoute) getSourceAS() uint32 {
+	return t.SourceAS
+}
+
+func (t *InterASIPMSIADRoute) getSource() []byte {
+	return nil
+}
+
+func (t *InterASIPMSIADRoute) getGroup() []byte {
+	return nil
+}
+
+// UnmarshalInterASIPMSIAD instantiates Inter-AS I-PMSI A-D route object
+func UnmarshalInterASIPMSIAD(b []byte) (*InterASIPMSIADRoute, error) {
+	var err error
+	if len(b) != 12 {
+		return nil, fmt.Errorf("invalid length of Inter-AS I-PMSI A-D route %d", len(b))
+	}
+	t := InterASIPMSIADRoute{}
+	if t.RD, err = base.MakeRD(b[:8]); err != nil {
+		return nil, err
+	}
+	t.SourceAS = binary.BigEndian.Uint32(b[8:12])
+
+	return &t, nil
+}
@@ -0,0 +1,57 @@
+package mvpn
+
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// LeafADRoute defines a structure of Route type 4
+// (Leaf A-D route)
+type LeafADRoute struct {
+	// RouteKey is MCAST-VPN NLRI of the route the Leaf A-D route is sent in response to
+	RouteKey     *NLRI
+	OriginatorIP []byte
+}
+
+// GetRouteTypeSpec returns the instance of the Leaf A-D route object
+func (t *LeafADRoute) GetRouteTypeSpec() interface{} {
+	return t
+}
+
+func (t *LeafADRoute) getRD() *base.RD {
+	return t.RouteKey.GetRD()
+}
+
+func (t *LeafADRoute) getOriginatorIP() []byte {
+	return t.OriginatorIP
+}
+
+func (t *LeafADRoute) getSourceAS() uint32 {
+	return t.RouteKey.GetSourceAS()
+}
+
+func (t *LeafADRoute) getSource() []byte {
+	return t.RouteKey.GetSource()
+}
+
+func (t *LeafADRoute) getGroup() []byte {
+	return t.RouteKey.GetGroup()
+}
+
+// UnmarshalLeafAD instantiates Leaf A-D route object
+func UnmarshalLeafAD(b []byte) (*LeafADRoute, error) {
+	var err error
+	t := LeafADRoute{}
+	if t.RouteKey, err = unmarshalNLRI(b); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Leaf A-D route key with error: %+v", err)
+	}
+	if t.RouteKey.RouteType == LeafAD {
+		return nil, fmt.Errorf("invalid route type %d of Leaf A-D route key", t.RouteKey.RouteType)
+	}
+	if t.OriginatorIP, err = unmarshalOriginatorIP(b[int(t.RouteKey.Length)+2:]); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,169 @@
+package mvpn
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/tools"
+)
+
+const (
+	// IntraASIPMSIAD defines Intra-AS I-PMSI A-D route type
+	IntraASIPMSIAD = 1
+	// InterASIPMSIAD defines Inter-AS I-PMSI A-D route type
+	InterASIPMSIAD = 2
+	// SPMSIAD defines S-PMSI A-D route type
+	SPMSIAD = 3
+	// LeafAD defines Leaf A-D route type
+	LeafAD = 4
+	// SourceActiveAD defines Source Active A-D route type
+	SourceActiveAD = 5
+	// SharedTreeJoin defines Shared Tree Join C-multicast route type
+	SharedTreeJoin = 6
+	// SourceTreeJoin defines Source Tree Join C-multicast route type
+	SourceTreeJoin = 7
+)
+
+// RouteTypes defines names of MCAST-VPN route types
+var RouteTypes = map[uint8]string{
+	IntraASIPMSIAD: "Intra-AS I-PMSI A-D",
+	InterASIPMSIAD: "Inter-AS I-PMSI A-D",
+	SPMSIAD:        "S-PMSI A-D",
+	LeafAD:         "Leaf A-D",
+	SourceActiveAD: "Source Active A-D",
+	SharedTreeJoin: "Shared Tree Join",
+	SourceTreeJoin: "Source Tree Join",
+}
+
+// RouteTypeSpec defines a method to get a route type specific information
+type RouteTypeSpec interface {
+	GetRouteTypeSpec() interface{}
+	getRD() *base.RD
+	getOriginatorIP() []byte
+	getSourceAS() uint32
+	getSource() []byte
+	getGroup() []byte
+}
+
+// Route defines a collection of MCAST-VPN NLRI objects
+type Route struct {
+	Route []*NLRI
+}
+
+// NLRI defines a single MCAST-VPN NLRI object
+// https://tools.ietf.org/html/rfc6514#section-4
+type NLRI struct {
+	RouteType uint8
+	Length    uint8
+	RouteTypeSpec
+}
+
+// GetRouteTypeString returns the name of MCAST-VPN route type
+func (n *NLRI) GetRouteTypeString() string {
+	if s, ok := RouteTypes[n.RouteType]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown (%d)", n.RouteType)
+}
+
+// GetRD returns Route Distinguisher of the route, nil if the route type does not carry it
+func (n *NLRI) GetRD() *base.RD {
+	return n.RouteTypeSpec.getRD()
+}
+
+// GetOriginatorIP returns Originating Router's IP Address
+func (n *NLRI) GetOriginatorIP() []byte {
+	return n.RouteTypeSpec.getOriginatorIP()
+}
+
+// GetSourceAS returns Source AS
+func (n *NLRI) GetSourceAS() uint32 {
+	return n.RouteTypeSpec.getSourceAS()
+}
+
+// GetSource returns Multicast Source, an empty slice is returned for the wildcard
+// and nil if the route type does not carry it
+func (n *NLRI) GetSource() []byte {
+	return n.RouteTypeSpec.getSource()
+}
+
+// GetGroup returns Multicast Group, an empty slice is returned for the wildcard
+// and nil if the route type does not carry it
+func (n *NLRI) GetGroup() []byte {
+	return n.RouteTypeSpec.getGroup()
+}
+
+// UnmarshalMVPNNLRI instantiates a MCAST-VPN Route object from a slice of bytes
+func UnmarshalMVPNNLRI(b []byte) (*Route, error) {
+	if glog.V(6) {
+		glog.Infof("MVPN NLRI Raw: %s", tools.MessageHex(b))
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("NLRI length is 0")
+	}
+	r := Route{
+		Route: make([]*NLRI, 0),
+	}
+	for p := 0; p < len(b); {
+		n, err := unmarshalNLRI(b[p:])
+		if err != nil {
+			return nil, err
+		}
+		r.Route = append(r.Route, n)
+		p += int(n.Length) + 2
+	}
+
+	return &r, nil
+}
+
+// unmarshalNLRI unmarshals a single MCAST-VPN NLRI found at the beginning of a slice of bytes
+func unmarshalNLRI(b []byte) (*NLRI, error) {
+	var err error
+	if len(b) < 2 {
+		return nil, fmt.Errorf("not enough bytes to unmarshal MVPN NLRI")
+	}
+	n := &NLRI{
+		RouteType: b[0],
+		Length:    b[1],
+	}
+	p := 2
+	l := int(n.Length)
+	if p+l > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal MVPN route type %d of length %d", n.RouteType, l)
+	}
+	switch n.RouteType {
+	case IntraASIPMSIAD:
+		n.RouteTypeSpec, err = UnmarshalIntraASIPMSIAD(b[p : p+l])
+	case InterASIPMSIAD:
+		n.RouteTypeSpec, err = UnmarshalInterASIPMSIAD(b[p : p+l])
+	case SPMSIAD:
+		n.RouteTypeSpec, err = UnmarshalSPMSIAD(b[p : p+l])
+	case LeafAD:
+		n.RouteTypeSpec, err = UnmarshalLeafAD(b[p : p+l])
+	case SourceActiveAD:
+		n.RouteTypeSpec, err = UnmarshalSourceActiveAD(b[p : p+l])
+	case SharedTreeJoin:
+		fallthrough
+	case SourceTreeJoin:
+		n.RouteTypeSpec, err = UnmarshalCMulticast(b[p : p+l])
+	default:
+		return nil, fmt.Errorf("unknown route type %d", n.RouteType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// unmarshalOriginatorIP unmarshals Originating Router's IP Address which occupies the rest of the route
+func unmarshalOriginatorIP(b []byte) ([]byte, error) {
+	if len(b) != 4 && len(b) != 16 {
+		return nil, fmt.Errorf("invalid length of Originating Router's IP Address %d", len(b))
+	}
+	ip := make([]byte, len(b))
+	copy(ip, b)
+
+	return ip, nil
+}
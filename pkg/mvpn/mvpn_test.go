@@ -0,0 +1,120 @@
+package mvpn
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+func TestUnmarshalMVPNNLRI(t *testing.T) {
+	rd := &base.RD{Type: 0, Value: []byte{0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64}}
+	spmsi := &NLRI{
+		RouteType: SPMSIAD,
+		Length:    22,
+		RouteTypeSpec: &SPMSIADRoute{
+			RD:           rd,
+			Source:       []byte{192, 168, 1, 1},
+			Group:        []byte{232, 1, 1, 1},
+			OriginatorIP: []byte{10, 0, 0, 1},
+		},
+	}
+	tests := []struct {
+		name   string
+		input  []byte
+		expect *Route
+		fail   bool
+	}{
+		{
+			name:  "intra-as i-pmsi a-d and inter-as i-pmsi a-d",
+			input: []byte{0x01, 0x0c, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x0a, 0x00, 0x00, 0x01, 0x02, 0x0c, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0xfd, 0xe9},
+			expect: &Route{
+				Route: []*NLRI{
+					{
+						RouteType:     IntraASIPMSIAD,
+						Length:        12,
+						RouteTypeSpec: &IntraASIPMSIADRoute{RD: rd, OriginatorIP: []byte{10, 0, 0, 1}},
+					},
+					{
+						RouteType:     InterASIPMSIAD,
+						Length:        12,
+						RouteTypeSpec: &InterASIPMSIADRoute{RD: rd, SourceAS: 65001},
+					},
+				},
+			},
+		},
+		{
+			name:  "s-pmsi a-d",
+			input: []byte{0x03, 0x16, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x20, 0xc0, 0xa8, 0x01, 0x01, 0x20, 0xe8, 0x01, 0x01, 0x01, 0x0a, 0x00, 0x00, 0x01},
+			expect: &Route{
+				Route: []*NLRI{spmsi},
+			},
+		},
+		{
+			name:  "leaf a-d with s-pmsi a-d route key",
+			input: []byte{0x04, 0x1c, 0x03, 0x16, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x20, 0xc0, 0xa8, 0x01, 0x01, 0x20, 0xe8, 0x01, 0x01, 0x01, 0x0a, 0x00, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x02},
+			expect: &Route{
+				Route: []*NLRI{
+					{
+						RouteType:     LeafAD,
+						Length:        28,
+						RouteTypeSpec: &LeafADRoute{RouteKey: spmsi, OriginatorIP: []byte{10, 0, 0, 2}},
+					},
+				},
+			},
+		},
+		{
+			name:  "source active a-d",
+			input: []byte{0x05, 0x12, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x20, 0xc0, 0xa8, 0x01, 0x01, 0x20, 0xe8, 0x01, 0x01, 0x01},
+			expect: &Route{
+				Route: []*NLRI{
+					{
+						RouteType:     SourceActiveAD,
+						Length:        18,
+						RouteTypeSpec: &SourceActiveADRoute{RD: rd, Source: []byte{192, 168, 1, 1}, Group: []byte{232, 1, 1, 1}},
+					},
+				},
+			},
+		},
+		{
+			name:  "shared tree join with wildcard source",
+			input: []byte{0x06, 0x12, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x20, 0xe0, 0x01, 0x01, 0x01},
+			expect: &Route{
+				Route: []*NLRI{
+					{
+						RouteType:     SharedTreeJoin,
+						Length:        18,
+						RouteTypeSpec: &CMulticastRoute{RD: rd, SourceAS: 65000, Source: []byte{}, Group: []byte{224, 1, 1, 1}},
+					},
+				},
+			},
+		},
+		{
+			name:  "source tree join with invalid group length",
+			input: []byte{0x07, 0x16, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0xfd, 0xe8, 0x20, 0xc0, 0xa8, 0x01, 0x01, 0x18, 0xe8, 0x01, 0x01, 0x01},
+			fail:  true,
+		},
+		{
+			name:  "unknown route type",
+			input: []byte{0x08, 0x00},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalMVPNNLRI(tt.input)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("expected %+v does not match computed %+v", tt.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package mvpn
+
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// SourceActiveADRoute defines a structure of Route type 5
+// (Source Active A-D route)
+type SourceActiveADRoute struct {
+	RD     *base.RD
+	Source []byte
+	Group  []byte
+}
+
+// GetRouteTypeSpec returns the instance of the Source Active A-D route object
+func (t *SourceActiveADRoute) GetRouteTypeSpec() interface{} {
+	return t
+}
+
+func (t *SourceActiveADRoute) getRD() *base.RD {
+	return t.RD
+}
+
+func (t *SourceActiveADRoute) getOriginatorIP() []byte {
+	return nil
+}
+
+func (t *SourceActiveADRoute) getSourceAS() uint32 {
+	return 0
+}
+
+func (t *SourceActiveADRoute) getSource() []byte {
+	return t.Source
+}
+
+func (t *SourceActiveADRoute) getGroup() []byte {
+	return t.Group
+}
+
+// UnmarshalSourceActiveAD instantiates Source Active A-D route object
+func UnmarshalSourceActiveAD(b []byte) (*SourceActiveADRoute, error) {
+	var err error
+	if len(b) < 10 {
+		return nil, fmt.Errorf("invalid length of Source Active A-D route %d", len(b))
+	}
+	t := SourceActiveADRoute{}
+	p := 0
+	if t.RD, err = base.MakeRD(b[p : p+8]); err != nil {
+		return nil, err
+	}
+	p += 8
+	l := 0
+	if t.Source, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if t.Group, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if p != len(b) {
+		return nil, fmt.Errorf("invalid length of Source Active A-D route %d", len(b))
+	}
+
+	return &t, nil
+}
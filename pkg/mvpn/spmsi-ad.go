@@ -0,0 +1,69 @@
+package mvpn
+
+import (
+	"fmt"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+// SPMSIADRoute defines a structure of Route type 3
+// (S-PMSI A-D route)
+type SPMSIADRoute struct {
+	RD           *base.RD
+	Source       []byte
+	Group        []byte
+	OriginatorIP []byte
+}
+
+// GetRouteTypeSpec returns the instance of the S-PMSI A-D route object
+func (t *SPMSIADRoute) GetRouteTypeSpec() interface{} {
+	return t
+}
+
+func (t *SPMSIADRoute) getRD() *base.RD {
+	return t.RD
+}
+
+func (t *SPMSIADRoute) getOriginatorIP() []byte {
+	return t.OriginatorIP
+}
+
+func (t *SPMSIADRoute) getSourceAS() uint32 {
+	return 0
+}
+
+func (t *SPMSIADRoute) getSource() []byte {
+	return t.Source
+}
+
+func (t *SPMSIADRoute) getGroup() []byte {
+	return t.Group
+}
+
+// UnmarshalSPMSIAD instantiates S-PMSI A-D route object
+func UnmarshalSPMSIAD(b []byte) (*SPMSIADRoute, error) {
+	var err error
+	if len(b) < 14 {
+		return nil, fmt.Errorf("invalid length of S-PMSI A-D route %d", len(b))
+	}
+	t := SPMSIADRoute{}
+	p := 0
+	if t.RD, err = base.MakeRD(b[p : p+8]); err != nil {
+		return nil, err
+	}
+	p += 8
+	l := 0
+	if t.Source, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if t.Group, l, err = base.UnmarshalMulticastAddress(b[p:]); err != nil {
+		return nil, err
+	}
+	p += l
+	if t.OriginatorIP, err = unmarshalOriginatorIP(b[p:]); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
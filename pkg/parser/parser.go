@@ -1,17 +1,46 @@
 package parser
 
 import (
+	"sync"
+
 	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bgp"
 	"github.com/sbezverk/gobmp/pkg/bmp"
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
 
-// Parser dispatches workers upon request received from the channel
+// Session defines methods to parse messages of a single BMP session, the session keeps
+// capabilities negotiated by the monitored peers, which are required to decode their updates.
+type Session interface {
+	Parse(b []byte, producerQueue chan bmp.Message)
+}
+
+type session struct {
+	sync.RWMutex
+	peers map[string]*bgp.NegotiatedCapabilities
+}
+
+// NewSession returns a new instance of BMP session parser, messages are parsed in the calling
+// goroutine, so the order of messages is preserved.
+func NewSession() Session {
+	return &session{
+		peers: make(map[string]*bgp.NegotiatedCapabilities),
+	}
+}
+
+// Parser dispatches workers upon request received from the channel, Peer Up messages are parsed
+// before dispatching following messages, so updates are decoded with the peer's negotiated capabilities.
 func Parser(queue chan []byte, producerQueue chan bmp.Message, stop chan struct{}) {
+	s := NewSession()
 	for {
 		select {
 		case msg := <-queue:
-			go parsingWorker(msg, producerQueue)
+			// Message type is the last byte of BMP Common Header
+			if len(msg) >= bmp.CommonHeaderLength && msg[bmp.CommonHeaderLength-1] == bmp.PeerUpMsg {
+				s.Parse(msg, producerQueue)
+				continue
+			}
+			go s.Parse(msg, producerQueue)
 		case <-stop:
 			glog.Infof("received interrupt, stopping.")
 			return
@@ -19,7 +48,38 @@ func Parser(queue chan []byte, producerQueue chan bmp.Message, stop chan struct{
 	}
 }
 
-func parsingWorker(b []byte, producerQueue chan bmp.Message) {
+func peerKey(pph *bmp.PerPeerHeader) string {
+	return string(pph.PeerDistinguisher) + string(pph.PeerAddress)
+}
+
+// capabilities returns capabilities negotiated by the peer, when Peer Up message of the peer
+// has not been seen, 4 bytes AS capability is derived from the A flag of Per Peer Header and
+// Extended Message capability is assumed.
+func (s *session) capabilities(pph *bmp.PerPeerHeader) *bgp.NegotiatedCapabilities {
+	s.RLock()
+	defer s.RUnlock()
+	if caps, ok := s.peers[peerKey(pph)]; ok {
+		return caps
+	}
+
+	return &bgp.NegotiatedCapabilities{AS4: !pph.FlagA, ExtendedMessage: true}
+}
+
+func (s *session) peerUp(pph *bmp.PerPeerHeader, pu *bmp.PeerUpMessage) {
+	caps := bgp.NegotiateCapabilities(pu.SentOpen.GetCapabilities(), pu.ReceivedOpen.GetCapabilities())
+	s.Lock()
+	defer s.Unlock()
+	s.peers[peerKey(pph)] = caps
+}
+
+func (s *session) peerDown(pph *bmp.PerPeerHeader) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.peers, peerKey(pph))
+}
+
+// Parse parses BMP messages found in the byte slice and sends the results to producerQueue
+func (s *session) Parse(b []byte, producerQueue chan bmp.Message) {
 	perPerHeaderLen := 0
 	var bmpMsg bmp.Message
 	// Loop through all found Common Headers in the slice and process them
@@ -40,7 +100,7 @@ func parsingWorker(b []byte, producerQueue chan bmp.Message) {
 				return
 			}
 			perPerHeaderLen = bmp.PerPeerHeaderLength
-			rm, err := bmp.UnmarshalBMPRouteMonitorMessage(b[p+perPerHeaderLen : p+int(ch.MessageLength)-bmp.CommonHeaderLength])
+			rm, err := bmp.UnmarshalBMPRouteMonitorMessage(b[p+perPerHeaderLen:p+int(ch.MessageLength)-bmp.CommonHeaderLength], s.capabilities(bmpMsg.PeerHeader))
 			if err != nil {
 				glog.Errorf("fail to recover BMP Route Monitoring with error: %+v", err)
 				glog.V(5).Infof("common header content: %+v", ch)
@@ -67,6 +127,8 @@ func parsingWorker(b []byte, producerQueue chan bmp.Message) {
 				glog.Errorf("fail to recover BMP Per Peer Header with error: %+v", err)
 				return
 			}
+			// Capabilities negotiated by the peer are no longer valid once the peer is down
+			s.peerDown(bmpMsg.PeerHeader)
 			perPerHeaderLen = bmp.PerPeerHeaderLength
 			if bmpMsg.Payload, err = bmp.UnmarshalPeerDownMessage(b[p+perPerHeaderLen : p+int(ch.MessageLength)-bmp.CommonHeaderLength]); err != nil {
 				glog.Errorf("fail to recover BMP Peer Down message with error: %+v", err)
@@ -79,10 +141,13 @@ func parsingWorker(b []byte, producerQueue chan bmp.Message) {
 				return
 			}
 			perPerHeaderLen = bmp.PerPeerHeaderLength
-			if bmpMsg.Payload, err = bmp.UnmarshalPeerUpMessage(b[p+perPerHeaderLen : p+int(ch.MessageLength)-bmp.CommonHeaderLength]); err != nil {
+			pu, err := bmp.UnmarshalPeerUpMessage(b[p+perPerHeaderLen : p+int(ch.MessageLength)-bmp.CommonHeaderLength])
+			if err != nil {
 				glog.Errorf("fail to recover BMP Peer Up message with error: %+v", err)
 				return
 			}
+			s.peerUp(bmpMsg.PeerHeader, pu)
+			bmpMsg.Payload = pu
 			p += perPerHeaderLen
 		case bmp.InitiationMsg:
 			if _, err := bmp.UnmarshalInitiationMessage(b[p : p+(int(ch.MessageLength)-bmp.CommonHeaderLength)]); err != nil {
@@ -0,0 +1,12 @@
+//go:build go1.18
+// +build go1.18
+
+package prefixsid
+
+import "testing"
+
+func FuzzUnmarshalBGPAttrPrefixSID(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalBGPAttrPrefixSID(b)
+	})
+}
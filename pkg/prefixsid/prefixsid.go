@@ -2,6 +2,7 @@ package prefixsid
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/srv6"
@@ -52,9 +53,18 @@ func UnmarshalBGPAttrPrefixSID(b []byte) (*PSid, error) {
 		OriginatorSRGB: nil,
 	}
 	for p := 0; p < len(b); {
+		if p+3 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal Prefix SID TLV")
+		}
+		if l := int(binary.BigEndian.Uint16(b[p+1 : p+3])); p+3+l > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal Prefix SID TLV type %d of length %d", b[p], l)
+		}
 		// Determin the type, currently only type 1 and 3 are supported
 		switch b[p] {
 		case 1:
+			if binary.BigEndian.Uint16(b[p+1:p+3]) < 7 {
+				return nil, fmt.Errorf("invalid length of Label Index TLV")
+			}
 			p++
 			psid.LabelIndex = &LabelIndexTLV{}
 			psid.LabelIndex.Type = 1
@@ -67,6 +77,9 @@ func UnmarshalBGPAttrPrefixSID(b []byte) (*PSid, error) {
 			psid.LabelIndex.LabelIndex = binary.BigEndian.Uint32(b[p : p+4])
 			p += 4
 		case 3:
+			if binary.BigEndian.Uint16(b[p+1:p+3]) < 2 {
+				return nil, fmt.Errorf("invalid length of Originator SRGB TLV")
+			}
 			p++
 			psid.OriginatorSRGB = &OriginatorSRGBTLV{}
 			psid.OriginatorSRGB.Type = 1
@@ -0,0 +1,348 @@
+package recorder
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+const (
+	pcapGlobalHeaderLength = 24
+	pcapRecordHeaderLength = 16
+	pcapMagicMicro         = 0xa1b2c3d4
+	pcapMagicNano          = 0xa1b23c4d
+
+	linkTypeNull     = 0
+	linkTypeEthernet = 1
+	linkTypeRaw      = 101
+	linkTypeLoop     = 108
+	linkTypeLinuxSLL = 113
+	linkTypeIPv4     = 228
+	linkTypeIPv6     = 229
+	linkTypeSLL2     = 276
+
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86dd
+	etherTypeVLAN = 0x8100
+	etherTypeQinQ = 0x88a8
+
+	protoTCP = 6
+)
+
+func isPcapMagic(b []byte) bool {
+	switch binary.BigEndian.Uint32(b) {
+	case pcapMagicMicro, pcapMagicNano:
+		return true
+	}
+	switch binary.LittleEndian.Uint32(b) {
+	case pcapMagicMicro, pcapMagicNano:
+		return true
+	}
+
+	return false
+}
+
+// tcpStream defines a state of a single direction of a TCP connection carrying BMP messages
+type tcpStream struct {
+	router  string
+	synced  bool
+	nextSeq uint32
+	// pending stores out of order segments keyed by their sequence number
+	pending map[uint32][]byte
+	data    []byte
+	// broken is set when the stream does not carry valid BMP messages
+	broken bool
+}
+
+type pcapReader struct {
+	r        *bufio.Reader
+	order    binary.ByteOrder
+	nano     bool
+	linkType uint32
+	port     uint16
+	max      int
+	streams  map[string]*tcpStream
+	ready    []*Record
+}
+
+func newPcapReader(r *bufio.Reader, port uint16, o readerOptions) (Reader, error) {
+	hdr := make([]byte, pcapGlobalHeaderLength)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, fmt.Errorf("fail to read pcap global header with error: %+v", err)
+	}
+	pr := &pcapReader{
+		r:       r,
+		port:    port,
+		max:     o.maxMessageLength,
+		streams: make(map[string]*tcpStream),
+		ready:   make([]*Record, 0),
+	}
+	switch {
+	case binary.BigEndian.Uint32(hdr) == pcapMagicMicro:
+		pr.order = binary.BigEndian
+	case binary.BigEndian.Uint32(hdr) == pcapMagicNano:
+		pr.order = binary.BigEndian
+		pr.nano = true
+	case binary.LittleEndian.Uint32(hdr) == pcapMagicMicro:
+		pr.order = binary.LittleEndian
+	case binary.LittleEndian.Uint32(hdr) == pcapMagicNano:
+		pr.order = binary.LittleEndian
+		pr.nano = true
+	default:
+		return nil, fmt.Errorf("invalid pcap magic %x", hdr[:4])
+	}
+	pr.linkType = pr.order.Uint32(hdr[20:24]) & 0x0fffffff
+
+	return pr, nil
+}
+
+func (pr *pcapReader) Next() (*Record, error) {
+	for len(pr.ready) == 0 {
+		if err := pr.readPacket(); err != nil {
+			return nil, err
+		}
+	}
+	r := pr.ready[0]
+	pr.ready = pr.ready[1:]
+
+	return r, nil
+}
+
+func (pr *pcapReader) readPacket() error {
+	hdr := make([]byte, pcapRecordHeaderLength)
+	if _, err := io.ReadFull(pr.r, hdr); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("truncated pcap record header")
+		}
+		return err
+	}
+	sec := int64(pr.order.Uint32(hdr[0:4]))
+	frac := int64(pr.order.Uint32(hdr[4:8]))
+	if !pr.nano {
+		frac *= 1000
+	}
+	ts := time.Unix(sec, frac)
+	data := make([]byte, pr.order.Uint32(hdr[8:12]))
+	if _, err := io.ReadFull(pr.r, data); err != nil {
+		return fmt.Errorf("truncated pcap record with error: %+v", err)
+	}
+	if pr.order.Uint32(hdr[8:12]) < pr.order.Uint32(hdr[12:16]) {
+		// Packet was truncated by the snap length, TCP stream cannot be reassembled
+		glog.Warningf("pcap record captured %d bytes out of %d, ignoring it", pr.order.Uint32(hdr[8:12]), pr.order.Uint32(hdr[12:16]))
+		return nil
+	}
+	pkt, ok := pr.linkPayload(data)
+	if !ok {
+		return nil
+	}
+	pr.processIP(ts, pkt)
+
+	return nil
+}
+
+// linkPayload strips link layer header and returns IP packet with its ether type
+func (pr *pcapReader) linkPayload(b []byte) ([]byte, bool) {
+	var et uint16
+	switch pr.linkType {
+	case linkTypeEthernet:
+		if len(b) < 14 {
+			return nil, false
+		}
+		et = binary.BigEndian.Uint16(b[12:14])
+		b = b[14:]
+		for et == etherTypeVLAN || et == etherTypeQinQ {
+			if len(b) < 4 {
+				return nil, false
+			}
+			et = binary.BigEndian.Uint16(b[2:4])
+			b = b[4:]
+		}
+	case linkTypeLinuxSLL:
+		if len(b) < 16 {
+			return nil, false
+		}
+		et = binary.BigEndian.Uint16(b[14:16])
+		b = b[16:]
+	case linkTypeSLL2:
+		if len(b) < 20 {
+			return nil, false
+		}
+		et = binary.BigEndian.Uint16(b[0:2])
+		b = b[20:]
+	case linkTypeNull, linkTypeLoop:
+		if len(b) < 4 {
+			return nil, false
+		}
+		b = b[4:]
+		et = 0
+	case linkTypeRaw, linkTypeIPv4, linkTypeIPv6:
+		et = 0
+	default:
+		return nil, false
+	}
+	if len(b) == 0 {
+		return nil, false
+	}
+	switch et {
+	case 0:
+		// Ether type is not known, relying on IP version
+		return b, b[0]>>4 == 4 || b[0]>>4 == 6
+	case etherTypeIPv4, etherTypeIPv6:
+		return b, true
+	}
+
+	return nil, false
+}
+
+func (pr *pcapReader) processIP(ts time.Time, b []byte) {
+	var src, dst net.IP
+	var tcp []byte
+	switch b[0] >> 4 {
+	case 4:
+		if len(b) < 20 {
+			return
+		}
+		ihl := int(b[0]&0x0f) * 4
+		total := int(binary.BigEndian.Uint16(b[2:4]))
+		if ihl < 20 || total < ihl || total > len(b) {
+			return
+		}
+		if b[9] != protoTCP {
+			return
+		}
+		// Fragmented packets are not reassembled
+		if binary.BigEndian.Uint16(b[6:8])&0x3fff != 0 {
+			return
+		}
+		src = net.IP(b[12:16])
+		dst = net.IP(b[16:20])
+		tcp = b[ihl:total]
+	case 6:
+		if len(b) < 40 {
+			return
+		}
+		total := 40 + int(binary.BigEndian.Uint16(b[4:6]))
+		if total > len(b) || b[6] != protoTCP {
+			return
+		}
+		src = net.IP(b[8:24])
+		dst = net.IP(b[24:40])
+		tcp = b[40:total]
+	default:
+		return
+	}
+	if len(tcp) < 20 {
+		return
+	}
+	sport := binary.BigEndian.Uint16(tcp[0:2])
+	dport := binary.BigEndian.Uint16(tcp[2:4])
+	if pr.port != 0 && sport != pr.port && dport != pr.port {
+		return
+	}
+	seq := binary.BigEndian.Uint32(tcp[4:8])
+	off := int(tcp[12]>>4) * 4
+	if off < 20 || off > len(tcp) {
+		return
+	}
+	flags := tcp[13]
+	router := net.JoinHostPort(src.String(), strconv.Itoa(int(sport)))
+	key := router + "-" + net.JoinHostPort(dst.String(), strconv.Itoa(int(dport)))
+	s, ok := pr.streams[key]
+	if !ok {
+		s = &tcpStream{
+			router:  router,
+			pending: make(map[uint32][]byte),
+		}
+		pr.streams[key] = s
+	}
+	// SYN flag
+	if flags&0x02 != 0 {
+		s.synced = true
+		s.nextSeq = seq + 1
+		s.data = nil
+		s.broken = false
+		s.pending = make(map[uint32][]byte)
+		return
+	}
+	// FIN or RST flags close the stream
+	closing := flags&0x05 != 0
+	payload := tcp[off:]
+	if len(payload) != 0 && !s.broken {
+		if !s.synced {
+			s.synced = true
+			s.nextSeq = seq
+		}
+		p := make([]byte, len(payload))
+		copy(p, payload)
+		s.pending[seq] = p
+		pr.reassemble(ts, s)
+	}
+	if closing {
+		delete(pr.streams, key)
+	}
+}
+
+// reassemble moves in order segments into the stream data and extracts complete BMP messages
+func (pr *pcapReader) reassemble(ts time.Time, s *tcpStream) {
+	for {
+		progress := false
+		seqs := make([]uint32, 0, len(s.pending))
+		for seq := range s.pending {
+			seqs = append(seqs, seq)
+		}
+		sort.Slice(seqs, func(i, j int) bool { return int32(seqs[i]-s.nextSeq) < int32(seqs[j]-s.nextSeq) })
+		for _, seq := range seqs {
+			p := s.pending[seq]
+			diff := int32(seq - s.nextSeq)
+			if diff > 0 {
+				// Gap, waiting for missing segments
+				break
+			}
+			delete(s.pending, seq)
+			// Retransmission overlapping already received data
+			if int(-diff) >= len(p) {
+				continue
+			}
+			p = p[-diff:]
+			s.data = append(s.data, p...)
+			s.nextSeq += uint32(len(p))
+			progress = true
+		}
+		if !progress {
+			break
+		}
+	}
+	for len(s.data) >= bmp.CommonHeaderLength {
+		ch, err := bmp.UnmarshalCommonHeader(s.data[:bmp.CommonHeaderLength])
+		if err == nil && (ch.MessageLength < bmp.CommonHeaderLength || int(ch.MessageLength) > pr.max) {
+			err = fmt.Errorf("invalid BMP message length %d", ch.MessageLength)
+		}
+		if err != nil {
+			glog.Warningf("stream from %s does not carry valid BMP messages: %+v", s.router, err)
+			s.broken = true
+			s.data = nil
+			s.pending = make(map[uint32][]byte)
+			return
+		}
+		if len(s.data) < int(ch.MessageLength) {
+			// Waiting for the rest of the message
+			return
+		}
+		msg := make([]byte, ch.MessageLength)
+		copy(msg, s.data)
+		pr.ready = append(pr.ready, &Record{
+			Timestamp: ts,
+			Router:    s.router,
+			Message:   msg,
+		})
+		s.data = s.data[len(msg):]
+	}
+}
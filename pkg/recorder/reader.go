@@ -0,0 +1,159 @@
+package recorder
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+// Reader defines a method to read raw BMP messages records, Next returns io.EOF
+// when no more records are available.
+type Reader interface {
+	Next() (*Record, error)
+}
+
+// ReaderOption defines an optional parameter of the Reader
+type ReaderOption func(*readerOptions)
+
+type readerOptions struct {
+	maxMessageLength int
+}
+
+// WithMaxMessageLength sets the maximum length of BMP message accepted by the Reader, a longer
+// message is reported as an error, bmp.DefaultMaxMessageLength is used when not set or when l is not positive.
+func WithMaxMessageLength(l int) ReaderOption {
+	return func(o *readerOptions) {
+		if l > 0 {
+			o.maxMessageLength = l
+		}
+	}
+}
+
+func makeReaderOptions(opts []ReaderOption) readerOptions {
+	o := readerOptions{
+		maxMessageLength: bmp.DefaultMaxMessageLength,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+type captureReader struct {
+	r   *bufio.Reader
+	max int
+}
+
+func (c *captureReader) Next() (*Record, error) {
+	hdr := make([]byte, 10)
+	if _, err := io.ReadFull(c.r, hdr); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("truncated capture record header")
+		}
+		return nil, err
+	}
+	r := &Record{
+		Timestamp: time.Unix(0, int64(binary.BigEndian.Uint64(hdr[0:8]))),
+	}
+	router := make([]byte, binary.BigEndian.Uint16(hdr[8:10]))
+	if _, err := io.ReadFull(c.r, router); err != nil {
+		return nil, fmt.Errorf("truncated capture record router address with error: %+v", err)
+	}
+	r.Router = string(router)
+	l := make([]byte, 4)
+	if _, err := io.ReadFull(c.r, l); err != nil {
+		return nil, fmt.Errorf("truncated capture record message length with error: %+v", err)
+	}
+	ml := binary.BigEndian.Uint32(l)
+	if ml < bmp.CommonHeaderLength || ml > uint32(c.max) {
+		return nil, fmt.Errorf("invalid capture record message length %d", ml)
+	}
+	r.Message = make([]byte, ml)
+	if _, err := io.ReadFull(c.r, r.Message); err != nil {
+		return nil, fmt.Errorf("truncated capture record message with error: %+v", err)
+	}
+
+	return r, nil
+}
+
+// streamReader splits a raw stream of BMP messages, as sent by a router, into records.
+type streamReader struct {
+	r   *bufio.Reader
+	max int
+}
+
+func (s *streamReader) Next() (*Record, error) {
+	hdr := make([]byte, bmp.CommonHeaderLength)
+	if _, err := io.ReadFull(s.r, hdr); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("truncated BMP message common header")
+		}
+		return nil, err
+	}
+	msg, err := readBMPMessage(hdr, s.r, s.max)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Record{
+		Message: msg,
+	}, nil
+}
+
+// readBMPMessage validates BMP Common Header and reads the rest of BMP message from r,
+// it returns the complete message including the Common Header, which must not exceed max length.
+func readBMPMessage(hdr []byte, r io.Reader, max int) ([]byte, error) {
+	ch, err := bmp.UnmarshalCommonHeader(hdr)
+	if err != nil {
+		return nil, err
+	}
+	if ch.MessageLength < bmp.CommonHeaderLength || int(ch.MessageLength) > max {
+		return nil, fmt.Errorf("invalid BMP message length %d", ch.MessageLength)
+	}
+	msg := make([]byte, ch.MessageLength)
+	copy(msg, hdr)
+	if _, err := io.ReadFull(r, msg[bmp.CommonHeaderLength:]); err != nil {
+		return nil, fmt.Errorf("truncated BMP message with error: %+v", err)
+	}
+
+	return msg, nil
+}
+
+// NewReader returns a Reader for r, the format of the data is detected automatically and can be
+// a gobmp capture file, a pcap file or a raw stream of BMP messages.
+func NewReader(r io.Reader, opts ...ReaderOption) (Reader, error) {
+	o := makeReaderOptions(opts)
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil {
+		return nil, fmt.Errorf("fail to detect the format of the capture with error: %+v", err)
+	}
+	if isPcapMagic(magic) {
+		return newPcapReader(br, 0, o)
+	}
+	if m, err := br.Peek(captureHeaderLength); err == nil && string(m[:len(CaptureMagic)]) == CaptureMagic {
+		if v := binary.BigEndian.Uint16(m[len(CaptureMagic):]); v != CaptureVersion {
+			return nil, fmt.Errorf("unsupported capture version %d", v)
+		}
+		if _, err := br.Discard(captureHeaderLength); err != nil {
+			return nil, err
+		}
+		return &captureReader{r: br, max: o.maxMessageLength}, nil
+	}
+	if magic[0] == 3 {
+		return &streamReader{r: br, max: o.maxMessageLength}, nil
+	}
+
+	return nil, fmt.Errorf("unknown capture format")
+}
+
+// NewPcapReader returns a Reader reassembling BMP messages from TCP streams found in the pcap
+// formatted data, if port is not 0, only TCP streams using this port are processed.
+func NewPcapReader(r io.Reader, port uint16, opts ...ReaderOption) (Reader, error) {
+	return newPcapReader(bufio.NewReader(r), port, makeReaderOptions(opts))
+}
@@ -0,0 +1,215 @@
+package recorder
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// CaptureMagic defines the marker found at the beginning of every capture file
+	CaptureMagic = "GOBMPCAP"
+	// CaptureVersion defines the version of capture file format
+	CaptureVersion = 1
+	// CaptureFileExt defines the extension of capture files
+	CaptureFileExt = ".bmpcap"
+	// captureHeaderLength is the length of the magic followed by 2 bytes of the version
+	captureHeaderLength = 10
+	captureTimeFormat   = "20060102T150405.000000000"
+)
+
+// Record defines a single raw BMP message received from a router.
+// Capture file is a sequence of records following the capture header, each record is encoded as:
+//
+//	8 bytes receive timestamp in nanoseconds since Unix epoch
+//	2 bytes length of the router address
+//	router address as "ip:port" string
+//	4 bytes length of BMP message
+//	raw BMP message including the Common Header
+type Record struct {
+	Timestamp time.Time
+	Router    string
+	Message   []byte
+}
+
+// Serialize generates a slice of bytes from Record structure
+func (r *Record) Serialize() []byte {
+	b := make([]byte, 8+2+len(r.Router)+4+len(r.Message))
+	p := 0
+	binary.BigEndian.PutUint64(b[p:p+8], uint64(r.Timestamp.UnixNano()))
+	p += 8
+	binary.BigEndian.PutUint16(b[p:p+2], uint16(len(r.Router)))
+	p += 2
+	p += copy(b[p:], r.Router)
+	binary.BigEndian.PutUint32(b[p:p+4], uint32(len(r.Message)))
+	p += 4
+	copy(b[p:], r.Message)
+
+	return b
+}
+
+// Recorder defines methods to capture raw BMP messages of BMP sessions
+type Recorder interface {
+	NewSession(router net.Addr) (Session, error)
+}
+
+// Session defines methods to capture raw BMP messages of a single BMP session
+type Session interface {
+	Record(t time.Time, msg []byte) error
+	Close() error
+}
+
+type recorder struct {
+	dir string
+}
+
+type session struct {
+	router string
+	file   *os.File
+}
+
+func (r *recorder) NewSession(router net.Addr) (Session, error) {
+	name := strings.NewReplacer(":", "_", "[", "", "]", "").Replace(router.String())
+	name = filepath.Join(r.dir, name+"_"+time.Now().UTC().Format(captureTimeFormat)+CaptureFileExt)
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+	hdr := make([]byte, captureHeaderLength)
+	copy(hdr, CaptureMagic)
+	binary.BigEndian.PutUint16(hdr[len(CaptureMagic):], CaptureVersion)
+	if _, err := f.Write(hdr); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &session{
+		router: router.String(),
+		file:   f,
+	}, nil
+}
+
+// Record writes a raw BMP message received at time t into the capture file, the record is
+// written with a single write to keep the capture usable if the process crashes.
+func (s *session) Record(t time.Time, msg []byte) error {
+	r := &Record{
+		Timestamp: t,
+		Router:    s.router,
+		Message:   msg,
+	}
+	if _, err := s.file.Write(r.Serialize()); err != nil {
+		return fmt.Errorf("fail to write capture record to %s with error: %+v", s.file.Name(), err)
+	}
+
+	return nil
+}
+
+func (s *session) Close() error {
+	return s.file.Close()
+}
+
+// NewRecorder returns a new instance of raw BMP messages recorder storing capture files
+// in the directory dir, the directory is created if it does not exist.
+func NewRecorder(dir string) (Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &recorder{
+		dir: dir,
+	}, nil
+}
+
+type tee struct {
+	recorders []Recorder
+}
+
+// teeSession passes messages to sessions of all recorders, a session failing to record a message
+// is closed and dropped while the remaining sessions keep recording.
+type teeSession struct {
+	router   string
+	sessions []Session
+}
+
+// NewSession opens sessions of all recorders, recorders failing to open the session are skipped,
+// the error is returned only when no session could be opened.
+func (t *tee) NewSession(router net.Addr) (Session, error) {
+	s := &teeSession{
+		router:   router.String(),
+		sessions: make([]Session, 0, len(t.recorders)),
+	}
+	var err error
+	for _, r := range t.recorders {
+		rs, e := r.NewSession(router)
+		if e != nil {
+			glog.Errorf("fail to open recorder session for %s with error: %+v", s.router, e)
+			err = e
+			continue
+		}
+		s.sessions = append(s.sessions, rs)
+	}
+	if len(s.sessions) == 0 {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Record passes the message to all sessions, a failing session is closed and dropped,
+// the error is returned only when no sessions are left.
+func (s *teeSession) Record(t time.Time, msg []byte) error {
+	var err error
+	sessions := s.sessions[:0]
+	for _, rs := range s.sessions {
+		if e := rs.Record(t, msg); e != nil {
+			glog.Errorf("fail to record BMP message of %s, dropping the recorder session with error: %+v", s.router, e)
+			rs.Close()
+			err = e
+			continue
+		}
+		sessions = append(sessions, rs)
+	}
+	s.sessions = sessions
+	if len(s.sessions) == 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (s *teeSession) Close() error {
+	var err error
+	for _, rs := range s.sessions {
+		if e := rs.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	s.sessions = nil
+
+	return err
+}
+
+// NewTee returns a Recorder passing raw BMP messages to all recorders, nil recorders are skipped
+// and nil is returned when no recorders are left.
+func NewTee(recorders ...Recorder) Recorder {
+	t := &tee{}
+	for _, r := range recorders {
+		if r != nil {
+			t.recorders = append(t.recorders, r)
+		}
+	}
+	switch len(t.recorders) {
+	case 0:
+		return nil
+	case 1:
+		return t.recorders[0]
+	}
+
+	return t
+}
@@ -0,0 +1,386 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// bmpMessages carries Initiation and Peer Up messages
+var bmpMessages = [][]byte{
+	{3, 0, 0, 0, 32, 4, 0, 1, 0, 10, 32, 55, 46, 50, 46, 49, 46, 50, 51, 73, 0, 2, 0, 8, 120, 114, 118, 57, 107, 45, 114, 49},
+	{3, 0, 0, 0, 234, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 192, 168, 80, 103, 0, 0, 19, 206, 57, 112, 1, 254, 94, 98, 129, 171, 0, 0, 215, 126, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 192, 168, 80, 128, 0, 179, 131, 152, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 0, 91, 1, 4, 19, 206, 0, 90, 192, 168, 8, 8, 62, 2, 6, 1, 4, 0, 1, 0, 1, 2, 6, 1, 4, 0, 1, 0, 4, 2, 6, 1, 4, 0, 1, 0, 128, 2, 2, 128, 0, 2, 2, 2, 0, 2, 6, 65, 4, 0, 0, 19, 206, 2, 20, 5, 18, 0, 1, 0, 1, 0, 2, 0, 1, 0, 2, 0, 2, 0, 1, 0, 128, 0, 2, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 0, 75, 1, 4, 19, 206, 0, 90, 57, 112, 1, 254, 46, 2, 44, 2, 0, 1, 4, 0, 1, 0, 1, 1, 4, 0, 2, 0, 1, 1, 4, 0, 1, 0, 4, 1, 4, 0, 2, 0, 4, 1, 4, 0, 1, 0, 128, 1, 4, 0, 2, 0, 128, 65, 4, 0, 0, 19, 206},
+}
+
+func readAll(t *testing.T, r Reader) []*Record {
+	records := make([]*Record, 0)
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read record with error: %+v", err)
+		}
+		records = append(records, rec)
+	}
+
+	return records
+}
+
+func TestCaptureRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recorder")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory with error: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+	rec, err := NewRecorder(dir)
+	if err != nil {
+		t.Fatalf("failed to create recorder with error: %+v", err)
+	}
+	router := &net.TCPAddr{IP: net.ParseIP("192.168.80.103"), Port: 32000}
+	s, err := rec.NewSession(router)
+	if err != nil {
+		t.Fatalf("failed to create session with error: %+v", err)
+	}
+	ts := time.Unix(1599168269, 800)
+	for _, m := range bmpMessages {
+		if err := s.Record(ts, m); err != nil {
+			t.Fatalf("failed to record message with error: %+v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("failed to close session with error: %+v", err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*"+CaptureFileExt))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected a single capture file but found %+v", files)
+	}
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatalf("failed to open capture file with error: %+v", err)
+	}
+	defer f.Close()
+	r, err := NewReader(f)
+	if err != nil {
+		t.Fatalf("failed to create reader with error: %+v", err)
+	}
+	records := readAll(t, r)
+	if len(records) != len(bmpMessages) {
+		t.Fatalf("expected %d records but got %d", len(bmpMessages), len(records))
+	}
+	for i, r := range records {
+		if !r.Timestamp.Equal(ts) || r.Router != router.String() || !reflect.DeepEqual(r.Message, bmpMessages[i]) {
+			t.Fatalf("record %d does not match, got: %+v", i, r)
+		}
+	}
+}
+
+func TestRawStream(t *testing.T) {
+	r, err := NewReader(bytes.NewReader(append(append([]byte{}, bmpMessages[0]...), bmpMessages[1]...)))
+	if err != nil {
+		t.Fatalf("failed to create reader with error: %+v", err)
+	}
+	records := readAll(t, r)
+	if len(records) != len(bmpMessages) {
+		t.Fatalf("expected %d records but got %d", len(bmpMessages), len(records))
+	}
+}
+
+// tcpPacket builds Ethernet frame carrying IPv4 TCP segment
+func tcpPacket(seq uint32, flags byte, payload []byte) []byte {
+	b := make([]byte, 14+20+20+len(payload))
+	binary.BigEndian.PutUint16(b[12:14], etherTypeIPv4)
+	ip := b[14:]
+	ip[0] = 0x45
+	binary.BigEndian.PutUint16(ip[2:4], uint16(40+len(payload)))
+	ip[9] = protoTCP
+	copy(ip[12:16], net.ParseIP("192.168.80.103").To4())
+	copy(ip[16:20], net.ParseIP("192.168.80.1").To4())
+	tcp := ip[20:]
+	binary.BigEndian.PutUint16(tcp[0:2], 32000)
+	binary.BigEndian.PutUint16(tcp[2:4], 5000)
+	binary.BigEndian.PutUint32(tcp[4:8], seq)
+	tcp[12] = 5 << 4
+	tcp[13] = flags
+	copy(tcp[20:], payload)
+
+	return b
+}
+
+func TestPcapReassembly(t *testing.T) {
+	stream := append(append([]byte{}, bmpMessages[0]...), bmpMessages[1]...)
+	// Splitting the stream in 3 segments, the second message is split between segments
+	// and segments arrive out of order with a retransmission.
+	seq := uint32(1000)
+	seg1 := stream[:40]
+	seg2 := stream[40:100]
+	seg3 := stream[100:]
+	packets := [][]byte{
+		tcpPacket(seq-1, 0x02, nil),
+		tcpPacket(seq, 0x18, seg1),
+		tcpPacket(seq+100, 0x18, seg3),
+		tcpPacket(seq, 0x18, seg1),
+		tcpPacket(seq+40, 0x18, seg2),
+	}
+	var buf bytes.Buffer
+	hdr := make([]byte, pcapGlobalHeaderLength)
+	binary.LittleEndian.PutUint32(hdr[0:4], pcapMagicMicro)
+	binary.LittleEndian.PutUint16(hdr[4:6], 2)
+	binary.LittleEndian.PutUint16(hdr[6:8], 4)
+	binary.LittleEndian.PutUint32(hdr[16:20], 65535)
+	binary.LittleEndian.PutUint32(hdr[20:24], linkTypeEthernet)
+	buf.Write(hdr)
+	for i, p := range packets {
+		rh := make([]byte, pcapRecordHeaderLength)
+		binary.LittleEndian.PutUint32(rh[0:4], uint32(1599168269+i))
+		binary.LittleEndian.PutUint32(rh[8:12], uint32(len(p)))
+		binary.LittleEndian.PutUint32(rh[12:16], uint32(len(p)))
+		buf.Write(rh)
+		buf.Write(p)
+	}
+	tests := []struct {
+		name   string
+		port   uint16
+		expect int
+	}{
+		{
+			name:   "all ports",
+			port:   0,
+			expect: 2,
+		},
+		{
+			name:   "matching port",
+			port:   5000,
+			expect: 2,
+		},
+		{
+			name:   "not matching port",
+			port:   179,
+			expect: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewPcapReader(bytes.NewReader(buf.Bytes()), tt.port)
+			if err != nil {
+				t.Fatalf("failed to create pcap reader with error: %+v", err)
+			}
+			records := readAll(t, r)
+			if len(records) != tt.expect {
+				t.Fatalf("expected %d records but got %d", tt.expect, len(records))
+			}
+			for i, r := range records {
+				if r.Router != "192.168.80.103:32000" || !reflect.DeepEqual(r.Message, bmpMessages[i]) {
+					t.Fatalf("record %d does not match, got: %+v", i, r)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidMessageLength(t *testing.T) {
+	capture := []byte(CaptureMagic)
+	capture = append(capture, 0, CaptureVersion)
+	// Record with the timestamp, no router address and the message length of 4GB
+	capture = append(capture, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff)
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{
+			name:  "capture record longer than maximum BMP message length",
+			input: capture,
+		},
+		{
+			name:  "raw BMP message longer than maximum BMP message length",
+			input: []byte{3, 0x7f, 0xff, 0xff, 0xff, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(bytes.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("failed to create reader with error: %+v", err)
+			}
+			if _, err := r.Next(); err == nil || !strings.Contains(err.Error(), "invalid") {
+				t.Fatalf("expected invalid length error but got: %+v", err)
+			}
+		})
+	}
+}
+
+func TestMaxMessageLengthOption(t *testing.T) {
+	// Initiation message of 10 bytes with empty String TLV
+	msg := []byte{3, 0, 0, 0, 10, 4, 0, 0, 0, 0}
+	capture := []byte(CaptureMagic)
+	capture = append(capture, 0, CaptureVersion)
+	capture = append(capture, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10)
+	capture = append(capture, msg...)
+	tests := []struct {
+		name  string
+		input []byte
+		max   int
+		fail  bool
+	}{
+		{
+			name:  "capture record within default maximum length",
+			input: capture,
+		},
+		{
+			name:  "capture record longer than maximum length",
+			input: capture,
+			max:   8,
+			fail:  true,
+		},
+		{
+			name:  "raw BMP message of maximum length",
+			input: msg,
+			max:   10,
+		},
+		{
+			name:  "raw BMP message longer than maximum length",
+			input: msg,
+			max:   8,
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(bytes.NewReader(tt.input), WithMaxMessageLength(tt.max))
+			if err != nil {
+				t.Fatalf("failed to create reader with error: %+v", err)
+			}
+			rec, err := r.Next()
+			if err != nil {
+				if !tt.fail {
+					t.Fatalf("expected to succeed but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.fail {
+				t.Fatalf("expected to fail but succeeded")
+			}
+			if !bytes.Equal(rec.Message, msg) {
+				t.Fatalf("expected message %v but got %v", msg, rec.Message)
+			}
+		})
+	}
+}
+
+// testRecorder counts recorded messages, its sessions fail to open when failOpen is set
+// and fail to record after failAfter messages when failAfter is not 0.
+type testRecorder struct {
+	failOpen  bool
+	failAfter int
+	recorded  int
+	closed    bool
+}
+
+func (r *testRecorder) NewSession(router net.Addr) (Session, error) {
+	if r.failOpen {
+		return nil, fmt.Errorf("failed to open session")
+	}
+	return r, nil
+}
+
+func (r *testRecorder) Record(t time.Time, msg []byte) error {
+	if r.failAfter != 0 && r.recorded == r.failAfter {
+		return fmt.Errorf("failed to record message")
+	}
+	r.recorded++
+	return nil
+}
+
+func (r *testRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestTee(t *testing.T) {
+	router := &net.TCPAddr{IP: net.ParseIP("192.168.80.103"), Port: 32000}
+	tests := []struct {
+		name         string
+		recorders    []*testRecorder
+		failOpen     bool
+		failRecord   bool
+		expectRecord []int
+		expectClosed []bool
+	}{
+		{
+			name:         "all sessions record",
+			recorders:    []*testRecorder{{}, {}},
+			expectRecord: []int{3, 3},
+			expectClosed: []bool{false, false},
+		},
+		{
+			name:         "session failing to open is skipped",
+			recorders:    []*testRecorder{{failOpen: true}, {}},
+			expectRecord: []int{0, 3},
+			expectClosed: []bool{false, false},
+		},
+		{
+			name:      "no session opened",
+			recorders: []*testRecorder{{failOpen: true}, {failOpen: true}},
+			failOpen:  true,
+		},
+		{
+			name:         "session failing to record is dropped",
+			recorders:    []*testRecorder{{failAfter: 1}, {}},
+			expectRecord: []int{1, 3},
+			expectClosed: []bool{true, false},
+		},
+		{
+			name:         "all sessions failing to record",
+			recorders:    []*testRecorder{{failAfter: 1}, {failAfter: 2}},
+			failRecord:   true,
+			expectRecord: []int{1, 2},
+			expectClosed: []bool{true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorders := make([]Recorder, len(tt.recorders))
+			for i, r := range tt.recorders {
+				recorders[i] = r
+			}
+			s, err := NewTee(recorders...).NewSession(router)
+			if err != nil {
+				if !tt.failOpen {
+					t.Fatalf("expected to open session but failed with error: %+v", err)
+				}
+				return
+			}
+			if tt.failOpen {
+				t.Fatalf("expected to fail to open session but succeeded")
+			}
+			var recErr error
+			for i := 0; i < 3; i++ {
+				if err := s.Record(time.Now(), bmpMessages[0]); err != nil {
+					recErr = err
+					break
+				}
+			}
+			if tt.failRecord != (recErr != nil) {
+				t.Fatalf("expected record failure %t but got error: %+v", tt.failRecord, recErr)
+			}
+			for i, r := range tt.recorders {
+				if r.recorded != tt.expectRecord[i] {
+					t.Errorf("recorder %d: expected %d recorded messages but got %d", i, tt.expectRecord[i], r.recorded)
+				}
+				if r.closed != tt.expectClosed[i] {
+					t.Errorf("recorder %d: expected closed %t but got %t", i, tt.expectClosed[i], r.closed)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+//go:build go1.18
+// +build go1.18
+
+package rtc
+
+import "testing"
+
+func FuzzUnmarshalRTCNLRI(f *testing.F) {
+	f.Add([]byte{0x60, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalRTCNLRI(b)
+	})
+}
@@ -0,0 +1,73 @@
+package rtc
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/tools"
+)
+
+// Route defines a collection of Route Target Constraint NLRI objects
+type Route struct {
+	NLRI []*NLRI
+}
+
+// NLRI defines a single Route Target Constraint NLRI object
+// https://tools.ietf.org/html/rfc4684#section-4
+type NLRI struct {
+	// Length is the prefix length in bits, covering Origin AS and Route Target
+	Length   uint8
+	OriginAS uint32
+	// RouteTarget is 8 bytes of Route Target extended community, bytes beyond the prefix length are set to 0
+	RouteTarget []byte
+}
+
+// IsDefault returns true for the default Route Target Constraint route which matches any Route Target
+func (n *NLRI) IsDefault() bool {
+	return n.Length == 0
+}
+
+// RouteTargetLength returns the number of Route Target bits covered by the prefix
+func (n *NLRI) RouteTargetLength() int {
+	if n.Length < 32 {
+		return 0
+	}
+	return int(n.Length) - 32
+}
+
+// UnmarshalRTCNLRI instantiates Route Target Constraint Route object from a slice of bytes
+func UnmarshalRTCNLRI(b []byte) (*Route, error) {
+	if glog.V(6) {
+		glog.Infof("RTC NLRI Raw: %s", tools.MessageHex(b))
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("NLRI length is 0")
+	}
+	r := Route{
+		NLRI: make([]*NLRI, 0),
+	}
+	for p := 0; p < len(b); {
+		n := &NLRI{
+			Length: b[p],
+		}
+		p++
+		// Prefix length of 0 is the default route, otherwise Origin AS must be present in full
+		if n.Length != 0 && (n.Length < 32 || n.Length > 96) {
+			return nil, fmt.Errorf("invalid Route Target Constraint prefix length %d", n.Length)
+		}
+		l := (int(n.Length) + 7) / 8
+		if p+l > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal Route Target Constraint NLRI of length %d", n.Length)
+		}
+		if l != 0 {
+			n.OriginAS = binary.BigEndian.Uint32(b[p : p+4])
+			n.RouteTarget = make([]byte, 8)
+			copy(n.RouteTarget, b[p+4:p+l])
+		}
+		r.NLRI = append(r.NLRI, n)
+		p += l
+	}
+
+	return &r, nil
+}
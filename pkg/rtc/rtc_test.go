@@ -0,0 +1,72 @@
+package rtc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalRTCNLRI(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		expect *Route
+		fail   bool
+	}{
+		{
+			name:  "full route target and default route",
+			input: []byte{0x60, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00},
+			expect: &Route{
+				NLRI: []*NLRI{
+					{
+						Length:      96,
+						OriginAS:    65000,
+						RouteTarget: []byte{0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64},
+					},
+					{
+						Length: 0,
+					},
+				},
+			},
+		},
+		{
+			name:  "route target prefix",
+			input: []byte{0x48, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x02, 0xfd, 0xe8, 0x00},
+			expect: &Route{
+				NLRI: []*NLRI{
+					{
+						Length:      72,
+						OriginAS:    65000,
+						RouteTarget: []byte{0x00, 0x02, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x00},
+					},
+				},
+			},
+		},
+		{
+			name:  "partial origin as",
+			input: []byte{0x10, 0x00, 0x00},
+			fail:  true,
+		},
+		{
+			name:  "truncated route target",
+			input: []byte{0x60, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x02},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalRTCNLRI(tt.input)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("expected %+v does not match computed %+v", tt.expect, got)
+			}
+		})
+	}
+}
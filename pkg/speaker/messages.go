@@ -0,0 +1,231 @@
+package speaker
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+)
+
+const (
+	bgpMarkerLength = 16
+	bgpHeaderLength = 19
+	// bgpMaxMessageLength defines the maximum BGP message length without Extended Message capability
+	bgpMaxMessageLength = 4096
+	// asTrans defines AS_TRANS used in 2 bytes AS fields by 4 bytes AS speakers
+	asTrans = 23456
+)
+
+// Peer defines a BGP peer emulated by the speaker
+type Peer struct {
+	Address  net.IP
+	AS       uint32
+	BGPID    net.IP
+	LocalAS  uint32
+	LocalIP  net.IP
+	LocalID  net.IP
+	HoldTime uint16
+}
+
+// Route defines an IPv4 prefix advertised or withdrawn by a peer
+type Route struct {
+	Prefix net.IP
+	Length uint8
+}
+
+// Attributes defines path attributes sent with advertised routes
+type Attributes struct {
+	ASPath  []uint32
+	Nexthop net.IP
+	MED     uint32
+}
+
+func bmpMessage(msgType byte, body ...[]byte) []byte {
+	l := bmp.CommonHeaderLength
+	for _, b := range body {
+		l += len(b)
+	}
+	ch := &bmp.CommonHeader{
+		Version:       3,
+		MessageLength: int32(l),
+		MessageType:   msgType,
+	}
+	b, _ := ch.Serialize()
+	for _, e := range body {
+		b = append(b, e...)
+	}
+
+	return b
+}
+
+func bmpTLV(t uint16, v []byte) []byte {
+	b := make([]byte, 4+len(v))
+	binary.BigEndian.PutUint16(b[0:2], t)
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(v)))
+	copy(b[4:], v)
+
+	return b
+}
+
+func (p *Peer) perPeerHeader(ts time.Time) []byte {
+	ph := &bmp.PerPeerHeader{
+		PeerType:          0,
+		PeerDistinguisher: make([]byte, 8),
+		PeerAddress:       make([]byte, 16),
+		PeerAS:            int32(p.AS),
+		PeerBGPID:         make([]byte, 4),
+		PeerTimestamp:     make([]byte, 8),
+	}
+	if a := p.Address.To4(); a != nil {
+		copy(ph.PeerAddress[12:], a)
+	} else {
+		ph.FlagV = true
+		copy(ph.PeerAddress, p.Address.To16())
+	}
+	copy(ph.PeerBGPID, p.BGPID.To4())
+	binary.BigEndian.PutUint32(ph.PeerTimestamp[0:4], uint32(ts.Unix()))
+	binary.BigEndian.PutUint32(ph.PeerTimestamp[4:8], uint32(ts.Nanosecond()/1000))
+	b, _ := ph.Serialize()
+
+	return b
+}
+
+func bgpMessage(msgType byte, body []byte) []byte {
+	b := make([]byte, bgpHeaderLength+len(body))
+	for i := 0; i < bgpMarkerLength; i++ {
+		b[i] = 0xff
+	}
+	binary.BigEndian.PutUint16(b[16:18], uint16(len(b)))
+	b[18] = msgType
+	copy(b[bgpHeaderLength:], body)
+
+	return b
+}
+
+// bgpOpen builds BGP Open message advertising IPv4 Unicast and 4 bytes AS capabilities
+func bgpOpen(as uint32, holdTime uint16, id net.IP) []byte {
+	caps := []byte{
+		// Multiprotocol Extensions AFI 1 SAFI 1
+		2, 6, 1, 4, 0, 1, 0, 1,
+		// Support for 4-octet AS number
+		2, 6, 65, 4, 0, 0, 0, 0,
+	}
+	binary.BigEndian.PutUint32(caps[12:16], as)
+	b := make([]byte, 10+len(caps))
+	b[0] = 4
+	myAS := uint16(as)
+	if as > 0xffff {
+		myAS = asTrans
+	}
+	binary.BigEndian.PutUint16(b[1:3], myAS)
+	binary.BigEndian.PutUint16(b[3:5], holdTime)
+	copy(b[5:9], id.To4())
+	b[9] = byte(len(caps))
+	copy(b[10:], caps)
+
+	return bgpMessage(1, b)
+}
+
+// Initiation builds BMP Initiation message
+func Initiation(sysName, sysDescr string) []byte {
+	return bmpMessage(bmp.InitiationMsg, bmpTLV(2, []byte(sysName)), bmpTLV(1, []byte(sysDescr)))
+}
+
+// Termination builds BMP Termination message with the reason "Session administratively closed"
+func Termination() []byte {
+	return bmpMessage(bmp.TerminationMsg, bmpTLV(1, []byte{0, 0}))
+}
+
+// PeerUp builds BMP Peer Up message for the peer
+func (p *Peer) PeerUp(ts time.Time) []byte {
+	b := make([]byte, 20)
+	if a := p.LocalIP.To4(); a != nil {
+		copy(b[12:16], a)
+	} else {
+		copy(b[0:16], p.LocalIP.To16())
+	}
+	binary.BigEndian.PutUint16(b[16:18], 179)
+	binary.BigEndian.PutUint16(b[18:20], 179)
+	sent := bgpOpen(p.LocalAS, p.HoldTime, p.LocalID)
+	rcvd := bgpOpen(p.AS, p.HoldTime, p.BGPID)
+
+	return bmpMessage(bmp.PeerUpMsg, p.perPeerHeader(ts), b, sent, rcvd)
+}
+
+// PeerDown builds BMP Peer Down message with the reason "Local system closed, no notification"
+func (p *Peer) PeerDown(ts time.Time) []byte {
+	return bmpMessage(bmp.PeerDownMsg, p.perPeerHeader(ts), []byte{2, 0, 0})
+}
+
+func encodeRoutes(routes []Route) []byte {
+	b := make([]byte, 0)
+	for _, r := range routes {
+		l := int(r.Length+7) / 8
+		b = append(b, r.Length)
+		b = append(b, r.Prefix.To4()[:l]...)
+	}
+
+	return b
+}
+
+func pathAttribute(flags, t byte, v []byte) []byte {
+	if len(v) > 0xff {
+		b := []byte{flags | 0x10, t, 0, 0}
+		binary.BigEndian.PutUint16(b[2:4], uint16(len(v)))
+		return append(b, v...)
+	}
+
+	return append([]byte{flags, t, byte(len(v))}, v...)
+}
+
+func (a *Attributes) encode() []byte {
+	b := make([]byte, 0)
+	// ORIGIN IGP
+	b = append(b, pathAttribute(0x40, 1, []byte{0})...)
+	// AS_PATH with a single AS_SEQUENCE of 4 bytes ASes
+	path := make([]byte, 0)
+	if len(a.ASPath) != 0 {
+		path = append(path, 2, byte(len(a.ASPath)))
+		for _, as := range a.ASPath {
+			e := make([]byte, 4)
+			binary.BigEndian.PutUint32(e, as)
+			path = append(path, e...)
+		}
+	}
+	b = append(b, pathAttribute(0x40, 2, path)...)
+	// NEXT_HOP
+	b = append(b, pathAttribute(0x40, 3, a.Nexthop.To4())...)
+	// MULTI_EXIT_DISC
+	med := make([]byte, 4)
+	binary.BigEndian.PutUint32(med, a.MED)
+	b = append(b, pathAttribute(0x80, 4, med)...)
+
+	return b
+}
+
+func bgpUpdate(withdrawn []Route, attrs *Attributes, nlri []Route) []byte {
+	w := encodeRoutes(withdrawn)
+	var pa []byte
+	if attrs != nil && len(nlri) != 0 {
+		pa = attrs.encode()
+	}
+	n := encodeRoutes(nlri)
+	b := make([]byte, 2+len(w)+2+len(pa)+len(n))
+	p := 0
+	binary.BigEndian.PutUint16(b[p:p+2], uint16(len(w)))
+	p += 2
+	p += copy(b[p:], w)
+	binary.BigEndian.PutUint16(b[p:p+2], uint16(len(pa)))
+	p += 2
+	p += copy(b[p:], pa)
+	copy(b[p:], n)
+
+	return bgpMessage(2, b)
+}
+
+// RouteMonitor builds BMP Route Monitoring message carrying BGP Update with withdrawn
+// and advertised routes, the caller is responsible to keep BGP Update within 4096 bytes.
+func (p *Peer) RouteMonitor(ts time.Time, withdrawn []Route, attrs *Attributes, nlri []Route) []byte {
+	return bmpMessage(bmp.RouteMonitorMsg, p.perPeerHeader(ts), bgpUpdate(withdrawn, attrs, nlri))
+}
@@ -0,0 +1,316 @@
+package speaker
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/recorder"
+)
+
+// Stats defines counters of BMP messages sent to the collector, counters are safe for concurrent use.
+type Stats struct {
+	messages uint64
+	bytes    uint64
+	start    time.Time
+}
+
+// NewStats returns a new instance of Stats with the start time set to now
+func NewStats() *Stats {
+	return &Stats{
+		start: time.Now(),
+	}
+}
+
+func (s *Stats) add(l int) {
+	atomic.AddUint64(&s.messages, 1)
+	atomic.AddUint64(&s.bytes, uint64(l))
+}
+
+// Messages returns the number of BMP messages sent
+func (s *Stats) Messages() uint64 {
+	return atomic.LoadUint64(&s.messages)
+}
+
+// Bytes returns the number of bytes sent
+func (s *Stats) Bytes() uint64 {
+	return atomic.LoadUint64(&s.bytes)
+}
+
+// String returns throughput since the start
+func (s *Stats) String() string {
+	m, b := s.Messages(), s.Bytes()
+	d := time.Since(s.start).Seconds()
+	if d == 0 {
+		d = 1
+	}
+	return fmt.Sprintf("%d messages %d bytes in %.3f seconds, %.1f msg/s %.3f MB/s", m, b, d, float64(m)/d, float64(b)/d/1e6)
+}
+
+// Report logs throughput every interval until stop is closed
+func (s *Stats) Report(interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var lm, lb uint64
+	for {
+		select {
+		case <-ticker.C:
+			m, b := s.Messages(), s.Bytes()
+			glog.Infof("last %s: %.1f msg/s %.3f MB/s, total: %s", interval, float64(m-lm)/interval.Seconds(), float64(b-lb)/interval.Seconds()/1e6, s)
+			lm, lb = m, b
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Speaker defines a BMP speaker sending BMP messages to a collector
+type Speaker struct {
+	conn  io.WriteCloser
+	stats *Stats
+}
+
+// Send sends a single BMP message to the collector
+func (s *Speaker) Send(msg []byte) error {
+	if _, err := s.conn.Write(msg); err != nil {
+		return err
+	}
+	s.stats.add(len(msg))
+
+	return nil
+}
+
+// Close closes the connection to the collector
+func (s *Speaker) Close() error {
+	return s.conn.Close()
+}
+
+// NewSpeaker returns a new instance of Speaker sending BMP messages over conn
+func NewSpeaker(conn io.WriteCloser, stats *Stats) *Speaker {
+	return &Speaker{
+		conn:  conn,
+		stats: stats,
+	}
+}
+
+// Dial connects to the collector and returns a new instance of Speaker
+func Dial(collector string, stats *Stats) (*Speaker, error) {
+	conn, err := net.Dial("tcp", collector)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSpeaker(conn, stats), nil
+}
+
+// pacer waits between events to follow the schedule, schedule is computed from the start
+// to avoid accumulation of timer errors.
+type pacer struct {
+	start time.Time
+}
+
+func (p *pacer) waitUntil(offset time.Duration, stop <-chan struct{}) bool {
+	d := time.Until(p.start.Add(offset))
+	if d <= 0 {
+		select {
+		case <-stop:
+			return false
+		default:
+			return true
+		}
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// Replay sends records to the collector, records of each router are sent over a dedicated connection.
+// Gaps between records receive timestamps are preserved and divided by speed, when speed is 0,
+// records are sent as fast as possible.
+func Replay(r recorder.Reader, collector string, speed float64, stats *Stats, stop <-chan struct{}) error {
+	speakers := make(map[string]*Speaker)
+	defer func() {
+		for _, s := range speakers {
+			s.Close()
+		}
+	}()
+	p := &pacer{start: time.Now()}
+	var first time.Time
+	for {
+		rec, err := r.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if speed > 0 && !rec.Timestamp.IsZero() {
+			if first.IsZero() {
+				first = rec.Timestamp
+			}
+			if !p.waitUntil(time.Duration(float64(rec.Timestamp.Sub(first))/speed), stop) {
+				return nil
+			}
+		} else {
+			select {
+			case <-stop:
+				return nil
+			default:
+			}
+		}
+		s, ok := speakers[rec.Router]
+		if !ok {
+			if s, err = Dial(collector, stats); err != nil {
+				return err
+			}
+			speakers[rec.Router] = s
+		}
+		if err := s.Send(rec.Message); err != nil {
+			return err
+		}
+	}
+}
+
+// SyntheticConfig defines parameters of a synthetic BMP session
+type SyntheticConfig struct {
+	// RouterIP is the address of the emulated router and the local address of all peers
+	RouterIP net.IP
+	// RouterAS is the local AS of the emulated router
+	RouterAS uint32
+	// Peers is the number of emulated peers, peers get consecutive addresses and ASes
+	Peers int
+	// PeerIP is the address of the first peer
+	PeerIP net.IP
+	// PeerAS is the AS of the first peer
+	PeerAS uint32
+	// Prefixes is the number of /24 prefixes advertised by each peer
+	Prefixes int
+	// PrefixesPerUpdate is the number of prefixes packed in a single BGP Update of the initial table dump
+	PrefixesPerUpdate int
+	// ChurnRate is the number of Route Monitoring messages per second, each flapping a single prefix
+	ChurnRate float64
+	// Duration is the duration of churn after the initial table dump, 0 runs until stopped
+	Duration time.Duration
+}
+
+func addIP(ip net.IP, n uint32) net.IP {
+	a := make(net.IP, 4)
+	binary.BigEndian.PutUint32(a, binary.BigEndian.Uint32(ip.To4())+n)
+
+	return a
+}
+
+// prefix returns n-th /24 prefix starting from 10.0.0.0
+func prefix(n int) Route {
+	return Route{
+		Prefix: addIP(net.IPv4(10, 0, 0, 0), uint32(n)<<8),
+		Length: 24,
+	}
+}
+
+func (c *SyntheticConfig) peers() []*Peer {
+	peers := make([]*Peer, c.Peers)
+	for i := 0; i < c.Peers; i++ {
+		a := addIP(c.PeerIP, uint32(i))
+		peers[i] = &Peer{
+			Address:  a,
+			AS:       c.PeerAS + uint32(i),
+			BGPID:    a,
+			LocalAS:  c.RouterAS,
+			LocalIP:  c.RouterIP,
+			LocalID:  c.RouterIP,
+			HoldTime: 90,
+		}
+	}
+
+	return peers
+}
+
+func (c *SyntheticConfig) attributes(p *Peer, med uint32) *Attributes {
+	return &Attributes{
+		ASPath:  []uint32{p.AS, 64512 + uint32(rand.Intn(1000))},
+		Nexthop: p.Address,
+		MED:     med,
+	}
+}
+
+// Synthetic runs a synthetic BMP session, it sends Initiation, Peer Up and the initial table
+// of every peer, then it flaps random prefixes at the churn rate and finally sends Peer Down
+// and Termination messages.
+func Synthetic(s *Speaker, c *SyntheticConfig, stop <-chan struct{}) error {
+	if c.Peers <= 0 {
+		return fmt.Errorf("number of peers must be greater than 0")
+	}
+	if c.RouterIP.To4() == nil || c.PeerIP.To4() == nil {
+		return fmt.Errorf("router and peer addresses must be valid IPv4 addresses")
+	}
+	if c.PrefixesPerUpdate <= 0 {
+		c.PrefixesPerUpdate = 1
+	}
+	// A /24 prefix takes 4 bytes, keeping BGP Update within 4096 bytes
+	if max := (bgpMaxMessageLength - bgpHeaderLength - 128) / 4; c.PrefixesPerUpdate > max {
+		c.PrefixesPerUpdate = max
+	}
+	if err := s.Send(Initiation("gobmp-speaker", "gobmp synthetic BMP speaker")); err != nil {
+		return err
+	}
+	peers := c.peers()
+	advertised := make([][]bool, len(peers))
+	for i, p := range peers {
+		if err := s.Send(p.PeerUp(time.Now())); err != nil {
+			return err
+		}
+		advertised[i] = make([]bool, c.Prefixes)
+		for n := 0; n < c.Prefixes; n += c.PrefixesPerUpdate {
+			routes := make([]Route, 0, c.PrefixesPerUpdate)
+			for j := n; j < n+c.PrefixesPerUpdate && j < c.Prefixes; j++ {
+				routes = append(routes, prefix(j))
+				advertised[i][j] = true
+			}
+			if err := s.Send(p.RouteMonitor(time.Now(), nil, c.attributes(p, 0), routes)); err != nil {
+				return err
+			}
+		}
+	}
+	if c.ChurnRate > 0 && c.Prefixes > 0 {
+		pc := &pacer{start: time.Now()}
+		interval := time.Duration(float64(time.Second) / c.ChurnRate)
+		for n := 0; c.Duration == 0 || time.Duration(n)*interval < c.Duration; n++ {
+			if !pc.waitUntil(time.Duration(n)*interval, stop) {
+				break
+			}
+			i := rand.Intn(len(peers))
+			j := rand.Intn(c.Prefixes)
+			var msg []byte
+			if advertised[i][j] {
+				msg = peers[i].RouteMonitor(time.Now(), []Route{prefix(j)}, nil, nil)
+			} else {
+				msg = peers[i].RouteMonitor(time.Now(), nil, c.attributes(peers[i], uint32(n)), []Route{prefix(j)})
+			}
+			advertised[i][j] = !advertised[i][j]
+			if err := s.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+	for _, p := range peers {
+		if err := s.Send(p.PeerDown(time.Now())); err != nil {
+			return err
+		}
+	}
+
+	return s.Send(Termination())
+}
@@ -0,0 +1,146 @@
+package speaker
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sbezverk/gobmp/pkg/bmp"
+	"github.com/sbezverk/gobmp/pkg/recorder"
+)
+
+type buffer struct {
+	bytes.Buffer
+}
+
+func (b *buffer) Close() error {
+	return nil
+}
+
+func TestMessagesRoundTrip(t *testing.T) {
+	p := &Peer{
+		Address:  net.ParseIP("192.168.1.1"),
+		AS:       4200000001,
+		BGPID:    net.ParseIP("192.168.1.1"),
+		LocalAS:  65000,
+		LocalIP:  net.ParseIP("192.168.0.1"),
+		LocalID:  net.ParseIP("192.168.0.1"),
+		HoldTime: 90,
+	}
+	ts := time.Unix(1599168269, 0)
+	pu := p.PeerUp(ts)
+	up, err := bmp.UnmarshalPeerUpMessage(pu[bmp.CommonHeaderLength+bmp.PerPeerHeaderLength:])
+	if err != nil {
+		t.Fatalf("failed to unmarshal Peer Up message with error: %+v", err)
+	}
+	if as, ok := up.ReceivedOpen.Is4BytesASCapable(); !ok || uint32(as) != p.AS {
+		t.Fatalf("expected received Open with 4 bytes AS %d but got %d", p.AS, as)
+	}
+	if up.SentOpen.MyAS != 65000 || up.ReceivedOpen.MyAS != asTrans {
+		t.Fatalf("invalid My AS in Open messages, sent: %d received: %d", up.SentOpen.MyAS, up.ReceivedOpen.MyAS)
+	}
+	routes := []Route{prefix(0), prefix(1), prefix(256)}
+	rm := p.RouteMonitor(ts, nil, &Attributes{ASPath: []uint32{p.AS, 65002}, Nexthop: p.Address, MED: 10}, routes)
+	ph, err := bmp.UnmarshalPerPeerHeader(rm[bmp.CommonHeaderLength:])
+	if err != nil {
+		t.Fatalf("failed to unmarshal Per Peer Header with error: %+v", err)
+	}
+	if ph.GetPeerAddrString() != "192.168.1.1" || uint32(ph.PeerAS) != p.AS {
+		t.Fatalf("invalid Per Peer Header: %+v", ph)
+	}
+	m, err := bmp.UnmarshalBMPRouteMonitorMessage(rm[bmp.CommonHeaderLength+bmp.PerPeerHeaderLength:], nil)
+	if err != nil {
+		t.Fatalf("failed to unmarshal Route Monitor message with error: %+v", err)
+	}
+	if len(m.Update.NLRI) != len(routes) {
+		t.Fatalf("expected %d routes but got %d", len(routes), len(m.Update.NLRI))
+	}
+	if !reflect.DeepEqual(m.Update.NLRI[2].Prefix, []byte{10, 1, 0}) {
+		t.Fatalf("invalid prefix %+v", m.Update.NLRI[2].Prefix)
+	}
+	if m.Update.BaseAttributes.Nexthop != "192.168.1.1" || m.Update.BaseAttributes.MED != 10 {
+		t.Fatalf("invalid base attributes: %+v", m.Update.BaseAttributes)
+	}
+	wd := p.RouteMonitor(ts, routes[:1], nil, nil)
+	m, err = bmp.UnmarshalBMPRouteMonitorMessage(wd[bmp.CommonHeaderLength+bmp.PerPeerHeaderLength:], nil)
+	if err != nil {
+		t.Fatalf("failed to unmarshal Route Monitor message with error: %+v", err)
+	}
+	if len(m.Update.WithdrawnRoutes) != 1 || len(m.Update.NLRI) != 0 {
+		t.Fatalf("expected a single withdrawn route but got: %+v", m.Update)
+	}
+}
+
+func TestSynthetic(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *SyntheticConfig
+		expect uint64
+	}{
+		{
+			name: "2 peers no churn",
+			config: &SyntheticConfig{
+				RouterIP:          net.ParseIP("192.168.0.1"),
+				RouterAS:          65000,
+				Peers:             2,
+				PeerIP:            net.ParseIP("192.168.1.1"),
+				PeerAS:            65001,
+				Prefixes:          250,
+				PrefixesPerUpdate: 100,
+			},
+			// Initiation, 2 x (Peer Up, 3 Route Monitor, Peer Down), Termination
+			expect: 12,
+		},
+		{
+			name: "1 peer with churn",
+			config: &SyntheticConfig{
+				RouterIP:          net.ParseIP("192.168.0.1"),
+				RouterAS:          65000,
+				Peers:             1,
+				PeerIP:            net.ParseIP("192.168.1.1"),
+				PeerAS:            65001,
+				Prefixes:          10,
+				PrefixesPerUpdate: 10,
+				ChurnRate:         1000,
+				Duration:          10 * time.Millisecond,
+			},
+			// Initiation, Peer Up, Route Monitor, 10 flaps, Peer Down, Termination
+			expect: 15,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &buffer{}
+			stats := NewStats()
+			if err := Synthetic(NewSpeaker(b, stats), tt.config, make(chan struct{})); err != nil {
+				t.Fatalf("synthetic session failed with error: %+v", err)
+			}
+			if stats.Messages() != tt.expect {
+				t.Fatalf("expected %d messages but sent %d", tt.expect, stats.Messages())
+			}
+			if stats.Bytes() != uint64(b.Len()) {
+				t.Fatalf("expected %d bytes but sent %d", b.Len(), stats.Bytes())
+			}
+			r, err := recorder.NewReader(bytes.NewReader(b.Bytes()))
+			if err != nil {
+				t.Fatalf("failed to create reader with error: %+v", err)
+			}
+			n := uint64(0)
+			for {
+				if _, err := r.Next(); err != nil {
+					if err != io.EOF {
+						t.Fatalf("failed to read message with error: %+v", err)
+					}
+					break
+				}
+				n++
+			}
+			if n != tt.expect {
+				t.Fatalf("expected %d messages in the stream but found %d", tt.expect, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+//go:build go1.18
+// +build go1.18
+
+package sr
+
+import "testing"
+
+func FuzzUnmarshalSRCapabilityTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRCapabilityTLV(b)
+	})
+}
+
+func FuzzUnmarshalSRCapability(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRCapability(b)
+	})
+}
+
+func FuzzUnmarshalPrefixSIDTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalPrefixSIDTLV(b)
+	})
+}
+
+func FuzzUnmarshalSRLocalBlock(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRLocalBlock(b)
+	})
+}
+
+func FuzzUnmarshalPeerSID(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalPeerSID(b)
+	})
+}
+
+func FuzzUnmarshalSRLocalBlockTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRLocalBlockTLV(b)
+	})
+}
+
+func FuzzUnmarshalAdjacencySIDTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalAdjacencySIDTLV(b)
+	})
+}
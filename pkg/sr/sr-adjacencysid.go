@@ -1,6 +1,8 @@
 package sr
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
@@ -18,6 +20,9 @@ func UnmarshalAdjacencySIDTLV(b []byte) (*AdjacencySIDTLV, error) {
 	if glog.V(6) {
 		glog.Infof("Adjacency SID Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("invalid length of Adjacency SID TLV %d", len(b))
+	}
 	asid := AdjacencySIDTLV{}
 	p := 0
 	asid.Flags = b[p]
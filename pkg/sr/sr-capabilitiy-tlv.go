@@ -22,6 +22,9 @@ func UnmarshalSRCapabilityTLV(b []byte) ([]CapabilityTLV, error) {
 	}
 	caps := make([]CapabilityTLV, 0)
 	for p := 0; p < len(b); {
+		if p+7 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SR Capability TLV")
+		}
 		cap := CapabilityTLV{}
 		r := make([]byte, 4)
 		// Copy 3 bytes of Range into 4 byte slice to convert it into uint32
@@ -33,6 +36,9 @@ func UnmarshalSRCapabilityTLV(b []byte) ([]CapabilityTLV, error) {
 		p += 2
 		l := binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
+		if p+int(l) > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SR Capability TLV of length %d", l)
+		}
 		v := make([]byte, l)
 		copy(v, b[p:p+int(l)])
 		p += int(l)
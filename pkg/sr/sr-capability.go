@@ -1,6 +1,8 @@
 package sr
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
@@ -17,6 +19,9 @@ func UnmarshalSRCapability(b []byte) (*Capability, error) {
 	if glog.V(6) {
 		glog.Infof("SR Capability Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 2 {
+		return nil, fmt.Errorf("invalid length of SR Capability %d", len(b))
+	}
 	cap := Capability{}
 	p := 0
 	cap.Flags = b[p]
@@ -23,6 +23,9 @@ func UnmarshalSRLocalBlockTLV(b []byte) ([]LocalBlockTLV, error) {
 	}
 	tlvs := make([]LocalBlockTLV, 0)
 	for p := 0; p < len(b); {
+		if p+7 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SR Local Block TLV")
+		}
 		tlv := LocalBlockTLV{}
 		r := make([]byte, 4)
 		// Copy 3 bytes of Range into 4 byte slice to convert it into uint32
@@ -34,6 +37,9 @@ func UnmarshalSRLocalBlockTLV(b []byte) ([]LocalBlockTLV, error) {
 		p += 2
 		l := binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
+		if p+int(l) > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SR Local Block TLV of length %d", l)
+		}
 		v := make([]byte, 4)
 		if l == 3 {
 			copy(v[1:], b[p:p+int(l)])
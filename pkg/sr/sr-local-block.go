@@ -1,6 +1,8 @@
 package sr
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
@@ -17,6 +19,9 @@ func UnmarshalSRLocalBlock(b []byte) (*LocalBlock, error) {
 	if glog.V(6) {
 		glog.Infof("SR Local BLock Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 2 {
+		return nil, fmt.Errorf("invalid length of SR Local Block %d", len(b))
+	}
 	lb := LocalBlock{}
 	p := 0
 	lb.Flags = b[p]
@@ -1,6 +1,8 @@
 package sr
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
@@ -21,6 +23,9 @@ func UnmarshalPeerSID(b []byte) (*PeerSID, error) {
 	if glog.V(6) {
 		glog.Infof("Peer SID TLV Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("invalid length of Peer SID TLV %d", len(b))
+	}
 	psid := PeerSID{}
 	p := 0
 	psid.FlagV = b[p]&0x80 == 0x80
@@ -1,6 +1,8 @@
 package sr
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
@@ -18,6 +20,9 @@ func UnmarshalPrefixSIDTLV(b []byte) (*PrefixSIDTLV, error) {
 	if glog.V(6) {
 		glog.Infof("Prefix SID TLV Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("invalid length of Prefix SID TLV %d", len(b))
+	}
 	psid := PrefixSIDTLV{}
 	p := 0
 	psid.Flags = b[p]
@@ -0,0 +1,51 @@
+//go:build go1.18
+// +build go1.18
+
+package srpolicy
+
+import "testing"
+
+func FuzzUnmarshalBSIDSTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalBSIDSTLV(b)
+	})
+}
+
+func FuzzUnmarshalPreferenceSTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalPreferenceSTLV(b)
+	})
+}
+
+func FuzzUnmarshalSRPolicyTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRPolicyTLV(b)
+	})
+}
+
+func FuzzUnmarshalLSNLRI73(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalLSNLRI73(b)
+	})
+}
+
+func FuzzUnmarshalSegmentListSTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSegmentListSTLV(b)
+	})
+}
+
+func FuzzUnmarshalTypeASegment(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalTypeASegment(b)
+	})
+}
+
+func FuzzUnmarshalSegment(f *testing.F) {
+	f.Add(uint8(TypeC), []byte{0x60, 0x80, 0x0a, 0x00, 0x00, 0x01, 0x03, 0xe8, 0x10, 0x00})
+	f.Fuzz(func(t *testing.T, st uint8, b []byte) {
+		if s, err := UnmarshalSegment(SegmentType(st), b); err == nil {
+			s.Normalize()
+		}
+	})
+}
@@ -95,6 +95,10 @@ func (bsid *BindingSID) MarshalJSON() ([]byte, error) {
 			flags: bsid.BSID.GetFlag(),
 			bsid:  bsid.BSID.GetBSID(),
 		}
+		if s, ok := bsid.BSID.(SRv6BSID); ok {
+			sid.eb = s.GetEndpointBehavior()
+			sid.ss = s.GetSIDStructure()
+		}
 		return json.Marshal(&struct {
 			Type BSIDType  `json:"bsid_type,omitempty"`
 			BSID *srv6BSID `json:"bsid,omitempty"`
@@ -206,6 +210,7 @@ func (l *labelBSID) UnmarshalJSON(b []byte) error {
 // SRv6BSID defines SRv6 BSID specific method
 type SRv6BSID interface {
 	GetEndpointBehavior() *srv6.EndpointBehavior
+	GetSIDStructure() *srv6.SIDStructureSubSubTLV
 }
 
 // srv6BSID defines structure when Binding SID sub tlv carries a srv6 as Binding SID
@@ -213,9 +218,11 @@ type srv6BSID struct {
 	flags byte
 	bsid  []byte
 	eb    *srv6.EndpointBehavior
+	ss    *srv6.SIDStructureSubSubTLV
 }
 
 var _ BSID = &srv6BSID{}
+var _ SRv6BSID = &srv6BSID{}
 
 func (s *srv6BSID) GetFlag() byte {
 	return s.flags
@@ -229,14 +236,21 @@ func (s *srv6BSID) GetBSID() []byte {
 func (s *srv6BSID) GetEndpointBehavior() *srv6.EndpointBehavior {
 	return s.eb
 }
+func (s *srv6BSID) GetSIDStructure() *srv6.SIDStructureSubSubTLV {
+	return s.ss
+}
 
 func (s *srv6BSID) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
-		Flags byte   `json:"flags,omitempty"`
-		BSID  []byte `json:"srv6_bsid,omitempty"`
+		Flags        byte                        `json:"flags,omitempty"`
+		BSID         []byte                      `json:"srv6_bsid,omitempty"`
+		EB           *srv6.EndpointBehavior      `json:"endpoint_behavior,omitempty"`
+		SIDStructure *srv6.SIDStructureSubSubTLV `json:"sid_structure,omitempty"`
 	}{
-		Flags: s.flags,
-		BSID:  s.bsid,
+		Flags:        s.flags,
+		BSID:         s.bsid,
+		EB:           s.eb,
+		SIDStructure: s.ss,
 	})
 }
 
@@ -255,6 +269,16 @@ func (s *srv6BSID) UnmarshalJSON(b []byte) error {
 			return err
 		}
 	}
+	if b, ok := objmap["endpoint_behavior"]; ok {
+		if err := json.Unmarshal(b, &s.eb); err != nil {
+			return err
+		}
+	}
+	if b, ok := objmap["sid_structure"]; ok {
+		if err := json.Unmarshal(b, &s.ss); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -279,13 +303,26 @@ func UnmarshalBSIDSTLV(b []byte) (BSID, error) {
 			flags: b[p],
 			bsid:  v,
 		}
-	case 18:
+	case 18, 26:
 		sid := make([]byte, 16)
 		copy(sid, b[p+2:p+2+16])
-		bsid = &srv6BSID{
+		s := &srv6BSID{
 			flags: b[p],
 			bsid:  sid,
 		}
+		if len(b) == 26 {
+			// SRv6 Binding SID Sub TLV carries optional SRv6 Endpoint Behavior and SID Structure
+			s.eb = &srv6.EndpointBehavior{
+				EndpointBehavior: binary.BigEndian.Uint16(b[p+18 : p+20]),
+			}
+			s.ss = &srv6.SIDStructureSubSubTLV{
+				LocalBlockLength: b[p+22],
+				LocalNodeLength:  b[p+23],
+				FunctionLength:   b[p+24],
+				ArgumentLength:   b[p+25],
+			}
+		}
+		bsid = s
 	default:
 		return nil, fmt.Errorf("invalid length of binding sid stlv")
 	}
@@ -0,0 +1,53 @@
+package srpolicy
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/srv6"
+)
+
+func TestSRv6BSID(t *testing.T) {
+	eb := uint16(71)
+	input := []byte{
+		// Binding SID with S-Flag and SRv6 SID 2001:db8::100
+		0x80, 0x00, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x00,
+		// Endpoint Behavior End.B6.Encaps.Red and SID Structure
+		0x00, 0x47, 0x00, 0x00, 0x20, 0x10, 0x10, 0x00,
+	}
+	bsid, err := UnmarshalBSIDSTLV(input)
+	if err != nil {
+		t.Fatalf("failed with error: %+v", err)
+	}
+	tlv := &TLV{
+		BindingSID: &BindingSID{
+			Type: bsid.GetType(),
+			BSID: bsid,
+		},
+	}
+	expect := &CandidatePath{
+		Preference:        DefaultPreference,
+		BSID:              "2001:db8::100",
+		SpecifiedBSIDOnly: true,
+		EndpointBehavior:  &eb,
+		SIDStructure:      &srv6.SIDStructureSubSubTLV{LocalBlockLength: 32, LocalNodeLength: 16, FunctionLength: 16},
+	}
+	if got := tlv.GetCandidatePath(); !reflect.DeepEqual(expect, got) {
+		t.Logf("Diffs: %+v", deep.Equal(expect, got))
+		t.Fatalf("expected candidate path %+v does not match computed %+v", expect, got)
+	}
+	b, err := json.Marshal(tlv.BindingSID)
+	if err != nil {
+		t.Fatalf("failed to marshal binding sid with error: %+v", err)
+	}
+	got := &BindingSID{}
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatalf("failed to unmarshal binding sid with error: %+v", err)
+	}
+	if !reflect.DeepEqual(tlv.BindingSID, got) {
+		t.Logf("Diffs: %+v", deep.Equal(tlv.BindingSID, got))
+		t.Fatalf("binding sid %s does not match original %+v", string(b), tlv.BindingSID)
+	}
+}
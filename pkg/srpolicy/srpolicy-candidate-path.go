@@ -0,0 +1,107 @@
+package srpolicy
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+
+	"github.com/sbezverk/gobmp/pkg/srv6"
+)
+
+const (
+	// DefaultPreference defines the preference of the candidate path which does not carry Preference Sub TLV
+	DefaultPreference = 100
+	// DefaultWeight defines the weight of the segment list which does not carry Weight Sub TLV
+	DefaultWeight = 1
+)
+
+var enlpNames = map[byte]string{
+	1: "Push IPv4 Explicit NULL",
+	2: "Push IPv6 Explicit NULL",
+	3: "Push IPv4 and IPv6 Explicit NULL",
+	4: "Do not push Explicit NULL",
+}
+
+// NormalizedSegmentList defines a segment list of the candidate path with its weight
+// and the segments in the uniform form
+type NormalizedSegmentList struct {
+	Weight   uint32               `json:"weight"`
+	Segments []*NormalizedSegment `json:"segments,omitempty"`
+}
+
+// CandidatePath defines the attributes of SR Policy candidate path in the uniform form
+// https://tools.ietf.org/html/rfc9256#section-2
+type CandidatePath struct {
+	Preference uint32 `json:"preference"`
+	// BSID is the label or SRv6 SID of Binding SID
+	BSID              string                   `json:"bsid,omitempty"`
+	SpecifiedBSIDOnly bool                     `json:"specified_bsid_only,omitempty"`
+	DropUponInvalid   bool                     `json:"drop_upon_invalid,omitempty"`
+	ENLP              byte                     `json:"enlp,omitempty"`
+	ENLPName          string                   `json:"enlp_name,omitempty"`
+	Priority          byte                     `json:"priority,omitempty"`
+	PolicyName        string                   `json:"policy_name,omitempty"`
+	PathName          string                   `json:"path_name,omitempty"`
+	SegmentLists      []*NormalizedSegmentList `json:"segment_lists,omitempty"`
+	// EndpointBehavior and SIDStructure are set when SRv6 Binding SID carries SRv6 Endpoint Behavior and SID Structure
+	EndpointBehavior *uint16                     `json:"endpoint_behavior,omitempty"`
+	SIDStructure     *srv6.SIDStructureSubSubTLV `json:"sid_structure,omitempty"`
+}
+
+// GetCandidatePath returns the candidate path described by SR Policy TLV, the defaults
+// are applied for Preference and Weight when the corresponding Sub TLVs are not present.
+func (tlv *TLV) GetCandidatePath() *CandidatePath {
+	cp := &CandidatePath{
+		Preference: DefaultPreference,
+		Priority:   tlv.Priority,
+		PolicyName: tlv.Name,
+		PathName:   tlv.PathName,
+	}
+	if tlv.Preference != nil {
+		cp.Preference = tlv.Preference.Preference
+	}
+	if tlv.BindingSID != nil && tlv.BindingSID.BSID != nil {
+		f := tlv.BindingSID.BSID.GetFlag()
+		cp.SpecifiedBSIDOnly = f&0x80 == 0x80
+		cp.DropUponInvalid = f&0x40 == 0x40
+		switch sid := tlv.BindingSID.BSID.GetBSID(); tlv.BindingSID.Type {
+		case LABELBSID:
+			if len(sid) == 4 {
+				cp.BSID = strconv.FormatUint(uint64(binary.BigEndian.Uint32(sid)), 10)
+			}
+		case SRV6BSID:
+			if len(sid) == 16 {
+				cp.BSID = net.IP(sid).String()
+			}
+			if s, ok := tlv.BindingSID.BSID.(SRv6BSID); ok {
+				if eb := s.GetEndpointBehavior(); eb != nil {
+					v := eb.EndpointBehavior
+					cp.EndpointBehavior = &v
+				}
+				cp.SIDStructure = s.GetSIDStructure()
+			}
+		}
+	}
+	if tlv.ENLP != nil {
+		cp.ENLP = tlv.ENLP.ENLP
+		cp.ENLPName = enlpNames[tlv.ENLP.ENLP]
+	}
+	for _, sl := range tlv.SegmentList {
+		if sl == nil {
+			continue
+		}
+		nsl := &NormalizedSegmentList{
+			Weight:   DefaultWeight,
+			Segments: make([]*NormalizedSegment, 0, len(sl.Segment)),
+		}
+		if sl.Weight != nil {
+			nsl.Weight = sl.Weight.Weight
+		}
+		for _, s := range sl.Segment {
+			nsl.Segments = append(nsl.Segments, s.Normalize())
+		}
+		cp.SegmentLists = append(cp.SegmentLists, nsl)
+	}
+
+	return cp
+}
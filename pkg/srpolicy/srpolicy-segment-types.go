@@ -0,0 +1,172 @@
+package srpolicy
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/srv6"
+	"github.com/sbezverk/gobmp/pkg/tools"
+)
+
+var segmentTypeNames = map[SegmentType]string{
+	TypeA: "A",
+	TypeB: "B",
+	TypeC: "C",
+	TypeD: "D",
+	TypeE: "E",
+	TypeF: "F",
+	TypeG: "G",
+	TypeH: "H",
+	TypeI: "I",
+	TypeJ: "J",
+	TypeK: "K",
+}
+
+// NormalizedSegment defines a uniform representation of a Segment of any type
+type NormalizedSegment struct {
+	Type     SegmentType   `json:"segment_type"`
+	TypeName string        `json:"type_name,omitempty"`
+	Flags    *SegmentFlags `json:"flags,omitempty"`
+	// Label is set for SR-MPLS segments
+	Label *uint32 `json:"label,omitempty"`
+	// SID is set for SRv6 segments
+	SID string `json:"sid,omitempty"`
+	// Algorithm is set for segment types carrying SR Algorithm (C, D, I, J and K)
+	Algorithm         *uint8 `json:"algorithm,omitempty"`
+	LocalAddress      string `json:"local_address,omitempty"`
+	RemoteAddress     string `json:"remote_address,omitempty"`
+	LocalInterfaceID  uint32 `json:"local_interface_id,omitempty"`
+	RemoteInterfaceID uint32 `json:"remote_interface_id,omitempty"`
+	// EndpointBehavior and SIDStructure are set when SRv6 segment carries SRv6 Endpoint Behavior and SID Structure
+	EndpointBehavior *uint16                     `json:"endpoint_behavior,omitempty"`
+	SIDStructure     *srv6.SIDStructureSubSubTLV `json:"sid_structure,omitempty"`
+}
+
+// segment defines Segment of types B to K, the segment's fields are kept in the normalized form
+type segment struct {
+	n *NormalizedSegment
+}
+
+var _ Segment = &segment{}
+
+func (s *segment) GetType() SegmentType {
+	return s.n.Type
+}
+
+func (s *segment) GetFlags() *SegmentFlags {
+	return s.n.Flags
+}
+
+func (s *segment) Normalize() *NormalizedSegment {
+	return s.n
+}
+
+func (s *segment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.n)
+}
+
+// UnmarshalSegment instantiates an instance of Segment sub tlv of types B to K,
+// the optional SID, SRv6 Endpoint Behavior and SID Structure are detected by the length.
+func UnmarshalSegment(t SegmentType, b []byte) (Segment, error) {
+	if glog.V(5) {
+		glog.Infof("SR Policy Type %s Segment STLV Raw: %s", segmentTypeNames[t], tools.MessageHex(b))
+	}
+	// Length of the mandatory part of the segment following Flags and SR Algorithm or Reserved byte
+	var l int
+	mpls := true
+	algo := false
+	switch t {
+	case TypeB:
+		l, mpls = 16, false
+	case TypeC:
+		l, algo = 4, true
+	case TypeD:
+		l, algo = 16, true
+	case TypeE, TypeF:
+		l = 8
+	case TypeG:
+		l = 40
+	case TypeH:
+		l = 32
+	case TypeI:
+		l, mpls, algo = 16, false, true
+	case TypeJ:
+		l, mpls, algo = 40, false, true
+	case TypeK:
+		l, mpls, algo = 32, false, true
+	default:
+		return nil, fmt.Errorf("unknown type of segment sub tlv %d", t)
+	}
+	if len(b) < 2+l {
+		return nil, fmt.Errorf("invalid length %d of Type %s Segment STLV", len(b), segmentTypeNames[t])
+	}
+	n := &NormalizedSegment{
+		Type:     t,
+		TypeName: segmentTypeNames[t],
+		Flags:    NewSegmentFlags(b[0]),
+	}
+	if algo {
+		a := b[1]
+		n.Algorithm = &a
+	}
+	p := 2
+	switch t {
+	case TypeB:
+		n.SID = net.IP(b[p : p+16]).String()
+	case TypeC:
+		n.LocalAddress = net.IP(b[p : p+4]).String()
+	case TypeD, TypeI:
+		n.LocalAddress = net.IP(b[p : p+16]).String()
+	case TypeE:
+		n.LocalInterfaceID = binary.BigEndian.Uint32(b[p : p+4])
+		n.LocalAddress = net.IP(b[p+4 : p+8]).String()
+	case TypeF:
+		n.LocalAddress = net.IP(b[p : p+4]).String()
+		n.RemoteAddress = net.IP(b[p+4 : p+8]).String()
+	case TypeG, TypeJ:
+		n.LocalInterfaceID = binary.BigEndian.Uint32(b[p : p+4])
+		n.LocalAddress = net.IP(b[p+4 : p+20]).String()
+		n.RemoteInterfaceID = binary.BigEndian.Uint32(b[p+20 : p+24])
+		n.RemoteAddress = net.IP(b[p+24 : p+40]).String()
+	case TypeH, TypeK:
+		n.LocalAddress = net.IP(b[p : p+16]).String()
+		n.RemoteAddress = net.IP(b[p+16 : p+32]).String()
+	}
+	p += l
+	rest := b[p:]
+	if mpls {
+		switch len(rest) {
+		case 0:
+		case 4:
+			// SR-MPLS SID is encoded the same way as Type A Segment's label
+			label := binary.BigEndian.Uint32(rest) >> 12
+			n.Label = &label
+		default:
+			return nil, fmt.Errorf("invalid length %d of Type %s Segment STLV", len(b), segmentTypeNames[t])
+		}
+		return &segment{n: n}, nil
+	}
+	if t != TypeB && len(rest) >= 16 {
+		n.SID = net.IP(rest[:16]).String()
+		rest = rest[16:]
+	}
+	switch len(rest) {
+	case 0:
+	case 8:
+		eb := binary.BigEndian.Uint16(rest[0:2])
+		n.EndpointBehavior = &eb
+		n.SIDStructure = &srv6.SIDStructureSubSubTLV{
+			LocalBlockLength: rest[4],
+			LocalNodeLength:  rest[5],
+			FunctionLength:   rest[6],
+			ArgumentLength:   rest[7],
+		}
+	default:
+		return nil, fmt.Errorf("invalid length %d of Type %s Segment STLV", len(b), segmentTypeNames[t])
+	}
+
+	return &segment{n: n}, nil
+}
@@ -57,6 +57,7 @@ const (
 type Segment interface {
 	GetType() SegmentType
 	GetFlags() *SegmentFlags
+	Normalize() *NormalizedSegment
 	MarshalJSON() ([]byte, error)
 }
 
@@ -98,26 +99,16 @@ func (sl *SegmentList) UnmarshalJSON(b []byte) error {
 					return err
 				}
 				seg = t
-			case TypeB:
-				fallthrough
-			case TypeC:
-				fallthrough
-			case TypeD:
-				fallthrough
-			case TypeE:
-				fallthrough
-			case TypeF:
-				fallthrough
-			case TypeG:
-				fallthrough
-			case TypeH:
-				fallthrough
-			case TypeI:
-				fallthrough
-			case TypeJ:
-				fallthrough
-			case TypeK:
-				return fmt.Errorf("unsupported type of segment sub tlv %d", segType)
+			case TypeB, TypeC, TypeD, TypeE, TypeF, TypeG, TypeH, TypeI, TypeJ, TypeK:
+				raw, err := json.Marshal(s)
+				if err != nil {
+					return err
+				}
+				n := &NormalizedSegment{}
+				if err := json.Unmarshal(raw, n); err != nil {
+					return err
+				}
+				seg = &segment{n: n}
 			default:
 				return fmt.Errorf("unknown type of segment sub tlv %d", segType)
 
@@ -142,6 +133,10 @@ func UnmarshalSegmentListSTLV(b []byte) (*SegmentList, error) {
 	for p < len(b) {
 		t := int(b[p])
 		p++
+		// Each Sub TLV carries at least 1 byte of length
+		if p >= len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal Segment List Sub TLV %d", t)
+		}
 		switch t {
 		case WEIGHTSTLV:
 			if sl.Weight != nil {
@@ -149,7 +144,7 @@ func UnmarshalSegmentListSTLV(b []byte) (*SegmentList, error) {
 			}
 			l := b[p]
 			p++
-			if l != 6 {
+			if l != 6 || p+int(l) > len(b) {
 				return nil, fmt.Errorf("invalid length %d of raw data for Weight Sub TLV", l)
 			}
 			w := &Weight{
@@ -162,7 +157,7 @@ func UnmarshalSegmentListSTLV(b []byte) (*SegmentList, error) {
 			glog.Infof("Segment of type A")
 			l := b[p]
 			p++
-			if l != 6 {
+			if l != 6 || p+int(l) > len(b) {
 				return nil, fmt.Errorf("invalid length %d of raw data for Type A Segment Sub TLV", l)
 			}
 			s, err := UnmarshalTypeASegment(b[p : p+int(l)])
@@ -171,26 +166,18 @@ func UnmarshalSegmentListSTLV(b []byte) (*SegmentList, error) {
 			}
 			sl.Segment = append(sl.Segment, s)
 			p += int(l)
-		case int(TypeB):
-			glog.Infof("Segment of type B not implemented")
-		case int(TypeC):
-			glog.Infof("Segment of type C not implemented")
-		case int(TypeD):
-			glog.Infof("Segment of type D not implemented")
-		case int(TypeE):
-			glog.Infof("Segment of type E not implemented")
-		case int(TypeF):
-			glog.Infof("Segment of type F not implemented")
-		case int(TypeG):
-			glog.Infof("Segment of type G not implemented")
-		case int(TypeH):
-			glog.Infof("Segment of type H not implemented")
-		case int(TypeI):
-			glog.Infof("Segment of type I not implemented")
-		case int(TypeJ):
-			glog.Infof("Segment of type J not implemented")
-		case int(TypeK):
-			glog.Infof("Segment of type K not implemented")
+		case int(TypeB), int(TypeC), int(TypeD), int(TypeE), int(TypeF), int(TypeG), int(TypeH), int(TypeI), int(TypeJ), int(TypeK):
+			l := b[p]
+			p++
+			if p+int(l) > len(b) {
+				return nil, fmt.Errorf("invalid length %d of raw data for Segment Sub TLV %d", l, t)
+			}
+			s, err := UnmarshalSegment(SegmentType(t), b[p:p+int(l)])
+			if err != nil {
+				return nil, err
+			}
+			sl.Segment = append(sl.Segment, s)
+			p += int(l)
 		default:
 			return nil, fmt.Errorf("unknown type of segment sub tlv %d", t)
 		}
@@ -286,6 +273,16 @@ func (ta *typeASegment) GetTTL() byte {
 	return ta.ttl
 }
 
+func (ta *typeASegment) Normalize() *NormalizedSegment {
+	l := ta.label
+	return &NormalizedSegment{
+		Type:     TypeA,
+		TypeName: segmentTypeNames[TypeA],
+		Flags:    ta.flags,
+		Label:    &l,
+	}
+}
+
 func (ta *typeASegment) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		SegmentType SegmentType   `json:"segment_type,omitempty"`
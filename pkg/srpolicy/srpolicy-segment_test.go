@@ -0,0 +1,216 @@
+package srpolicy
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/sbezverk/gobmp/pkg/srv6"
+)
+
+func TestUnmarshalSegment(t *testing.T) {
+	label := uint32(16001)
+	algo := uint8(128)
+	zero := uint8(0)
+	eb := uint16(48)
+	sid := []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01}
+	node := []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x02}
+	remote := []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x03}
+	structure := []byte{0x00, 0x30, 0x00, 0x00, 0x20, 0x10, 0x10, 0x00}
+	noFlags := &SegmentFlags{}
+	tests := []struct {
+		name    string
+		segType SegmentType
+		input   []byte
+		expect  *NormalizedSegment
+		fail    bool
+	}{
+		{
+			name:    "type b srv6 sid",
+			segType: TypeB,
+			input:   append([]byte{0x00, 0x00}, sid...),
+			expect:  &NormalizedSegment{Type: TypeB, TypeName: "B", Flags: noFlags, SID: "2001:db8::1"},
+		},
+		{
+			name:    "type c ipv4 node with sid",
+			segType: TypeC,
+			input:   []byte{0x60, 0x80, 0x0a, 0x00, 0x00, 0x01, 0x03, 0xe8, 0x10, 0x00},
+			expect: &NormalizedSegment{Type: TypeC, TypeName: "C", Flags: &SegmentFlags{Aflag: true, Sflag: true},
+				Algorithm: &algo, LocalAddress: "10.0.0.1", Label: &label},
+		},
+		{
+			name:    "type c ipv4 node without sid",
+			segType: TypeC,
+			input:   []byte{0x00, 0x00, 0x0a, 0x00, 0x00, 0x01},
+			expect:  &NormalizedSegment{Type: TypeC, TypeName: "C", Flags: noFlags, Algorithm: &zero, LocalAddress: "10.0.0.1"},
+		},
+		{
+			name:    "type e ipv4 node and local interface id",
+			segType: TypeE,
+			input:   []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x05, 0x0a, 0x00, 0x00, 0x01},
+			expect:  &NormalizedSegment{Type: TypeE, TypeName: "E", Flags: noFlags, LocalInterfaceID: 5, LocalAddress: "10.0.0.1"},
+		},
+		{
+			name:    "type f ipv4 adjacency with sid",
+			segType: TypeF,
+			input:   []byte{0x00, 0x00, 0x0a, 0x00, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x02, 0x03, 0xe8, 0x10, 0x00},
+			expect: &NormalizedSegment{Type: TypeF, TypeName: "F", Flags: noFlags,
+				LocalAddress: "10.0.0.1", RemoteAddress: "10.0.0.2", Label: &label},
+		},
+		{
+			name:    "type g ipv6 link local adjacency",
+			segType: TypeG,
+			input: append(append(append([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x01}, node...),
+				0x00, 0x00, 0x00, 0x02), remote...),
+			expect: &NormalizedSegment{Type: TypeG, TypeName: "G", Flags: noFlags, LocalInterfaceID: 1,
+				LocalAddress: "2001:db8::2", RemoteInterfaceID: 2, RemoteAddress: "2001:db8::3"},
+		},
+		{
+			name:    "type i ipv6 node with srv6 sid and sid structure",
+			segType: TypeI,
+			input:   append(append(append([]byte{0x30, 0x00}, node...), sid...), structure...),
+			expect: &NormalizedSegment{Type: TypeI, TypeName: "I", Flags: &SegmentFlags{Sflag: true, Bflag: true},
+				Algorithm: &zero, LocalAddress: "2001:db8::2", SID: "2001:db8::1", EndpointBehavior: &eb,
+				SIDStructure: &srv6.SIDStructureSubSubTLV{LocalBlockLength: 32, LocalNodeLength: 16, FunctionLength: 16}},
+		},
+		{
+			name:    "type k ipv6 adjacency",
+			segType: TypeK,
+			input:   append(append([]byte{0x00, 0x00}, node...), remote...),
+			expect: &NormalizedSegment{Type: TypeK, TypeName: "K", Flags: noFlags, Algorithm: &zero,
+				LocalAddress: "2001:db8::2", RemoteAddress: "2001:db8::3"},
+		},
+		{
+			name:    "type d truncated",
+			segType: TypeD,
+			input:   []byte{0x00, 0x00, 0x20, 0x01},
+			fail:    true,
+		},
+		{
+			name:    "type h with invalid sid length",
+			segType: TypeH,
+			input:   append(append(append([]byte{0x00, 0x00}, node...), remote...), 0x00, 0x01),
+			fail:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalSegment(tt.segType, tt.input)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if got.GetType() != tt.segType {
+				t.Fatalf("expected type %d does not match computed %d", tt.segType, got.GetType())
+			}
+			if !reflect.DeepEqual(tt.expect, got.Normalize()) {
+				t.Logf("Diffs: %+v", deep.Equal(tt.expect, got.Normalize()))
+				t.Fatalf("expected segment %+v does not match computed %+v", tt.expect, got.Normalize())
+			}
+		})
+	}
+}
+
+func TestGetCandidatePath(t *testing.T) {
+	label := uint32(100010)
+	algo := uint8(0)
+	input := []byte{
+		// Tunnel Encapsulation type 15 and length
+		0x00, 0x0f, 0x00, 0x33,
+		// Binding SID with S-Flag and label 899840
+		0x0d, 0x06, 0x80, 0x00, 0xdb, 0xb0, 0x00, 0x00,
+		// ENLP
+		0x0e, 0x03, 0x00, 0x00, 0x03,
+		// Priority
+		0x0f, 0x02, 0x05, 0x00,
+		// Policy Name "red"
+		0x82, 0x00, 0x04, 0x00, 'r', 'e', 'd',
+		// Candidate Path Name "cp1"
+		0x81, 0x00, 0x04, 0x00, 'c', 'p', '1',
+		// Segment List with Type A and Type C segments without Weight
+		0x80, 0x00, 0x11, 0x00,
+		0x01, 0x06, 0x00, 0x00, 0x18, 0x6a, 0xa0, 0x00,
+		0x03, 0x06, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x01,
+	}
+	expect := &CandidatePath{
+		Preference:        DefaultPreference,
+		BSID:              "899840",
+		SpecifiedBSIDOnly: true,
+		ENLP:              3,
+		ENLPName:          "Push IPv4 and IPv6 Explicit NULL",
+		Priority:          5,
+		PolicyName:        "red",
+		PathName:          "cp1",
+		SegmentLists: []*NormalizedSegmentList{
+			{
+				Weight: DefaultWeight,
+				Segments: []*NormalizedSegment{
+					{Type: TypeA, TypeName: "A", Flags: &SegmentFlags{}, Label: &label},
+					{Type: TypeC, TypeName: "C", Flags: &SegmentFlags{}, Algorithm: &algo, LocalAddress: "10.0.0.1"},
+				},
+			},
+		},
+	}
+	tlv, err := UnmarshalSRPolicyTLV(input)
+	if err != nil {
+		t.Fatalf("failed with error: %+v", err)
+	}
+	got := tlv.GetCandidatePath()
+	if !reflect.DeepEqual(expect, got) {
+		t.Logf("Diffs: %+v", deep.Equal(expect, got))
+		t.Fatalf("expected candidate path %+v does not match computed %+v", expect, got)
+	}
+}
+
+func TestSegmentListJSON(t *testing.T) {
+	label := uint32(16001)
+	algo := uint8(128)
+	zero := uint8(0)
+	eb := uint16(48)
+	structure := &srv6.SIDStructureSubSubTLV{LocalBlockLength: 32, LocalNodeLength: 16, FunctionLength: 16}
+	flags := &SegmentFlags{Sflag: true, Bflag: true}
+	expect := &SegmentList{
+		Weight: &Weight{Weight: 10},
+		Segment: []Segment{
+			&typeASegment{flags: &SegmentFlags{}, label: 100010},
+			&segment{n: &NormalizedSegment{Type: TypeB, TypeName: "B", Flags: flags, SID: "2001:db8::1",
+				EndpointBehavior: &eb, SIDStructure: structure}},
+			&segment{n: &NormalizedSegment{Type: TypeC, TypeName: "C", Flags: &SegmentFlags{Aflag: true, Sflag: true},
+				Algorithm: &algo, LocalAddress: "10.0.0.1", Label: &label}},
+			&segment{n: &NormalizedSegment{Type: TypeD, TypeName: "D", Flags: &SegmentFlags{}, Algorithm: &zero,
+				LocalAddress: "2001:db8::2", Label: &label}},
+			&segment{n: &NormalizedSegment{Type: TypeE, TypeName: "E", Flags: &SegmentFlags{}, LocalInterfaceID: 5,
+				LocalAddress: "10.0.0.1"}},
+			&segment{n: &NormalizedSegment{Type: TypeF, TypeName: "F", Flags: &SegmentFlags{}, LocalAddress: "10.0.0.1",
+				RemoteAddress: "10.0.0.2", Label: &label}},
+			&segment{n: &NormalizedSegment{Type: TypeG, TypeName: "G", Flags: &SegmentFlags{}, LocalInterfaceID: 1,
+				LocalAddress: "fe80::2", RemoteInterfaceID: 2, RemoteAddress: "fe80::3"}},
+			&segment{n: &NormalizedSegment{Type: TypeH, TypeName: "H", Flags: &SegmentFlags{}, LocalAddress: "2001:db8::2",
+				RemoteAddress: "2001:db8::3", Label: &label}},
+			&segment{n: &NormalizedSegment{Type: TypeI, TypeName: "I", Flags: flags, Algorithm: &zero,
+				LocalAddress: "2001:db8::2", SID: "2001:db8::1", EndpointBehavior: &eb, SIDStructure: structure}},
+			&segment{n: &NormalizedSegment{Type: TypeJ, TypeName: "J", Flags: &SegmentFlags{Vflag: true}, Algorithm: &algo,
+				LocalInterfaceID: 1, LocalAddress: "fe80::2", RemoteInterfaceID: 2, RemoteAddress: "fe80::3", SID: "2001:db8::1"}},
+			&segment{n: &NormalizedSegment{Type: TypeK, TypeName: "K", Flags: &SegmentFlags{}, Algorithm: &zero,
+				LocalAddress: "2001:db8::2", RemoteAddress: "2001:db8::3"}},
+		},
+	}
+	b, err := json.Marshal(expect)
+	if err != nil {
+		t.Fatalf("failed to marshal segment list with error: %+v", err)
+	}
+	got := &SegmentList{}
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatalf("failed to unmarshal segment list with error: %+v", err)
+	}
+	if !reflect.DeepEqual(expect, got) {
+		t.Logf("Diffs: %+v", deep.Equal(expect, got))
+		t.Fatalf("segment list %s does not match original %+v", string(b), expect)
+	}
+}
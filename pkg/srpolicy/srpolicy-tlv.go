@@ -38,8 +38,8 @@ const (
 	SEGMENTLISTSTLV = 128
 	// BSIDSTLV defines Binding SID Sub TLV code
 	BSIDSTLV = 13
-	// SRV6STLV defines SRv6 Binding SID Sub TLV code (NOT YET DEFINED)
-	SRV6STLV = 255
+	// SRV6STLV defines SRv6 Binding SID Sub TLV code
+	SRV6STLV = 20
 	// PREFERENCESTLV defines Preference Sub TLV code
 	PREFERENCESTLV = 12
 	// ENLPSTLV defines Explicit Null Label Policy Sub TLV code
@@ -48,8 +48,8 @@ const (
 	PRIORITYSTLV = 15
 	// PATHNAMESTLV defines  Policy Candidate Path Name Sub-TLV code
 	PATHNAMESTLV = 129
-	// POLICYNAMESTLV defines Policy Name Sub-TLV Sub TLV code (NOT YET DEFINED)
-	POLICYNAMESTLV = 254
+	// POLICYNAMESTLV defines Policy Name Sub-TLV Sub TLV code
+	POLICYNAMESTLV = 130
 )
 
 // UnmarshalSRPolicyTLV builds Link State NLRI object for SAFI 73
@@ -83,23 +83,35 @@ func UnmarshalSRPolicyTLV(b []byte) (*TLV, error) {
 		st := b[p]
 		sl := 0
 		p++
+		if p >= len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SR Policy Sub TLV %d", st)
+		}
 		switch st {
 		case SEGMENTLISTSTLV:
 			glog.Infof("Segment List Sub TLV")
+			if p+3 > len(b) {
+				return nil, fmt.Errorf("not enough bytes to unmarshal Segment List Sub TLV")
+			}
 			sl = int(binary.BigEndian.Uint16(b[p : p+2]))
 			p += 2
 			// Skip reserved byte
 			p++
 			sl--
+			if sl < 0 || p+sl > len(b) {
+				return nil, fmt.Errorf("invalid length %d of Segment List Sub TLV", sl+1)
+			}
 			l, err := UnmarshalSegmentListSTLV(b[p : p+sl])
 			if err != nil {
 				return nil, err
 			}
 			tlv.SegmentList = append(tlv.SegmentList, l)
-		case BSIDSTLV:
+		case BSIDSTLV, SRV6STLV:
 			glog.Infof("Binding SID Sub TLV")
 			sl = int(b[p])
 			p++
+			if p+sl > len(b) {
+				return nil, fmt.Errorf("not enough bytes to unmarshal SR Policy Sub TLV %d of length %d", st, sl)
+			}
 			tlv.BindingSID = &BindingSID{}
 			if tlv.BindingSID.BSID, err = UnmarshalBSIDSTLV(b[p : p+sl]); err != nil {
 				return nil, err
@@ -109,6 +121,9 @@ func UnmarshalSRPolicyTLV(b []byte) (*TLV, error) {
 			glog.Infof("Preference Sub TLV")
 			sl = int(b[p])
 			p++
+			if p+sl > len(b) {
+				return nil, fmt.Errorf("not enough bytes to unmarshal SR Policy Sub TLV %d of length %d", st, sl)
+			}
 			if tlv.Preference, err = UnmarshalPreferenceSTLV(b[p : p+sl]); err != nil {
 				return nil, err
 			}
@@ -119,6 +134,12 @@ func UnmarshalSRPolicyTLV(b []byte) (*TLV, error) {
 			glog.Infof("ENLP Sub TLV")
 			sl = int(b[p])
 			p++
+			if p+sl > len(b) {
+				return nil, fmt.Errorf("not enough bytes to unmarshal SR Policy Sub TLV %d of length %d", st, sl)
+			}
+			if sl < 3 {
+				return nil, fmt.Errorf("invalid length %d of ENLP Sub TLV", sl)
+			}
 			tlv.ENLP = &ENLP{
 				Flags: b[p],
 				ENLP:  b[p+2],
@@ -127,16 +148,36 @@ func UnmarshalSRPolicyTLV(b []byte) (*TLV, error) {
 			glog.Infof("Priority Sub TLV")
 			sl = int(b[p])
 			p++
+			if p+sl > len(b) {
+				return nil, fmt.Errorf("not enough bytes to unmarshal SR Policy Sub TLV %d of length %d", st, sl)
+			}
+			if sl < 1 {
+				return nil, fmt.Errorf("invalid length %d of Priority Sub TLV", sl)
+			}
 			tlv.Priority = b[p]
-		case PATHNAMESTLV:
-			glog.Infof("Policy Candidate Path Name Sub TLV")
-			sl = int(b[p])
-			p++
-			tlv.PathName = string(b[p : p+sl])
+		case PATHNAMESTLV, POLICYNAMESTLV:
+			glog.Infof("Policy Name or Candidate Path Name Sub TLV")
+			// Both Sub TLVs carry 2 bytes of length followed by reserved byte
+			if p+2 > len(b) {
+				return nil, fmt.Errorf("not enough bytes to unmarshal SR Policy Sub TLV %d", st)
+			}
+			sl = int(binary.BigEndian.Uint16(b[p : p+2]))
+			p += 2
+			if sl < 1 || p+sl > len(b) {
+				return nil, fmt.Errorf("invalid length %d of SR Policy Sub TLV %d", sl, st)
+			}
+			if st == PATHNAMESTLV {
+				tlv.PathName = string(b[p+1 : p+sl])
+			} else {
+				tlv.Name = string(b[p+1 : p+sl])
+			}
 		default:
 			glog.Warningf("SR Policy Sub TLV %+v is not supported", st)
 			sl = int(b[p])
 			p++
+			if p+sl > len(b) {
+				return nil, fmt.Errorf("not enough bytes to unmarshal SR Policy Sub TLV %d of length %d", st, sl)
+			}
 		}
 		p += sl
 	}
@@ -0,0 +1,84 @@
+//go:build go1.18
+// +build go1.18
+
+package srv6
+
+import "testing"
+
+func FuzzUnmarshalSRv6SIDStructureTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRv6SIDStructureTLV(b)
+	})
+}
+
+func FuzzUnmarshalSRv6SIDNLRI(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRv6SIDNLRI(b)
+	})
+}
+
+func FuzzUnmarshalSRv6BGPPeerNodeSIDTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRv6BGPPeerNodeSIDTLV(b)
+	})
+}
+
+func FuzzUnmarshalSRv6SIDDescriptor(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRv6SIDDescriptor(b)
+	})
+}
+
+func FuzzUnmarshalSRv6CapabilityTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRv6CapabilityTLV(b)
+	})
+}
+
+func FuzzUnmarshalSIDStructureSubSubTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSIDStructureSubSubTLV(b)
+	})
+}
+
+func FuzzUnmarshalInformationSubTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalInformationSubTLV(b)
+	})
+}
+
+func FuzzUnmarshalSRv6L3Service(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRv6L3Service(b)
+	})
+}
+
+func FuzzUnmarshalSRv6L3ServiceSubTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRv6L3ServiceSubTLV(b)
+	})
+}
+
+func FuzzUnmarshalSRv6L3ServiceSubSubTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRv6L3ServiceSubSubTLV(b)
+	})
+}
+
+func FuzzUnmarshalSRv6LocatorTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRv6LocatorTLV(b)
+	})
+}
+
+func FuzzUnmarshalSRv6EndpointBehaviorTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRv6EndpointBehaviorTLV(b)
+	})
+}
+
+func FuzzUnmarshalSRv6EndXSIDTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalSRv6EndXSIDTLV(b)
+	})
+}
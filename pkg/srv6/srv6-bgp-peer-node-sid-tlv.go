@@ -2,6 +2,7 @@ package srv6
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
@@ -21,6 +22,9 @@ func UnmarshalSRv6BGPPeerNodeSIDTLV(b []byte) (*BGPPeerNodeSID, error) {
 	if glog.V(6) {
 		glog.Infof("SRv6 BGP Peer Node SID TLV Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 12 {
+		return nil, fmt.Errorf("invalid length of SRv6 BGP Peer Node SID TLV %d", len(b))
+	}
 	bgp := BGPPeerNodeSID{}
 	p := 0
 	bgp.Flag = b[p]
@@ -2,6 +2,7 @@ package srv6
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
@@ -20,6 +21,9 @@ func UnmarshalSRv6EndpointBehaviorTLV(b []byte) (*EndpointBehavior, error) {
 	if glog.V(6) {
 		glog.Infof("SRv6 End.X SID TLV Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("invalid length of SRv6 Endpoint Behavior TLV %d", len(b))
+	}
 	e := EndpointBehavior{}
 	p := 0
 	e.EndpointBehavior = binary.BigEndian.Uint16(b[p : p+2])
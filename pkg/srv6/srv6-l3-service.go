@@ -24,7 +24,9 @@ type SIDStructureSubSubTLV struct {
 
 // UnmarshalSIDStructureSubSubTLV instantiates SID Structure Sub Sub TLV
 func UnmarshalSIDStructureSubSubTLV(b []byte) (*SIDStructureSubSubTLV, error) {
-	// Skip Resrved byte
+	if len(b) < 6 {
+		return nil, fmt.Errorf("invalid length of SID Structure Sub Sub TLV %d", len(b))
+	}
 	p := 0
 	tlv := &SIDStructureSubSubTLV{}
 	tlv.LocalBlockLength = b[p]
@@ -97,6 +99,9 @@ func (istlv *InformationSubTLV) UnmarshalJSON(b []byte) error {
 
 // UnmarshalInformationSubTLV instantiates Information SubT LV
 func UnmarshalInformationSubTLV(b []byte) (*InformationSubTLV, error) {
+	if len(b) < 20 {
+		return nil, fmt.Errorf("invalid length of SRv6 Information Sub TLV %d", len(b))
+	}
 	// Skip Resrved byte
 	p := 1
 	tlv := &InformationSubTLV{}
@@ -176,6 +181,9 @@ func UnmarshalSRv6L3Service(b []byte) (*L3Service, error) {
 	l3 := L3Service{
 		SubTLVs: make(map[uint8][]SubTLV),
 	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("invalid length of SRv6 L3 Service %d", len(b))
+	}
 	// Skipping reserved byte
 	stlv, err := UnmarshalSRv6L3ServiceSubTLV(b[1:])
 	if err != nil {
@@ -193,8 +201,14 @@ func UnmarshalSRv6L3ServiceSubTLV(b []byte) (map[uint8][]SubTLV, error) {
 	for p := 0; p < len(b); {
 		t := b[p]
 		p++
+		if p+2 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SRv6 L3 Service Sub TLV")
+		}
 		l := binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
+		if p+int(l) > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SRv6 L3 Service Sub TLV of length %d", l)
+		}
 		var s SubTLV
 		switch t {
 		case 1:
@@ -224,12 +238,19 @@ func UnmarshalSRv6L3ServiceSubSubTLV(b []byte) (map[uint8][]SubSubTLV, error) {
 	for p := 1; p < len(b); {
 		t := b[p]
 		p++
+		if p+2 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SRv6 L3 Service Sub Sub TLV")
+		}
 		l := binary.BigEndian.Uint16(b[p : p+2])
 		p += 2
+		if p+int(l) > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal SRv6 L3 Service Sub Sub TLV of length %d", l)
+		}
 		var s SubSubTLV
 		switch t {
 		case 1:
 			if s, err = UnmarshalSIDStructureSubSubTLV(b[p : p+int(l)]); err != nil {
+				return nil, err
 			}
 		default:
 			s = make([]byte, l)
@@ -25,15 +25,12 @@ func UnmarshalSRv6LocatorTLV(b []byte) (*LocatorTLV, error) {
 	}
 	p := 0
 	loc := LocatorTLV{}
-	loc.Flag = b[p]
-	if p+1 > len(b) {
+	if p+2 > len(b) {
 		return nil, fmt.Errorf("invalid input %s", tools.MessageHex(b))
 	}
+	loc.Flag = b[p]
 	p++
 	loc.Algorithm = b[p]
-	if p+1 > len(b) {
-		return nil, fmt.Errorf("invalid input %s", tools.MessageHex(b))
-	}
 	p++
 	// Skip reserved byte
 	if p+2 > len(b) {
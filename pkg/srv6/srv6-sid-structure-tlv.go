@@ -1,6 +1,8 @@
 package srv6
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 	"github.com/sbezverk/gobmp/pkg/tools"
 )
@@ -19,6 +21,9 @@ func UnmarshalSRv6SIDStructureTLV(b []byte) (*SIDStructure, error) {
 	if glog.V(6) {
 		glog.Infof("SRv6 SID Structure TLV Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("invalid length of SRv6 SID Structure TLV %d", len(b))
+	}
 	st := SIDStructure{}
 	p := 0
 	st.LBLength = b[p]
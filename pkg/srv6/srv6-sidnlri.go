@@ -85,11 +85,20 @@ func UnmarshalSRv6SIDNLRI(b []byte) (*SIDNLRI, error) {
 	p := 0
 	sr.ProtocolID = base.ProtoID(b[p])
 	p++
+	if p+8 > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal SRv6 SID NLRI")
+	}
 	sr.Identifier = make([]byte, 8)
 	copy(sr.Identifier, b[p:p+8])
 	p += 8
 	// Get Node Descriptor's length, skip Node Descriptor Type
+	if p+4 > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal Node Descriptor")
+	}
 	l := binary.BigEndian.Uint16(b[p+2 : p+4])
+	if p+int(l)+4 > len(b) {
+		return nil, fmt.Errorf("not enough bytes to unmarshal Node Descriptor of length %d", l)
+	}
 	ln, err := base.UnmarshalNodeDescriptor(b[p : p+int(l)+4])
 	if err != nil {
 		return nil, err
@@ -0,0 +1,48 @@
+//go:build go1.18
+// +build go1.18
+
+package te
+
+import "testing"
+
+func FuzzUnmarshalPolicyCandidatePathDescriptor(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalPolicyCandidatePathDescriptor(b)
+	})
+}
+
+func FuzzUnmarshalLocalMPLSCrossConnect(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalLocalMPLSCrossConnect(b)
+	})
+}
+
+func FuzzUnmarshalLocalMPLSCrossConnectSubTLV(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalLocalMPLSCrossConnectSubTLV(b)
+	})
+}
+
+func FuzzUnmarshalLocalMPLSCrossConnectFEC(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalLocalMPLSCrossConnectFEC(b)
+	})
+}
+
+func FuzzUnmarshalLocalMPLSCrossConnectInterface(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalLocalMPLSCrossConnectInterface(b)
+	})
+}
+
+func FuzzUnmarshalTEPolicyNLRI(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalTEPolicyNLRI(b)
+	})
+}
+
+func FuzzUnmarshalPolicyDescriptor(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalPolicyDescriptor(b)
+	})
+}
@@ -24,7 +24,7 @@ func UnmarshalPolicyDescriptor(b []byte) (*PolicyDescriptor, error) {
 	p := 0
 	for p < len(b) {
 		tlv := &base.TLV{}
-		if p+4 >= len(b) {
+		if p+4 > len(b) {
 			return nil, fmt.Errorf("not enough bytes to process TE Policy Descriptor")
 		}
 		tlv.Type = binary.BigEndian.Uint16(b[p : p+2])
@@ -36,13 +36,13 @@ func UnmarshalPolicyDescriptor(b []byte) (*PolicyDescriptor, error) {
 		}
 		tlv.Value = make([]byte, tlv.Length)
 		copy(tlv.Value, b[p:p+int(tlv.Length)])
+		p += int(tlv.Length)
 		if _, ok := tlvs[tlv.Type]; ok {
 			glog.Warningf("Found duplicate TLV of type %d in the list of TE Policy Descriptor's TLVs, please file an issue for gobmp", tlv.Type)
 			glog.Infof("TE Policy Descriptor Raw: %s", tools.MessageHex(b))
 			continue
 		}
 		tlvs[tlv.Type] = tlv
-		p += int(tlv.Length)
 	}
 
 	return &PolicyDescriptor{
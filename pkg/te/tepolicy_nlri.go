@@ -19,6 +19,16 @@ type NLRI struct {
 	Policy      *PolicyDescriptor    `json:"te_policy_descriptor,omitempty"`
 }
 
+// GetIdentifier returns value of Identifier as int64
+func (te *NLRI) GetIdentifier() int64 {
+	return int64(binary.BigEndian.Uint64(te.Identifier))
+}
+
+// GetProtocolID returns a string representation of TE Policy NLRI ProtocolID field
+func (te *NLRI) GetProtocolID() string {
+	return base.ProtocolIDString(te.ProtocolID)
+}
+
 // UnmarshalTEPolicyNLRI builds SRv6SIDNLRI NLRI object
 func UnmarshalTEPolicyNLRI(b []byte) (*NLRI, error) {
 	if glog.V(6) {
@@ -51,7 +61,7 @@ func UnmarshalTEPolicyNLRI(b []byte) (*NLRI, error) {
 	}
 	// Get Node Descriptor's length, skip Node Descriptor Type
 	l := binary.BigEndian.Uint16(b[p+2 : p+4])
-	if p+int(l) >= len(b) {
+	if p+int(l)+4 > len(b) {
 		return nil, fmt.Errorf("not enough bytes to process TE Policy NLRI")
 	}
 	he, err := base.UnmarshalNodeDescriptor(b[p : p+int(l)+4])
@@ -62,6 +72,8 @@ func UnmarshalTEPolicyNLRI(b []byte) (*NLRI, error) {
 	// TODO Add check and return error if these two TLVs are missing
 	te.HeadEnd = he
 	te.HeadEndHash = fmt.Sprintf("%x", md5.Sum(b[p:p+int(l)+4]))
+	// Skip Node Descriptor Type and Length 4 bytes
+	p += 4
 	p += int(l)
 	// TE Policy Descriptor consists of list of TLVs, minimal TLV length is 4 bytes
 	if p+4 < len(b) {
@@ -0,0 +1,71 @@
+package te
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalTEPolicyNLRI(t *testing.T) {
+	input := []byte{
+		// Protocol ID Segment Routing
+		0x09,
+		// Identifier
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// Head-End Node Descriptor, AS 65000 and BGP Router ID 10.0.0.1
+		0x01, 0x00, 0x00, 0x10,
+		0x02, 0x00, 0x00, 0x04, 0x00, 0x00, 0xfd, 0xe8,
+		0x02, 0x04, 0x00, 0x04, 0x0a, 0x00, 0x00, 0x01,
+		// Tunnel ID 5
+		0x02, 0x26, 0x00, 0x02, 0x00, 0x05,
+		// Policy Candidate Path Descriptor
+		0x02, 0x2a, 0x00, 0x18,
+		0x02, 0x00, 0x00, 0x00,
+		0x0a, 0x00, 0x00, 0x02,
+		0x00, 0x00, 0x00, 0x64,
+		0x00, 0x00, 0xfd, 0xe8,
+		0x0a, 0x00, 0x00, 0x01,
+		0x00, 0x00, 0x00, 0x01,
+	}
+	nlri, err := UnmarshalTEPolicyNLRI(input)
+	if err != nil {
+		t.Fatalf("supposed to succeed but failed with error: %+v", err)
+	}
+	if asn := nlri.HeadEnd.GetASN(); asn != 65000 {
+		t.Fatalf("expected Head-End ASN 65000 got %d", asn)
+	}
+	if nlri.Policy == nil {
+		t.Fatalf("TE Policy Descriptor is missing")
+	}
+	id, err := nlri.Policy.GetTunnelID()
+	if err != nil {
+		t.Fatalf("failed to get Tunnel ID with error: %+v", err)
+	}
+	if id != 5 {
+		t.Fatalf("expected Tunnel ID 5 got %d", id)
+	}
+	cp, err := nlri.Policy.GetPolicyCandidatePathDescriptor()
+	if err != nil {
+		t.Fatalf("failed to get Candidate Path Descriptor with error: %+v", err)
+	}
+	expect := &PolicyCandidatePathDescriptor{
+		ProtocolOrigin: BGPSRPolicy,
+		Endpoint:       []byte{10, 0, 0, 2},
+		Color:          100,
+		OriginatorASN:  65000,
+		OriginatorAddr: []byte{10, 0, 0, 1},
+		Descriminator:  1,
+	}
+	if !reflect.DeepEqual(expect, cp) {
+		t.Fatalf("expected %+v does not match computed %+v", expect, cp)
+	}
+}
+
+func TestUnmarshalPolicyDescriptorDuplicateTLV(t *testing.T) {
+	pd, err := UnmarshalPolicyDescriptor([]byte{0x02, 0x26, 0x00, 0x02, 0x00, 0x05, 0x02, 0x26, 0x00, 0x02, 0x00, 0x06})
+	if err != nil {
+		t.Fatalf("supposed to succeed but failed with error: %+v", err)
+	}
+	if id, _ := pd.GetTunnelID(); id != 5 {
+		t.Fatalf("expected the first Tunnel ID 5 got %d", id)
+	}
+}
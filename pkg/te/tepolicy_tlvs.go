@@ -145,8 +145,21 @@ func UnmarshalPolicyCandidatePathDescriptor(b []byte) (*PolicyCandidatePathDescr
 	p++
 	pc.FlagE = b[p]&0x80 == 0x80
 	pc.FlagO = b[p]&0x40 == 0x40
+	p++
 	// Skip reserved 2 bytes
 	p += 2
+	// Endpoint and Originator Address are 4 bytes for ipv4 and 16 bytes for ipv6,
+	// Color, Originator ASN and Descriminator are 4 bytes each.
+	el, ol := 4, 4
+	if pc.FlagE {
+		el = 16
+	}
+	if pc.FlagO {
+		ol = 16
+	}
+	if p+el+ol+12 > len(b) {
+		return nil, fmt.Errorf("invalid length of bytes %d for flags 0x%02x", len(b), b[1])
+	}
 	if pc.FlagE {
 		// Endpoint is ipv6 address
 		pc.Endpoint = make([]byte, 16)
@@ -276,6 +289,9 @@ func UnmarshalLocalMPLSCrossConnectFEC(b []byte) (*LocalMPLSCrossConnectFEC, err
 	if glog.V(6) {
 		glog.Infof("Local MPLS Cross Connect FEC Sub TLV Raw: %s", tools.MessageHex(b))
 	}
+	if len(b) < 2 {
+		return nil, fmt.Errorf("invalid length of Local MPLS Cross Connect FEC Sub TLV %d", len(b))
+	}
 	f := &LocalMPLSCrossConnectFEC{}
 	p := 0
 	f.Flag4 = b[p]&0x80 == 0x80
@@ -0,0 +1,18 @@
+//go:build go1.18
+// +build go1.18
+
+package unicast
+
+import "testing"
+
+func FuzzUnmarshalUnicastNLRI(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalUnicastNLRI(b)
+	})
+}
+
+func FuzzUnmarshalLUNLRI(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalLUNLRI(b)
+	})
+}
@@ -24,7 +24,7 @@ func UnmarshalUnicastNLRI(b []byte) (*base.MPNLRI, error) {
 	for p := 0; p < len(b); {
 		up := base.Route{}
 		// When default prefix is sent, actual NLRI is 1 byte with value of 0x0
-		if b[p] == 0x0 && len(b) != 1 {
+		if b[p] == 0x0 && len(b) != 1 && p+4 < len(b) {
 			up.PathID = binary.BigEndian.Uint32(b[p : p+4])
 			p += 4
 		}
@@ -34,6 +34,9 @@ func UnmarshalUnicastNLRI(b []byte) (*base.MPNLRI, error) {
 		if up.Length%8 != 0 {
 			l++
 		}
+		if l < 0 || p+l > len(b) {
+			return nil, fmt.Errorf("invalid prefix length %d", up.Length)
+		}
 		up.Prefix = make([]byte, l)
 		copy(up.Prefix, b[p:p+l])
 		p += l
@@ -55,7 +58,7 @@ func UnmarshalLUNLRI(b []byte) (*base.MPNLRI, error) {
 		up := base.Route{
 			Label: make([]*base.Label, 0),
 		}
-		if b[p] == 0x0 && len(b) != 1 {
+		if b[p] == 0x0 && len(b) != 1 && p+4 < len(b) {
 			up.PathID = binary.BigEndian.Uint32(b[p : p+4])
 			p += 4
 		}
@@ -64,6 +67,9 @@ func UnmarshalLUNLRI(b []byte) (*base.MPNLRI, error) {
 		// Next 3 bytes are a part of Compatibility field 0x800000
 		// then it is MP_UNREACH_NLRI and no Label information is present
 		compatibilityField := 0
+		if p+3 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal label")
+		}
 		if bytes.Equal([]byte{0x80, 0x00, 0x00}, b[p:p+3]) {
 			up.Label = nil
 			compatibilityField = 3
@@ -72,7 +78,7 @@ func UnmarshalLUNLRI(b []byte) (*base.MPNLRI, error) {
 			// Otherwise getting labels
 			up.Label = make([]*base.Label, 0)
 			bos := false
-			for !bos && p < len(b) {
+			for !bos && p+3 <= len(b) {
 				l, err := base.MakeLabel(b[p : p+3])
 				if err != nil {
 					return nil, err
@@ -88,6 +94,9 @@ func UnmarshalLUNLRI(b []byte) (*base.MPNLRI, error) {
 		if up.Length%8 != 0 {
 			l++
 		}
+		if l < 0 || p+l > len(b) {
+			return nil, fmt.Errorf("invalid prefix length %d", up.Length)
+		}
 		up.Prefix = make([]byte, l)
 		copy(up.Prefix, b[p:p+l])
 		p += l
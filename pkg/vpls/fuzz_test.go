@@ -0,0 +1,20 @@
+//go:build go1.18
+// +build go1.18
+
+package vpls
+
+import "testing"
+
+func FuzzUnmarshalVPLSNLRI(f *testing.F) {
+	f.Add([]byte{0x00, 0x11, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x01, 0x00, 0x01, 0x00, 0x0a, 0x18, 0x6a, 0x01})
+	f.Add([]byte{0x00, 0x0c, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x64, 0x0a, 0x00, 0x00, 0x01})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalVPLSNLRI(b)
+	})
+}
+
+func FuzzUnmarshalLayer2Info(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		UnmarshalLayer2Info(b)
+	})
+}
@@ -0,0 +1,80 @@
+package vpls
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+const (
+	// ControlFlagSequenced defines S bit of Layer2 Info control flags, sequenced delivery is required
+	ControlFlagSequenced = 0x01
+	// ControlFlagControlWord defines C bit of Layer2 Info control flags, control word must be present
+	ControlFlagControlWord = 0x02
+)
+
+// EncapsulationTypes defines names of Pseudowire types used as Layer2 Info encapsulation types
+// https://www.iana.org/assignments/pwe3-parameters/pwe3-parameters.xhtml
+var EncapsulationTypes = map[uint8]string{
+	1:  "Frame Relay DLCI",
+	2:  "ATM AAL5 SDU VCC",
+	3:  "ATM transparent cell",
+	4:  "Ethernet Tagged Mode",
+	5:  "Ethernet",
+	6:  "HDLC",
+	7:  "PPP",
+	11: "IP Layer2 Transport",
+	19: "VPLS",
+}
+
+// Layer2Info defines Layer2 Info Extended Community
+// https://tools.ietf.org/html/rfc4761#section-3.2.4
+type Layer2Info struct {
+	EncapsulationType uint8
+	ControlFlags      uint8
+	MTU               uint16
+}
+
+// ControlWord returns true when C bit is set
+func (l *Layer2Info) ControlWord() bool {
+	return l.ControlFlags&ControlFlagControlWord != 0
+}
+
+// Sequenced returns true when S bit is set
+func (l *Layer2Info) Sequenced() bool {
+	return l.ControlFlags&ControlFlagSequenced != 0
+}
+
+// EncapsulationTypeString returns the name of the encapsulation type
+func (l *Layer2Info) EncapsulationTypeString() string {
+	if s, ok := EncapsulationTypes[l.EncapsulationType]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown (%d)", l.EncapsulationType)
+}
+
+func (l *Layer2Info) String() string {
+	flags := make([]string, 0)
+	if l.ControlWord() {
+		flags = append(flags, "C")
+	}
+	if l.Sequenced() {
+		flags = append(flags, "S")
+	}
+	if rest := l.ControlFlags &^ (ControlFlagControlWord | ControlFlagSequenced); rest != 0 {
+		flags = append(flags, fmt.Sprintf("0x%02x", rest))
+	}
+	return fmt.Sprintf("encap:%s flags:%s mtu:%d", l.EncapsulationTypeString(), strings.Join(flags, ","), l.MTU)
+}
+
+// UnmarshalLayer2Info instantiates Layer2 Info object from the 6 bytes value of the extended community
+func UnmarshalLayer2Info(b []byte) (*Layer2Info, error) {
+	if len(b) != 6 {
+		return nil, fmt.Errorf("invalid length of Layer2 Info extended community %d", len(b))
+	}
+	return &Layer2Info{
+		EncapsulationType: b[0],
+		ControlFlags:      b[1],
+		MTU:               binary.BigEndian.Uint16(b[2:4]),
+	}, nil
+}
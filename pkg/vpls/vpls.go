@@ -0,0 +1,124 @@
+package vpls
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/golang/glog"
+	"github.com/sbezverk/gobmp/pkg/base"
+	"github.com/sbezverk/gobmp/pkg/tools"
+)
+
+const (
+	// nlriLength defines the length of VPLS NLRI used by BGP based auto-discovery and signaling (rfc4761)
+	nlriLength = 17
+	// adIPv4Length defines the length of BGP-AD NLRI carrying IPv4 PE address (rfc6074)
+	adIPv4Length = 12
+	// adIPv6Length defines the length of BGP-AD NLRI carrying IPv6 PE address (rfc6074)
+	adIPv6Length = 24
+)
+
+// Route defines a collection of VPLS NLRI objects
+type Route struct {
+	Route []*NLRI
+}
+
+// NLRI defines a single VPLS NLRI object, either rfc4761 VE NLRI or rfc6074 BGP-AD NLRI
+// https://tools.ietf.org/html/rfc4761#section-3.2.2
+// https://tools.ietf.org/html/rfc6074#section-3.2.2
+type NLRI struct {
+	Length        uint16
+	RD            *base.RD
+	VEID          uint16
+	VEBlockOffset uint16
+	VEBlockSize   uint16
+	LabelBase     uint32
+	PEAddr        []byte
+}
+
+// IsBGPAD returns true if NLRI is rfc6074 BGP Auto-Discovery NLRI
+func (n *NLRI) IsBGPAD() bool {
+	return n.PEAddr != nil
+}
+
+// GetRD returns a string representation of RD
+func (n *NLRI) GetRD() string {
+	if n.RD == nil {
+		return ""
+	}
+	return n.RD.String()
+}
+
+// GetPEAddr returns a string representation of PE address carried in BGP-AD NLRI
+func (n *NLRI) GetPEAddr() string {
+	if n.PEAddr == nil {
+		return ""
+	}
+	return net.IP(n.PEAddr).String()
+}
+
+// UnmarshalVPLSNLRI instantiates a VPLS Route object from the slice of bytes
+func UnmarshalVPLSNLRI(b []byte) (*Route, error) {
+	if glog.V(6) {
+		glog.Infof("VPLS NLRI Raw: %s", tools.MessageHex(b))
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("NLRI length is 0")
+	}
+	r := Route{
+		Route: make([]*NLRI, 0),
+	}
+	for p := 0; p < len(b); {
+		if p+2 > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal VPLS NLRI")
+		}
+		l := int(binary.BigEndian.Uint16(b[p : p+2]))
+		p += 2
+		if p+l > len(b) {
+			return nil, fmt.Errorf("not enough bytes to unmarshal VPLS NLRI of length %d", l)
+		}
+		n, err := unmarshalNLRI(b[p : p+l])
+		if err != nil {
+			return nil, err
+		}
+		r.Route = append(r.Route, n)
+		p += l
+	}
+
+	return &r, nil
+}
+
+func unmarshalNLRI(b []byte) (*NLRI, error) {
+	var err error
+	n := &NLRI{
+		Length: uint16(len(b)),
+	}
+	switch len(b) {
+	case nlriLength:
+	case adIPv4Length:
+	case adIPv6Length:
+	default:
+		return nil, fmt.Errorf("invalid length of VPLS NLRI %d", len(b))
+	}
+	p := 0
+	if n.RD, err = base.MakeRD(b[p : p+8]); err != nil {
+		return nil, err
+	}
+	p += 8
+	if len(b) != nlriLength {
+		n.PEAddr = make([]byte, len(b)-p)
+		copy(n.PEAddr, b[p:])
+		return n, nil
+	}
+	n.VEID = binary.BigEndian.Uint16(b[p : p+2])
+	p += 2
+	n.VEBlockOffset = binary.BigEndian.Uint16(b[p : p+2])
+	p += 2
+	n.VEBlockSize = binary.BigEndian.Uint16(b[p : p+2])
+	p += 2
+	// Label Base occupies high order 20 bits of 3 bytes field
+	n.LabelBase = (uint32(b[p])<<16 | uint32(b[p+1])<<8 | uint32(b[p+2])) >> 4
+
+	return n, nil
+}
@@ -0,0 +1,95 @@
+package vpls
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sbezverk/gobmp/pkg/base"
+)
+
+func TestUnmarshalVPLSNLRI(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		expect *Route
+		fail   bool
+	}{
+		{
+			name:  "rfc4761 ve nlri",
+			input: []byte{0x00, 0x11, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x01, 0x00, 0x01, 0x00, 0x0a, 0x18, 0x6a, 0x01},
+			expect: &Route{
+				Route: []*NLRI{
+					{
+						Length:        17,
+						RD:            &base.RD{Type: 0, Value: []byte{0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64}},
+						VEID:          1,
+						VEBlockOffset: 1,
+						VEBlockSize:   10,
+						LabelBase:     100000,
+					},
+				},
+			},
+		},
+		{
+			name: "rfc6074 bgp-ad ipv4 and ipv6 nlri",
+			input: []byte{
+				0x00, 0x0c, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x64, 0x0a, 0x00, 0x00, 0x01,
+				0x00, 0x18, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+			},
+			expect: &Route{
+				Route: []*NLRI{
+					{
+						Length: 12,
+						RD:     &base.RD{Type: 1, Value: []byte{0x0a, 0x00, 0x00, 0x01, 0x00, 0x64}},
+						PEAddr: []byte{0x0a, 0x00, 0x00, 0x01},
+					},
+					{
+						Length: 24,
+						RD:     &base.RD{Type: 0, Value: []byte{0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64}},
+						PEAddr: []byte{0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+					},
+				},
+			},
+		},
+		{
+			name:  "invalid nlri length",
+			input: []byte{0x00, 0x0a, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x01},
+			fail:  true,
+		},
+		{
+			name:  "truncated nlri",
+			input: []byte{0x00, 0x11, 0x00, 0x00, 0xfd, 0xe8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x01},
+			fail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalVPLSNLRI(tt.input)
+			if err != nil && !tt.fail {
+				t.Fatalf("supposed to succeed but failed with error: %+v", err)
+			}
+			if err == nil && tt.fail {
+				t.Fatalf("supposed to fail but succeeded")
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.expect, got) {
+				t.Fatalf("expected %+v does not match computed %+v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalLayer2Info(t *testing.T) {
+	l, err := UnmarshalLayer2Info([]byte{0x13, 0x02, 0x05, 0xdc, 0x00, 0x00})
+	if err != nil {
+		t.Fatalf("supposed to succeed but failed with error: %+v", err)
+	}
+	if s := l.String(); s != "encap:VPLS flags:C mtu:1500" {
+		t.Fatalf("unexpected Layer2 Info %s", s)
+	}
+	if _, err := UnmarshalLayer2Info([]byte{0x13, 0x02, 0x05}); err == nil {
+		t.Fatalf("supposed to fail but succeeded")
+	}
+}